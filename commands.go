@@ -0,0 +1,631 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopus/internal/clipboard"
+	"gopus/internal/config"
+	"gopus/internal/history"
+	"gopus/internal/mcp"
+	"gopus/internal/output"
+	"gopus/internal/serve"
+	"gopus/internal/table"
+	"gopus/internal/version"
+)
+
+// runCommand dispatches non-interactive CLI subcommands.
+// Returns an exit code.
+func runCommand(name string, args []string) int {
+	switch name {
+	case "sessions":
+		return runSessions(args)
+	case "stats":
+		return runStats(args)
+	case "tools":
+		return runTools(args)
+	case "config":
+		return runConfig(args)
+	case "doctor":
+		return runDoctor(args)
+	case "serve-mcp":
+		return runServeMCP(args)
+	case "new":
+		return runNew(args)
+	case "version", "--version":
+		fmt.Printf("gopus %s\n", version.String())
+		return 0
+	case "help", "-h", "--help":
+		printUsage(os.Stdout)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", name)
+		printUsage(os.Stderr)
+		return 2
+	}
+}
+
+// printUsage prints the CLI subcommand overview.
+func printUsage(w *os.File) {
+	fmt.Fprintln(w, "Usage: gopus [--safe] [-q|--quiet] [--no-color|--force-color] [--workspace <name>] [--sessions-dir <dir>] [command]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command, gopus starts an interactive chat session.")
+	fmt.Fprintln(w, "With --safe, destructive commands are blocked and every tool call")
+	fmt.Fprintln(w, "requires confirmation. With -q, the startup summary is suppressed.")
+	fmt.Fprintln(w, "With --workspace (or GOPUS_WORKSPACE), sessions come from the named")
+	fmt.Fprintln(w, "workspace configured under workspaces:; --sessions-dir overrides the")
+	fmt.Fprintln(w, "sessions directory directly.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintln(w, "  sessions list [--json]  List stored sessions")
+	fmt.Fprintln(w, "  sessions tree           Show fork relationships between sessions")
+	fmt.Fprintln(w, "  sessions reindex        Rebuild the sessions index from session files")
+	fmt.Fprintln(w, "  sessions encrypt-all    Encrypt plaintext session files with a passphrase")
+	fmt.Fprintln(w, "  sessions prune [--dry-run]")
+	fmt.Fprintln(w, "                          Move sessions violating the retention limits to trash")
+	fmt.Fprintln(w, "  sessions compact [--gzip-older-than <days>]")
+	fmt.Fprintln(w, "                          Rewrite session files compactly; optionally gzip old ones")
+	fmt.Fprintln(w, "  stats                   Show usage statistics across all sessions")
+	fmt.Fprintln(w, "  tools export --format md|json <file>")
+	fmt.Fprintln(w, "                          Write a documentation file for all available tools")
+	fmt.Fprintln(w, "  config init [--force] [path]")
+	fmt.Fprintln(w, "                          Interactively generate a config file")
+	fmt.Fprintln(w, "  config show             Print the effective config with the API key redacted")
+	fmt.Fprintln(w, "  doctor [--json]         Check the local environment")
+	fmt.Fprintln(w, "  serve-mcp [--allow-tools]")
+	fmt.Fprintln(w, "                          Serve chat and session tools over MCP on stdio")
+	fmt.Fprintln(w, "  new --template <name>   Create a session pre-seeded from a template")
+	fmt.Fprintln(w, "  version                 Print version and build information")
+	fmt.Fprintln(w, "  help                    Show this help")
+}
+
+// hasJSONFlag reports whether --json is among args and returns the remaining args.
+func hasJSONFlag(args []string) (bool, []string) {
+	var rest []string
+	found := false
+	for _, arg := range args {
+		if arg == "--json" {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return found, rest
+}
+
+// sessionsDirFromConfig returns the configured sessions directory, honoring
+// the --workspace and --sessions-dir overrides, falling back to the default
+// when no config file is available.
+func sessionsDirFromConfig() string {
+	if cfg, err := config.LoadQuiet(config.DefaultConfigPath); err == nil {
+		if err := applyStartupOverrides(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return cfg.History.SessionsDir
+	}
+	if sessionsDirOverride != "" {
+		return sessionsDirOverride
+	}
+	return "" // history.NewManager falls back to the default directory
+}
+
+// runSessions handles the `gopus sessions` command group.
+func runSessions(args []string) int {
+	asJSON, rest := hasJSONFlag(args)
+
+	if len(rest) == 0 || (rest[0] != "list" && rest[0] != "tree" && rest[0] != "reindex" && rest[0] != "encrypt-all" && rest[0] != "prune" && rest[0] != "compact") {
+		fmt.Fprintln(os.Stderr, "Usage: gopus sessions {list|tree|reindex|encrypt-all|prune|compact} [--json] [--dry-run]")
+		return 2
+	}
+
+	// encrypt-all always needs the passphrase; the others only when the
+	// config says sessions are encrypted
+	encryptionConfigured := false
+	if cfg, err := config.LoadQuiet(config.DefaultConfigPath); err == nil {
+		encryptionConfigured = cfg.History.Encryption
+	}
+	if rest[0] == "encrypt-all" || encryptionConfigured {
+		if err := enableHistoryEncryption(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	manager, err := history.NewManager(sessionsDirFromConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if rest[0] == "encrypt-all" {
+		converted, err := manager.EncryptAll()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Encrypted %d session file(s).\n", converted)
+		return 0
+	}
+
+	if rest[0] == "prune" {
+		return runSessionsPrune(manager, rest[1:])
+	}
+
+	if rest[0] == "compact" {
+		return runSessionsCompact(manager, rest[1:])
+	}
+
+	if rest[0] == "reindex" {
+		if err := manager.Reindex(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Reindexed %d session(s).\n", len(manager.IndexEntries()))
+		return 0
+	}
+
+	sessions, err := manager.ListSessions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if rest[0] == "tree" {
+		if len(sessions) == 0 {
+			fmt.Println("No sessions found.")
+			return 0
+		}
+		fmt.Print(history.RenderSessionTree(sessions))
+		return 0
+	}
+
+	if asJSON {
+		list := make([]output.Session, 0, len(sessions))
+		for _, s := range sessions {
+			list = append(list, output.Session{
+				ID:           s.ID,
+				Name:         s.Name,
+				MessageCount: len(s.Messages),
+				CreatedAt:    s.CreatedAt,
+				UpdatedAt:    s.UpdatedAt,
+			})
+		}
+		if err := output.Encode(os.Stdout, list); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No sessions found.")
+		return 0
+	}
+
+	history.SessionTable(sessions).Print(table.DefaultPrintOptions())
+	return 0
+}
+
+// runStats handles the `gopus stats` command: the usage dashboard
+// aggregated over every session in the sessions directory.
+func runStats(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "Usage: gopus stats")
+		return 2
+	}
+
+	encryptionConfigured := false
+	if cfg, err := config.LoadQuiet(config.DefaultConfigPath); err == nil {
+		encryptionConfigured = cfg.History.Encryption
+	}
+	if encryptionConfigured {
+		if err := enableHistoryEncryption(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	manager, err := history.NewManager(sessionsDirFromConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	stats, err := manager.DirectoryStats()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if stats.Sessions == 0 {
+		fmt.Println("No sessions found.")
+		return 0
+	}
+	fmt.Print(history.RenderDirectoryStats(stats, time.Now()))
+	return 0
+}
+
+// retentionPolicy maps the history retention settings onto the history
+// package's policy type.
+func retentionPolicy(cfg *config.Config) history.RetentionPolicy {
+	return history.RetentionPolicy{
+		MaxSessions:    cfg.History.MaxSessions,
+		MaxAgeDays:     cfg.History.MaxAgeDays,
+		MaxTotalSizeMB: cfg.History.MaxTotalSizeMB,
+	}
+}
+
+// runSessionsPrune handles `gopus sessions prune [--dry-run]`: it applies
+// the retention limits configured under history, moving violating sessions
+// into the trash subdirectory. With --dry-run it only lists what a real
+// prune would move.
+func runSessionsPrune(manager *history.Manager, args []string) int {
+	dryRun := false
+	for _, arg := range args {
+		if arg != "--dry-run" {
+			fmt.Fprintln(os.Stderr, "Usage: gopus sessions prune [--dry-run]")
+			return 2
+		}
+		dryRun = true
+	}
+
+	policy := history.RetentionPolicy{}
+	if cfg, err := config.LoadQuiet(config.DefaultConfigPath); err == nil {
+		policy = retentionPolicy(cfg)
+	}
+	if !policy.Enabled() {
+		fmt.Println("No retention limits configured under history; nothing to prune.")
+		return 0
+	}
+
+	if dryRun {
+		candidates, err := manager.PruneCandidates(policy, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if len(candidates) == 0 {
+			fmt.Println("All sessions satisfy the retention limits.")
+			return 0
+		}
+		fmt.Printf("Would move %d session(s) to %s:\n", len(candidates), history.TrashDirName)
+		printPruneCandidates(candidates)
+		return 0
+	}
+
+	archived, err := manager.Prune(policy, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(archived) == 0 {
+		fmt.Println("All sessions satisfy the retention limits.")
+		return 0
+	}
+	fmt.Printf("Moved %d session(s) to %s:\n", len(archived), history.TrashDirName)
+	printPruneCandidates(archived)
+	return 0
+}
+
+// runSessionsCompact handles `gopus sessions compact`: it rewrites session
+// files without indentation and optionally gzip-compresses old ones.
+func runSessionsCompact(manager *history.Manager, args []string) int {
+	olderThan := time.Duration(0)
+	for i := 0; i < len(args); i++ {
+		if args[i] != "--gzip-older-than" || i+1 >= len(args) {
+			fmt.Fprintln(os.Stderr, "Usage: gopus sessions compact [--gzip-older-than <days>]")
+			return 2
+		}
+		days, err := strconv.Atoi(args[i+1])
+		if err != nil || days < 0 {
+			fmt.Fprintf(os.Stderr, "Invalid number of days: %s\n", args[i+1])
+			return 2
+		}
+		olderThan = time.Duration(days) * 24 * time.Hour
+		i++
+	}
+
+	result, err := manager.Compact(olderThan, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Compacted %d session(s)", result.Rewritten)
+	if result.Compressed > 0 {
+		fmt.Printf(", gzip-compressed %d", result.Compressed)
+	}
+	if result.Skipped > 0 {
+		fmt.Printf(", skipped %d unreadable file(s)", result.Skipped)
+	}
+	fmt.Printf("; saved %.1f KB (%d -> %d bytes).\n",
+		float64(result.Saved())/1024, result.BytesBefore, result.BytesAfter)
+	return 0
+}
+
+// printPruneCandidates lists pruned (or prunable) sessions one per line.
+func printPruneCandidates(candidates []history.PruneCandidate) {
+	for _, c := range candidates {
+		name := c.Name
+		if name == "" {
+			name = "(unnamed)"
+		}
+		fmt.Printf("  %s  %s — %s\n", c.UpdatedAt.Format("2006-01-02"), name, c.Reason)
+	}
+}
+
+// runTools handles the `gopus tools` command group. Currently supports
+// `tools export --format md|json <file>`, which gathers every available tool
+// and writes a documentation file. No chat session or API key is required;
+// external servers that cannot be reached are skipped with a warning.
+func runTools(args []string) int {
+	if len(args) == 0 || args[0] != "export" {
+		fmt.Fprintln(os.Stderr, "Usage: gopus tools export [--format md|json] <file>")
+		return 2
+	}
+	args = args[1:]
+
+	format := "md"
+	var file string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--format" && i+1 < len(args):
+			i++
+			format = args[i]
+		case strings.HasPrefix(args[i], "--"):
+			fmt.Fprintf(os.Stderr, "Unknown flag: %s\n", args[i])
+			return 2
+		default:
+			file = args[i]
+		}
+	}
+	if file == "" || (format != "md" && format != "json") {
+		fmt.Fprintln(os.Stderr, "Usage: gopus tools export [--format md|json] <file>")
+		return 2
+	}
+
+	// The config is optional here: without one the builtin tools are still
+	// documented, which keeps the export usable offline.
+	mcpCfg := config.MCPConfig{ToolConfirmation: config.ToolConfirmationAsk}
+	if cfg, err := config.LoadQuiet(config.DefaultConfigPath); err == nil {
+		mcpCfg = cfg.MCP
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: no usable config (%v); exporting builtin tools only\n", err)
+	}
+
+	ctx := context.Background()
+	manager := mcp.NewManager()
+	defer manager.Close()
+
+	if mcpCfg.Builtin.IsServerEnabled("builtin") && mcp.DefaultToolRegistry.Count() > 0 {
+		if err := manager.AddBuiltinServer(ctx, &mcp.BuiltinServer{}, mcp.Deps{}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to initialize builtin server: %v\n", err)
+		}
+	}
+	for _, serverCfg := range mcpCfg.Servers {
+		if !serverCfg.Enabled {
+			continue
+		}
+		if err := manager.AddServerWithConfig(ctx, serverCfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping MCP server %q: %v\n", serverCfg.Name, err)
+		}
+	}
+
+	infos := manager.ListToolInfos()
+
+	var data []byte
+	if format == "md" {
+		data = []byte(mcp.RenderToolCatalogMarkdown(infos, mcpCfg.ToolConfirmation))
+	} else {
+		list := make([]output.Tool, 0, len(infos))
+		for _, info := range infos {
+			list = append(list, output.Tool{
+				Name:        info.Tool.Name,
+				Description: info.Tool.Description,
+				Server:      info.ServerID,
+			})
+		}
+		var buf bytes.Buffer
+		if err := output.Encode(&buf, list); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		data = buf.Bytes()
+	}
+
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Exported %d tool(s) to %s\n", len(infos), file)
+	return 0
+}
+
+// runServeMCP handles `gopus serve-mcp [--allow-tools]`: it serves the chat
+// and session tools over MCP on stdio until the client disconnects. Stdout
+// belongs to the MCP transport, so all diagnostics go to stderr. Without
+// --allow-tools, chats driven through the server run with no tools, so an
+// MCP client cannot reach further tool capabilities through gopus.
+func runServeMCP(args []string) int {
+	allowTools := false
+	for _, arg := range args {
+		if arg != "--allow-tools" {
+			fmt.Fprintln(os.Stderr, "Usage: gopus serve-mcp [--allow-tools]")
+			return 2
+		}
+		allowTools = true
+	}
+
+	cfg, err := config.LoadQuiet(config.DefaultConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := applyStartupOverrides(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Stdin carries the MCP transport, so there is no way to prompt for a
+	// passphrase interactively.
+	if cfg.History.Encryption {
+		passphrase := os.Getenv("GOPUS_PASSPHRASE")
+		if passphrase == "" {
+			fmt.Fprintln(os.Stderr, "Error: serve-mcp cannot prompt for a passphrase; set GOPUS_PASSPHRASE")
+			return 1
+		}
+		if err := history.EnableEncryption(passphrase); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	srv, err := serve.New(cfg, allowTools)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintln(os.Stderr, "gopus MCP server listening on stdio")
+	if err := srv.Run(context.Background(), os.Stdin, os.Stdout); err != nil && !errors.Is(err, io.EOF) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runNew handles `gopus new --template <name>`: it creates a session
+// pre-seeded from the named template without entering the chat, so scripts
+// and shell aliases can prepare sessions ahead of time.
+func runNew(args []string) int {
+	template := ""
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--template" && i+1 < len(args) {
+			i++
+			template = args[i]
+			continue
+		}
+		fmt.Fprintln(os.Stderr, "Usage: gopus new --template <name>")
+		return 2
+	}
+	if template == "" {
+		fmt.Fprintln(os.Stderr, "Usage: gopus new --template <name>")
+		return 2
+	}
+
+	templatesDir := ""
+	encryptionConfigured := false
+	if cfg, err := config.LoadQuiet(config.DefaultConfigPath); err == nil {
+		templatesDir = cfg.History.TemplatesDir
+		encryptionConfigured = cfg.History.Encryption
+	}
+	if templatesDir == "" {
+		fmt.Fprintln(os.Stderr, "Error: no templates directory configured; set history.templates_dir in the config")
+		return 1
+	}
+	if encryptionConfigured {
+		if err := enableHistoryEncryption(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	tmpl, warnings, err := history.TemplateByName(templatesDir, template)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	manager, err := history.NewManager(sessionsDirFromConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	session, err := manager.NewSessionFromTemplate(tmpl)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := manager.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Created session %s from template %q; run gopus to continue it.\n", session.ID, tmpl.Name)
+	return 0
+}
+
+// runDoctor handles the `gopus doctor` command, checking the local environment.
+func runDoctor(args []string) int {
+	asJSON, _ := hasJSONFlag(args)
+
+	var report output.DoctorReport
+
+	cfg, err := config.LoadQuiet(config.DefaultConfigPath)
+	if err != nil {
+		report.ConfigError = err.Error()
+	} else {
+		report.ConfigOK = true
+		report.Model = cfg.OpenAI.Model
+	}
+
+	sessionsDir := ""
+	if cfg != nil {
+		sessionsDir = cfg.History.SessionsDir
+	}
+	if manager, err := history.NewManager(sessionsDir); err == nil {
+		report.SessionsDir = manager.SessionsDir()
+		report.SessionsDirWritable = true
+		if sessions, err := manager.ListSessions(); err == nil {
+			report.SessionCount = len(sessions)
+		}
+	}
+
+	if cb, err := clipboard.New(); err == nil {
+		report.ClipboardBackend = cb.Name()
+	}
+
+	if asJSON {
+		if err := output.Encode(os.Stdout, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	checkMark := func(ok bool) string {
+		if ok {
+			return "✓"
+		}
+		return "✗"
+	}
+
+	fmt.Printf("%s config: ", checkMark(report.ConfigOK))
+	if report.ConfigOK {
+		fmt.Printf("ok (model: %s)\n", report.Model)
+	} else {
+		fmt.Println(report.ConfigError)
+	}
+	fmt.Printf("%s sessions: %s (%d session(s))\n",
+		checkMark(report.SessionsDirWritable), report.SessionsDir, report.SessionCount)
+	if report.ClipboardBackend != "" {
+		fmt.Printf("✓ clipboard: %s\n", report.ClipboardBackend)
+	} else {
+		fmt.Println("✗ clipboard: no utility found")
+	}
+
+	if !report.ConfigOK {
+		return 1
+	}
+	return 0
+}
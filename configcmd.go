@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopus/internal/config"
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+
+	"gopkg.in/yaml.v3"
+)
+
+// commonModels is the menu of model choices offered by `config init`.
+// The first entry is the default.
+var commonModels = []string{
+	"gpt-4o-mini",
+	"gpt-4o",
+	"gpt-4.1-mini",
+	"gpt-3.5-turbo",
+}
+
+// runConfig handles the `gopus config` command group.
+func runConfig(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: gopus config {init [--force] [path]|show}")
+		return 2
+	}
+	switch args[0] {
+	case "init":
+		return runConfigInit(args[1:])
+	case "show":
+		return runConfigShow()
+	default:
+		fmt.Fprintln(os.Stderr, "Usage: gopus config {init [--force] [path]|show}")
+		return 2
+	}
+}
+
+// runConfigInit interactively generates a config file. It refuses to
+// overwrite an existing file unless --force is given, and writes the result
+// with mode 0600 since it contains the API key.
+func runConfigInit(args []string) int {
+	force := false
+	path := config.DefaultConfigPath
+	for _, arg := range args {
+		if arg == "--force" {
+			force = true
+			continue
+		}
+		path = arg
+	}
+
+	if _, err := os.Stat(path); err == nil && !force {
+		fmt.Fprintf(os.Stderr, "%s already exists; use --force to overwrite\n", path)
+		return 1
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	apiKey, err := readSecret("OpenAI API key: ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+	if apiKey == "" {
+		fmt.Fprintln(os.Stderr, "An API key is required.")
+		return 1
+	}
+	if !strings.HasPrefix(apiKey, "sk-") {
+		fmt.Println("Warning: the key doesn't start with \"sk-\"; double-check it if requests fail.")
+	}
+
+	fmt.Println("\nModel:")
+	for i, model := range commonModels {
+		fmt.Printf("  %d) %s\n", i+1, model)
+	}
+	fmt.Printf("Choose a model (1-%d, or type a name) [%s]: ", len(commonModels), commonModels[0])
+	model := commonModels[0]
+	if line, err := reader.ReadString('\n'); err == nil {
+		line = strings.TrimSpace(line)
+		if num, err := strconv.Atoi(line); err == nil && num >= 1 && num <= len(commonModels) {
+			model = commonModels[num-1]
+		} else if line != "" {
+			model = line
+		}
+	}
+
+	fmt.Print("Sessions directory (empty for default .gopus/sessions): ")
+	sessionsDir := ""
+	if line, err := reader.ReadString('\n'); err == nil {
+		sessionsDir = strings.TrimSpace(line)
+	}
+
+	fmt.Print("Verify the key with a test request before saving? [y/N]: ")
+	if line, err := reader.ReadString('\n'); err == nil {
+		if answer := strings.TrimSpace(strings.ToLower(line)); answer == "y" || answer == "yes" {
+			if err := verifyAPIKey(apiKey, model); err != nil {
+				fmt.Printf("Warning: test request failed: %v\n", err)
+				fmt.Println("Saving anyway; fix the key later if needed.")
+			} else {
+				fmt.Println("Key verified.")
+			}
+		}
+	}
+
+	content := renderConfigFile(apiKey, model, sessionsDir)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+		return 1
+	}
+
+	fmt.Printf("Wrote %s. Run gopus to start chatting.\n", path)
+	return 0
+}
+
+// readSecret reads a line without echoing it when stdin is a terminal.
+// Falls back to a plain read when echo cannot be disabled.
+func readSecret(prompt string) (string, error) {
+	fmt.Print(prompt)
+
+	echoOff := printer.IsTerminal(os.Stdin)
+	if echoOff {
+		cmd := exec.Command("stty", "-echo")
+		cmd.Stdin = os.Stdin
+		if err := cmd.Run(); err != nil {
+			echoOff = false
+		}
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+
+	if echoOff {
+		cmd := exec.Command("stty", "echo")
+		cmd.Stdin = os.Stdin
+		_ = cmd.Run()
+		fmt.Println()
+	}
+
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// verifyAPIKey fires a minimal chat completion to check the key works.
+func verifyAPIKey(apiKey, model string) error {
+	cfg := &config.Config{OpenAI: config.OpenAIConfig{APIKey: apiKey, Model: model}}
+	cfg.ApplyDefaults()
+
+	client, err := openai.NewChatClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err = client.ChatCompletionX(ctx, []openai.ChatCompletionRequestMessage{
+		{Role: openai.RoleUser, Content: openai.StringContent("ping")},
+	})
+	return err
+}
+
+// renderConfigFile produces a commented config.yaml with the chosen values.
+func renderConfigFile(apiKey, model, sessionsDir string) string {
+	var b strings.Builder
+
+	b.WriteString("# gopus configuration (generated by `gopus config init`)\n")
+	b.WriteString("# See config.example.yaml for all available settings.\n\n")
+	b.WriteString("openai:\n")
+	fmt.Fprintf(&b, "  api_key: %s\n", apiKey)
+	fmt.Fprintf(&b, "  model: %s\n", model)
+
+	b.WriteString("\nhistory:\n")
+	if sessionsDir != "" {
+		fmt.Fprintf(&b, "  sessions_dir: %s\n", sessionsDir)
+	} else {
+		b.WriteString("  # sessions_dir defaults to .gopus/sessions in the working directory\n")
+		b.WriteString("  # sessions_dir: /path/to/sessions\n")
+	}
+
+	return b.String()
+}
+
+// runConfigShow prints the effective configuration with the API key redacted.
+func runConfigShow() int {
+	cfg, err := config.LoadQuiet(config.DefaultConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	redacted := *cfg
+	if redacted.OpenAI.APIKey != "" {
+		redacted.OpenAI.APIKey = "***redacted***"
+	}
+
+	data, err := yaml.Marshal(&redacted)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	os.Stdout.Write(data)
+	return 0
+}
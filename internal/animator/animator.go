@@ -5,6 +5,10 @@ package animator
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
 	"time"
 )
 
@@ -30,12 +34,17 @@ type Animation interface {
 
 // Animator manages the animation loop and timing.
 // It delegates all visual rendering and terminal output to an Animation implementation,
-// handling only the goroutine lifecycle and frame timing.
+// handling only the goroutine lifecycle and frame timing. A mutex serializes
+// frame rendering with Println, so other goroutines can print whole lines
+// while the animation runs without splicing text into a frame.
 type Animator struct {
-	interval  time.Duration      // time between frames
-	cancel    context.CancelFunc // cancels the animation goroutine
-	done      chan struct{}      // signals animation goroutine has exited
-	animation Animation          // the visual implementation
+	interval  time.Duration // time between frames
+	animation Animation     // the visual implementation
+	out       io.Writer     // destination for Println output
+
+	mu     sync.Mutex         // serializes animation calls and Println writes
+	cancel context.CancelFunc // cancels the animation goroutine
+	done   chan struct{}      // signals animation goroutine has exited
 }
 
 // NewAnimator creates a new Animator with the given Animation implementation.
@@ -44,12 +53,25 @@ func NewAnimator(animation Animation) *Animator {
 	return &Animator{
 		interval:  80 * time.Millisecond,
 		animation: animation,
+		out:       os.Stdout,
 	}
 }
 
+// SetOutput redirects Println output, e.g. to a buffer in tests. The
+// Animation implementation controls where frames go; for the serialization
+// guarantee to hold both must write to the same destination.
+func (a *Animator) SetOutput(out io.Writer) {
+	a.mu.Lock()
+	a.out = out
+	a.mu.Unlock()
+}
+
 // Start begins the animation in a background goroutine.
 // If the animation is already running, this is a no-op.
 func (a *Animator) Start() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
 	if a.cancel != nil {
 		return // already running
 	}
@@ -63,22 +85,31 @@ func (a *Animator) Start() {
 
 // run is the animation loop goroutine. It calls Animation.Start() once,
 // then calls Animation.Render() on each tick until the context is cancelled,
-// at which point it calls Animation.Stop() and exits.
+// at which point it calls Animation.Stop() and exits. Each animation call
+// holds the mutex so Println cannot write mid-frame.
 func (a *Animator) run(ctx context.Context) {
 	defer close(a.done)
 
 	ticker := time.NewTicker(a.interval)
 	defer ticker.Stop()
 
+	a.mu.Lock()
 	a.animation.Start()
+	a.mu.Unlock()
 
 	for {
 		select {
 		case <-ctx.Done():
+			a.mu.Lock()
 			a.animation.Stop()
+			a.mu.Unlock()
 			return
 		case <-ticker.C:
-			a.animation.Render()
+			a.mu.Lock()
+			if ctx.Err() == nil {
+				a.animation.Render()
+			}
+			a.mu.Unlock()
 		}
 	}
 }
@@ -86,11 +117,32 @@ func (a *Animator) run(ctx context.Context) {
 // Stop stops the animation and waits for the goroutine to exit.
 // If the animation is not running, this is a no-op.
 func (a *Animator) Stop() {
+	a.mu.Lock()
 	if a.cancel == nil {
+		a.mu.Unlock()
 		return // not running
 	}
-
-	a.cancel()
-	<-a.done
+	cancel, done := a.cancel, a.done
 	a.cancel = nil
+	a.mu.Unlock()
+
+	cancel()
+	<-done
+}
+
+// Println prints a whole line while the animation runs: the current frame's
+// line is cleared, the message printed, and the next frame redrawn, all
+// under the render lock so a concurrent tick cannot splice a frame into the
+// message. When the animation is not running the message prints as-is.
+func (a *Animator) Println(msg string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cancel == nil {
+		fmt.Fprintln(a.out, msg)
+		return
+	}
+
+	fmt.Fprint(a.out, "\r\033[K"+msg+"\n")
+	a.animation.Render()
 }
@@ -0,0 +1,126 @@
+package animator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTerminal records everything written to it. Writes are safe from
+// multiple goroutines, mirroring a real terminal fd.
+type fakeTerminal struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (t *fakeTerminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.b.Write(p)
+}
+
+func (t *fakeTerminal) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.b.String()
+}
+
+// splitAnimation renders frames as a marker pair in two separate writes, so
+// an unsynchronized print can land between them — exactly the splicing the
+// Animator's render lock must prevent.
+type splitAnimation struct {
+	out io.Writer
+}
+
+func (a *splitAnimation) Start()          { a.Render() }
+func (a *splitAnimation) Stop()           { fmt.Fprint(a.out, "<clear>") }
+func (a *splitAnimation) FrameCount() int { return 1 }
+
+func (a *splitAnimation) Render() {
+	fmt.Fprint(a.out, "<frame")
+	fmt.Fprint(a.out, ">")
+}
+
+func TestPrintlnDoesNotInterleaveWithFrames(t *testing.T) {
+	term := &fakeTerminal{}
+	anim := NewAnimator(&splitAnimation{out: term})
+	anim.SetOutput(term)
+	anim.interval = time.Millisecond
+
+	anim.Start()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := range 20 {
+				anim.Println(fmt.Sprintf("[tool %d-%d completed]", i, j))
+			}
+		}(i)
+	}
+	wg.Wait()
+	anim.Stop()
+
+	output := term.String()
+	if !strings.Contains(output, "<frame>") {
+		t.Fatal("expected the animation to render frames during the prints")
+	}
+
+	// Every frame must be intact, and every printed line must start at the
+	// cleared-line escape and end before the next frame begins.
+	stripped := strings.ReplaceAll(output, "<frame>", "")
+	stripped = strings.ReplaceAll(stripped, "<clear>", "")
+	for _, line := range strings.Split(stripped, "\n") {
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "\r\033[K[tool ") || !strings.HasSuffix(line, "completed]") {
+			t.Errorf("corrupted output line %q", line)
+		}
+	}
+}
+
+func TestPrintlnWithoutAnimationPrintsPlainly(t *testing.T) {
+	term := &fakeTerminal{}
+	anim := NewAnimator(&splitAnimation{out: term})
+	anim.SetOutput(term)
+
+	anim.Println("hello")
+
+	if got := term.String(); got != "hello\n" {
+		t.Errorf("expected a plain line when not animating, got %q", got)
+	}
+}
+
+func TestStartIsIdempotent(t *testing.T) {
+	term := &fakeTerminal{}
+	anim := NewAnimator(&splitAnimation{out: term})
+	anim.SetOutput(term)
+
+	anim.Start()
+	anim.Start() // second start must not spawn another loop
+	anim.Stop()
+
+	// Exactly one Start/Stop cycle: one cleanup marker at the end
+	if got := strings.Count(term.String(), "<clear>"); got != 1 {
+		t.Errorf("expected one animation lifecycle, saw %d cleanups", got)
+	}
+}
+
+func TestStopIsIdempotent(t *testing.T) {
+	term := &fakeTerminal{}
+	anim := NewAnimator(&splitAnimation{out: term})
+	anim.SetOutput(term)
+
+	anim.Start()
+	anim.Stop()
+	anim.Stop() // second stop must be a no-op
+
+	if !strings.HasSuffix(term.String(), "<clear>") {
+		t.Errorf("expected the animation to clean up on stop, got %q", term.String())
+	}
+}
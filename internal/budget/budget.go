@@ -0,0 +1,125 @@
+// Package budget persists daily API spending in a small ledger file
+// shared by every session in a sessions directory, so spending limits can
+// be enforced across sessions and restarts.
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ledgerFile is the ledger's name inside the sessions directory.
+const ledgerFile = "spend-ledger.json"
+
+// dateFormat keys ledger entries by calendar day in local time.
+const dateFormat = "2006-01-02"
+
+// retainDays is how long old entries are kept before a write trims them.
+const retainDays = 90
+
+// Ledger tracks USD spending per day in a JSON file written atomically.
+// The zero value is not usable; create one with NewLedger.
+type Ledger struct {
+	mu   sync.Mutex
+	path string
+
+	// now is the clock, swappable in tests.
+	now func() time.Time
+}
+
+// NewLedger returns a ledger stored in the given sessions directory.
+func NewLedger(sessionsDir string) *Ledger {
+	return &Ledger{
+		path: filepath.Join(sessionsDir, ledgerFile),
+		now:  time.Now,
+	}
+}
+
+// Add records spending against today's entry.
+func (l *Ledger) Add(usd float64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, err := l.load()
+	if err != nil {
+		return err
+	}
+	entries[l.today()] += usd
+	l.trim(entries)
+	return l.save(entries)
+}
+
+// SpentToday returns today's recorded spending. A missing ledger file
+// means nothing was spent yet.
+func (l *Ledger) SpentToday() (float64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, err := l.load()
+	if err != nil {
+		return 0, err
+	}
+	return entries[l.today()], nil
+}
+
+func (l *Ledger) today() string {
+	return l.now().Format(dateFormat)
+}
+
+func (l *Ledger) load() (map[string]float64, error) {
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return map[string]float64{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spend ledger: %w", err)
+	}
+
+	entries := map[string]float64{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse spend ledger %s: %w", l.path, err)
+	}
+	return entries, nil
+}
+
+// trim drops entries old enough that no daily limit can reference them.
+func (l *Ledger) trim(entries map[string]float64) {
+	cutoff := l.now().AddDate(0, 0, -retainDays).Format(dateFormat)
+	for date := range entries {
+		if date < cutoff {
+			delete(entries, date)
+		}
+	}
+}
+
+// save writes the ledger atomically: other gopus processes sharing the
+// sessions directory never observe a partial file.
+func (l *Ledger) save(entries map[string]float64) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal spend ledger: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), ".spend-ledger-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary ledger file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write spend ledger: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to close spend ledger: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), l.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to replace spend ledger: %w", err)
+	}
+	return nil
+}
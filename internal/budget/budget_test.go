@@ -0,0 +1,87 @@
+package budget
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLedgerAccumulatesAndPersists(t *testing.T) {
+	dir := t.TempDir()
+
+	ledger := NewLedger(dir)
+	if spent, err := ledger.SpentToday(); err != nil || spent != 0 {
+		t.Fatalf("expected a fresh ledger to report 0, got %v, %v", spent, err)
+	}
+
+	if err := ledger.Add(0.25); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := ledger.Add(0.50); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if spent, _ := ledger.SpentToday(); spent != 0.75 {
+		t.Errorf("expected 0.75 spent today, got %v", spent)
+	}
+
+	// A second ledger on the same directory sees the same totals, like
+	// another gopus process would
+	other := NewLedger(dir)
+	if spent, _ := other.SpentToday(); spent != 0.75 {
+		t.Errorf("expected a shared total of 0.75, got %v", spent)
+	}
+}
+
+func TestLedgerSeparatesDays(t *testing.T) {
+	ledger := NewLedger(t.TempDir())
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	ledger.now = func() time.Time { return yesterday }
+	if err := ledger.Add(1.00); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	ledger.now = time.Now
+	if spent, _ := ledger.SpentToday(); spent != 0 {
+		t.Errorf("yesterday's spending must not count today, got %v", spent)
+	}
+}
+
+func TestLedgerTrimsOldEntries(t *testing.T) {
+	ledger := NewLedger(t.TempDir())
+	ancient := time.Now().AddDate(0, 0, -(retainDays + 10))
+
+	ledger.now = func() time.Time { return ancient }
+	if err := ledger.Add(1.00); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	ledger.now = time.Now
+	if err := ledger.Add(0.10); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	entries, err := ledger.load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected the ancient entry to be trimmed, got %v", entries)
+	}
+}
+
+func TestLedgerRejectsCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ledgerFile), []byte("not json"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	ledger := NewLedger(dir)
+	if _, err := ledger.SpentToday(); err == nil {
+		t.Error("expected an error for a corrupt ledger file")
+	}
+	if err := ledger.Add(1.00); err == nil {
+		t.Error("expected Add to refuse overwriting a corrupt ledger")
+	}
+}
@@ -0,0 +1,297 @@
+package canvas
+
+import (
+	"math"
+	"unicode"
+)
+
+// This file implements shape and text primitives on top of the per-pixel
+// operations. Each primitive enumerates its pixels through a plot callback,
+// so the same geometry can set or clear pixels; all plotting goes through
+// Set/Clear and therefore clips to the canvas bounds without panicking.
+
+// DrawLine draws a straight line from (x0, y0) to (x1, y1) using
+// Bresenham's algorithm.
+func (c *Canvas) DrawLine(x0, y0, x1, y1 int) {
+	eachLinePoint(x0, y0, x1, y1, c.Set)
+}
+
+// ClearLine turns off the pixels DrawLine with the same arguments turns on.
+func (c *Canvas) ClearLine(x0, y0, x1, y1 int) {
+	eachLinePoint(x0, y0, x1, y1, c.Clear)
+}
+
+// DrawRect draws the outline of the axis-aligned rectangle with opposite
+// corners (x0, y0) and (x1, y1). The corners may be given in any order.
+func (c *Canvas) DrawRect(x0, y0, x1, y1 int) {
+	eachRectPoint(x0, y0, x1, y1, c.Set)
+}
+
+// ClearRect turns off the pixels DrawRect with the same arguments turns on.
+func (c *Canvas) ClearRect(x0, y0, x1, y1 int) {
+	eachRectPoint(x0, y0, x1, y1, c.Clear)
+}
+
+// FillRect fills the axis-aligned rectangle with opposite corners (x0, y0)
+// and (x1, y1), borders included. The corners may be given in any order.
+func (c *Canvas) FillRect(x0, y0, x1, y1 int) {
+	eachFillRectPoint(x0, y0, x1, y1, c.Set)
+}
+
+// ClearFillRect turns off the pixels FillRect with the same arguments
+// turns on.
+func (c *Canvas) ClearFillRect(x0, y0, x1, y1 int) {
+	eachFillRectPoint(x0, y0, x1, y1, c.Clear)
+}
+
+// DrawCircle draws a circle of radius r centered at (cx, cy) using the
+// midpoint circle algorithm. A zero radius draws a single pixel; a
+// negative radius draws nothing.
+func (c *Canvas) DrawCircle(cx, cy, r int) {
+	eachCirclePoint(cx, cy, r, c.Set)
+}
+
+// ClearCircle turns off the pixels DrawCircle with the same arguments
+// turns on.
+func (c *Canvas) ClearCircle(cx, cy, r int) {
+	eachCirclePoint(cx, cy, r, c.Clear)
+}
+
+// FillCircle fills a circle of radius r centered at (cx, cy), border
+// included.
+func (c *Canvas) FillCircle(cx, cy, r int) {
+	eachFillCirclePoint(cx, cy, r, c.Set)
+}
+
+// ClearFillCircle turns off the pixels FillCircle with the same arguments
+// turns on.
+func (c *Canvas) ClearFillCircle(cx, cy, r int) {
+	eachFillCirclePoint(cx, cy, r, c.Clear)
+}
+
+// DrawText renders text in a 3x5 pixel font with (x, y) as the top-left
+// corner of the first glyph. Letters render in uppercase; runes without a
+// glyph leave a blank cell. Each glyph advances the cursor by four pixels
+// (three columns plus one of spacing).
+func (c *Canvas) DrawText(x, y int, text string) {
+	eachTextPoint(x, y, text, c.Set)
+}
+
+// ClearText turns off the pixels DrawText with the same arguments turns on.
+func (c *Canvas) ClearText(x, y int, text string) {
+	eachTextPoint(x, y, text, c.Clear)
+}
+
+// eachLinePoint enumerates the Bresenham line from (x0, y0) to (x1, y1).
+func eachLinePoint(x0, y0, x1, y1 int, plot func(x, y int)) {
+	dx, dy := absInt(x1-x0), -absInt(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+
+	for {
+		plot(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// eachRectPoint enumerates the outline of the rectangle spanned by the two
+// corners, visiting each border pixel exactly once.
+func eachRectPoint(x0, y0, x1, y1 int, plot func(x, y int)) {
+	x0, x1 = minMax(x0, x1)
+	y0, y1 = minMax(y0, y1)
+
+	for x := x0; x <= x1; x++ {
+		plot(x, y0)
+		if y1 != y0 {
+			plot(x, y1)
+		}
+	}
+	for y := y0 + 1; y < y1; y++ {
+		plot(x0, y)
+		if x1 != x0 {
+			plot(x1, y)
+		}
+	}
+}
+
+// eachFillRectPoint enumerates every pixel of the rectangle spanned by the
+// two corners, borders included.
+func eachFillRectPoint(x0, y0, x1, y1 int, plot func(x, y int)) {
+	x0, x1 = minMax(x0, x1)
+	y0, y1 = minMax(y0, y1)
+
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			plot(x, y)
+		}
+	}
+}
+
+// eachCirclePoint enumerates the midpoint circle of radius r centered at
+// (cx, cy), plotting all eight octants per step. Pixels on the axes and
+// diagonals are visited more than once, which Set and Clear both tolerate.
+func eachCirclePoint(cx, cy, r int, plot func(x, y int)) {
+	if r < 0 {
+		return
+	}
+	if r == 0 {
+		plot(cx, cy)
+		return
+	}
+
+	x, y := 0, r
+	d := 1 - r
+	for x <= y {
+		plot(cx+x, cy+y)
+		plot(cx-x, cy+y)
+		plot(cx+x, cy-y)
+		plot(cx-x, cy-y)
+		plot(cx+y, cy+x)
+		plot(cx-y, cy+x)
+		plot(cx+y, cy-x)
+		plot(cx-y, cy-x)
+
+		if d < 0 {
+			d += 2*x + 3
+		} else {
+			d += 2*(x-y) + 5
+			y--
+		}
+		x++
+	}
+}
+
+// eachFillCirclePoint enumerates every pixel within radius r of (cx, cy)
+// as horizontal spans, one per row.
+func eachFillCirclePoint(cx, cy, r int, plot func(x, y int)) {
+	if r < 0 {
+		return
+	}
+	for dy := -r; dy <= r; dy++ {
+		dx := isqrt(r*r - dy*dy)
+		for x := cx - dx; x <= cx+dx; x++ {
+			plot(x, cy+dy)
+		}
+	}
+}
+
+// eachTextPoint enumerates the lit pixels of text rendered in the 3x5 font
+// with (x, y) as the top-left corner of the first glyph.
+func eachTextPoint(x, y int, text string, plot func(x, y int)) {
+	for _, r := range text {
+		glyph, ok := font3x5[unicode.ToUpper(r)]
+		if ok {
+			for row, line := range glyph {
+				for col, pixel := range line {
+					if pixel == '#' {
+						plot(x+col, y+row)
+					}
+				}
+			}
+		}
+		x += glyphAdvance
+	}
+}
+
+// glyphAdvance is the horizontal distance between glyph origins: three
+// pixel columns plus one of spacing.
+const glyphAdvance = 4
+
+// font3x5 is a tiny 3x5 pixel font covering digits, uppercase letters, and
+// common punctuation. '#' marks a lit pixel; rows run top to bottom.
+var font3x5 = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", ".##", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'A': {".#.", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {".##", "#..", "#..", "#..", ".##"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {".##", "#..", "#.#", "#.#", ".##"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", ".#."},
+	'K': {"#.#", "##.", "#..", "##.", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"#.#", "###", "###", "###", "#.#"},
+	'O': {".#.", "#.#", "#.#", "#.#", ".#."},
+	'P': {"##.", "#.#", "##.", "#..", "#.."},
+	'Q': {".#.", "#.#", "#.#", "##.", ".##"},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {".##", "#..", ".#.", "..#", "##."},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	' ': {"...", "...", "...", "...", "..."},
+	'.': {"...", "...", "...", "...", ".#."},
+	',': {"...", "...", "...", ".#.", "#.."},
+	':': {"...", ".#.", "...", ".#.", "..."},
+	'-': {"...", "...", "###", "...", "..."},
+	'+': {"...", ".#.", "###", ".#.", "..."},
+	'/': {"..#", "..#", ".#.", "#..", "#.."},
+	'%': {"#.#", "..#", ".#.", "#..", "#.#"},
+	'!': {".#.", ".#.", ".#.", "...", ".#."},
+	'?': {"###", "..#", ".#.", "...", ".#."},
+}
+
+// absInt returns the absolute value of n.
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// minMax returns a and b in ascending order.
+func minMax(a, b int) (int, int) {
+	if a > b {
+		return b, a
+	}
+	return a, b
+}
+
+// isqrt returns the integer square root of n (the largest s with s*s <= n).
+func isqrt(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	s := int(math.Sqrt(float64(n)))
+	for s*s > n {
+		s--
+	}
+	for (s+1)*(s+1) <= n {
+		s++
+	}
+	return s
+}
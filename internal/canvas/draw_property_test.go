@@ -0,0 +1,172 @@
+package canvas
+
+import (
+	"fmt"
+	"testing"
+	"testing/quick"
+)
+
+// isEmpty reports whether no pixel on the canvas is lit.
+func isEmpty(c *Canvas) bool {
+	for y := 0; y < c.Height(); y++ {
+		for x := 0; x < c.Width(); x++ {
+			if c.Get(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// TestCircleQuadrantSymmetry verifies that a drawn circle mirrors across
+// both axes through its center: the four quadrants are reflections of each
+// other.
+func TestCircleQuadrantSymmetry(t *testing.T) {
+	property := func(radius uint8) bool {
+		r := int(radius%14) + 1
+		cx, cy := 16, 16
+		c := New(34, 34)
+		c.DrawCircle(cx, cy, r)
+
+		for dy := 0; dy <= r; dy++ {
+			for dx := 0; dx <= r; dx++ {
+				p := c.Get(cx+dx, cy+dy)
+				if c.Get(cx-dx, cy+dy) != p || c.Get(cx+dx, cy-dy) != p || c.Get(cx-dx, cy-dy) != p {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestFillCircleQuadrantSymmetry verifies the same mirror property for
+// filled circles.
+func TestFillCircleQuadrantSymmetry(t *testing.T) {
+	property := func(radius uint8) bool {
+		r := int(radius%14) + 1
+		cx, cy := 16, 16
+		c := New(34, 34)
+		c.FillCircle(cx, cy, r)
+
+		for dy := 0; dy <= r; dy++ {
+			for dx := 0; dx <= r; dx++ {
+				p := c.Get(cx+dx, cy+dy)
+				if c.Get(cx-dx, cy+dy) != p || c.Get(cx+dx, cy-dy) != p || c.Get(cx-dx, cy-dy) != p {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestDrawClearLineRoundTrip verifies that clearing a line restores an
+// empty canvas after drawing it, for arbitrary (including out-of-bounds)
+// endpoints.
+func TestDrawClearLineRoundTrip(t *testing.T) {
+	property := func(x0, y0, x1, y1 int8) bool {
+		c := New(16, 16)
+		c.DrawLine(int(x0), int(y0), int(x1), int(y1))
+		c.ClearLine(int(x0), int(y0), int(x1), int(y1))
+		return isEmpty(c)
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestDrawClearRectRoundTrip verifies the draw-then-clear property for
+// rectangle outlines and fills.
+func TestDrawClearRectRoundTrip(t *testing.T) {
+	property := func(x0, y0, x1, y1 int8) bool {
+		c := New(16, 16)
+		c.DrawRect(int(x0), int(y0), int(x1), int(y1))
+		c.ClearRect(int(x0), int(y0), int(x1), int(y1))
+		if !isEmpty(c) {
+			return false
+		}
+
+		c.FillRect(int(x0), int(y0), int(x1), int(y1))
+		c.ClearFillRect(int(x0), int(y0), int(x1), int(y1))
+		return isEmpty(c)
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestDrawClearCircleRoundTrip verifies the draw-then-clear property for
+// circle outlines and fills.
+func TestDrawClearCircleRoundTrip(t *testing.T) {
+	property := func(cx, cy int8, radius uint8) bool {
+		r := int(radius % 20)
+		c := New(16, 16)
+		c.DrawCircle(int(cx), int(cy), r)
+		c.ClearCircle(int(cx), int(cy), r)
+		if !isEmpty(c) {
+			return false
+		}
+
+		c.FillCircle(int(cx), int(cy), r)
+		c.ClearFillCircle(int(cx), int(cy), r)
+		return isEmpty(c)
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestDrawClearTextRoundTrip verifies the draw-then-clear property for
+// rendered text.
+func TestDrawClearTextRoundTrip(t *testing.T) {
+	property := func(x, y int8, n uint16) bool {
+		text := fmt.Sprintf("%d", n)
+		c := New(32, 8)
+		c.DrawText(int(x), int(y), text)
+		c.ClearText(int(x), int(y), text)
+		return isEmpty(c)
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestFillContainsOutline verifies that a filled rectangle covers every
+// pixel of its outline.
+func TestFillContainsOutline(t *testing.T) {
+	property := func(x0, y0, x1, y1 uint8) bool {
+		a, b := int(x0%16), int(y0%16)
+		d, e := int(x1%16), int(y1%16)
+
+		outline := New(16, 16)
+		outline.DrawRect(a, b, d, e)
+		filled := New(16, 16)
+		filled.FillRect(a, b, d, e)
+
+		for y := 0; y < 16; y++ {
+			for x := 0; x < 16; x++ {
+				if outline.Get(x, y) && !filled.Get(x, y) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,230 @@
+package canvas
+
+import (
+	"testing"
+)
+
+// countPixels returns the number of lit pixels on the canvas.
+func countPixels(c *Canvas) int {
+	count := 0
+	for y := 0; y < c.Height(); y++ {
+		for x := 0; x < c.Width(); x++ {
+			if c.Get(x, y) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestDrawLine(t *testing.T) {
+	tests := []struct {
+		name           string
+		x0, y0, x1, y1 int
+		want           [][2]int
+	}{
+		{"horizontal", 1, 2, 4, 2, [][2]int{{1, 2}, {2, 2}, {3, 2}, {4, 2}}},
+		{"vertical", 3, 0, 3, 3, [][2]int{{3, 0}, {3, 1}, {3, 2}, {3, 3}}},
+		{"diagonal", 0, 0, 3, 3, [][2]int{{0, 0}, {1, 1}, {2, 2}, {3, 3}}},
+		{"reversed", 4, 2, 1, 2, [][2]int{{1, 2}, {2, 2}, {3, 2}, {4, 2}}},
+		{"single point", 2, 2, 2, 2, [][2]int{{2, 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(6, 4)
+			c.DrawLine(tt.x0, tt.y0, tt.x1, tt.y1)
+
+			for _, p := range tt.want {
+				if !c.Get(p[0], p[1]) {
+					t.Errorf("expected pixel (%d, %d) to be set", p[0], p[1])
+				}
+			}
+			if got := countPixels(c); got != len(tt.want) {
+				t.Errorf("expected %d pixels, got %d", len(tt.want), got)
+			}
+		})
+	}
+}
+
+func TestDrawLineClipsWithoutPanic(t *testing.T) {
+	c := New(4, 4)
+	c.DrawLine(-10, -10, 20, 20)
+
+	// The in-bounds part of the diagonal must still be drawn
+	if !c.Get(0, 0) || !c.Get(3, 3) {
+		t.Error("expected the in-bounds segment to be drawn")
+	}
+}
+
+func TestDrawRect(t *testing.T) {
+	c := New(8, 8)
+	c.DrawRect(1, 1, 5, 6)
+
+	// Corners and edges are set
+	for _, p := range [][2]int{{1, 1}, {5, 1}, {1, 6}, {5, 6}, {3, 1}, {3, 6}, {1, 3}, {5, 3}} {
+		if !c.Get(p[0], p[1]) {
+			t.Errorf("expected border pixel (%d, %d) to be set", p[0], p[1])
+		}
+	}
+	// The interior stays clear
+	if c.Get(3, 3) {
+		t.Error("expected the interior to stay clear")
+	}
+	// Perimeter of a 5x6 rectangle: 2*5 + 2*6 - 4 corners
+	if got := countPixels(c); got != 18 {
+		t.Errorf("expected 18 border pixels, got %d", got)
+	}
+}
+
+func TestDrawRectSwappedCorners(t *testing.T) {
+	c1, c2 := New(8, 8), New(8, 8)
+	c1.DrawRect(1, 1, 5, 6)
+	c2.DrawRect(5, 6, 1, 1)
+
+	if c1.String() != c2.String() {
+		t.Error("expected corner order not to matter")
+	}
+}
+
+func TestDrawRectDegenerate(t *testing.T) {
+	c := New(8, 8)
+	c.DrawRect(2, 3, 2, 3) // a single point
+	if !c.Get(2, 3) || countPixels(c) != 1 {
+		t.Errorf("expected a single pixel, got %d", countPixels(c))
+	}
+
+	c.Reset()
+	c.DrawRect(1, 2, 4, 2) // a horizontal line
+	if countPixels(c) != 4 {
+		t.Errorf("expected 4 pixels for a flat rectangle, got %d", countPixels(c))
+	}
+}
+
+func TestFillRect(t *testing.T) {
+	c := New(8, 8)
+	c.FillRect(2, 1, 5, 4)
+
+	if got := countPixels(c); got != 16 {
+		t.Errorf("expected 4x4 = 16 pixels, got %d", got)
+	}
+	if !c.Get(3, 2) {
+		t.Error("expected the interior to be filled")
+	}
+}
+
+func TestDrawCircle(t *testing.T) {
+	c := New(16, 16)
+	c.DrawCircle(8, 8, 5)
+
+	// The four cardinal points lie exactly on the circle
+	for _, p := range [][2]int{{13, 8}, {3, 8}, {8, 13}, {8, 3}} {
+		if !c.Get(p[0], p[1]) {
+			t.Errorf("expected cardinal point (%d, %d) to be set", p[0], p[1])
+		}
+	}
+	// The center stays clear
+	if c.Get(8, 8) {
+		t.Error("expected the center to stay clear")
+	}
+}
+
+func TestDrawCircleZeroAndNegativeRadius(t *testing.T) {
+	c := New(8, 8)
+	c.DrawCircle(4, 4, 0)
+	if !c.Get(4, 4) || countPixels(c) != 1 {
+		t.Errorf("expected a single center pixel for radius 0, got %d", countPixels(c))
+	}
+
+	c.Reset()
+	c.DrawCircle(4, 4, -1)
+	if countPixels(c) != 0 {
+		t.Error("expected nothing drawn for a negative radius")
+	}
+}
+
+func TestFillCircle(t *testing.T) {
+	c := New(16, 16)
+	c.FillCircle(8, 8, 5)
+
+	if !c.Get(8, 8) {
+		t.Error("expected the center to be filled")
+	}
+	for _, p := range [][2]int{{13, 8}, {3, 8}, {8, 13}, {8, 3}} {
+		if !c.Get(p[0], p[1]) {
+			t.Errorf("expected boundary point (%d, %d) to be set", p[0], p[1])
+		}
+	}
+	// A corner outside the radius stays clear
+	if c.Get(13, 13) {
+		t.Error("expected pixels beyond the radius to stay clear")
+	}
+}
+
+func TestDrawCircleClipsWithoutPanic(t *testing.T) {
+	c := New(4, 4)
+	c.DrawCircle(0, 0, 10)
+	c.FillCircle(-5, -5, 3)
+	c.DrawCircle(100, 100, 50)
+}
+
+func TestDrawText(t *testing.T) {
+	c := New(16, 8)
+	c.DrawText(0, 0, "1")
+
+	// The '1' glyph has its full bottom row lit
+	for x := 0; x < 3; x++ {
+		if !c.Get(x, 4) {
+			t.Errorf("expected bottom-row pixel (%d, 4) of '1' to be set", x)
+		}
+	}
+	if c.Get(0, 0) {
+		t.Error("expected pixel (0, 0) of '1' to be clear")
+	}
+}
+
+func TestDrawTextAdvancesPerGlyph(t *testing.T) {
+	c := New(16, 8)
+	c.DrawText(0, 0, "11")
+
+	// The second glyph starts one advance (4 pixels) to the right
+	if !c.Get(4, 4) || !c.Get(6, 4) {
+		t.Error("expected the second glyph in the next cell")
+	}
+}
+
+func TestDrawTextLowercaseAndUnknownRunes(t *testing.T) {
+	c1, c2 := New(16, 8), New(16, 8)
+	c1.DrawText(0, 0, "ok")
+	c2.DrawText(0, 0, "OK")
+	if c1.String() != c2.String() {
+		t.Error("expected lowercase to render as uppercase")
+	}
+
+	c := New(16, 8)
+	c.DrawText(0, 0, "~") // no glyph: a blank cell, no panic
+	if countPixels(c) != 0 {
+		t.Error("expected an unknown rune to render blank")
+	}
+}
+
+func TestDrawTextClipsWithoutPanic(t *testing.T) {
+	c := New(8, 8)
+	c.DrawText(-2, -3, "42")
+	c.DrawText(100, 0, "42")
+}
+
+func TestFontGlyphShape(t *testing.T) {
+	for r, glyph := range font3x5 {
+		for row, line := range glyph {
+			if len(line) != 3 {
+				t.Errorf("glyph %q row %d has %d columns, want 3", r, row, len(line))
+			}
+			for _, pixel := range line {
+				if pixel != '#' && pixel != '.' {
+					t.Errorf("glyph %q contains invalid pixel %q", r, pixel)
+				}
+			}
+		}
+	}
+}
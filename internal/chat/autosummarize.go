@@ -0,0 +1,125 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gopus/internal/history"
+	"gopus/internal/hooks"
+	"gopus/internal/openai"
+)
+
+// pendingSummary is a finished background auto-summarization waiting to be
+// merged back into the session on the chat goroutine.
+type pendingSummary struct {
+	sessionID   string
+	snapshotLen int             // how many messages the snapshot covered
+	lastMessage history.Message // last snapshot message, to detect removals
+	messages    []history.Message
+}
+
+// checkAutoSummarize triggers auto-summarization when the threshold is
+// exceeded. The work runs on a background goroutine against a snapshot of
+// the messages, so the user gets the prompt back immediately; the result is
+// merged at the start of a later turn by applyPendingSummary. A second
+// trigger while one run is in flight is skipped.
+func (c *ChatLoop) checkAutoSummarize(ctx context.Context, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	c.applyPendingSummary(chatHistory)
+
+	session := c.historyManager.Current()
+	if session == nil || !c.summarizer.ShouldAutoSummarize(session.Messages) {
+		return
+	}
+
+	c.autoSumMu.Lock()
+	if c.autoSumRunning || c.autoSumResult != nil {
+		c.autoSumMu.Unlock()
+		return
+	}
+	c.autoSumRunning = true
+	c.autoSumMu.Unlock()
+
+	// Snapshot on the chat goroutine; the background run never touches the
+	// live session, so the chat loop can keep appending meanwhile
+	shadow := &history.Session{
+		ID:          session.ID,
+		Name:        session.Name,
+		PinnedFacts: append([]string{}, session.PinnedFacts...),
+		Messages:    append([]history.Message{}, session.Messages...),
+	}
+
+	fmt.Println("\n[Auto-summarizing history in the background...]")
+
+	go func() {
+		// Archive from the snapshot before summarization discards messages
+		// (no-op unless embeddings are enabled)
+		c.archiveForRecall(ctx, shadow.ID, shadow.Messages)
+
+		newMessages, err := c.summarizer.ProcessSession(ctx, shadow)
+
+		c.autoSumMu.Lock()
+		defer c.autoSumMu.Unlock()
+		c.autoSumRunning = false
+		if err != nil {
+			// Not printed: the user is likely mid-prompt. /stats shows it.
+			c.autoSumErr = err
+			return
+		}
+		c.autoSumErr = nil
+		c.autoSumResult = &pendingSummary{
+			sessionID:   shadow.ID,
+			snapshotLen: len(shadow.Messages),
+			lastMessage: shadow.Messages[len(shadow.Messages)-1],
+			messages:    newMessages,
+		}
+	}()
+}
+
+// applyPendingSummary merges a finished background summarization into the
+// current session. The merge only happens when the session is still the one
+// the snapshot was taken from and no snapshot messages were removed in the
+// meantime; messages appended since then are kept as the new tail. Runs on
+// the chat goroutine; the swap itself goes through the manager so its
+// background save timers never marshal a half-updated session.
+func (c *ChatLoop) applyPendingSummary(chatHistory *[]openai.ChatCompletionRequestMessage) {
+	c.autoSumMu.Lock()
+	pending := c.autoSumResult
+	c.autoSumResult = nil
+	c.autoSumMu.Unlock()
+
+	if pending == nil {
+		return
+	}
+
+	session := c.historyManager.Current()
+	if session == nil || session.ID != pending.sessionID {
+		return
+	}
+	// A shrunk or rewritten prefix means /rollback or /summarize ran in the
+	// meantime; the snapshot is stale, so drop the result
+	if len(session.Messages) < pending.snapshotLen {
+		return
+	}
+	if last := session.Messages[pending.snapshotLen-1]; last.Role != pending.lastMessage.Role ||
+		!last.CreatedAt.Equal(pending.lastMessage.CreatedAt) {
+		return
+	}
+
+	oldCount := len(session.Messages)
+	tail := session.Messages[pending.snapshotLen:]
+	merged := append(pending.messages, tail...)
+	if err := c.historyManager.ReplaceMessages(merged); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
+	}
+
+	*chatHistory = c.buildChatHistory()
+	fmt.Printf("[✓ Auto-summarized: %d → %d messages]\n", oldCount, len(merged))
+
+	c.hooks.Fire(hooks.EventSummarizationDone, hooks.SummarizationPayload{
+		Event:          hooks.EventSummarizationDone,
+		SessionID:      session.ID,
+		MessagesBefore: oldCount,
+		MessagesAfter:  len(merged),
+	})
+}
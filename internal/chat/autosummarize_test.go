@@ -0,0 +1,89 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+)
+
+func sessionMessage(content string, at time.Time) history.Message {
+	return history.Message{Role: history.RoleUser, Content: content, CreatedAt: at}
+}
+
+func TestApplyPendingSummaryKeepsAppendedTail(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	session := loop.historyManager.Current()
+
+	base := time.Now()
+	for i, content := range []string{"one", "two", "three", "appended"} {
+		session.Messages = append(session.Messages, sessionMessage(content, base.Add(time.Duration(i)*time.Second)))
+	}
+
+	loop.autoSumResult = &pendingSummary{
+		sessionID:   session.ID,
+		snapshotLen: 3,
+		lastMessage: session.Messages[2],
+		messages: []history.Message{{
+			Role:    history.RoleSystem,
+			Content: "summary of one to three",
+			Type:    history.TypeSummary,
+		}},
+	}
+
+	var chatHistory []openai.ChatCompletionRequestMessage
+	loop.applyPendingSummary(&chatHistory)
+
+	if len(session.Messages) != 2 {
+		t.Fatalf("expected summary plus appended tail, got %d messages", len(session.Messages))
+	}
+	if session.Messages[0].Content != "summary of one to three" || session.Messages[1].Content != "appended" {
+		t.Errorf("unexpected merged messages: %+v", session.Messages)
+	}
+	if loop.autoSumResult != nil {
+		t.Error("expected the pending result to be consumed")
+	}
+}
+
+func TestApplyPendingSummarySkipsStaleSnapshots(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	session := loop.historyManager.Current()
+
+	base := time.Now()
+	session.Messages = []history.Message{
+		sessionMessage("one", base),
+		sessionMessage("two", base.Add(time.Second)),
+	}
+	summary := []history.Message{{Role: history.RoleSystem, Content: "summary", Type: history.TypeSummary}}
+
+	tests := []struct {
+		name    string
+		pending *pendingSummary
+	}{
+		{
+			"session switched",
+			&pendingSummary{sessionID: "other-session", snapshotLen: 2, lastMessage: session.Messages[1], messages: summary},
+		},
+		{
+			"messages removed",
+			&pendingSummary{sessionID: session.ID, snapshotLen: 5, lastMessage: session.Messages[1], messages: summary},
+		},
+		{
+			"prefix rewritten",
+			&pendingSummary{sessionID: session.ID, snapshotLen: 2, lastMessage: sessionMessage("two", base.Add(time.Hour)), messages: summary},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loop.autoSumResult = tt.pending
+
+			var chatHistory []openai.ChatCompletionRequestMessage
+			loop.applyPendingSummary(&chatHistory)
+
+			if len(session.Messages) != 2 || session.Messages[0].Content != "one" {
+				t.Errorf("expected the session to be left untouched, got %+v", session.Messages)
+			}
+		})
+	}
+}
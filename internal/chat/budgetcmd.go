@@ -0,0 +1,141 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+)
+
+// effectiveModel returns the model the next request will use: the session
+// override when set, otherwise the config default.
+func (c *ChatLoop) effectiveModel() string {
+	if opts := c.requestOptions(); opts != nil && opts.Model != "" {
+		return opts.Model
+	}
+	return c.config.OpenAI.Model
+}
+
+// effectiveMaxTokens returns the max_tokens the next request will use.
+func (c *ChatLoop) effectiveMaxTokens() int {
+	if opts := c.requestOptions(); opts != nil && opts.MaxTokens > 0 {
+		return opts.MaxTokens
+	}
+	return c.config.OpenAI.MaxTokens
+}
+
+// worstCaseCost estimates the most a request can cost: every message and
+// tool definition as prompt tokens, plus a completion that uses the whole
+// max_tokens allowance. ok is false when the model has no known pricing.
+func (c *ChatLoop) worstCaseCost(model string, messages []openai.ChatCompletionRequestMessage, tools []openai.ChatCompletionTool) (float64, bool) {
+	promptTokens := 0
+	for _, msg := range messages {
+		promptTokens += estimateEntryTokens(msg)
+	}
+	if len(tools) > 0 {
+		if data, err := json.Marshal(tools); err == nil {
+			promptTokens += openai.EstimateTokens(string(data))
+		}
+	}
+	return openai.Cost(model, promptTokens, c.effectiveMaxTokens())
+}
+
+// confirmBudget enforces the configured spending limits before a request
+// is sent. When the worst case would push the session or the day over a
+// limit it asks for explicit confirmation, or refuses outright when
+// limits.hard is set; the returned error aborts the turn.
+func (c *ChatLoop) confirmBudget(messages []openai.ChatCompletionRequestMessage, tools []openai.ChatCompletionTool) error {
+	limits := c.config.Limits
+	if limits.PerSessionUSD == 0 && limits.PerDayUSD == 0 {
+		return nil
+	}
+
+	model := c.effectiveModel()
+	worst, ok := c.worstCaseCost(model, messages, tools)
+	if !ok {
+		printer.PrintWarning("No pricing known for model %s; spending limits are not enforced", model)
+		return nil
+	}
+
+	over := ""
+	if limits.PerSessionUSD > 0 && c.historyManager.SpentUSD()+worst > limits.PerSessionUSD {
+		over = fmt.Sprintf("session limit of $%.2f ($%.4f spent)", limits.PerSessionUSD, c.historyManager.SpentUSD())
+	}
+	if over == "" && limits.PerDayUSD > 0 {
+		today, err := c.ledger.SpentToday()
+		if err != nil {
+			printer.PrintWarning("Spend ledger unreadable; the daily limit cannot be checked: %v", err)
+		} else if today+worst > limits.PerDayUSD {
+			over = fmt.Sprintf("daily limit of $%.2f ($%.4f spent today)", limits.PerDayUSD, today)
+		}
+	}
+	if over == "" {
+		return nil
+	}
+
+	if limits.Hard {
+		return fmt.Errorf("request refused: a worst case of ~$%.4f would exceed the %s (limits.hard is set)", worst, over)
+	}
+	if !confirmPrompt(fmt.Sprintf("A worst case of ~$%.4f would exceed the %s. Send anyway? [y/N]: ", worst, over)) {
+		return fmt.Errorf("request cancelled: a worst case of ~$%.4f would exceed the %s", worst, over)
+	}
+	return nil
+}
+
+// recordSpend books a response's actual cost against the session and the
+// daily ledger. Best effort: a bookkeeping failure never fails the turn,
+// and unknown models are simply not booked.
+func (c *ChatLoop) recordSpend(resp *openai.ChatCompletionResponse) {
+	if resp.Usage == nil {
+		return
+	}
+	cost, ok := openai.Cost(resp.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	if !ok {
+		return
+	}
+	if err := c.historyManager.AddSpend(cost); err != nil {
+		printer.PrintWarning("Could not record session spending: %v", err)
+	}
+	if err := c.ledger.Add(cost); err != nil {
+		printer.PrintWarning("Could not record daily spending: %v", err)
+	}
+}
+
+// handleBudget processes the /budget command: it shows today's and this
+// session's recorded spending and the headroom left under each limit.
+func (c *ChatLoop) handleBudget() {
+	limits := c.config.Limits
+
+	fmt.Println("\nSpending (estimated):")
+	fmt.Printf("  This session: $%.4f%s\n", c.historyManager.SpentUSD(), headroom(limits.PerSessionUSD, c.historyManager.SpentUSD()))
+
+	if today, err := c.ledger.SpentToday(); err != nil {
+		fmt.Printf("  Today:        unavailable (%v)\n", err)
+	} else {
+		fmt.Printf("  Today:        $%.4f%s\n", today, headroom(limits.PerDayUSD, today))
+	}
+
+	switch {
+	case limits.PerSessionUSD == 0 && limits.PerDayUSD == 0:
+		fmt.Println("\nNo spending limits configured (limits.per_session_usd, limits.per_day_usd).")
+	case limits.Hard:
+		fmt.Println("\nOver-limit requests are refused (limits.hard).")
+	default:
+		fmt.Println("\nOver-limit requests ask for confirmation before sending.")
+	}
+	fmt.Println()
+}
+
+// headroom renders the limit and remaining allowance, or nothing when the
+// limit is disabled.
+func headroom(limit, spent float64) string {
+	if limit == 0 {
+		return ""
+	}
+	left := limit - spent
+	if left < 0 {
+		left = 0
+	}
+	return fmt.Sprintf(" (limit $%.2f, $%.2f left)", limit, left)
+}
@@ -0,0 +1,125 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+
+	"gopus/internal/openai"
+)
+
+func budgetTestMessages() []openai.ChatCompletionRequestMessage {
+	return []openai.ChatCompletionRequestMessage{
+		{Role: openai.RoleUser, Content: openai.StringContent("hello")},
+	}
+}
+
+func TestConfirmBudgetNoLimits(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	if err := c.confirmBudget(budgetTestMessages(), nil); err != nil {
+		t.Errorf("expected no enforcement without limits, got %v", err)
+	}
+}
+
+func TestConfirmBudgetUnknownModelIsNotEnforced(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	c.config.Limits.PerDayUSD = 0.0001
+	c.config.Limits.Hard = true
+
+	// newTestChatLoop uses "test-model", which has no known pricing
+	if err := c.confirmBudget(budgetTestMessages(), nil); err != nil {
+		t.Errorf("expected an unpriced model to pass through, got %v", err)
+	}
+}
+
+func TestConfirmBudgetHardDailyLimit(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	c.config.OpenAI.Model = "gpt-4"
+	c.config.OpenAI.MaxTokens = 100000
+	c.config.Limits.PerDayUSD = 0.001
+	c.config.Limits.Hard = true
+
+	err := c.confirmBudget(budgetTestMessages(), nil)
+	if err == nil {
+		t.Fatal("expected a hard daily limit to refuse the request")
+	}
+	if !strings.Contains(err.Error(), "daily limit") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestConfirmBudgetHardSessionLimit(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	c.config.OpenAI.Model = "gpt-4"
+	c.config.OpenAI.MaxTokens = 100000
+	c.config.Limits.PerSessionUSD = 1.00
+	c.config.Limits.Hard = true
+
+	if err := c.historyManager.AddSpend(0.99); err != nil {
+		t.Fatalf("AddSpend failed: %v", err)
+	}
+
+	err := c.confirmBudget(budgetTestMessages(), nil)
+	if err == nil {
+		t.Fatal("expected a hard session limit to refuse the request")
+	}
+	if !strings.Contains(err.Error(), "session limit") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestConfirmBudgetUnderLimit(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	c.config.OpenAI.Model = "gpt-4o-mini"
+	c.config.OpenAI.MaxTokens = 100
+	c.config.Limits.PerSessionUSD = 5.00
+	c.config.Limits.PerDayUSD = 10.00
+	c.config.Limits.Hard = true
+
+	if err := c.confirmBudget(budgetTestMessages(), nil); err != nil {
+		t.Errorf("expected a cheap request to pass, got %v", err)
+	}
+}
+
+func TestRecordSpendBooksSessionAndLedger(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+
+	resp := &openai.ChatCompletionResponse{
+		Model: "gpt-4o",
+		Usage: &openai.CompletionUsage{
+			PromptTokens:     1_000_000,
+			CompletionTokens: 100_000,
+		},
+	}
+	c.recordSpend(resp)
+	c.recordSpend(resp)
+
+	if spent := c.historyManager.SpentUSD(); spent != 7.00 {
+		t.Errorf("session spend = %v, want 7.00", spent)
+	}
+	if today, err := c.ledger.SpentToday(); err != nil || today != 7.00 {
+		t.Errorf("daily spend = %v, %v, want 7.00", today, err)
+	}
+
+	// Unknown models are not booked
+	c.recordSpend(&openai.ChatCompletionResponse{
+		Model: "test-model",
+		Usage: &openai.CompletionUsage{PromptTokens: 1000, CompletionTokens: 1000},
+	})
+	if spent := c.historyManager.SpentUSD(); spent != 7.00 {
+		t.Errorf("an unpriced model must not change the spend, got %v", spent)
+	}
+}
+
+func TestEffectiveModel(t *testing.T) {
+	loop := newTestChatLoop(t, "http://localhost:0")
+	loop.config.OpenAI.Model = "test-model"
+
+	if got := loop.effectiveModel(); got != "test-model" {
+		t.Errorf("effectiveModel() = %q, want the config default", got)
+	}
+
+	loop.historyManager.Current().Model = "gpt-4o"
+	if got := loop.effectiveModel(); got != "gpt-4o" {
+		t.Errorf("effectiveModel() = %q, want the session override", got)
+	}
+}
@@ -0,0 +1,246 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"gopus/internal/history"
+	"gopus/internal/mcp"
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+)
+
+// handleCall runs an MCP tool directly from the prompt, without the model
+// asking for it. Arguments can be a JSON object or key=value pairs; they
+// are validated against the tool's input schema before the call. With
+// --share the exchange is also recorded in the conversation so the model
+// sees it on the next turn.
+func (c *ChatLoop) handleCall(ctx context.Context, args string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	if c.mcpManager == nil {
+		fmt.Println("No MCP servers configured.")
+		return
+	}
+
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		fmt.Println("Usage: /call <tool> [--share] <json-args|key=value ...>")
+		return
+	}
+	name := fields[0]
+	share := false
+	rest := make([]string, 0, len(fields)-1)
+	for _, field := range fields[1:] {
+		if field == "--share" {
+			share = true
+			continue
+		}
+		rest = append(rest, field)
+	}
+
+	tool, ok := c.mcpManager.GetTool(name)
+	if !ok {
+		fmt.Printf("Unknown tool %q. Use /tools to list the available ones.\n", name)
+		return
+	}
+
+	callArgs, err := parseCallArguments(strings.Join(rest, " "), tool)
+	if err == nil {
+		err = validateCallArguments(callArgs, tool)
+	}
+	if err != nil {
+		fmt.Printf("%sInvalid arguments for %s: %v%s\n", printer.ColorYellow, name, err, printer.ColorReset)
+		fmt.Print(mcp.RenderToolSchemaText(tool))
+		return
+	}
+
+	// Same per-call timeout the model's tool calls get
+	callCtx := ctx
+	if timeout := time.Duration(c.config.MCP.DefaultTimeout) * time.Second; timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result, err := c.mcpManager.CallTool(callCtx, name, callArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error calling %s: %v\n", name, err)
+		return
+	}
+
+	text := c.formatToolContent(result.Content)
+	if result.IsError {
+		fmt.Printf("%sTool error: %s%s\n", printer.ColorRed, text, printer.ColorReset)
+		return
+	}
+	if text == "" {
+		text = "(no output)"
+	}
+	fmt.Println(text)
+
+	if share {
+		c.shareCallResult(name, callArgs, text, chatHistory)
+	}
+}
+
+// shareCallResult records a manual tool call in the conversation. A
+// tool-role message needs a paired assistant tool call the model never
+// made, so the exchange goes in as a single user message instead.
+func (c *ChatLoop) shareCallResult(name string, args map[string]any, result string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	argsJSON, err := json.Marshal(args)
+	if err != nil {
+		argsJSON = []byte("{}")
+	}
+	note := fmt.Sprintf("I ran the tool %s myself with arguments %s. Result:\n%s", name, argsJSON, result)
+
+	*chatHistory = append(*chatHistory, openai.ChatCompletionRequestMessage{
+		Role:    openai.ChatCompletionRequestMessageRoleUser,
+		Content: openai.StringContent(note),
+	})
+	if err := c.historyManager.AddMessage(history.RoleUser, note); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving shared result: %v\n", err)
+		return
+	}
+	fmt.Printf("%sShared with the model for the next turn.%s\n", printer.ColorDim, printer.ColorReset)
+}
+
+// parseCallArguments parses /call arguments: a JSON object verbatim, or
+// whitespace-separated key=value pairs. Pair values are strings unless the
+// tool's schema declares a number, integer, or boolean type for the key.
+func parseCallArguments(raw string, tool mcplib.Tool) (map[string]any, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return map[string]any{}, nil
+	}
+
+	if strings.HasPrefix(raw, "{") {
+		var args map[string]any
+		if err := json.Unmarshal([]byte(raw), &args); err != nil {
+			return nil, fmt.Errorf("arguments are not a valid JSON object")
+		}
+		return args, nil
+	}
+
+	args := make(map[string]any)
+	for _, pair := range strings.Fields(raw) {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%q is not a key=value pair (use a JSON object for values with spaces)", pair)
+		}
+		args[key] = coerceCallValue(value, schemaField(tool.InputSchema.Properties[key], "type"))
+	}
+	return args, nil
+}
+
+// coerceCallValue converts a key=value string to the type the schema
+// declares. Values that do not parse stay strings so validation can report
+// the mismatch by name instead of a parse error.
+func coerceCallValue(value, schemaType string) any {
+	switch schemaType {
+	case "number", "integer":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// schemaField extracts a string field from a JSON schema property value.
+func schemaField(prop any, field string) string {
+	m, ok := prop.(map[string]any)
+	if !ok {
+		return ""
+	}
+	s, _ := m[field].(string)
+	return s
+}
+
+// validateCallArguments checks the arguments against the tool's input
+// schema: required fields must be present, known fields must match their
+// declared type, and unknown fields are rejected when the schema lists any
+// properties at all.
+func validateCallArguments(args map[string]any, tool mcplib.Tool) error {
+	for _, name := range tool.InputSchema.Required {
+		if _, ok := args[name]; !ok {
+			return fmt.Errorf("missing required parameter %q", name)
+		}
+	}
+
+	for name, value := range args {
+		prop, ok := tool.InputSchema.Properties[name]
+		if !ok {
+			if len(tool.InputSchema.Properties) > 0 {
+				return fmt.Errorf("unknown parameter %q", name)
+			}
+			continue
+		}
+		want := schemaField(prop, "type")
+		if want == "" {
+			continue
+		}
+		if !callValueMatchesType(value, want) {
+			return fmt.Errorf("parameter %q expects %s, got %s", name, want, callValueTypeName(value))
+		}
+	}
+	return nil
+}
+
+// callValueMatchesType reports whether a parsed argument satisfies a JSON
+// schema primitive type name.
+func callValueMatchesType(value any, schemaType string) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	}
+	return true
+}
+
+// callValueTypeName names a parsed argument's type in schema terms for
+// error messages.
+func callValueTypeName(value any) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
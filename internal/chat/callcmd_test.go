@@ -0,0 +1,138 @@
+package chat
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"gopus/internal/mcp"
+	"gopus/internal/openai"
+)
+
+func callTestTool() mcplib.Tool {
+	return mcplib.NewTool("call_args_test",
+		mcplib.WithString("text", mcplib.Required()),
+		mcplib.WithNumber("count"),
+		mcplib.WithBoolean("loud"),
+	)
+}
+
+func TestParseCallArguments(t *testing.T) {
+	tool := callTestTool()
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    map[string]any
+		wantErr bool
+	}{
+		{"empty", "", map[string]any{}, false},
+		{"json object", `{"text": "hi", "count": 2}`, map[string]any{"text": "hi", "count": float64(2)}, false},
+		{"key=value with coercion", "text=hi count=2 loud=true", map[string]any{"text": "hi", "count": float64(2), "loud": true}, false},
+		{"unparseable coercion stays string", "text=hi count=lots", map[string]any{"text": "hi", "count": "lots"}, false},
+		{"broken json", `{"text": `, nil, true},
+		{"bare token", "text", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCallArguments(tt.raw, tool)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCallArguments(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCallArguments(%q) = %#v, want %#v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCallArguments(t *testing.T) {
+	tool := callTestTool()
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		wantErr string
+	}{
+		{"valid", map[string]any{"text": "hi", "count": float64(2)}, ""},
+		{"missing required", map[string]any{"count": float64(2)}, `missing required parameter "text"`},
+		{"wrong type", map[string]any{"text": "hi", "loud": "yes please"}, `parameter "loud" expects boolean, got string`},
+		{"unknown parameter", map[string]any{"text": "hi", "volume": float64(11)}, `unknown parameter "volume"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCallArguments(tt.args, tool)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected valid arguments, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestHandleCallShare(t *testing.T) {
+	mcp.DefaultToolRegistry.Register(
+		mcplib.NewTool("shout_test",
+			mcplib.WithString("text", mcplib.Required()),
+		),
+		func(deps mcp.Deps) mcp.ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				args, _ := req.Params.Arguments.(map[string]any)
+				text, _ := args["text"].(string)
+				return mcplib.NewToolResultText(text + "!"), nil
+			}
+		},
+	)
+	loop := newToolTestChatLoop(t)
+
+	var chatHistory []openai.ChatCompletionRequestMessage
+	loop.handleCall(context.Background(), "shout_test --share text=hello", &chatHistory)
+
+	if len(chatHistory) != 1 {
+		t.Fatalf("expected the shared exchange in the chat history, got %d message(s)", len(chatHistory))
+	}
+	if chatHistory[0].Role != openai.ChatCompletionRequestMessageRoleUser {
+		t.Errorf("expected a user message, got role %q", chatHistory[0].Role)
+	}
+	content, _ := openai.ContentAsString(chatHistory[0].Content)
+	for _, want := range []string{"shout_test", "hello!"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("shared message %q should mention %q", content, want)
+		}
+	}
+
+	session := loop.historyManager.Current()
+	if session == nil || len(session.Messages) != 1 {
+		t.Fatalf("expected the shared exchange saved to the session, got %+v", session)
+	}
+}
+
+func TestHandleCallInvalidArgsShowsSchema(t *testing.T) {
+	mcp.DefaultToolRegistry.Register(
+		mcplib.NewTool("strict_test",
+			mcplib.WithString("text", mcplib.Required()),
+		),
+		func(deps mcp.Deps) mcp.ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				t.Error("the tool must not run with invalid arguments")
+				return mcplib.NewToolResultText(""), nil
+			}
+		},
+	)
+	loop := newToolTestChatLoop(t)
+
+	var chatHistory []openai.ChatCompletionRequestMessage
+	loop.handleCall(context.Background(), "strict_test", &chatHistory)
+
+	if len(chatHistory) != 0 {
+		t.Errorf("expected nothing added to the chat history, got %d message(s)", len(chatHistory))
+	}
+}
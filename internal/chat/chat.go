@@ -5,15 +5,32 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"gopus/internal/animator"
+	"gopus/internal/budget"
+	"gopus/internal/clipboard"
 	"gopus/internal/config"
+	"gopus/internal/embeddings"
 	"gopus/internal/history"
+	"gopus/internal/hooks"
+	"gopus/internal/i18n"
 	"gopus/internal/mcp"
+	"gopus/internal/memory"
+	"gopus/internal/notify"
 	"gopus/internal/openai"
 	"gopus/internal/printer"
+	"gopus/internal/redact"
+	"gopus/internal/spinner"
 	"gopus/internal/summarize"
 
 	mcplib "github.com/mark3labs/mcp-go/mcp"
@@ -26,17 +43,106 @@ type ChatLoop struct {
 	summarizer     *summarize.Summarizer
 	mcpManager     *mcp.Manager
 	config         *config.Config
+	clipboard      clipboard.Clipboard // lazily initialized on first use
+	notifier       notify.Notifier
+	async          *printer.AsyncPrinter // for output arriving while the user types
+	configPath     string                // config file re-read by /reload and SIGHUP
+	draft          *draftFile            // crash recovery for in-progress input
+	listed         []*history.Session    // numbering from the last /list, used by /delete
+
+	// lastShellCommand is the previous `!` escape, rerun by `!!`.
+	lastShellCommand string
+
+	// pendingSessionsDir is a sessions directory change deferred by /reload
+	// ("keep the current session"); it is applied when a new session starts.
+	pendingSessionsDir string
+
+	// hooks runs user-configured automation commands on chat events.
+	// Nil unless hooks.allow_hooks is set.
+	hooks *hooks.Runner
+
+	// redactor scans outgoing messages and tool results for secrets.
+	// Nil unless redaction.mode is set.
+	redactor *redact.Scanner
+
+	// recaller archives summarized-away messages and recalls the relevant
+	// ones per prompt. Nil unless embeddings.enabled is set.
+	recaller *embeddings.Recaller
+
+	// ledger persists daily spending across sessions, for the limits
+	// config and the /budget command.
+	ledger *budget.Ledger
+
+	// memory is the global user-preferences store injected into every
+	// request; nil when the memory file location cannot be determined.
+	memory *memory.Store
+
+	// toolSpinner is the spinner shown while tools execute, so progress
+	// notifications can relabel it, and toolAnimator the animator driving
+	// it, so status lines can print without corrupting a frame. Both are
+	// nil outside a tool run.
+	toolSpinnerMu sync.Mutex
+	toolSpinner   spinner.Spinner
+	toolAnimator  *animator.Animator
+
+	// Background auto-summarization state: one run at a time, with the
+	// finished result held until the chat goroutine merges it.
+	autoSumMu      sync.Mutex
+	autoSumRunning bool
+	autoSumErr     error // last background run's failure, shown by /stats
+	autoSumResult  *pendingSummary
+
+	quitting bool // set by /quit once the exit is confirmed
 }
 
 // NewChatLoop creates a new chat loop with the given dependencies.
 func NewChatLoop(client *openai.ChatClient, historyManager *history.Manager, mcpManager *mcp.Manager, cfg *config.Config) *ChatLoop {
-	return &ChatLoop{
+	c := &ChatLoop{
 		client:         client,
 		historyManager: historyManager,
 		summarizer:     summarize.New(client, cfg.Summarization),
 		mcpManager:     mcpManager,
 		config:         cfg,
+		notifier:       notify.New(),
+		async:          printer.NewAsyncPrinter(os.Stdout),
+		configPath:     config.DefaultConfigPath,
+		redactor:       newRedactor(cfg.Redaction),
+		ledger:         budget.NewLedger(historyManager.SessionsDir()),
 	}
+	// The global memory stays off when the home directory is unknown
+	if path, err := memory.DefaultPath(); err == nil {
+		c.memory = memory.NewStore(path)
+	} else {
+		printer.PrintWarning("Memory disabled: %v", err)
+	}
+	// Recall stays off (nil recaller) unless embeddings are enabled
+	if cfg.Embeddings.Enabled {
+		embedder := embeddings.NewOpenAIEmbedder(client, cfg.Embeddings.Model)
+		c.recaller = embeddings.NewRecaller(embedder, historyManager.SessionsDir(), cfg.Embeddings)
+	}
+	// Hooks stay off (nil runner) unless explicitly allowed
+	if cfg.Hooks.AllowHooks && len(cfg.Hooks.Events) > 0 {
+		c.hooks = hooks.NewRunner(cfg.Hooks.Events, time.Duration(cfg.Hooks.Timeout)*time.Second, c.async.Printf)
+	}
+	// Show progress from long-running MCP tools on the spinner line
+	if mcpManager != nil {
+		mcpManager.SetProgressHandler(c.showToolProgress)
+	}
+	return c
+}
+
+// getClipboard returns the clipboard backend, initializing it on first use.
+func (c *ChatLoop) getClipboard() (clipboard.Clipboard, error) {
+	if c.clipboard == nil {
+		cb, err := clipboard.NewWithOptions(clipboard.Options{
+			DisableOSC52: c.config.Clipboard.DisableOSC52,
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.clipboard = cb
+	}
+	return c.clipboard, nil
 }
 
 // Run runs the main chat loop, reading user input and sending requests to OpenAI.
@@ -44,21 +150,93 @@ func (c *ChatLoop) Run(ctx context.Context, scanner *bufio.Scanner) {
 	// Display help at startup
 	c.handleHelp()
 
+	// Flush drafts and index writes and let quick hooks finish on exit
+	defer c.finishPendingWork()
+
+	// Reload the configuration on SIGHUP, the conventional daemon idiom.
+	// The signal is only noted here; the loop below applies it on this
+	// goroutine before the next prompt, because handleReload swaps
+	// c.config and reads stdin — neither is safe from a second goroutine.
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
 	// Convert session messages to OpenAI format for API calls
-	session := c.historyManager.Current()
-	chatHistory := history.MessagesToOpenAI(session.Messages)
+	chatHistory := c.buildChatHistory()
+
+	// Offer to restore an input draft left behind by a previous run, and
+	// drop any stale ones while we're at it.
+	draftDir := filepath.Dir(c.historyManager.SessionsDir())
+	cleanupStaleDrafts(draftDir)
+	c.draft = newDraftFile(draftPath(draftDir, c.historyManager.Current().ID))
+	pendingInput := ""
+	if content, ok := c.draft.Load(); ok {
+		fmt.Printf("Found an unsent draft from a previous run:\n%s\n", content)
+		fmt.Print(i18n.T(i18n.ConfirmRestoreDraft))
+		if scanner.Scan() && isAffirmative(scanner.Text()) {
+			pendingInput = content
+		} else {
+			c.draft.Clear()
+		}
+	}
 
-	for {
-		fmt.Printf("%suser:%s ", printer.ColorGreen, printer.ColorReset)
+	// Mark the prompt so it's obvious when safe mode is active.
+	promptPrefix := ""
+	if c.config.Chat.SafeMode {
+		promptPrefix = "🛡 "
+	}
 
-		// Read user input (Ctrl+D ends the input stream)
-		if !scanner.Scan() {
-			// EOF (Ctrl+D) or error - exit the loop
-			fmt.Println()
-			break
+	for {
+		// Apply a pending SIGHUP reload before prompting
+		select {
+		case <-sighup:
+			fmt.Println("Received SIGHUP, reloading configuration...")
+			c.handleReload(ctx)
+		default:
 		}
 
-		input := strings.TrimSpace(scanner.Text())
+		var input string
+		if pendingInput != "" {
+			// A restored draft becomes the first input of the loop
+			input = pendingInput
+			pendingInput = ""
+		} else {
+			// A * marks unsaved in-memory changes (cleared by the debounced
+			// save or /save). Suppressed off-terminal, where nothing
+			// interactive watches the prompt.
+			dirtyMark := ""
+			if printer.IsTerminal(os.Stdout) && c.historyManager.Dirty() {
+				dirtyMark = "*"
+			}
+			prompt := fmt.Sprintf("%s%suser:%s%s ", promptPrefix, printer.ColorGreen, dirtyMark, printer.ColorReset)
+			if printer.IsNarrow() {
+				// Collapse to a single-character indicator on narrow terminals
+				prompt = fmt.Sprintf("%s%s❯%s%s ", promptPrefix, printer.ColorGreen, dirtyMark, printer.ColorReset)
+			}
+			fmt.Print(prompt)
+
+			// Let background messages redraw the prompt while we wait for input
+			c.async.SetPrompt(prompt)
+
+			// Read user input (Ctrl+D ends the input stream)
+			if !scanner.Scan() {
+				// EOF (Ctrl+D) or error - exit the loop. There is no way to
+				// ask for confirmation without input, so report what is
+				// being finished instead.
+				c.async.ClearPrompt()
+				fmt.Println()
+				if work := c.pendingWork(); len(work) > 0 {
+					fmt.Println("Finishing pending work before exit:")
+					for _, desc := range work {
+						fmt.Printf("  - %s\n", desc)
+					}
+				}
+				break
+			}
+			c.async.ClearPrompt()
+
+			input = strings.TrimSpace(scanner.Text())
+		}
 
 		// Check for empty input
 		if input == "" {
@@ -68,55 +246,153 @@ func (c *ChatLoop) Run(ctx context.Context, scanner *bufio.Scanner) {
 		// Handle commands
 		if strings.HasPrefix(input, "/") {
 			if c.handleCommand(ctx, input, &chatHistory) {
+				if c.quitting {
+					break
+				}
 				continue
 			}
 		}
 
+		// `!command` runs locally and optionally shares its output
+		if strings.HasPrefix(input, "!") {
+			c.handleShellEscape(ctx, input, &chatHistory)
+			continue
+		}
+
+		// Scan for secrets before the message reaches disk or the API
+		input, send := c.checkOutgoingMessage(input)
+		if !send {
+			continue
+		}
+
+		// Keep the input as a draft until it is safely in the session
+		c.draft.Save(input)
+		c.draft.Flush()
+
 		// Add user message to history manager (auto-saves)
 		if err := c.historyManager.AddMessage(history.RoleUser, input); err != nil {
 			fmt.Fprintf(os.Stderr, "Error saving message: %v\n", err)
+		} else {
+			c.draft.Clear()
 		}
 
 		// Add user message to chat history for API
 		chatHistory = append(chatHistory, openai.ChatCompletionRequestMessage{
 			Role:    openai.RoleUser,
-			Content: &input,
+			Content: openai.StringContent(input),
 		})
 
 		// Process the conversation (may involve multiple tool calls)
 		if err := c.processConversation(ctx, &chatHistory); err != nil {
 			printer.PrintError("Error: %v", err)
+
+			// An empty response is a soft failure: the request itself was
+			// valid, so keep the user message and record what happened.
+			if errors.Is(err, openai.ErrEmptyResponse) {
+				noteErr := c.historyManager.AddSystemNote("The model returned an empty response to the previous message.")
+				if noteErr != nil {
+					fmt.Fprintf(os.Stderr, "Error saving note: %v\n", noteErr)
+				}
+				continue
+			}
+
 			// Remove the failed message from both histories
 			chatHistory = chatHistory[:len(chatHistory)-1]
-			// Remove from session history too
-			session := c.historyManager.Current()
-			if len(session.Messages) > 0 {
-				session.Messages = session.Messages[:len(session.Messages)-1]
-				c.historyManager.SaveCurrent()
+			c.rollbackLastSessionMessage()
+
+			// A network-level failure never reached the API; offer to
+			// queue the message instead of discarding it
+			if openai.IsNetworkError(err) {
+				c.offerQueue(input)
 			}
 			continue
 		}
 
 		// Check for auto-summarization
 		c.checkAutoSummarize(ctx, &chatHistory)
+
+		// Connectivity is back, so remind the user about waiting messages
+		if pending := c.historyManager.PendingQueued(); pending > 0 {
+			fmt.Printf("%d queued message(s) waiting; send them with /flush.\n", pending)
+		}
 	}
 }
 
 // processConversation handles the conversation loop including tool calls.
-func (c *ChatLoop) processConversation(ctx context.Context, chatHistory *[]openai.ChatCompletionRequestMessage) error {
+func (c *ChatLoop) processConversation(ctx context.Context, chatHistory *[]openai.ChatCompletionRequestMessage) (err error) {
+	// Merge a background summarization that finished while the user typed,
+	// so this request already benefits from the smaller history
+	c.applyPendingSummary(chatHistory)
+
+	// A tool-call turn appends several messages (assistant tool calls, tool
+	// results) before the final completion. If the turn fails partway, roll
+	// them all back: a lone tool message left behind would be rejected by
+	// the API on the next request.
+	preTurnLen := len(*chatHistory)
+	defer func() {
+		if err != nil {
+			*chatHistory = (*chatHistory)[:preTurnLen]
+		}
+	}()
+
 	// Get tools from MCP client if available
-	tools := c.getOpenAITools()
+	tools := c.getOpenAITools(ctx)
+
+	// Recall relevant archived messages for this prompt (opt-in). The block
+	// is computed once per turn and injected into each request of the turn,
+	// but never persisted into the session history.
+	recallBlock := c.recallContext(ctx, *chatHistory)
+
+	// The session's context set (files and notes from /context add) and
+	// the global memory are likewise rebuilt from disk once per turn and
+	// never persisted, so summarization can never consume them
+	injected := append(c.memoryMessages(), c.contextSetMessages()...)
+
+	emptyRetries := 0
+	jsonRetries := 0
+
+	// Accumulated metadata across all API calls in this turn
+	turnStart := time.Now()
+	meta := history.Meta{}
+
+	// Notify when a long turn finishes, whether it succeeded or errored
+	defer func() {
+		c.maybeNotify(time.Since(turnStart))
+	}()
 
 	for {
-		// Send request to OpenAI with spinner and extract first choice
-		choice, err := WithSpinner(func() (*openai.ChatCompletionChoice, error) {
-			return c.client.ChatCompletionWithToolsX(ctx, *chatHistory, tools)
+		request := withContextSet(withRecallContext(*chatHistory, recallBlock), injected)
+
+		// Enforce spending limits on the worst case before sending
+		if err := c.confirmBudget(request, tools); err != nil {
+			return err
+		}
+
+		// Send request to OpenAI with spinner
+		resp, err := spinner.Run("thinking…", func() (*openai.ChatCompletionResponse, error) {
+			return c.client.ChatCompletionWithOptions(ctx, request, tools, c.requestOptions())
 		})
 
 		if err != nil {
 			return err
 		}
 
+		// Accumulate response metadata
+		meta.Model = resp.Model
+		if resp.Usage != nil {
+			meta.PromptTokens += resp.Usage.PromptTokens
+			meta.CompletionTokens += resp.Usage.CompletionTokens
+		}
+		c.recordSpend(resp)
+
+		choice, err := openai.ExtractFirstChoice(resp)
+		if err != nil {
+			return err
+		}
+		if choice.FinishReason != nil {
+			meta.FinishReason = string(*choice.FinishReason)
+		}
+
 		message := choice.Message
 
 		// Check if the model wants to call tools
@@ -143,180 +419,221 @@ func (c *ChatLoop) processConversation(ctx context.Context, chatHistory *[]opena
 				continue
 			}
 
-			// Execute each tool call
-			for _, toolCall := range *message.ToolCalls {
-				fmt.Printf("%s[Executing %s...]%s\n", printer.ColorCyan, toolCall.Function.Name, printer.ColorReset)
-				result, err := c.executeToolCall(ctx, toolCall)
-				if err != nil {
-					// Add error result to history
-					toolResultMsg := c.buildToolResultMessage(toolCall.Id, fmt.Sprintf("Error: %v", err))
-					*chatHistory = append(*chatHistory, toolResultMsg)
-					fmt.Printf("%s[Tool %s failed: %v]%s\n", printer.ColorRed, toolCall.Function.Name, err, printer.ColorReset)
-				} else {
-					// Add success result to history
-					toolResultMsg := c.buildToolResultMessage(toolCall.Id, result)
-					*chatHistory = append(*chatHistory, toolResultMsg)
-					fmt.Printf("%s[Tool %s completed]%s\n", printer.ColorGreen, toolCall.Function.Name, printer.ColorReset)
-				}
+			// Execute the approved tool calls concurrently. Outcomes come
+			// back in the request order, so the tool result messages line
+			// up with the tool call IDs the API expects. Ctrl+C while a
+			// tool runs cancels that run rather than ending the session.
+			meta.ToolsExecuted += len(*message.ToolCalls)
+			outcomes, interrupted := c.executeToolCallsInterruptible(ctx, *message.ToolCalls)
+			if interrupted {
+				fmt.Printf("%s[Tool execution cancelled]%s\n", printer.ColorYellow, printer.ColorReset)
+				outcomes = c.offerSkippedToolCalls(ctx, *message.ToolCalls, outcomes)
+			}
+			for _, outcome := range outcomes {
+				content := c.redactToolResult(toolResultContent(outcome))
+				toolResultMsg := c.buildToolResultMessage((*message.ToolCalls)[outcome.index].Id, content)
+				*chatHistory = append(*chatHistory, toolResultMsg)
+			}
+
+			// After an interrupt, hand control back to the prompt instead of
+			// asking the model to react to the cancelled results. The results
+			// are recorded above, so the next request stays API-valid.
+			if interrupted {
+				return nil
 			}
 
 			// Continue the loop to get the model's response after tool execution
 			continue
 		}
 
-		// No tool calls - this is the final response
+		// No tool calls - this is the final response.
+		// Some providers occasionally return a choice with null content and
+		// finish_reason stop; retry once before treating it as a failure.
 		if message.Content == nil {
+			if emptyRetries == 0 {
+				emptyRetries++
+				fmt.Printf("%s[Empty response from model, retrying...]%s\n", printer.ColorYellow, printer.ColorReset)
+				continue
+			}
 			return openai.ErrEmptyResponse
 		}
 
 		assistantMessage := *message.Content
-		printer.PrintMessage(string(history.RoleAssistant), assistantMessage, false)
+
+		// With JSON mode active, an unparseable (or schema-violating) reply
+		// gets one corrective round trip before the raw text is surfaced
+		display, correction := c.validateJSONResponse(assistantMessage)
+		if correction != "" && jsonRetries == 0 {
+			jsonRetries++
+			fmt.Printf("%s[Response was not valid JSON, requesting a correction...]%s\n", printer.ColorYellow, printer.ColorReset)
+			*chatHistory = append(*chatHistory,
+				openai.ChatCompletionRequestMessage{
+					Role:    openai.RoleAssistant,
+					Content: openai.StringContent(assistantMessage),
+				},
+				openai.ChatCompletionRequestMessage{
+					Role:    openai.RoleUser,
+					Content: openai.StringContent(correction),
+				})
+			continue
+		}
+		if correction != "" {
+			printer.PrintWarning("Response is still not valid JSON; showing the raw text.")
+		}
+
+		printer.PrintWrapped(string(history.RoleAssistant), display, printer.DefaultWrapOptions())
 		fmt.Println()
 
-		// Add assistant response to history manager (auto-saves)
-		if err := c.historyManager.AddMessage(history.RoleAssistant, assistantMessage); err != nil {
+		printFinishReasonNotice(meta.FinishReason)
+
+		// Before the reply is saved, the session's last assistant message
+		// is still the previous one to compare against
+		c.maybeShowDiff(assistantMessage)
+
+		meta.DurationMS = time.Since(turnStart).Milliseconds()
+		if c.config.Chat.ShowStats {
+			c.printTurnStats(&meta)
+		}
+
+		// Add assistant response with turn metadata to history manager (auto-saves)
+		if err := c.historyManager.AddMessageWithMeta(history.RoleAssistant, assistantMessage, &meta); err != nil {
 			fmt.Fprintf(os.Stderr, "Error saving message: %v\n", err)
 		}
 
+		if session := c.historyManager.Current(); session != nil {
+			c.hooks.Fire(hooks.EventTurnCompleted, hooks.TurnPayload{
+				Event:            hooks.EventTurnCompleted,
+				SessionID:        session.ID,
+				SessionName:      session.Name,
+				AssistantMessage: assistantMessage,
+				ToolsExecuted:    meta.ToolsExecuted,
+				DurationMS:       meta.DurationMS,
+			})
+		}
+
 		// Add assistant response to chat history for API
 		*chatHistory = append(*chatHistory, openai.ChatCompletionRequestMessage{
 			Role:    openai.RoleAssistant,
-			Content: &assistantMessage,
+			Content: openai.StringContent(assistantMessage),
 		})
 
 		return nil
 	}
 }
 
-// getOpenAITools converts MCP tools to OpenAI format.
-func (c *ChatLoop) getOpenAITools() []openai.ChatCompletionTool {
-	if c.mcpManager == nil {
-		return nil
+// maybeNotify sends a desktop notification when a turn took longer than the
+// configured threshold. Errors are ignored: notifications are best-effort
+// and must never disturb the chat loop.
+func (c *ChatLoop) maybeNotify(elapsed time.Duration) {
+	threshold := c.config.Chat.NotifyAfterSeconds
+	if threshold <= 0 || elapsed < time.Duration(threshold)*time.Second {
+		return
 	}
 
-	mcpTools := c.mcpManager.ListTools()
-	if len(mcpTools) == 0 {
-		return nil
+	name := "(unnamed)"
+	if session := c.historyManager.Current(); session != nil && session.Name != "" {
+		name = session.Name
 	}
 
-	tools := make([]openai.ChatCompletionTool, 0, len(mcpTools))
-	for _, tool := range mcpTools {
-		// Convert MCP tool schema to OpenAI format
-		// Marshal the InputSchema to JSON and unmarshal to map[string]interface{}
-		schemaBytes, err := json.Marshal(tool.InputSchema)
-		if err != nil {
-			continue // Skip tools with invalid schemas
-		}
-
-		var params map[string]interface{}
-		if err := json.Unmarshal(schemaBytes, &params); err != nil {
-			continue // Skip tools with invalid schemas
-		}
+	_ = c.notifier.Notify("gopus", fmt.Sprintf("Response ready in session %s", name))
+}
 
-		tools = append(tools, openai.ChatCompletionTool{
-			Type: openai.Function,
-			Function: openai.FunctionDefinition{
-				Name:        tool.Name,
-				Description: &tool.Description,
-				Parameters:  &params,
-			},
-		})
+// printTurnStats prints a dim one-line summary of the completed turn.
+func (c *ChatLoop) printTurnStats(meta *history.Meta) {
+	line := fmt.Sprintf("⏱ %.1fs · %s prompt + %s completion tokens · %s · finish: %s",
+		float64(meta.DurationMS)/1000.0,
+		groupDigits(meta.PromptTokens),
+		groupDigits(meta.CompletionTokens),
+		meta.Model,
+		meta.FinishReason)
+	if meta.ToolsExecuted > 0 {
+		line += fmt.Sprintf(" · %d tool(s)", meta.ToolsExecuted)
 	}
-
-	return tools
+	fmt.Printf("%s%s%s\n", printer.ColorDim, line, printer.ColorReset)
 }
 
-// buildAssistantMessageWithToolCalls creates an assistant message containing tool calls.
-func (c *ChatLoop) buildAssistantMessageWithToolCalls(message openai.ChatCompletionResponseMessage) openai.ChatCompletionRequestMessage {
-	role := openai.ChatCompletionRequestMessageRoleAssistant
-
-	// Convert response tool calls to request format
-	var toolCalls []openai.ChatCompletionMessageToolCall
-	if message.ToolCalls != nil {
-		for _, tc := range *message.ToolCalls {
-			toolCalls = append(toolCalls, openai.ChatCompletionMessageToolCall{
-				Id:   tc.Id,
-				Type: openai.ChatCompletionMessageToolCallTypeFunction,
-				Function: openai.ChatCompletionMessageToolCallFunction{
-					Name:      tc.Function.Name,
-					Arguments: tc.Function.Arguments,
-				},
-			})
+// groupDigits formats n with thousands separators (e.g. 1240 -> "1,240").
+func groupDigits(n int) string {
+	s := strconv.Itoa(n)
+	if len(s) <= 3 {
+		return s
+	}
+
+	var sb strings.Builder
+	lead := len(s) % 3
+	if lead > 0 {
+		sb.WriteString(s[:lead])
+	}
+	for i := lead; i < len(s); i += 3 {
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
 		}
+		sb.WriteString(s[i : i+3])
 	}
+	return sb.String()
+}
 
-	return openai.ChatCompletionRequestMessage{
-		Role:      role,
-		Content:   message.Content,
-		ToolCalls: &toolCalls,
+// getOpenAITools converts MCP tools to OpenAI format, omitting tools from
+// servers currently marked unhealthy.
+func (c *ChatLoop) getOpenAITools(ctx context.Context) []openai.ChatCompletionTool {
+	if c.mcpManager == nil {
+		return nil
 	}
+	return c.mcpManager.OpenAITools(ctx)
+}
+
+// buildAssistantMessageWithToolCalls creates an assistant message containing tool calls.
+func (c *ChatLoop) buildAssistantMessageWithToolCalls(message openai.ChatCompletionResponseMessage) openai.ChatCompletionRequestMessage {
+	return openai.AssistantMessageWithToolCalls(message)
 }
 
 // buildToolResultMessage creates a tool result message.
 func (c *ChatLoop) buildToolResultMessage(toolCallID, content string) openai.ChatCompletionRequestMessage {
-	role := openai.ChatCompletionRequestMessageRoleTool
-	return openai.ChatCompletionRequestMessage{
-		Role:       role,
-		Content:    &content,
-		ToolCallId: &toolCallID,
-	}
+	return openai.ToolResultMessage(toolCallID, content)
 }
 
-// executeToolCall executes a single tool call via MCP.
-func (c *ChatLoop) executeToolCall(ctx context.Context, toolCall openai.ChatCompletionMessageToolCall) (string, error) {
+// executeToolCall executes a single tool call via MCP. The second return
+// reports whether the result was served from the tool result cache.
+func (c *ChatLoop) executeToolCall(ctx context.Context, toolCall openai.ChatCompletionMessageToolCall) (string, bool, error) {
 	if c.mcpManager == nil {
-		return "", fmt.Errorf("MCP manager not configured")
+		return "", false, fmt.Errorf("MCP manager not configured")
 	}
 
 	// Parse the arguments into map[string]any
 	var args map[string]any
 	if toolCall.Function.Arguments != "" {
 		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
-			return "", fmt.Errorf("failed to parse tool arguments: %w", err)
+			return "", false, fmt.Errorf("failed to parse tool arguments: %w", err)
 		}
 	}
 
 	// Call the tool
-	result, err := c.mcpManager.CallTool(ctx, toolCall.Function.Name, args)
+	result, cached, err := c.mcpManager.CallToolCached(ctx, toolCall.Function.Name, args)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	// Format the result content
 	if result.IsError {
-		return fmt.Sprintf("Tool error: %s", c.formatToolContent(result.Content)), nil
+		return fmt.Sprintf("Tool error: %s", c.formatToolContent(result.Content)), cached, nil
 	}
 
-	return c.formatToolContent(result.Content), nil
+	return c.formatToolContent(result.Content), cached, nil
 }
 
 // formatToolContent formats tool result content for display.
 func (c *ChatLoop) formatToolContent(content []mcplib.Content) string {
-	var parts []string
-	for _, item := range content {
-		switch c := item.(type) {
-		case mcplib.TextContent:
-			parts = append(parts, c.Text)
-		case *mcplib.TextContent:
-			parts = append(parts, c.Text)
-		case mcplib.ImageContent:
-			parts = append(parts, "[image content]")
-		case *mcplib.ImageContent:
-			parts = append(parts, "[image content]")
-		case mcplib.AudioContent:
-			parts = append(parts, "[audio content]")
-		case *mcplib.AudioContent:
-			parts = append(parts, "[audio content]")
-		default:
-			parts = append(parts, "[unknown content]")
-		}
-	}
-	return strings.Join(parts, "\n")
+	return mcp.FormatContent(content)
 }
 
 // confirmToolExecution checks if tool execution should proceed based on config.
 // Returns true if execution should proceed, false if declined.
 func (c *ChatLoop) confirmToolExecution(toolCalls []openai.ChatCompletionMessageToolCall) bool {
+	// Safe mode always requires explicit confirmation, whatever is configured.
+	if c.config.Chat.SafeMode {
+		return c.promptForConfirmation(toolCalls)
+	}
+
 	confirmation := c.config.MCP.ToolConfirmation
 
 	switch confirmation {
@@ -341,7 +658,7 @@ func (c *ChatLoop) confirmToolExecution(toolCalls []openai.ChatCompletionMessage
 
 // promptForConfirmation asks the user to confirm tool execution.
 func (c *ChatLoop) promptForConfirmation(toolCalls []openai.ChatCompletionMessageToolCall) bool {
-	fmt.Printf("\n%sExecute these tools? [y/N]: %s", printer.ColorYellow, printer.ColorReset)
+	fmt.Printf("\n%s%s%s", printer.ColorYellow, i18n.T(i18n.ConfirmExecuteTools), printer.ColorReset)
 
 	// Read a single line of input
 	reader := bufio.NewReader(os.Stdin)
@@ -354,36 +671,56 @@ func (c *ChatLoop) promptForConfirmation(toolCalls []openai.ChatCompletionMessag
 	return input == "y" || input == "yes"
 }
 
-// checkAutoSummarize checks if auto-summarization should be triggered.
-func (c *ChatLoop) checkAutoSummarize(ctx context.Context, chatHistory *[]openai.ChatCompletionRequestMessage) {
+// requestOptions returns per-call overrides from the current session, or
+// nil when the client defaults apply.
+func (c *ChatLoop) requestOptions() *openai.RequestOptions {
 	session := c.historyManager.Current()
-
-	if !c.summarizer.ShouldAutoSummarize(session.Messages) {
-		return
+	if session == nil {
+		return nil
 	}
-
-	fmt.Println("\n[Auto-summarizing history...]")
-
-	// Process the session with spinner
-	newMessages, err := WithSpinner(func() ([]history.Message, error) {
-		return c.summarizer.ProcessSession(ctx, session)
-	})
-
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Auto-summarization error: %v\n", err)
-		return
+	var temperature *float32
+	if session.Temperature != nil {
+		t := float32(*session.Temperature)
+		temperature = &t
 	}
-
-	// Update session with summarized messages
-	oldCount := len(session.Messages)
-	session.Messages = newMessages
-	if err := c.historyManager.SaveCurrent(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
-		return
+	opts := &openai.RequestOptions{
+		Model:            session.Model,
+		MaxTokens:        session.MaxTokens,
+		Temperature:      temperature,
+		TopP:             session.TopP,
+		PresencePenalty:  session.PresencePenalty,
+		FrequencyPenalty: session.FrequencyPenalty,
+		Seed:             session.Seed,
+		ResponseFormat:   responseFormatFor(session),
+	}
+	if opts.Model == "" && opts.MaxTokens == 0 && opts.Temperature == nil &&
+		opts.TopP == nil && opts.PresencePenalty == nil &&
+		opts.FrequencyPenalty == nil && opts.Seed == nil && opts.ResponseFormat == nil {
+		return nil
 	}
+	return opts
+}
 
-	// Update the chat history for API calls
-	*chatHistory = history.MessagesToOpenAI(newMessages)
+// activeSystemPrompt returns the system prompt in effect: the session's
+// override when set, otherwise the config default. Empty means none.
+func (c *ChatLoop) activeSystemPrompt() string {
+	if session := c.historyManager.Current(); session != nil && session.SystemPrompt != "" {
+		return session.SystemPrompt
+	}
+	return c.config.Chat.SystemPrompt
+}
 
-	fmt.Printf("[✓ Auto-summarized: %d → %d messages]\n\n", oldCount, len(newMessages))
+// buildChatHistory converts the current session's messages to API format,
+// prepending the active system prompt. The prompt lives only in the request
+// history, never in the session, so summarization cannot condense it away.
+func (c *ChatLoop) buildChatHistory() []openai.ChatCompletionRequestMessage {
+	messages := history.MessagesToOpenAI(c.historyManager.Current().Messages)
+	prompt := c.activeSystemPrompt()
+	if prompt == "" {
+		return messages
+	}
+	return append([]openai.ChatCompletionRequestMessage{{
+		Role:    openai.RoleSystem,
+		Content: openai.StringContent(prompt),
+	}}, messages...)
 }
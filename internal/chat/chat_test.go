@@ -0,0 +1,237 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gopus/internal/config"
+	"gopus/internal/history"
+	"gopus/internal/openai"
+	"gopus/internal/testutil"
+)
+
+// scriptedServer returns an httptest server that replies to successive
+// chat completion requests with the given response bodies in order.
+// Each entry is either an HTTP status code paired with a raw JSON body.
+type scriptedResponse struct {
+	status int
+	body   string
+}
+
+func scriptedServer(t *testing.T, responses []scriptedResponse) *httptest.Server {
+	t.Helper()
+	script := make([]testutil.Response, len(responses))
+	for i, resp := range responses {
+		script[i] = testutil.Response{Status: resp.status, Body: resp.body}
+	}
+	return testutil.NewServer(t, script).Server
+}
+
+// newTestChatLoop creates a ChatLoop wired to the given base URL with a
+// fresh session in a temporary directory.
+func newTestChatLoop(t *testing.T, baseURL string) *ChatLoop {
+	t.Helper()
+
+	cfg := &config.Config{
+		OpenAI: config.OpenAIConfig{
+			APIKey:  "test-key",
+			Model:   "test-model",
+			BaseURL: baseURL,
+		},
+		MCP: config.MCPConfig{
+			ToolConfirmation: config.ToolConfirmationNever,
+		},
+	}
+
+	client, err := openai.NewChatClient(cfg)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	manager, err := history.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create history manager: %v", err)
+	}
+	manager.NewSession()
+
+	return NewChatLoop(client, manager, nil, cfg)
+}
+
+// completionBody builds a minimal chat completion response body.
+// content may be the JSON literal "null" or a quoted string.
+func completionBody(content, finishReason string) string {
+	return testutil.CompletionBody(content, finishReason)
+}
+
+func userMessages(text string) []openai.ChatCompletionRequestMessage {
+	return []openai.ChatCompletionRequestMessage{
+		{Role: openai.RoleUser, Content: openai.StringContent(text)},
+	}
+}
+
+// TestProcessConversationNilContentRetries verifies that a null-content
+// response with no tool calls triggers a single automatic retry.
+func TestProcessConversationNilContentRetries(t *testing.T) {
+	srv := scriptedServer(t, []scriptedResponse{
+		{http.StatusOK, completionBody("null", "stop")},
+		{http.StatusOK, completionBody(`"hello after retry"`, "stop")},
+	})
+	defer srv.Close()
+
+	loop := newTestChatLoop(t, srv.URL)
+	chatHistory := userMessages("hi")
+
+	if err := loop.processConversation(context.Background(), &chatHistory); err != nil {
+		t.Fatalf("expected retry to recover, got error: %v", err)
+	}
+
+	last := chatHistory[len(chatHistory)-1]
+	content, ok := openai.ContentAsString(last.Content)
+	if last.Role != openai.RoleAssistant || !ok || content != "hello after retry" {
+		t.Errorf("expected assistant message from retry, got %+v", last)
+	}
+}
+
+// TestProcessConversationNilContentGivesUp verifies that two consecutive
+// null-content responses surface ErrEmptyResponse.
+func TestProcessConversationNilContentGivesUp(t *testing.T) {
+	srv := scriptedServer(t, []scriptedResponse{
+		{http.StatusOK, completionBody("null", "stop")},
+		{http.StatusOK, completionBody("null", "stop")},
+	})
+	defer srv.Close()
+
+	loop := newTestChatLoop(t, srv.URL)
+	chatHistory := userMessages("hi")
+
+	err := loop.processConversation(context.Background(), &chatHistory)
+	if !errors.Is(err, openai.ErrEmptyResponse) {
+		t.Fatalf("expected ErrEmptyResponse, got %v", err)
+	}
+}
+
+// TestProcessConversationToolCallsNotEmpty verifies that a null-content
+// response carrying tool calls is not treated as an empty response.
+func TestProcessConversationToolCallsNotEmpty(t *testing.T) {
+	toolCallMsg := `{
+		"id": "chatcmpl-test",
+		"object": "chat.completion",
+		"created": 0,
+		"model": "test-model",
+		"choices": [{
+			"index": 0,
+			"message": {
+				"role": "assistant",
+				"content": null,
+				"tool_calls": [{
+					"id": "call_1",
+					"type": "function",
+					"function": {"name": "echo", "arguments": "{}"}
+				}]
+			},
+			"finish_reason": "tool_calls"
+		}]
+	}`
+
+	srv := scriptedServer(t, []scriptedResponse{
+		{http.StatusOK, toolCallMsg},
+		{http.StatusOK, completionBody(`"done"`, "stop")},
+	})
+	defer srv.Close()
+
+	loop := newTestChatLoop(t, srv.URL)
+	chatHistory := userMessages("hi")
+
+	// No MCP manager is configured, so the tool call fails locally; the
+	// error is fed back as a tool result and the conversation continues.
+	if err := loop.processConversation(context.Background(), &chatHistory); err != nil {
+		t.Fatalf("expected tool-call turn to complete, got error: %v", err)
+	}
+
+	last := chatHistory[len(chatHistory)-1]
+	if content, ok := openai.ContentAsString(last.Content); !ok || content != "done" {
+		t.Errorf("expected final assistant message, got %+v", last)
+	}
+}
+
+// TestProcessConversationAPIError verifies that genuine API errors are
+// surfaced as-is, without the empty-response retry.
+func TestProcessConversationAPIError(t *testing.T) {
+	srv := scriptedServer(t, []scriptedResponse{
+		{http.StatusInternalServerError, `{"error": {"message": "server exploded", "type": "server_error", "code": null, "param": null}}`},
+	})
+	defer srv.Close()
+
+	loop := newTestChatLoop(t, srv.URL)
+	chatHistory := userMessages("hi")
+
+	err := loop.processConversation(context.Background(), &chatHistory)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if errors.Is(err, openai.ErrEmptyResponse) {
+		t.Fatalf("API error should not be ErrEmptyResponse, got %v", err)
+	}
+
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *openai.APIError, got %T: %v", err, err)
+	}
+	if apiErr.Message != "server exploded" {
+		t.Errorf("unexpected API error message: %q", apiErr.Message)
+	}
+}
+
+// TestProcessConversationRollsBackToolTurnOnError verifies that a failure
+// after tool execution removes the accumulated assistant tool-call and tool
+// result messages, leaving the history exactly as it was before the turn.
+func TestProcessConversationRollsBackToolTurnOnError(t *testing.T) {
+	toolCallMsg := `{
+		"id": "chatcmpl-test",
+		"object": "chat.completion",
+		"created": 0,
+		"model": "test-model",
+		"choices": [{
+			"index": 0,
+			"message": {
+				"role": "assistant",
+				"content": null,
+				"tool_calls": [{
+					"id": "call_1",
+					"type": "function",
+					"function": {"name": "echo", "arguments": "{}"}
+				}]
+			},
+			"finish_reason": "tool_calls"
+		}]
+	}`
+
+	// The follow-up completion after the tool results fails
+	srv := scriptedServer(t, []scriptedResponse{
+		{http.StatusOK, toolCallMsg},
+		{http.StatusInternalServerError, `{"error": {"message": "server exploded", "type": "server_error", "code": null, "param": null}}`},
+	})
+	defer srv.Close()
+
+	loop := newTestChatLoop(t, srv.URL)
+	chatHistory := userMessages("hi")
+	sessionLenBefore := len(loop.historyManager.Current().Messages)
+
+	err := loop.processConversation(context.Background(), &chatHistory)
+	if err == nil {
+		t.Fatal("expected the follow-up completion to fail")
+	}
+
+	if len(chatHistory) != 1 {
+		t.Errorf("expected chat history rolled back to the user message, got %d messages", len(chatHistory))
+	}
+	if chatHistory[0].Role != openai.RoleUser {
+		t.Errorf("expected the surviving message to be the user message, got %+v", chatHistory[0])
+	}
+	if got := len(loop.historyManager.Current().Messages); got != sessionLenBefore {
+		t.Errorf("expected session untouched, got %d messages (had %d)", got, sessionLenBefore)
+	}
+}
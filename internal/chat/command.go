@@ -1,19 +1,42 @@
 package chat
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"gopus/internal/history"
+	"gopus/internal/hooks"
+	"gopus/internal/i18n"
+	"gopus/internal/mcp"
 	"gopus/internal/openai"
+	"gopus/internal/printer"
+	"gopus/internal/spinner"
+	"gopus/internal/table"
+	"gopus/internal/text"
 )
 
+// destructiveCommands names commands that modify or remove stored data.
+// They are rejected while safe mode is active.
+var destructiveCommands = map[string]bool{
+	"delete":   true,
+	"mcp":      true, // launches arbitrary commands and can rewrite the config
+	"prune":    true,
+	"rollback": true,
+}
+
 // handleCommand processes slash commands. Returns true if the command was handled.
+// User-defined aliases from chat.aliases expand first; the command table
+// then resolves exact names, declared aliases, and unambiguous prefixes.
 func (c *ChatLoop) handleCommand(ctx context.Context, input string, chatHistory *[]openai.ChatCompletionRequestMessage) bool {
+	input = c.expandAlias(input)
+
 	// Parse command and arguments
 	cmdLine := strings.TrimPrefix(input, "/")
 	parts := strings.SplitN(cmdLine, " ", 2)
@@ -23,29 +46,25 @@ func (c *ChatLoop) handleCommand(ctx context.Context, input string, chatHistory
 		args = strings.TrimSpace(parts[1])
 	}
 
-	switch cmd {
-	case "summarize":
-		c.handleSummarize(ctx, chatHistory)
-		return true
-	case "stats":
-		c.handleStats()
-		return true
-	case "tools":
-		c.handleTools()
-		return true
-	case "servers":
-		c.handleServers()
-		return true
-	case "sleep":
-		c.handleSleep(args)
-		return true
-	case "help":
-		c.handleHelp()
+	spec, candidates := resolveCommand(cmd)
+	if spec == nil {
+		if len(candidates) > 1 {
+			fmt.Printf("Ambiguous command: /%s matches %s.\n", cmd, strings.Join(candidates, ", "))
+		} else if closest := nearestCommand(cmd); closest != "" {
+			fmt.Printf("Unknown command: /%s (did you mean /%s?)\n", cmd, closest)
+		} else {
+			fmt.Printf("Unknown command: %s (type /help for available commands)\n", input)
+		}
 		return true
-	default:
-		fmt.Printf("Unknown command: %s (type /help for available commands)\n", input)
+	}
+
+	if c.config.Chat.SafeMode && destructiveCommands[spec.name] {
+		fmt.Printf("Command /%s is disabled in safe mode.\n", spec.name)
 		return true
 	}
+
+	spec.run(c, ctx, args, chatHistory)
+	return true
 }
 
 // handleSummarize processes the /summarize command.
@@ -57,6 +76,14 @@ func (c *ChatLoop) handleSummarize(ctx context.Context, chatHistory *[]openai.Ch
 		return
 	}
 
+	c.autoSumMu.Lock()
+	running := c.autoSumRunning
+	c.autoSumMu.Unlock()
+	if running {
+		fmt.Println("A background summarization is already in progress; try again shortly.")
+		return
+	}
+
 	if !c.summarizer.NeedsSummarization(session.Messages) {
 		fmt.Println("No messages need summarization yet.")
 		stats := c.summarizer.GetStats(session.Messages)
@@ -70,8 +97,12 @@ func (c *ChatLoop) handleSummarize(ctx context.Context, chatHistory *[]openai.Ch
 	fmt.Printf("Summarizing: %d messages to compress, %d to condense, keeping %d recent\n",
 		stats.CompressedCount, stats.CondensedMessages, stats.RecentMessages)
 
+	// Archive the messages about to be summarized away so they stay
+	// recallable (no-op unless embeddings are enabled)
+	c.archiveForRecall(ctx, session.ID, session.Messages)
+
 	// Process the session with spinner
-	newMessages, err := WithSpinner(func() ([]history.Message, error) {
+	newMessages, err := spinner.Run("summarizing…", func() ([]history.Message, error) {
 		return c.summarizer.ProcessSession(ctx, session)
 	})
 
@@ -81,23 +112,371 @@ func (c *ChatLoop) handleSummarize(ctx context.Context, chatHistory *[]openai.Ch
 	}
 
 	// Update session with summarized messages
-	session.Messages = newMessages
-	if err := c.historyManager.SaveCurrent(); err != nil {
+	if err := c.historyManager.ReplaceMessages(newMessages); err != nil {
 		fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
 		return
 	}
 
 	// Update the chat history for API calls
-	*chatHistory = history.MessagesToOpenAI(newMessages)
+	*chatHistory = c.buildChatHistory()
 
 	// Show results
 	newStats := c.summarizer.GetStats(newMessages)
 	fmt.Printf("✓ Summarization complete. New message count: %d (was %d)\n",
 		newStats.TotalMessages, stats.TotalMessages)
+
+	c.hooks.Fire(hooks.EventSummarizationDone, hooks.SummarizationPayload{
+		Event:          hooks.EventSummarizationDone,
+		SessionID:      session.ID,
+		MessagesBefore: stats.TotalMessages,
+		MessagesAfter:  newStats.TotalMessages,
+	})
+}
+
+// handleNote saves a private note to the session history.
+// Notes are persisted and displayed but never sent to the model.
+func (c *ChatLoop) handleNote(args string) {
+	if args == "" {
+		fmt.Println("Usage: /note <text>")
+		return
+	}
+
+	if err := c.historyManager.AddNote(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving note: %v\n", err)
+		return
+	}
+
+	fmt.Printf("%sNote saved.%s\n", printer.ColorMagenta, printer.ColorReset)
+}
+
+// handlePinFact pins a fact that summarization must always preserve, or
+// lists the pinned ones (session and config) when called without text.
+func (c *ChatLoop) handlePinFact(args string) {
+	if args == "" {
+		configFacts := c.config.Summarization.PinnedFacts
+		sessionFacts := c.historyManager.PinnedFacts()
+		if len(configFacts) == 0 && len(sessionFacts) == 0 {
+			fmt.Println("No pinned facts. Pin one with /pin-fact <text>.")
+			return
+		}
+		for _, fact := range configFacts {
+			fmt.Printf("  - %s (config)\n", fact)
+		}
+		for _, fact := range sessionFacts {
+			fmt.Printf("  - %s\n", fact)
+		}
+		return
+	}
+
+	if err := c.historyManager.PinFact(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error pinning fact: %v\n", err)
+		return
+	}
+
+	fmt.Printf("%sFact pinned; summaries will always keep it.%s\n", printer.ColorMagenta, printer.ColorReset)
+}
+
+// handleSave processes the /save command: it forces the debounced save to
+// disk immediately and reports what was written.
+func (c *ChatLoop) handleSave() {
+	session := c.historyManager.Current()
+	if session == nil {
+		fmt.Println("No active session to save.")
+		return
+	}
+
+	wasDirty := c.historyManager.Dirty()
+	if err := c.historyManager.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
+		return
+	}
+
+	path := filepath.Join(c.historyManager.SessionsDir(), session.ID+".json")
+	size := ""
+	if info, err := os.Stat(path); err == nil {
+		size = fmt.Sprintf(" (%s bytes)", groupDigits(int(info.Size())))
+	}
+	if wasDirty {
+		fmt.Printf("Saved %s%s.\n", path, size)
+	} else {
+		fmt.Printf("Nothing to save; %s%s is up to date.\n", path, size)
+	}
+}
+
+// handleInfo processes the /info command: it shows the current session's
+// identity and metadata, the counterpart of /stats for everything that is
+// not about summarization.
+func (c *ChatLoop) handleInfo() {
+	session := c.historyManager.Current()
+	if session == nil {
+		fmt.Println("No active session.")
+		return
+	}
+
+	name := session.Name
+	if name == "" {
+		name = "(unnamed)"
+	}
+	summaries, notes := 0, 0
+	for _, msg := range session.Messages {
+		if msg.IsSummary() {
+			summaries++
+		}
+		if msg.IsNote() {
+			notes++
+		}
+	}
+
+	fmt.Println("\n=== Session Info ===")
+	fmt.Printf("ID:       %s\n", session.ID)
+	fmt.Printf("Name:     %s\n", name)
+	fmt.Printf("File:     %s\n", filepath.Join(c.historyManager.SessionsDir(), session.ID+".json"))
+	fmt.Printf("Created:  %s\n", session.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Printf("Updated:  %s\n", session.UpdatedAt.Format("2006-01-02 15:04"))
+	fmt.Printf("Messages: %d (%d summaries, %d notes)\n", len(session.Messages), summaries, notes)
+	if session.ParentID != "" {
+		fmt.Printf("Forked from: %s\n", session.ParentID)
+	}
+	if session.Pinned {
+		fmt.Println("Pinned:   yes (protected from retention pruning)")
+	}
+	if session.SpentUSD > 0 {
+		fmt.Printf("Spent:    $%.4f\n", session.SpentUSD)
+	}
+	if pending := c.historyManager.PendingQueued(); pending > 0 {
+		fmt.Printf("Queued:   %d unsent message(s); /flush sends them\n", pending)
+	}
+	if session.SystemPrompt != "" {
+		fmt.Println("System prompt: overridden for this session (/system shows it)")
+	}
+	if opts := c.requestOptions(); opts != nil {
+		fmt.Printf("Overrides: %s\n", c.effectiveParamsLine())
+	}
+	fmt.Println()
+}
+
+// handlePin processes the /pin command: it toggles whether the current
+// session is protected from retention pruning.
+func (c *ChatLoop) handlePin() {
+	session := c.historyManager.Current()
+	if session == nil {
+		fmt.Println("No active session to pin.")
+		return
+	}
+
+	session.Pinned = !session.Pinned
+	if err := c.historyManager.SaveCurrent(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
+		return
+	}
+
+	if session.Pinned {
+		fmt.Println("Session pinned; retention pruning will never remove it.")
+	} else {
+		fmt.Println("Session unpinned; retention pruning may remove it again.")
+	}
+}
+
+// handleImage attaches a local image to a user message and sends it.
+// Usage: /image <path> [prompt text]
+func (c *ChatLoop) handleImage(ctx context.Context, args string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	if args == "" {
+		fmt.Println("Usage: /image <path> [prompt text]")
+		return
+	}
+
+	parts := strings.SplitN(args, " ", 2)
+	path := parts[0]
+	prompt := "What is in this image?"
+	if len(parts) > 1 && strings.TrimSpace(parts[1]) != "" {
+		prompt = strings.TrimSpace(parts[1])
+	}
+
+	// Fail locally before the API call for models without image support;
+	// a session override (templates, /params) trumps the config default
+	if model := c.effectiveModel(); !openai.SupportsVision(model) {
+		printer.PrintError("Model %q does not support image input.", model)
+		return
+	}
+
+	// Validates the format and size, and encodes the image
+	dataURI, err := openai.ImageDataURI(path)
+	if err != nil {
+		printer.PrintError("Error: %v", err)
+		return
+	}
+
+	// Persist with a file reference instead of embedding the base64 data
+	if err := c.historyManager.AddImageMessage(prompt, path); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving message: %v\n", err)
+	}
+
+	*chatHistory = append(*chatHistory, openai.ChatCompletionRequestMessage{
+		Role: openai.RoleUser,
+		Content: openai.PartsContent([]openai.ChatCompletionContentPart{
+			openai.NewTextPart(prompt),
+			openai.NewImagePart(dataURI),
+		}),
+	})
+
+	if err := c.processConversation(ctx, chatHistory); err != nil {
+		printer.PrintError("Error: %v", err)
+		// Remove the failed message from both histories
+		*chatHistory = (*chatHistory)[:len(*chatHistory)-1]
+		c.rollbackLastSessionMessage()
+	}
 }
 
-// handleStats shows summarization statistics.
-func (c *ChatLoop) handleStats() {
+// lastAssistantMessage returns the Nth-from-last assistant message in the
+// given messages (n=1 is the most recent). Returns false if there are fewer
+// than n assistant messages.
+func lastAssistantMessage(messages []history.Message, n int) (history.Message, bool) {
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		if msg.Role != history.RoleAssistant || !msg.IsMessage() {
+			continue
+		}
+		n--
+		if n == 0 {
+			return msg, true
+		}
+	}
+	return history.Message{}, false
+}
+
+// extractCodeBlocks returns the contents of all fenced code blocks in text,
+// concatenated with blank lines between blocks.
+func extractCodeBlocks(text string) string {
+	var blocks []string
+	var current []string
+	inBlock := false
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			if inBlock {
+				blocks = append(blocks, strings.Join(current, "\n"))
+				current = nil
+			}
+			inBlock = !inBlock
+			continue
+		}
+		if inBlock {
+			current = append(current, line)
+		}
+	}
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// handleCopy copies an assistant message to the system clipboard.
+// Usage: /copy [N|code] - N selects the Nth-from-last assistant message,
+// "code" copies just the fenced code blocks of the most recent one.
+func (c *ChatLoop) handleCopy(args string) {
+	session := c.historyManager.Current()
+
+	n := 1
+	codeOnly := false
+	switch {
+	case args == "":
+		// Most recent assistant message
+	case args == "code":
+		codeOnly = true
+	default:
+		parsed, err := strconv.Atoi(args)
+		if err != nil || parsed < 1 {
+			fmt.Println("Usage: /copy [N|code]")
+			return
+		}
+		n = parsed
+	}
+
+	msg, ok := lastAssistantMessage(session.Messages, n)
+	if !ok {
+		fmt.Println("No matching assistant message to copy.")
+		return
+	}
+
+	text := msg.Content
+	if codeOnly {
+		text = extractCodeBlocks(text)
+		if text == "" {
+			fmt.Println("No fenced code blocks in the last assistant message.")
+			return
+		}
+	}
+
+	cb, err := c.getClipboard()
+	if err != nil {
+		printer.PrintError("Error: %v", err)
+		return
+	}
+
+	if err := cb.Write(text); err != nil {
+		printer.PrintError("Error copying to clipboard: %v", err)
+		return
+	}
+
+	fmt.Printf("Copied %d characters to clipboard (%s).\n", len(text), cb.Name())
+}
+
+// handleClip reads the clipboard and submits it as the next user message,
+// with an optional prefix. Usage: /clip [prefix text]
+func (c *ChatLoop) handleClip(ctx context.Context, args string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	cb, err := c.getClipboard()
+	if err != nil {
+		printer.PrintError("Error: %v", err)
+		return
+	}
+
+	text, err := cb.Read()
+	if err != nil {
+		printer.PrintError("Error reading clipboard: %v", err)
+		return
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		fmt.Println("Clipboard is empty.")
+		return
+	}
+
+	input := text
+	if args != "" {
+		input = args + "\n\n" + text
+	}
+
+	fmt.Printf("Submitting clipboard contents (%d characters).\n", len(input))
+
+	if err := c.historyManager.AddMessage(history.RoleUser, input); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving message: %v\n", err)
+	}
+
+	*chatHistory = append(*chatHistory, openai.ChatCompletionRequestMessage{
+		Role:    openai.RoleUser,
+		Content: openai.StringContent(input),
+	})
+
+	if err := c.processConversation(ctx, chatHistory); err != nil {
+		printer.PrintError("Error: %v", err)
+		// Remove the failed message from both histories
+		*chatHistory = (*chatHistory)[:len(*chatHistory)-1]
+		c.rollbackLastSessionMessage()
+	}
+}
+
+// handleStats shows summarization statistics for the current session, or
+// the directory-wide usage dashboard with --all.
+func (c *ChatLoop) handleStats(args string) {
+	switch strings.TrimSpace(args) {
+	case "":
+	case "--all", "all":
+		c.handleStatsAll()
+		return
+	default:
+		fmt.Println("Usage: /stats [--all]")
+		return
+	}
+
 	session := c.historyManager.Current()
 	stats := c.summarizer.GetStats(session.Messages)
 
@@ -107,6 +486,35 @@ func (c *ChatLoop) handleStats() {
 	fmt.Printf("To condense:         %d\n", stats.CondensedMessages)
 	fmt.Printf("To compress:         %d\n", stats.CompressedCount)
 	fmt.Printf("Existing summaries:  %d\n", stats.ExistingSummaries)
+	fmt.Printf("Notes:               %d\n", stats.Notes)
+	fmt.Printf("JSON output:         %s\n", jsonModeDescription(session))
+
+	// Per-turn latency from the recorded message timestamps; turns from
+	// before timestamps were recorded are skipped
+	if latencies := history.TurnLatencies(session.Messages); len(latencies) > 0 {
+		var total time.Duration
+		for _, d := range latencies {
+			total += d
+		}
+		avg := (total / time.Duration(len(latencies))).Round(100 * time.Millisecond)
+		last := latencies[len(latencies)-1].Round(100 * time.Millisecond)
+		fmt.Printf("Turn latency:        avg %s over %d turn(s), last %s\n", avg, len(latencies), last)
+	}
+
+	// Per-model attribution for assistant replies
+	usage := history.ModelUsage(session.Messages)
+	if len(usage) > 0 {
+		models := make([]string, 0, len(usage))
+		for model := range usage {
+			models = append(models, model)
+		}
+		sort.Strings(models)
+		parts := make([]string, 0, len(models))
+		for _, model := range models {
+			parts = append(parts, fmt.Sprintf("%s (%d)", model, usage[model]))
+		}
+		fmt.Printf("Models used:         %s\n", strings.Join(parts, ", "))
+	}
 	fmt.Println()
 
 	if c.config.Summarization.AutoSummarize {
@@ -116,6 +524,32 @@ func (c *ChatLoop) handleStats() {
 	} else {
 		fmt.Println("Auto-summarization: disabled")
 	}
+
+	c.autoSumMu.Lock()
+	running, pending, lastErr := c.autoSumRunning, c.autoSumResult != nil, c.autoSumErr
+	c.autoSumMu.Unlock()
+	switch {
+	case running:
+		fmt.Println("Summarization in progress (background)...")
+	case pending:
+		fmt.Println("Summarization finished; it will be merged on the next turn.")
+	}
+	if lastErr != nil {
+		fmt.Printf("Last auto-summarization error: %v\n", lastErr)
+	}
+	fmt.Println()
+}
+
+// handleStatsAll shows the usage dashboard aggregated over every session
+// in the sessions directory.
+func (c *ChatLoop) handleStatsAll() {
+	stats, err := c.historyManager.DirectoryStats()
+	if err != nil {
+		printer.PrintError("Failed to collect statistics: %v", err)
+		return
+	}
+	fmt.Println("\n=== All Sessions ===")
+	fmt.Print(history.RenderDirectoryStats(stats, time.Now()))
 	fmt.Println()
 }
 
@@ -145,7 +579,7 @@ func (c *ChatLoop) handleSleep(args string) {
 	fmt.Printf("Sleeping for %.1f seconds...\n", seconds)
 
 	// Sleep with spinner animation
-	_, _ = WithSpinner(func() (any, error) {
+	_, _ = spinner.Run("", func() (any, error) {
 		time.Sleep(time.Duration(seconds * float64(time.Second)))
 		return nil, nil
 	})
@@ -154,26 +588,88 @@ func (c *ChatLoop) handleSleep(args string) {
 }
 
 // handleTools shows available MCP tools.
-func (c *ChatLoop) handleTools() {
+func (c *ChatLoop) handleTools(args string) {
 	if c.mcpManager == nil {
 		fmt.Println("MCP is not configured.")
 		return
 	}
 
-	tools := c.mcpManager.ListTools()
-	if len(tools) == 0 {
+	if args != "" {
+		c.handleToolDetail(args)
+		return
+	}
+
+	infos := c.mcpManager.ListToolInfos()
+	_, hidden := c.mcpManager.ListHealthyTools(context.Background())
+	if len(infos) == 0 {
 		fmt.Println("No tools available.")
 		return
 	}
 
-	fmt.Println("\n=== Available Tools ===")
-	for _, tool := range tools {
-		fmt.Printf("  %s\n", tool.Name)
-		if tool.Description != "" {
-			fmt.Printf("    %s\n", tool.Description)
+	tbl := table.New(
+		table.Column{Header: "Tool", MinWidth: 4, Align: table.AlignLeft},
+		table.Column{Header: "Server", MinWidth: 6, Align: table.AlignLeft},
+		table.Column{Header: "Required Args", MinWidth: 8, Align: table.AlignLeft},
+		table.Column{Header: "Description", MaxWidth: 50, Align: table.AlignLeft},
+	)
+	shown := 0
+	for _, info := range infos {
+		if _, bad := hidden[info.ServerID]; bad {
+			continue
+		}
+		desc := info.Tool.Description
+		if i := strings.IndexByte(desc, '\n'); i >= 0 {
+			desc = desc[:i]
 		}
+		tbl.AddRow(info.Tool.Name, info.ServerID,
+			strings.Join(info.Tool.InputSchema.Required, ", "),
+			text.TruncateWidth(desc, 50))
+		shown++
 	}
-	fmt.Printf("\nTotal: %d tool(s)\n\n", len(tools))
+
+	// Stable sorts compose: by name first, then by server, so tools group
+	// per server and sort alphabetically within it
+	tbl.SortBy(0, true, false)
+	tbl.SortBy(1, true, false)
+
+	fmt.Println()
+	tbl.Print(table.DefaultPrintOptions())
+	fmt.Printf("\nTotal: %d tool(s); /tools <name> shows details\n", shown)
+	for server, count := range hidden {
+		fmt.Printf("%d tool(s) hidden: server %s unavailable\n", count, server)
+	}
+	fmt.Println()
+}
+
+// handleToolDetail shows one tool in full: the owning server, the complete
+// description, each input-schema property, and the effective policies.
+func (c *ChatLoop) handleToolDetail(name string) {
+	var found *mcp.ToolInfo
+	for _, info := range c.mcpManager.ListToolInfos() {
+		if info.Tool.Name == name {
+			found = &info
+			break
+		}
+	}
+	if found == nil {
+		fmt.Printf("Unknown tool: %s (try /tools for the list)\n", name)
+		return
+	}
+
+	fmt.Printf("\n%s%s%s  (server: %s)\n", printer.ColorCyan, found.Tool.Name, printer.ColorReset, found.ServerID)
+	if found.Tool.Description != "" {
+		fmt.Println(found.Tool.Description)
+	}
+	fmt.Println()
+	fmt.Print(mcp.RenderToolSchemaText(found.Tool))
+
+	// Per-tool confirmation policies don't exist yet, so the global
+	// setting is the effective one for every tool
+	fmt.Printf("\nConfirmation policy: %s (mcp.tool_confirmation)\n", c.config.MCP.ToolConfirmation)
+	if toolCfg, ok := c.config.MCP.Tools[name]; ok && toolCfg.CacheTTL > 0 {
+		fmt.Printf("Result cache: %ds (mcp.tools.%s.cache_ttl)\n", toolCfg.CacheTTL, name)
+	}
+	fmt.Println()
 }
 
 // handleServers shows connected MCP servers.
@@ -183,25 +679,534 @@ func (c *ChatLoop) handleServers() {
 		return
 	}
 
-	serverCount := c.mcpManager.ServerCount()
-	if serverCount == 0 {
+	statuses := c.mcpManager.ServerStatuses()
+	if len(statuses) == 0 {
 		fmt.Println("No MCP servers connected.")
 		return
 	}
 
-	fmt.Println("\n=== Connected MCP Servers ===")
-	fmt.Printf("Total: %d server(s) connected\n", serverCount)
-	fmt.Printf("Total tools: %d\n\n", c.mcpManager.ToolCount())
+	fmt.Println()
+	tbl := table.New(
+		table.Column{Header: "Name", MinWidth: 4, Align: table.AlignLeft},
+		table.Column{Header: "Type", MinWidth: 4, Align: table.AlignLeft},
+		table.Column{Header: "Status", MinWidth: 6, Align: table.AlignLeft},
+		table.Column{Header: "Tools", MinWidth: 5, Align: table.AlignRight},
+		table.Column{Header: "Info", MinWidth: 4, MaxWidth: 50, Align: table.AlignLeft},
+	)
+	for _, s := range statuses {
+		status := "connected"
+		info := strings.TrimSpace(s.ServerName + " " + s.Version)
+		switch {
+		case !s.Connected:
+			status = "failed"
+			info = s.LastError
+		case s.LastError != "":
+			status = "unhealthy"
+			info = s.LastError
+		}
+		tbl.AddRow(s.ID, s.Type, status, fmt.Sprintf("%d", s.Tools), info)
+	}
+	tbl.Print(table.DefaultPrintOptions())
+
+	// Recent server notifications help when diagnosing a misbehaving server
+	for _, s := range statuses {
+		logs := c.mcpManager.ServerLogs(s.ID)
+		if len(logs) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s notifications:\n", s.ID)
+		for _, line := range logs {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	fmt.Println()
+}
+
+// handleFork processes the /fork command: it copies the current session's
+// messages into a new session and switches to it. An optional "@N" first
+// argument limits the copy to the first N messages; the remainder is the
+// fork's name.
+func (c *ChatLoop) handleFork(args string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	upTo := 0
+	name := strings.TrimSpace(args)
+
+	if strings.HasPrefix(name, "@") {
+		numStr, rest, _ := strings.Cut(name, " ")
+		num, err := strconv.Atoi(strings.TrimPrefix(numStr, "@"))
+		if err != nil || num < 1 {
+			fmt.Printf("Invalid message count %q (usage: /fork [@N] [name])\n", numStr)
+			return
+		}
+		upTo = num
+		name = strings.TrimSpace(rest)
+	}
+
+	original := c.historyManager.Current()
+	fork, err := c.historyManager.Fork(name, upTo)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error forking session: %v\n", err)
+		return
+	}
+
+	// Rebuild the in-memory chat history from the forked messages
+	*chatHistory = c.buildChatHistory()
+
+	fmt.Printf("Forked %q at %d message(s) into new session: %s\n",
+		original.Name, len(fork.Messages), fork.Name)
+}
+
+// handleCheckpoint processes the /checkpoint command, recording the current
+// message index under an optional label.
+func (c *ChatLoop) handleCheckpoint(args string) {
+	cp, err := c.historyManager.AddCheckpoint(strings.TrimSpace(args))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating checkpoint: %v\n", err)
+		return
+	}
+	fmt.Printf("Checkpoint %q recorded at message %d.\n", cp.Label, cp.MessageIndex)
+}
+
+// handleCheckpoints lists the session's checkpoints with their timestamps.
+func (c *ChatLoop) handleCheckpoints() {
+	checkpoints := c.historyManager.ValidCheckpoints()
+	if len(checkpoints) == 0 {
+		fmt.Println("No checkpoints in this session.")
+		return
+	}
+
+	fmt.Println("\n=== Checkpoints ===")
+	for i, cp := range checkpoints {
+		fmt.Printf("%d. %s - message %d (%s)\n",
+			i+1, cp.Label, cp.MessageIndex, cp.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Println()
+}
+
+// handleRollback processes the /rollback command: after confirmation it
+// truncates the session and chat history back to the named checkpoint,
+// archiving the removed tail so it stays recoverable.
+func (c *ChatLoop) handleRollback(args string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	target := strings.TrimSpace(args)
+	if target == "" {
+		fmt.Println("Usage: /rollback <label|number>")
+		return
+	}
+
+	checkpoints := c.historyManager.ValidCheckpoints()
+	var cp *history.Checkpoint
+	if num, err := strconv.Atoi(target); err == nil && num >= 1 && num <= len(checkpoints) {
+		cp = &checkpoints[num-1]
+	} else {
+		for i := range checkpoints {
+			if checkpoints[i].Label == target {
+				cp = &checkpoints[i]
+				break
+			}
+		}
+	}
+	if cp == nil {
+		fmt.Printf("No checkpoint %q (see /checkpoints).\n", target)
+		return
+	}
+
+	session := c.historyManager.Current()
+	removeCount := len(session.Messages) - cp.MessageIndex
+	if removeCount <= 0 {
+		fmt.Printf("Already at checkpoint %q; nothing to roll back.\n", cp.Label)
+		return
+	}
+
+	// Rolling back past a summary discards compressed history that cannot
+	// be reconstructed; point that out before asking.
+	for _, msg := range session.Messages[cp.MessageIndex:] {
+		if msg.IsSummary() {
+			fmt.Printf("%sWarning: rolling back past a summary; the summarized originals cannot be restored.%s\n",
+				printer.ColorYellow, printer.ColorReset)
+			break
+		}
+	}
+
+	if !confirmPrompt(i18n.T(i18n.ConfirmRollback, removeCount, cp.Label)) {
+		fmt.Println("Rollback cancelled.")
+		return
+	}
+
+	removed, err := c.historyManager.RollbackTo(cp.MessageIndex)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rolling back: %v\n", err)
+		return
+	}
+
+	// Rebuild the in-memory chat history from the truncated session
+	*chatHistory = c.buildChatHistory()
+
+	fmt.Printf("Rolled back to checkpoint %q; %d message(s) archived.\n", cp.Label, len(removed))
+}
+
+// handleSystem processes the /system command: with no arguments it shows the
+// active system prompt, "set <text>" overrides it for this session, and
+// "reset" reverts to the config default. Changes rebuild the live chat
+// history so they take effect on the next API call.
+func (c *ChatLoop) handleSystem(args string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	session := c.historyManager.Current()
+	sub, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+
+	switch sub {
+	case "":
+		prompt := c.activeSystemPrompt()
+		if prompt == "" {
+			fmt.Println("No system prompt is set.")
+			return
+		}
+		source := "config default"
+		if session.SystemPrompt != "" {
+			source = "session override"
+		}
+		fmt.Printf("System prompt (%s):\n%s\n", source, prompt)
+	case "set":
+		text := strings.TrimSpace(rest)
+		if text == "" {
+			fmt.Println("Usage: /system set <text>")
+			return
+		}
+		session.SystemPrompt = text
+		if err := c.historyManager.SaveCurrent(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
+			return
+		}
+		*chatHistory = c.buildChatHistory()
+		fmt.Println("System prompt updated for this session.")
+	case "reset":
+		session.SystemPrompt = ""
+		if err := c.historyManager.SaveCurrent(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
+			return
+		}
+		*chatHistory = c.buildChatHistory()
+		if c.config.Chat.SystemPrompt == "" {
+			fmt.Println("System prompt cleared; no config default is set.")
+		} else {
+			fmt.Println("System prompt reset to the config default.")
+		}
+	default:
+		fmt.Println("Usage: /system [set <text>|reset]")
+	}
+}
+
+// handleMaxTokens processes the /max-tokens command: with no arguments it
+// shows the effective value, a number sets a session override (validated
+// against the model's limit when known), and "reset" reverts to the config
+// default. The override persists with the session.
+func (c *ChatLoop) handleMaxTokens(args string) {
+	session := c.historyManager.Current()
+	args = strings.TrimSpace(args)
+
+	switch {
+	case args == "":
+		if session.MaxTokens > 0 {
+			fmt.Printf("max_tokens: %d (session override)\n", session.MaxTokens)
+		} else {
+			fmt.Printf("max_tokens: %d (config default)\n", c.config.OpenAI.MaxTokens)
+		}
+	case args == "reset":
+		session.MaxTokens = 0
+		if err := c.historyManager.SaveCurrent(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
+			return
+		}
+		fmt.Printf("max_tokens reset to the config default (%d).\n", c.config.OpenAI.MaxTokens)
+	default:
+		n, err := strconv.Atoi(args)
+		if err != nil || n <= 0 {
+			fmt.Println("Usage: /max-tokens <n|reset>")
+			return
+		}
+		if limit := openai.MaxTokensLimit(c.config.OpenAI.Model); limit > 0 && n > limit {
+			fmt.Printf("%d exceeds the limit of %d for model %s.\n", n, limit, c.config.OpenAI.Model)
+			return
+		}
+		session.MaxTokens = n
+		if err := c.historyManager.SaveCurrent(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
+			return
+		}
+		fmt.Printf("max_tokens set to %d for this session.\n", n)
+	}
+}
+
+// handleList shows all sessions in a numbered table and remembers the
+// numbering so /delete can target a session without switching into it.
+func (c *ChatLoop) handleList() {
+	sessions, err := c.historyManager.ListSessions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing sessions: %v\n", err)
+		return
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No sessions found.")
+		return
+	}
+
+	opts := table.DefaultPrintOptions()
+	opts.HighlightColumn = 0
+	history.SessionTable(sessions).Print(opts)
+
+	if current := c.historyManager.Current(); current != nil {
+		for i, session := range sessions {
+			if session.ID == current.ID {
+				fmt.Printf("Current session: %d\n", i+1)
+				break
+			}
+		}
+	}
+
+	c.listed = sessions
+}
+
+// handleNew starts a fresh session, optionally pre-seeded from a named
+// template. The current one stays saved on disk and can be returned to
+// with /list and /switch.
+func (c *ChatLoop) handleNew(args string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	if template := strings.TrimSpace(args); template != "" {
+		c.handleNewFromTemplate(template, chatHistory)
+		return
+	}
+	c.newSession()
+	*chatHistory = c.buildChatHistory()
+	c.listed = nil
+	fmt.Println("Started a new session.")
+}
+
+// handleSwitch makes another session the active conversation, resolved by
+// number or name from the last /list. The current session is auto-saved, so
+// nothing is lost by switching away.
+func (c *ChatLoop) handleSwitch(args string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	target := strings.TrimSpace(args)
+	if target == "" {
+		fmt.Println("Usage: /switch <number|name> (run /list to see the numbering)")
+		return
+	}
+
+	session := c.resolveListedSession(target)
+	if session == nil {
+		return
+	}
+	if current := c.historyManager.Current(); current != nil && session.ID == current.ID {
+		fmt.Println("Already in that session.")
+		return
+	}
+
+	loaded, err := c.historyManager.LoadSessionByID(session.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading session: %v\n", err)
+		return
+	}
+	c.historyManager.SetCurrent(loaded)
+	*chatHistory = c.buildChatHistory()
+	fmt.Printf("Switched to session %q (%d message(s)).\n", loaded.Name, len(loaded.Messages))
+}
+
+// handleRename renames the current session.
+func (c *ChatLoop) handleRename(args string) {
+	name := strings.TrimSpace(args)
+	if name == "" {
+		fmt.Println("Usage: /rename <name>")
+		return
+	}
+
+	session := c.historyManager.Current()
+	oldName := session.Name
+	session.Name = name
+	if err := c.historyManager.SaveCurrent(); err != nil {
+		session.Name = oldName
+		fmt.Fprintf(os.Stderr, "Error renaming session: %v\n", err)
+		return
+	}
+	// The remembered /list numbering shows the old name now
+	c.listed = nil
+	fmt.Printf("Session renamed to %q.\n", name)
+}
+
+// handleDelete processes the /delete command. With no argument it deletes the
+// current session after confirmation and starts a fresh one. With a number or
+// name from the last /list it deletes that session without switching, leaving
+// the current conversation untouched.
+func (c *ChatLoop) handleDelete(args string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	target := strings.TrimSpace(args)
+
+	if target == "" {
+		session := c.historyManager.Current()
+		if !confirmPrompt(i18n.T(i18n.ConfirmDeleteCurrent, session.Name)) {
+			fmt.Println("Deletion cancelled.")
+			return
+		}
+		if err := c.historyManager.DeleteSession(session.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error deleting session: %v\n", err)
+			return
+		}
+		c.newSession()
+		*chatHistory = c.buildChatHistory()
+		c.listed = nil
+		fmt.Println("Session deleted; started a new session.")
+		return
+	}
+
+	session := c.resolveListedSession(target)
+	if session == nil {
+		return
+	}
+	if current := c.historyManager.Current(); current != nil && session.ID == current.ID {
+		fmt.Println("That is the current session; use /delete with no argument.")
+		return
+	}
+
+	if !confirmPrompt(i18n.T(i18n.ConfirmDeleteSession, session.Name)) {
+		fmt.Println("Deletion cancelled.")
+		return
+	}
+	if err := c.historyManager.DeleteSession(session.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "Error deleting session: %v\n", err)
+		return
+	}
+
+	// The remembered numbering is stale now; require a fresh /list
+	c.listed = nil
+	fmt.Printf("Session %q deleted.\n", session.Name)
+}
+
+// resolveListedSession resolves a /switch or /delete target against the
+// numbering of the last /list: either a row number or a session name
+// (case-insensitive).
+// It prints a diagnostic and returns nil when the target cannot be resolved.
+func (c *ChatLoop) resolveListedSession(target string) *history.Session {
+	if len(c.listed) == 0 {
+		fmt.Println("Run /list first to pick a session by number or name.")
+		return nil
+	}
+
+	if num, err := strconv.Atoi(target); err == nil {
+		if num < 1 || num > len(c.listed) {
+			fmt.Printf("No session %d in the last /list (1-%d).\n", num, len(c.listed))
+			return nil
+		}
+		return c.listed[num-1]
+	}
+
+	var match *history.Session
+	for _, session := range c.listed {
+		if strings.EqualFold(session.Name, target) {
+			if match != nil {
+				fmt.Printf("Session name %q is ambiguous; use its number from /list.\n", target)
+				return nil
+			}
+			match = session
+		}
+	}
+	if match == nil {
+		fmt.Printf("No session %q in the last /list.\n", target)
+	}
+	return match
+}
+
+// newSession starts a fresh session, first applying a sessions directory
+// change that /reload deferred until the current session ended.
+func (c *ChatLoop) newSession() *history.Session {
+	if c.pendingSessionsDir != "" {
+		if err := c.historyManager.SetSessionsDir(c.pendingSessionsDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error switching sessions directory: %v\n", err)
+		} else {
+			c.pendingSessionsDir = ""
+		}
+	}
+	session := c.historyManager.NewSession()
+	c.hooks.Fire(hooks.EventSessionCreated, hooks.SessionPayload{
+		Event:       hooks.EventSessionCreated,
+		SessionID:   session.ID,
+		SessionName: session.Name,
+	})
+	return session
+}
+
+// confirmPrompt asks a yes/no question on stdin and reports whether the
+// user answered affirmatively.
+func confirmPrompt(prompt string) bool {
+	fmt.Print(prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer := strings.TrimSpace(strings.ToLower(line))
+	return answer == "y" || answer == "yes"
+}
+
+// handleTree shows the fork relationships between sessions as a tree.
+func (c *ChatLoop) handleTree() {
+	sessions, err := c.historyManager.ListSessions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing sessions: %v\n", err)
+		return
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No sessions found.")
+		return
+	}
+
+	fmt.Println()
+	fmt.Print(history.RenderSessionTree(sessions))
+	fmt.Println()
 }
 
 // handleHelp shows available commands.
+// handleWorkspace shows which workspace this run uses and lists the
+// configured ones. Switching live would mean swapping the history manager
+// mid-session; restart with --workspace (or GOPUS_WORKSPACE) instead.
+func (c *ChatLoop) handleWorkspace() {
+	if c.config.ActiveWorkspace == "" {
+		fmt.Println("No workspace active (using history.sessions_dir).")
+	} else {
+		fmt.Printf("Active workspace: %s\n", c.config.ActiveWorkspace)
+	}
+
+	if len(c.config.Workspaces) == 0 {
+		fmt.Println("No workspaces configured; add a workspaces: section to config.yaml.")
+		return
+	}
+
+	fmt.Println("Configured workspaces:")
+	for _, name := range c.config.WorkspaceNames() {
+		ws := c.config.Workspaces[name]
+		marker := "  "
+		if name == c.config.ActiveWorkspace {
+			marker = "* "
+		}
+		line := fmt.Sprintf("%s%s — %s", marker, name, ws.SessionsDir)
+		if ws.Model != "" {
+			line += fmt.Sprintf(" (model: %s)", ws.Model)
+		}
+		fmt.Println(line)
+	}
+	fmt.Println("Switch by restarting with --workspace <name> or GOPUS_WORKSPACE.")
+}
+
 func (c *ChatLoop) handleHelp() {
-	fmt.Println("\n=== Available Commands ===")
-	fmt.Println("/summarize      - Summarize older messages to reduce history size")
-	fmt.Println("/stats          - Show session statistics and summarization info")
-	fmt.Println("/tools          - List available MCP tools")
-	fmt.Println("/servers        - Show connected MCP servers")
-	fmt.Println("/sleep [secs]   - Test animation (default: 3 seconds)")
-	fmt.Println("/help           - Show this help message")
+	fmt.Println("\n" + i18n.T(i18n.HelpHeader))
+	for _, spec := range commandTable {
+		left := "/" + spec.name
+		if spec.usage != "" {
+			left += " " + spec.usage
+		}
+		line := fmt.Sprintf("%-28s - %s", left, i18n.T(spec.help))
+		if len(spec.aliases) > 0 {
+			aliases := make([]string, len(spec.aliases))
+			for i, alias := range spec.aliases {
+				aliases[i] = "/" + alias
+			}
+			line += i18n.T(i18n.HelpAlias, strings.Join(aliases, ", "))
+		}
+		fmt.Println(line)
+	}
+	fmt.Println("\n" + i18n.T(i18n.HelpShellEscape))
 	fmt.Println()
 }
@@ -0,0 +1,83 @@
+package chat
+
+import (
+	"testing"
+
+	"gopus/internal/clipboard"
+	"gopus/internal/history"
+)
+
+func TestExtractCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "no blocks",
+			text: "just some prose",
+			want: "",
+		},
+		{
+			name: "single block",
+			text: "before\n```go\nfmt.Println(\"hi\")\n```\nafter",
+			want: "fmt.Println(\"hi\")",
+		},
+		{
+			name: "two blocks concatenated",
+			text: "```\none\n```\ntext\n```\ntwo\n```",
+			want: "one\n\ntwo",
+		},
+		{
+			name: "unterminated block",
+			text: "```\ndangling",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractCodeBlocks(tt.text); got != tt.want {
+				t.Errorf("extractCodeBlocks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLastAssistantMessage(t *testing.T) {
+	messages := []history.Message{
+		{Role: history.RoleUser, Content: "q1"},
+		{Role: history.RoleAssistant, Content: "a1"},
+		{Role: history.RoleUser, Content: "q2"},
+		{Role: history.RoleAssistant, Content: "a2"},
+		{Role: history.RoleSystem, Content: "note", Type: history.TypeNote},
+	}
+
+	if msg, ok := lastAssistantMessage(messages, 1); !ok || msg.Content != "a2" {
+		t.Errorf("n=1: got %q, ok=%v; want a2", msg.Content, ok)
+	}
+	if msg, ok := lastAssistantMessage(messages, 2); !ok || msg.Content != "a1" {
+		t.Errorf("n=2: got %q, ok=%v; want a1", msg.Content, ok)
+	}
+	if _, ok := lastAssistantMessage(messages, 3); ok {
+		t.Error("n=3: expected no match")
+	}
+}
+
+func TestHandleCopyWritesToClipboard(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused.invalid")
+	fake := &clipboard.Fake{}
+	loop.clipboard = fake
+
+	session := loop.historyManager.Current()
+	session.Messages = []history.Message{
+		{Role: history.RoleUser, Content: "question"},
+		{Role: history.RoleAssistant, Content: "the answer"},
+	}
+
+	loop.handleCopy("")
+
+	if fake.Contents != "the answer" {
+		t.Errorf("clipboard contents = %q, want %q", fake.Contents, "the answer")
+	}
+}
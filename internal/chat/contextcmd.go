@@ -0,0 +1,156 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+)
+
+// messageOverheadTokens approximates the per-message framing cost (role
+// markers and delimiters) the API adds on top of the content.
+const messageOverheadTokens = 4
+
+// contextUsage is a token-estimate breakdown of everything the next
+// request will carry.
+type contextUsage struct {
+	SystemPrompt int // the system prompt entry, if any
+	Summaries    int // summary messages from earlier compaction
+	Messages     int // everything else in the request history
+	Tools        int // serialized tool definitions sent with each request
+}
+
+// Total returns the estimated size of the whole request.
+func (u contextUsage) Total() int {
+	return u.SystemPrompt + u.Summaries + u.Messages + u.Tools
+}
+
+// estimateEntryTokens estimates what one request message costs, including
+// tool calls and their arguments.
+func estimateEntryTokens(msg openai.ChatCompletionRequestMessage) int {
+	tokens := messageOverheadTokens
+	if content, ok := openai.ContentAsString(msg.Content); ok {
+		tokens += openai.EstimateTokens(content)
+	}
+	if msg.ToolCalls != nil {
+		for _, tc := range *msg.ToolCalls {
+			tokens += openai.EstimateTokens(tc.Function.Name) + openai.EstimateTokens(tc.Function.Arguments)
+		}
+	}
+	return tokens
+}
+
+// contextUsageBreakdown walks the request history and categorizes its
+// estimated token cost. Summary tokens are identified via the session,
+// since the request history no longer distinguishes them.
+func (c *ChatLoop) contextUsageBreakdown(ctx context.Context, chatHistory []openai.ChatCompletionRequestMessage) contextUsage {
+	var usage contextUsage
+
+	start := 0
+	if c.activeSystemPrompt() != "" && len(chatHistory) > 0 {
+		usage.SystemPrompt = estimateEntryTokens(chatHistory[0])
+		start = 1
+	}
+	for _, msg := range chatHistory[start:] {
+		usage.Messages += estimateEntryTokens(msg)
+	}
+
+	if session := c.historyManager.Current(); session != nil {
+		for _, msg := range session.Messages {
+			if msg.IsSummary() {
+				summary := openai.EstimateTokens(msg.Content) + messageOverheadTokens
+				usage.Summaries += summary
+				usage.Messages -= summary
+			}
+		}
+	}
+	if usage.Messages < 0 {
+		usage.Messages = 0
+	}
+
+	// Tool definitions ride along with every request, serialized exactly
+	// like the request marshals them
+	if tools := c.getOpenAITools(ctx); len(tools) > 0 {
+		if data, err := json.Marshal(tools); err == nil {
+			usage.Tools = openai.EstimateTokens(string(data))
+		}
+	}
+
+	return usage
+}
+
+// handleContext processes the /context command. Without arguments it
+// prints an estimated token breakdown of the next request; the add, list,
+// and remove subcommands manage the session's context set.
+func (c *ChatLoop) handleContext(ctx context.Context, args string, chatHistory []openai.ChatCompletionRequestMessage) {
+	sub, rest, _ := strings.Cut(args, " ")
+	rest = strings.TrimSpace(rest)
+	switch sub {
+	case "":
+		c.printContextUsage(ctx, chatHistory)
+	case "add":
+		c.handleContextAdd(rest)
+	case "list":
+		c.handleContextList()
+	case "remove":
+		c.handleContextRemove(rest)
+	default:
+		fmt.Println("Usage: /context [add <path|text> | list | remove <n>]")
+	}
+}
+
+// printContextUsage prints an estimated token breakdown of the next
+// request and how close it is to the model's context window.
+func (c *ChatLoop) printContextUsage(ctx context.Context, chatHistory []openai.ChatCompletionRequestMessage) {
+	usage := c.contextUsageBreakdown(ctx, chatHistory)
+
+	fmt.Println("\nContext usage (estimated):")
+	fmt.Printf("  System prompt:    ~%s tokens\n", groupDigits(usage.SystemPrompt))
+	fmt.Printf("  Summaries:        ~%s tokens\n", groupDigits(usage.Summaries))
+	fmt.Printf("  Messages:         ~%s tokens\n", groupDigits(usage.Messages))
+	fmt.Printf("  Tool definitions: ~%s tokens\n", groupDigits(usage.Tools))
+
+	total := usage.Total()
+	window := openai.ContextWindow(c.config.OpenAI.Model)
+	if window > 0 {
+		percent := total * 100 / window
+		fmt.Printf("\n  %s ~%s / %s tokens (%d%%)\n", usageBar(total, window), groupDigits(total), groupDigits(window), percent)
+	} else {
+		fmt.Printf("\n  ~%s tokens (context window unknown for model %s)\n", groupDigits(total), c.config.OpenAI.Model)
+	}
+
+	fmt.Printf("\nGeneration parameters: %s\n", c.effectiveParamsLine())
+
+	sum := c.config.Summarization
+	if sum.Enabled && sum.AutoSummarize {
+		count := 0
+		if session := c.historyManager.Current(); session != nil {
+			for _, msg := range session.Messages {
+				if msg.IsMessage() {
+					count++
+				}
+			}
+		}
+		fmt.Printf("\nAuto-summarize triggers above %d messages (currently %d).\n", sum.AutoThreshold, count)
+	} else {
+		fmt.Println("\nAuto-summarize is disabled.")
+	}
+	fmt.Println()
+}
+
+// usageBar renders a 24-cell bar of used context, clamped at full.
+func usageBar(used, window int) string {
+	const cells = 24
+	filled := used * cells / window
+	if filled > cells {
+		filled = cells
+	}
+	return fmt.Sprintf("%s[%s%s]%s",
+		printer.ColorDim,
+		strings.Repeat("█", filled),
+		strings.Repeat("░", cells-filled),
+		printer.ColorReset)
+}
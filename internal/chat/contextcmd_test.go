@@ -0,0 +1,59 @@
+package chat
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+)
+
+func TestEstimateEntryTokens(t *testing.T) {
+	plain := openai.ChatCompletionRequestMessage{
+		Role:    openai.RoleUser,
+		Content: openai.StringContent(strings.Repeat("x", 40)),
+	}
+	if got := estimateEntryTokens(plain); got != 10+messageOverheadTokens {
+		t.Errorf("expected %d tokens, got %d", 10+messageOverheadTokens, got)
+	}
+
+	toolCalls := []openai.ChatCompletionMessageToolCall{{
+		Id:       "call_1",
+		Type:     openai.ChatCompletionMessageToolCallTypeFunction,
+		Function: openai.ChatCompletionMessageToolCallFunction{Name: "read", Arguments: `{"path":"x"}`},
+	}}
+	withCalls := openai.ChatCompletionRequestMessage{Role: openai.RoleAssistant, ToolCalls: &toolCalls}
+	if got := estimateEntryTokens(withCalls); got <= messageOverheadTokens {
+		t.Errorf("expected tool calls to count, got %d", got)
+	}
+}
+
+func TestContextUsageBreakdown(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	loop.config.Chat.SystemPrompt = strings.Repeat("s", 40)
+
+	session := loop.historyManager.Current()
+	session.Messages = []history.Message{
+		{Role: history.RoleSystem, Content: strings.Repeat("m", 80), Type: history.TypeSummary},
+		{Role: history.RoleUser, Content: strings.Repeat("u", 40)},
+	}
+
+	usage := loop.contextUsageBreakdown(context.Background(), loop.buildChatHistory())
+
+	if usage.SystemPrompt != 10+messageOverheadTokens {
+		t.Errorf("expected system prompt tokens %d, got %d", 10+messageOverheadTokens, usage.SystemPrompt)
+	}
+	if usage.Summaries != 20+messageOverheadTokens {
+		t.Errorf("expected summary tokens %d, got %d", 20+messageOverheadTokens, usage.Summaries)
+	}
+	if usage.Messages != 10+messageOverheadTokens {
+		t.Errorf("expected message tokens %d, got %d", 10+messageOverheadTokens, usage.Messages)
+	}
+	if usage.Tools != 0 {
+		t.Errorf("expected no tool definition tokens without MCP, got %d", usage.Tools)
+	}
+	if usage.Total() != usage.SystemPrompt+usage.Summaries+usage.Messages {
+		t.Errorf("expected the total to sum the categories, got %d", usage.Total())
+	}
+}
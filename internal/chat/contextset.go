@@ -0,0 +1,145 @@
+package chat
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+)
+
+// handleContextAdd registers a file or literal note in the session's
+// context set. Arguments naming an existing file are stored as a path and
+// re-read before every request; anything else is kept as a note verbatim.
+func (c *ChatLoop) handleContextAdd(args string) {
+	if args == "" {
+		fmt.Println("Usage: /context add <path|text>")
+		return
+	}
+
+	entry := history.ContextEntry{Text: args}
+	if info, err := os.Stat(args); err == nil && !info.IsDir() {
+		path := args
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		entry = history.ContextEntry{Path: path}
+	}
+
+	if err := c.historyManager.AddContextEntry(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Error adding context entry: %v\n", err)
+		return
+	}
+
+	if entry.Path != "" {
+		fmt.Printf("Added %s to the context set; its current contents ride along with every request.\n", entry.Path)
+	} else {
+		fmt.Println("Added the note to the context set; it rides along with every request.")
+	}
+}
+
+// handleContextList prints the session's context set with the indexes
+// /context remove expects.
+func (c *ChatLoop) handleContextList() {
+	entries := c.historyManager.ContextEntries()
+	if len(entries) == 0 {
+		fmt.Println("The context set is empty. Add a file or note with /context add <path|text>.")
+		return
+	}
+
+	fmt.Printf("Context set (budget ~%s tokens, oldest dropped first when over):\n", groupDigits(c.config.Chat.ContextBudgetTokens))
+	for i, entry := range entries {
+		fmt.Printf("  %d. %s\n", i+1, entry.Label())
+	}
+}
+
+// handleContextRemove drops one entry from the context set by its
+// /context list index.
+func (c *ChatLoop) handleContextRemove(args string) {
+	index, err := strconv.Atoi(strings.TrimSpace(args))
+	if err != nil {
+		fmt.Println("Usage: /context remove <number> (see /context list)")
+		return
+	}
+
+	entry, err := c.historyManager.RemoveContextEntry(index - 1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error removing context entry: %v\n", err)
+		return
+	}
+	fmt.Printf("Removed %s from the context set.\n", entry.Label())
+}
+
+// contextSetMessages renders the session's context set as labeled system
+// messages for the next request. File entries are re-read so edits on disk
+// are picked up; unreadable files produce a one-time warning and are
+// skipped. Entries are kept within the configured token budget, dropping
+// the oldest first when it overflows.
+func (c *ChatLoop) contextSetMessages() []openai.ChatCompletionRequestMessage {
+	entries := c.historyManager.ContextEntries()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	blocks := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Path == "" {
+			blocks = append(blocks, fmt.Sprintf("Context note (auto-included):\n\n%s", entry.Text))
+			continue
+		}
+		data, err := os.ReadFile(entry.Path)
+		if err != nil {
+			printer.PrintWarning("Context file %s is unreadable and was skipped: %v", entry.Path, err)
+			continue
+		}
+		blocks = append(blocks, fmt.Sprintf("Context file %s (auto-included):\n\n%s", entry.Path, string(data)))
+	}
+
+	// Keep the newest entries that fit the budget, so the oldest are the
+	// ones dropped when the set outgrows it
+	budget := c.config.Chat.ContextBudgetTokens
+	used := 0
+	keepFrom := len(blocks)
+	for i := len(blocks) - 1; i >= 0; i-- {
+		cost := openai.EstimateTokens(blocks[i]) + messageOverheadTokens
+		if used+cost > budget {
+			break
+		}
+		used += cost
+		keepFrom = i
+	}
+	if dropped := keepFrom; dropped > 0 {
+		printer.PrintWarning("Context set over its ~%d token budget; the oldest %d entries were dropped (see /context list)", budget, dropped)
+	}
+
+	messages := make([]openai.ChatCompletionRequestMessage, 0, len(blocks)-keepFrom)
+	for _, block := range blocks[keepFrom:] {
+		messages = append(messages, openai.ChatCompletionRequestMessage{
+			Role:    openai.RoleSystem,
+			Content: openai.StringContent(block),
+		})
+	}
+	return messages
+}
+
+// withContextSet returns the request messages with the context set
+// inserted after the leading system prompt, if any. Like the recall block,
+// the entries are rebuilt per turn and never persisted into the session
+// history, so summarization cannot consume them.
+func withContextSet(messages, contextSet []openai.ChatCompletionRequestMessage) []openai.ChatCompletionRequestMessage {
+	if len(contextSet) == 0 {
+		return messages
+	}
+	pos := 0
+	if len(messages) > 0 && messages[0].Role == openai.RoleSystem {
+		pos = 1
+	}
+	out := make([]openai.ChatCompletionRequestMessage, 0, len(messages)+len(contextSet))
+	out = append(out, messages[:pos]...)
+	out = append(out, contextSet...)
+	return append(out, messages[pos:]...)
+}
@@ -0,0 +1,161 @@
+package chat
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopus/internal/openai"
+)
+
+func messageText(t *testing.T, msg openai.ChatCompletionRequestMessage) string {
+	t.Helper()
+	content, ok := openai.ContentAsString(msg.Content)
+	if !ok {
+		t.Fatalf("message content is not a string: %+v", msg.Content)
+	}
+	return content
+}
+
+func TestHandleContextAddListRemove(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	c.config.Chat.ContextBudgetTokens = 2000
+
+	file := filepath.Join(t.TempDir(), "README.md")
+	if err := os.WriteFile(file, []byte("# Project"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	c.handleContextAdd(file)
+	c.handleContextAdd("The API lives under /v2.")
+
+	entries := c.historyManager.ContextEntries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Path != file || entries[0].Text != "" {
+		t.Errorf("expected a path entry first, got %+v", entries[0])
+	}
+	if entries[1].Text != "The API lives under /v2." || entries[1].Path != "" {
+		t.Errorf("expected a note entry second, got %+v", entries[1])
+	}
+
+	c.handleContextRemove("1")
+	entries = c.historyManager.ContextEntries()
+	if len(entries) != 1 || entries[0].Path != "" {
+		t.Errorf("expected only the note to remain, got %+v", entries)
+	}
+
+	// The set lives on the session, so it survives a reload
+	session := c.historyManager.Current()
+	if err := c.historyManager.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	loaded, err := c.historyManager.LoadSessionByID(session.ID)
+	if err != nil {
+		t.Fatalf("failed to load session: %v", err)
+	}
+	if len(loaded.ContextSet) != 1 || loaded.ContextSet[0].Text == "" {
+		t.Errorf("context set lost on reload: %+v", loaded.ContextSet)
+	}
+}
+
+func TestContextSetMessagesRereadsFiles(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	c.config.Chat.ContextBudgetTokens = 2000
+
+	file := filepath.Join(t.TempDir(), "notes.md")
+	if err := os.WriteFile(file, []byte("first version"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	c.handleContextAdd(file)
+	c.handleContextAdd("a literal note")
+
+	messages := c.contextSetMessages()
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 context messages, got %d", len(messages))
+	}
+	for _, msg := range messages {
+		if msg.Role != openai.RoleSystem {
+			t.Errorf("expected a system message, got role %s", msg.Role)
+		}
+	}
+	if got := messageText(t, messages[0]); !strings.Contains(got, file) || !strings.Contains(got, "first version") {
+		t.Errorf("unexpected file block: %q", got)
+	}
+	if got := messageText(t, messages[1]); !strings.Contains(got, "Context note") || !strings.Contains(got, "a literal note") {
+		t.Errorf("unexpected note block: %q", got)
+	}
+
+	// Edits on disk are picked up on the next turn
+	if err := os.WriteFile(file, []byte("second version"), 0644); err != nil {
+		t.Fatalf("failed to rewrite file: %v", err)
+	}
+	messages = c.contextSetMessages()
+	if got := messageText(t, messages[0]); !strings.Contains(got, "second version") {
+		t.Errorf("file edit not picked up: %q", got)
+	}
+}
+
+func TestContextSetBudgetDropsOldestFirst(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	c.config.Chat.ContextBudgetTokens = 40
+
+	c.handleContextAdd("oldest " + strings.Repeat("x", 400))
+	c.handleContextAdd("newest note")
+
+	messages := c.contextSetMessages()
+	if len(messages) != 1 {
+		t.Fatalf("expected only the newest entry to fit, got %d messages", len(messages))
+	}
+	if got := messageText(t, messages[0]); !strings.Contains(got, "newest note") {
+		t.Errorf("expected the newest entry to survive, got %q", got)
+	}
+}
+
+func TestContextSetSkipsMissingFiles(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	c.config.Chat.ContextBudgetTokens = 2000
+
+	file := filepath.Join(t.TempDir(), "gone.md")
+	if err := os.WriteFile(file, []byte("soon gone"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	c.handleContextAdd(file)
+	c.handleContextAdd("still here")
+	if err := os.Remove(file); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+
+	messages := c.contextSetMessages()
+	if len(messages) != 1 {
+		t.Fatalf("expected the missing file to be skipped, got %d messages", len(messages))
+	}
+	if got := messageText(t, messages[0]); !strings.Contains(got, "still here") {
+		t.Errorf("unexpected surviving block: %q", got)
+	}
+}
+
+func TestWithContextSetInsertsAfterSystemPrompt(t *testing.T) {
+	system := openai.ChatCompletionRequestMessage{Role: openai.RoleSystem, Content: openai.StringContent("prompt")}
+	user := openai.ChatCompletionRequestMessage{Role: openai.RoleUser, Content: openai.StringContent("hi")}
+	extra := openai.ChatCompletionRequestMessage{Role: openai.RoleSystem, Content: openai.StringContent("context")}
+
+	original := []openai.ChatCompletionRequestMessage{system, user}
+	out := withContextSet(original, []openai.ChatCompletionRequestMessage{extra})
+
+	if len(out) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(out))
+	}
+	if got := messageText(t, out[1]); got != "context" {
+		t.Errorf("context message not inserted after the system prompt: %q", got)
+	}
+	if len(original) != 2 {
+		t.Errorf("input slice must not be modified")
+	}
+
+	if got := withContextSet(original, nil); len(got) != 2 {
+		t.Errorf("an empty context set must return the input unchanged")
+	}
+}
@@ -0,0 +1,114 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+	"gopus/internal/spinner"
+)
+
+// continueInstruction asks the model to resume a reply cut off by the
+// max_tokens limit. It is sent as a transient user message and never stored:
+// the continuation is merged into the truncated assistant message.
+const continueInstruction = "Continue your previous response exactly where it left off. Do not repeat anything you already wrote."
+
+// printFinishReasonNotice warns about finish reasons that need the user's
+// attention: truncation by max_tokens and provider content filtering.
+func printFinishReasonNotice(finishReason string) {
+	switch finishReason {
+	case string(openai.Length):
+		fmt.Printf("%s[response truncated by max_tokens — /continue resumes it]%s\n", printer.ColorDim, printer.ColorReset)
+	case string(openai.ContentFilter):
+		fmt.Printf("%s[response stopped by the provider's content filter; rephrasing the request may help]%s\n", printer.ColorYellow, printer.ColorReset)
+	}
+}
+
+// handleContinue processes the /continue command: it requests the rest of a
+// response truncated by max_tokens and appends it to the same assistant
+// message, in both the session history and the API history, so the reply
+// stays a single bubble.
+func (c *ChatLoop) handleContinue(ctx context.Context, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	session := c.historyManager.Current()
+	if session == nil || len(session.Messages) == 0 {
+		fmt.Println("Nothing to continue yet.")
+		return
+	}
+
+	last := session.Messages[len(session.Messages)-1]
+	if last.Role != history.RoleAssistant {
+		fmt.Println("The last message is not an assistant response; nothing to continue.")
+		return
+	}
+	if last.Meta == nil || last.Meta.FinishReason != string(openai.Length) {
+		fmt.Println("The last response was not truncated by max_tokens; nothing to continue.")
+		return
+	}
+
+	// Append the instruction to a copy of the API history so it does not
+	// linger as an extra user turn in subsequent requests.
+	request := make([]openai.ChatCompletionRequestMessage, 0, len(*chatHistory)+1)
+	request = append(request, *chatHistory...)
+	request = append(request, openai.ChatCompletionRequestMessage{
+		Role:    openai.RoleUser,
+		Content: openai.StringContent(continueInstruction),
+	})
+
+	start := time.Now()
+	resp, err := spinner.Run("continuing…", func() (*openai.ChatCompletionResponse, error) {
+		return c.client.ChatCompletionWithOptions(ctx, request, nil, c.requestOptions())
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error continuing response: %v\n", err)
+		return
+	}
+
+	choice, err := openai.ExtractFirstChoice(resp)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error continuing response: %v\n", err)
+		return
+	}
+	if choice.Message.Content == nil || *choice.Message.Content == "" {
+		fmt.Println("The model returned no continuation.")
+		return
+	}
+	continuation := *choice.Message.Content
+
+	printer.PrintWrapped(string(history.RoleAssistant), continuation, printer.DefaultWrapOptions())
+	fmt.Println()
+
+	meta := &history.Meta{
+		Model:      resp.Model,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if resp.Usage != nil {
+		meta.PromptTokens = resp.Usage.PromptTokens
+		meta.CompletionTokens = resp.Usage.CompletionTokens
+	}
+	c.recordSpend(resp)
+	if choice.FinishReason != nil {
+		meta.FinishReason = string(*choice.FinishReason)
+	}
+
+	if err := c.historyManager.ExtendLastAssistant(continuation, meta); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving continuation: %v\n", err)
+	}
+
+	// Merge into the trailing assistant entry of the API history as well,
+	// so the model sees one coherent reply on the next turn.
+	if n := len(*chatHistory); n > 0 {
+		entry := &(*chatHistory)[n-1]
+		if entry.Role == openai.RoleAssistant {
+			if existing, ok := openai.ContentAsString(entry.Content); ok {
+				entry.Content = openai.StringContent(existing + continuation)
+			}
+		}
+	}
+
+	// The continuation itself may have been truncated again
+	printFinishReasonNotice(meta.FinishReason)
+}
@@ -0,0 +1,64 @@
+package chat
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+)
+
+func TestHandleContinueMergesTruncatedResponse(t *testing.T) {
+	srv := scriptedServer(t, []scriptedResponse{
+		{http.StatusOK, completionBody(`" world"`, "stop")},
+	})
+	defer srv.Close()
+
+	loop := newTestChatLoop(t, srv.URL)
+	if err := loop.historyManager.AddMessageWithMeta(history.RoleAssistant, "Hello", &history.Meta{FinishReason: string(openai.Length)}); err != nil {
+		t.Fatalf("failed to seed history: %v", err)
+	}
+
+	chatHistory := []openai.ChatCompletionRequestMessage{
+		{Role: openai.RoleAssistant, Content: openai.StringContent("Hello")},
+	}
+	loop.handleContinue(context.Background(), &chatHistory)
+
+	session := loop.historyManager.Current()
+	last := session.Messages[len(session.Messages)-1]
+	if last.Content != "Hello world" {
+		t.Errorf("expected merged content %q, got %q", "Hello world", last.Content)
+	}
+	if last.Meta == nil || last.Meta.FinishReason != "stop" {
+		t.Errorf("expected finish reason updated to stop, got %+v", last.Meta)
+	}
+
+	if len(chatHistory) != 1 {
+		t.Fatalf("expected the continuation merged into the existing entry, got %d entries", len(chatHistory))
+	}
+	if content, _ := openai.ContentAsString(chatHistory[0].Content); content != "Hello world" {
+		t.Errorf("expected merged API history content %q, got %q", "Hello world", content)
+	}
+}
+
+func TestHandleContinueRequiresTruncation(t *testing.T) {
+	// No scripted responses: any request would fail the test
+	srv := scriptedServer(t, nil)
+	defer srv.Close()
+
+	loop := newTestChatLoop(t, srv.URL)
+	if err := loop.historyManager.AddMessageWithMeta(history.RoleAssistant, "Done.", &history.Meta{FinishReason: "stop"}); err != nil {
+		t.Fatalf("failed to seed history: %v", err)
+	}
+
+	chatHistory := []openai.ChatCompletionRequestMessage{
+		{Role: openai.RoleAssistant, Content: openai.StringContent("Done.")},
+	}
+	loop.handleContinue(context.Background(), &chatHistory)
+
+	session := loop.historyManager.Current()
+	if got := session.Messages[len(session.Messages)-1].Content; got != "Done." {
+		t.Errorf("expected the message to be left untouched, got %q", got)
+	}
+}
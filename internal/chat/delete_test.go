@@ -0,0 +1,75 @@
+package chat
+
+import (
+	"testing"
+
+	"gopus/internal/history"
+)
+
+// listTestSessions saves extra sessions and primes the /list numbering the
+// way handleList does, returning them oldest-numbered first.
+func listTestSessions(t *testing.T, loop *ChatLoop, names ...string) []*history.Session {
+	t.Helper()
+
+	current := loop.historyManager.Current()
+	sessions := []*history.Session{current}
+	for _, name := range names {
+		session := loop.historyManager.NewSession()
+		session.Name = name
+		if err := loop.historyManager.Save(session); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		sessions = append(sessions, session)
+	}
+	loop.historyManager.SetCurrent(current)
+	loop.listed = sessions
+	return sessions
+}
+
+func TestResolveListedSessionByNumberAndName(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	sessions := listTestSessions(t, loop, "api design", "deploy notes")
+
+	if got := loop.resolveListedSession("2"); got == nil || got.ID != sessions[1].ID {
+		t.Errorf("expected number 2 to resolve to %q", sessions[1].Name)
+	}
+	if got := loop.resolveListedSession("Deploy Notes"); got == nil || got.ID != sessions[2].ID {
+		t.Error("expected case-insensitive name match")
+	}
+	if got := loop.resolveListedSession("9"); got != nil {
+		t.Errorf("expected out-of-range number to fail, got %q", got.Name)
+	}
+	if got := loop.resolveListedSession("no such"); got != nil {
+		t.Errorf("expected unknown name to fail, got %q", got.Name)
+	}
+}
+
+func TestResolveListedSessionRequiresList(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+
+	if got := loop.resolveListedSession("1"); got != nil {
+		t.Error("expected resolution to fail before /list has run")
+	}
+}
+
+func TestResolveListedSessionAmbiguousName(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	listTestSessions(t, loop, "notes", "notes")
+
+	if got := loop.resolveListedSession("notes"); got != nil {
+		t.Errorf("expected ambiguous name to fail, got session %s", got.ID)
+	}
+}
+
+func TestDeleteOtherSessionKeepsCurrent(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	sessions := listTestSessions(t, loop, "doomed")
+
+	// Deleting a non-current session must not clear the active one
+	if err := loop.historyManager.DeleteSession(sessions[1].ID); err != nil {
+		t.Fatalf("DeleteSession: %v", err)
+	}
+	if current := loop.historyManager.Current(); current == nil || current.ID != sessions[0].ID {
+		t.Error("expected current session untouched after deleting another")
+	}
+}
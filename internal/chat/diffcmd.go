@@ -0,0 +1,170 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"gopus/internal/diff"
+	"gopus/internal/history"
+	"gopus/internal/printer"
+)
+
+// autoDiffThreshold is how similar a reply must be to the previous one
+// before chat.auto_diff shows the changes automatically.
+const autoDiffThreshold = 0.8
+
+// handleDiff processes the /diff command: it diffs the last two assistant
+// replies, or the ones numbered N and M (oldest reply is 1).
+func (c *ChatLoop) handleDiff(args string) {
+	session := c.historyManager.Current()
+	var replies []string
+	for _, msg := range session.Messages {
+		if msg.Role == history.RoleAssistant && msg.IsMessage() {
+			replies = append(replies, msg.Content)
+		}
+	}
+
+	n, m := len(replies)-1, len(replies)
+	if fields := strings.Fields(args); len(fields) > 0 {
+		if len(fields) != 2 {
+			fmt.Println("Usage: /diff [N M] (assistant reply numbers, oldest is 1)")
+			return
+		}
+		if _, err := fmt.Sscanf(args, "%d %d", &n, &m); err != nil {
+			fmt.Println("Usage: /diff [N M] (assistant reply numbers, oldest is 1)")
+			return
+		}
+	}
+	if len(replies) < 2 {
+		fmt.Println("Need at least two assistant replies to diff.")
+		return
+	}
+	if n < 1 || m < 1 || n > len(replies) || m > len(replies) {
+		fmt.Printf("Reply numbers must be between 1 and %d.\n", len(replies))
+		return
+	}
+
+	fmt.Printf("\nDiff of assistant replies %d and %d:\n", n, m)
+	fmt.Print(renderDiff(replies[n-1], replies[m-1]))
+	fmt.Println()
+}
+
+// renderDiff diffs two texts and renders the result with colors. When
+// both sides consist of a single fenced code block, only the fenced
+// content is compared, so unchanged prose around it stays out of the way.
+func renderDiff(older, newer string) string {
+	if fencedOld, ok := fencedContent(older); ok {
+		if fencedNew, ok := fencedContent(newer); ok {
+			older, newer = fencedOld, fencedNew
+		}
+	}
+
+	edits := diff.Lines(older, newer)
+	changed := false
+	for _, e := range edits {
+		if e.Op != diff.OpEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return "No changes.\n"
+	}
+	return renderLineEdits(edits)
+}
+
+// fencedContent returns the content of the text's code fence when the
+// text contains exactly one fenced block.
+func fencedContent(text string) (string, bool) {
+	lines := strings.Split(text, "\n")
+	var fences []int
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fences = append(fences, i)
+		}
+	}
+	if len(fences) != 2 {
+		return "", false
+	}
+	return strings.Join(lines[fences[0]+1:fences[1]], "\n"), true
+}
+
+// renderLineEdits renders a line diff, pairing equally sized runs of
+// removed and added lines so the changed words inside them can be
+// highlighted individually.
+func renderLineEdits(edits []diff.Edit) string {
+	var b strings.Builder
+	for i := 0; i < len(edits); {
+		if edits[i].Op == diff.OpEqual {
+			fmt.Fprintf(&b, "  %s\n", edits[i].Text)
+			i++
+			continue
+		}
+		var removed, added []string
+		for ; i < len(edits) && edits[i].Op == diff.OpDelete; i++ {
+			removed = append(removed, edits[i].Text)
+		}
+		for ; i < len(edits) && edits[i].Op == diff.OpInsert; i++ {
+			added = append(added, edits[i].Text)
+		}
+		if len(removed) == len(added) {
+			for j := range removed {
+				words := diff.Words(removed[j], added[j])
+				b.WriteString(renderWordEdits(words, diff.OpDelete, "-", printer.ColorRed))
+				b.WriteString(renderWordEdits(words, diff.OpInsert, "+", printer.ColorGreen))
+			}
+			continue
+		}
+		for _, line := range removed {
+			fmt.Fprintf(&b, "%s- %s%s\n", printer.ColorRed, line, printer.ColorReset)
+		}
+		for _, line := range added {
+			fmt.Fprintf(&b, "%s+ %s%s\n", printer.ColorGreen, line, printer.ColorReset)
+		}
+	}
+	return b.String()
+}
+
+// renderWordEdits renders one side of a word diff: equal words plain,
+// this side's changed words in color, the other side's words skipped.
+func renderWordEdits(words []diff.Edit, side diff.Op, marker, color string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s%s ", color, marker, printer.ColorReset)
+	for _, w := range words {
+		switch w.Op {
+		case diff.OpEqual:
+			b.WriteString(w.Text)
+		case side:
+			b.WriteString(color)
+			b.WriteString(w.Text)
+			b.WriteString(printer.ColorReset)
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// maybeShowDiff prints the diff against the previous assistant reply when
+// chat.auto_diff is on and the new reply mostly repeats it — the "change
+// the timeout and reprint the block" case.
+func (c *ChatLoop) maybeShowDiff(reply string) {
+	if !c.config.Chat.AutoDiff {
+		return
+	}
+	session := c.historyManager.Current()
+	if session == nil {
+		return
+	}
+	for i := len(session.Messages) - 1; i >= 0; i-- {
+		msg := session.Messages[i]
+		if msg.Role != history.RoleAssistant || !msg.IsMessage() {
+			continue
+		}
+		if msg.Content != reply && diff.Similarity(msg.Content, reply) >= autoDiffThreshold {
+			fmt.Println("Changes from the previous reply (/diff):")
+			fmt.Print(renderDiff(msg.Content, reply))
+			fmt.Println()
+		}
+		return
+	}
+}
@@ -0,0 +1,50 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFencedContent(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   string
+		fenced bool
+	}{
+		{"single block", "Here you go:\n```go\nx := 1\n```\nDone.", "x := 1", true},
+		{"no fences", "just prose", "", false},
+		{"two blocks", "```\na\n```\nand\n```\nb\n```", "", false},
+		{"unclosed fence", "```go\nx := 1", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := fencedContent(tt.text)
+			if ok != tt.fenced || got != tt.want {
+				t.Errorf("fencedContent(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.fenced)
+			}
+		})
+	}
+}
+
+func TestRenderDiff(t *testing.T) {
+	older := "Here:\n```go\ntimeout := 10 * time.Second\nreturn timeout\n```"
+	newer := "Here:\n```go\ntimeout := 30 * time.Second\nreturn timeout\n```"
+
+	out := renderDiff(older, newer)
+	if !strings.Contains(out, "- timeout := 10 * time.Second") {
+		t.Errorf("expected the removed line, got %q", out)
+	}
+	if !strings.Contains(out, "+ timeout := 30 * time.Second") {
+		t.Errorf("expected the added line, got %q", out)
+	}
+	// Both sides are single fenced blocks, so the prose stays out
+	if strings.Contains(out, "Here:") {
+		t.Errorf("expected only the fenced content to be diffed, got %q", out)
+	}
+
+	if out := renderDiff("same", "same"); !strings.Contains(out, "No changes.") {
+		t.Errorf("expected no changes, got %q", out)
+	}
+}
@@ -0,0 +1,305 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gopus/internal/i18n"
+	"gopus/internal/openai"
+	"gopus/internal/version"
+)
+
+// commandHandler runs one slash command with its parsed arguments.
+type commandHandler func(c *ChatLoop, ctx context.Context, args string, chatHistory *[]openai.ChatCompletionRequestMessage)
+
+// commandSpec describes one slash command: the canonical name, declared
+// short aliases, the argument hint /help shows, the catalog key of its
+// description, and the handler. The table below is the single source of
+// truth for dispatch and help output.
+type commandSpec struct {
+	name    string
+	aliases []string
+	usage   string
+	help    i18n.Key
+	run     commandHandler
+}
+
+// commandTable drives dispatch, alias resolution, and /help, in help
+// display order. It is filled by init to break the initialization cycle
+// with handleHelp, which ranges over it.
+var commandTable []commandSpec
+
+func init() {
+	commandTable = []commandSpec{
+		{name: "summarize", aliases: []string{"s"}, help: i18n.HelpCmdSummarize,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleSummarize(ctx, ch)
+			}},
+		{name: "diff", usage: "[N M]",
+			help: i18n.HelpCmdDiff,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleDiff(args)
+			}},
+		{name: "stats", usage: "[--all]",
+			help: i18n.HelpCmdStats,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleStats(args)
+			}},
+		{name: "context", usage: "[add <path|text> | list | remove <n>]",
+			help: i18n.HelpCmdContext,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleContext(ctx, args, *ch)
+			}},
+		{name: "flush", help: i18n.HelpCmdFlush,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleFlush(ctx, ch)
+			}},
+		{name: "budget", help: i18n.HelpCmdBudget,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleBudget()
+			}},
+		{name: "note", usage: "<text>", help: i18n.HelpCmdNote,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleNote(args)
+			}},
+		{name: "remember", usage: "<fact>", help: i18n.HelpCmdRemember,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleRemember(args)
+			}},
+		{name: "memory", help: i18n.HelpCmdMemory,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleMemory()
+			}},
+		{name: "forget", usage: "<n>", help: i18n.HelpCmdForget,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleForget(args)
+			}},
+		{name: "pin-fact", usage: "[text]", help: i18n.HelpCmdPinFact,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handlePinFact(args)
+			}},
+		{name: "continue", help: i18n.HelpCmdContinue,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleContinue(ctx, ch)
+			}},
+		{name: "pin", help: i18n.HelpCmdPin,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handlePin()
+			}},
+		{name: "image", usage: "<path> [prompt]", help: i18n.HelpCmdImage,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleImage(ctx, args, ch)
+			}},
+		{name: "copy", usage: "[N|code]", help: i18n.HelpCmdCopy,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleCopy(args)
+			}},
+		{name: "clip", usage: "[prefix]", help: i18n.HelpCmdClip,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleClip(ctx, args, ch)
+			}},
+		{name: "timestamps", usage: "[on|off]", help: i18n.HelpCmdTimestamps,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleTimestamps(args)
+			}},
+		{name: "tools", aliases: []string{"t"}, usage: "[name]", help: i18n.HelpCmdTools,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleTools(args)
+			}},
+		{name: "call", usage: "<tool> [--share] <json|key=value ...>", help: i18n.HelpCmdCall,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleCall(ctx, args, ch)
+			}},
+		{name: "servers", help: i18n.HelpCmdServers,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleServers()
+			}},
+		{name: "mcp", help: i18n.HelpCmdMCP,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleMCP(ctx, args)
+			}},
+		{name: "fork", usage: "[@N] [name]", help: i18n.HelpCmdFork,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleFork(args, ch)
+			}},
+		{name: "system", usage: "[set <text>|reset]", help: i18n.HelpCmdSystem,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleSystem(args, ch)
+			}},
+		{name: "max-tokens", usage: "[n|reset]", help: i18n.HelpCmdMaxTokens,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleMaxTokens(args)
+			}},
+		{name: "params", usage: "[name <value|reset>]", help: i18n.HelpCmdParams,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleParams(args)
+			}},
+		{name: "json", usage: "[on|off|schema <file>]", help: i18n.HelpCmdJSON,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleJSON(args)
+			}},
+		{name: "checkpoint", usage: "[label]", help: i18n.HelpCmdCheckpoint,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleCheckpoint(args)
+			}},
+		{name: "checkpoints", help: i18n.HelpCmdCheckpoints,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleCheckpoints()
+			}},
+		{name: "rollback", usage: "<label|number>", help: i18n.HelpCmdRollback,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleRollback(args, ch)
+			}},
+		{name: "list", help: i18n.HelpCmdList,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleList()
+			}},
+		{name: "new", usage: "[template]", help: i18n.HelpCmdNew,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleNew(args, ch)
+			}},
+		{name: "templates", help: i18n.HelpCmdTemplates,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleTemplates()
+			}},
+		{name: "switch", usage: "<name|number>", help: i18n.HelpCmdSwitch,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleSwitch(args, ch)
+			}},
+		{name: "rename", usage: "<name>", help: i18n.HelpCmdRename,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleRename(args)
+			}},
+		{name: "delete", usage: "[name|number]", help: i18n.HelpCmdDelete,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleDelete(args, ch)
+			}},
+		{name: "save", help: i18n.HelpCmdSave,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleSave()
+			}},
+		{name: "info", help: i18n.HelpCmdInfo,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleInfo()
+			}},
+		{name: "tree", help: i18n.HelpCmdTree,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleTree()
+			}},
+		{name: "reload", help: i18n.HelpCmdReload,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleReload(ctx)
+			}},
+		{name: "quit", aliases: []string{"q", "exit"}, usage: "[--force]", help: i18n.HelpCmdQuit,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.quitting = c.confirmExit(args)
+			}},
+		{name: "sleep", usage: "[secs]", help: i18n.HelpCmdSleep,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleSleep(args)
+			}},
+		{name: "version", help: i18n.HelpCmdVersion,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				fmt.Printf("gopus %s\n", version.String())
+			}},
+		{name: "workspace", help: i18n.HelpCmdWorkspace,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleWorkspace()
+			}},
+		{name: "help", aliases: []string{"?"}, help: i18n.HelpCmdHelp,
+			run: func(c *ChatLoop, ctx context.Context, args string, ch *[]openai.ChatCompletionRequestMessage) {
+				c.handleHelp()
+			}},
+	}
+}
+
+// resolveCommand finds the spec for cmd: an exact canonical or alias match
+// first, then an unambiguous prefix of a canonical name. A nil spec with a
+// non-empty candidate list means the prefix was ambiguous.
+func resolveCommand(cmd string) (*commandSpec, []string) {
+	if cmd == "" {
+		return nil, nil
+	}
+
+	for i := range commandTable {
+		spec := &commandTable[i]
+		if spec.name == cmd {
+			return spec, nil
+		}
+		for _, alias := range spec.aliases {
+			if alias == cmd {
+				return spec, nil
+			}
+		}
+	}
+
+	var matches []*commandSpec
+	for i := range commandTable {
+		if strings.HasPrefix(commandTable[i].name, cmd) {
+			matches = append(matches, &commandTable[i])
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+
+	candidates := make([]string, len(matches))
+	for i, spec := range matches {
+		candidates[i] = "/" + spec.name
+	}
+	return nil, candidates
+}
+
+// nearestCommand returns the canonical command closest to cmd by edit
+// distance, or "" when nothing is within distance 2.
+func nearestCommand(cmd string) string {
+	best, bestDist := "", 3
+	for _, spec := range commandTable {
+		if d := editDistance(cmd, spec.name); d < bestDist {
+			best, bestDist = spec.name, d
+		}
+	}
+	return best
+}
+
+// editDistance computes the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr := make([]int, len(rb)+1)
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, min(curr[j-1]+1, prev[j-1]+cost))
+		}
+		prev = curr
+	}
+	return prev[len(rb)]
+}
+
+// expandAlias substitutes a user-defined alias from chat.aliases for the
+// first token of input, keeping any extra arguments. Aliases expand once;
+// an alias cannot reference another alias.
+func (c *ChatLoop) expandAlias(input string) string {
+	if len(c.config.Chat.Aliases) == 0 {
+		return input
+	}
+	head, rest, _ := strings.Cut(input, " ")
+	replacement, ok := c.config.Chat.Aliases[head]
+	if !ok {
+		return input
+	}
+	if rest != "" {
+		return replacement + " " + rest
+	}
+	return replacement
+}
@@ -0,0 +1,125 @@
+package chat
+
+import (
+	"testing"
+)
+
+func TestResolveCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  string
+		want string // resolved canonical name, "" for no match
+	}{
+		{"exact", "summarize", "summarize"},
+		{"declared alias", "s", "summarize"},
+		{"tools alias", "t", "tools"},
+		{"help alias", "?", "help"},
+		{"quit alias", "q", "quit"},
+		{"exit alias", "exit", "quit"},
+		{"unambiguous prefix", "sum", "summarize"},
+		{"prefix of one", "worksp", "workspace"},
+		{"unknown", "frobnicate", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, _ := resolveCommand(tt.cmd)
+			got := ""
+			if spec != nil {
+				got = spec.name
+			}
+			if got != tt.want {
+				t.Errorf("resolveCommand(%q) = %q, want %q", tt.cmd, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveCommandAmbiguousPrefix(t *testing.T) {
+	spec, candidates := resolveCommand("c")
+	if spec != nil {
+		t.Fatalf("expected no resolution for an ambiguous prefix, got %q", spec.name)
+	}
+	if len(candidates) < 2 {
+		t.Errorf("expected multiple candidates for prefix \"c\", got %v", candidates)
+	}
+
+	// "checkpoint" is both a command and a prefix of "checkpoints";
+	// the exact match must win
+	spec, _ = resolveCommand("checkpoint")
+	if spec == nil || spec.name != "checkpoint" {
+		t.Errorf("expected the exact match to win over the prefix, got %v", spec)
+	}
+}
+
+func TestNearestCommand(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{"serbers", "servers"},
+		{"summarise", "summarize"},
+		{"helo", "help"},
+		{"zzzzzzzz", ""},
+	}
+	for _, tt := range tests {
+		if got := nearestCommand(tt.cmd); got != tt.want {
+			t.Errorf("nearestCommand(%q) = %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "abc", 0},
+		{"abc", "abd", 1},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestExpandAlias(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	loop.config.Chat.Aliases = map[string]string{
+		"/gs": "/call run_command command=git-status",
+	}
+
+	if got := loop.expandAlias("/gs"); got != "/call run_command command=git-status" {
+		t.Errorf("expected the alias to expand, got %q", got)
+	}
+	if got := loop.expandAlias("/gs --share"); got != "/call run_command command=git-status --share" {
+		t.Errorf("expected extra arguments appended, got %q", got)
+	}
+	if got := loop.expandAlias("/stats"); got != "/stats" {
+		t.Errorf("expected non-aliases to pass through, got %q", got)
+	}
+}
+
+func TestHelpCoversCommandTable(t *testing.T) {
+	// The table is the single source of truth; every entry needs help text
+	seen := make(map[string]bool)
+	for _, spec := range commandTable {
+		if spec.name == "" || spec.help == "" {
+			t.Errorf("command %+v is missing a name or help text", spec)
+		}
+		if seen[spec.name] {
+			t.Errorf("duplicate command name %q", spec.name)
+		}
+		seen[spec.name] = true
+		for _, alias := range spec.aliases {
+			if seen[alias] {
+				t.Errorf("alias %q collides with another command or alias", alias)
+			}
+			seen[alias] = true
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package chat
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// draftWriteDebounce coalesces rapid draft updates into one write.
+	draftWriteDebounce = 500 * time.Millisecond
+
+	// draftMaxAge is how long an abandoned draft survives before the
+	// startup cleanup removes it.
+	draftMaxAge = 7 * 24 * time.Hour
+)
+
+// draftFile persists the in-progress input for one session to
+// .gopus/draft-<session>.txt so a crash mid-turn does not lose it. Writes
+// are debounced; the file is removed once the input is handled. Without a
+// line editor the buffer can only be captured when a line is submitted, not
+// per keystroke; a future readline integration can call Save more often.
+type draftFile struct {
+	mu      sync.Mutex
+	path    string
+	pending string
+	dirty   bool
+	timer   *time.Timer
+}
+
+// draftPath returns the draft file path for a session inside dir
+// (the parent of the sessions directory).
+func draftPath(dir, sessionID string) string {
+	return filepath.Join(dir, "draft-"+sessionID+".txt")
+}
+
+// newDraftFile creates a draft store writing to the given path.
+func newDraftFile(path string) *draftFile {
+	return &draftFile{path: path}
+}
+
+// Save records content as the current draft and schedules a debounced write.
+func (d *draftFile) Save(content string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.pending = content
+	d.dirty = true
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.timer = time.AfterFunc(draftWriteDebounce, d.Flush)
+}
+
+// Flush writes any pending draft to disk immediately.
+func (d *draftFile) Flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if !d.dirty {
+		return
+	}
+	if err := os.WriteFile(d.path, []byte(d.pending), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save draft: %v\n", err)
+		return
+	}
+	d.dirty = false
+}
+
+// Clear drops any pending draft and removes the file.
+func (d *draftFile) Clear() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.pending = ""
+	d.dirty = false
+	if err := os.Remove(d.path); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove draft: %v\n", err)
+	}
+}
+
+// PendingWork reports a draft that has not reached disk yet.
+func (d *draftFile) PendingWork() (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.dirty {
+		return "", false
+	}
+	return "an unsaved input draft", true
+}
+
+// Load returns the saved draft, if one exists and is non-empty.
+func (d *draftFile) Load() (string, bool) {
+	data, err := os.ReadFile(d.path)
+	if err != nil {
+		return "", false
+	}
+	content := strings.TrimRight(string(data), "\n")
+	return content, content != ""
+}
+
+// isAffirmative reports whether a prompt reply means yes.
+func isAffirmative(reply string) bool {
+	reply = strings.TrimSpace(strings.ToLower(reply))
+	return reply == "y" || reply == "yes"
+}
+
+// cleanupStaleDrafts removes draft files in dir older than draftMaxAge.
+func cleanupStaleDrafts(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "draft-") || !strings.HasSuffix(name, ".txt") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if time.Since(info.ModTime()) > draftMaxAge {
+			_ = os.Remove(filepath.Join(dir, name))
+		}
+	}
+}
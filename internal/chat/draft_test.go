@@ -0,0 +1,84 @@
+package chat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDraftSaveFlushLoad(t *testing.T) {
+	path := draftPath(t.TempDir(), "abc")
+	d := newDraftFile(path)
+
+	d.Save("half-typed prompt\nwith a second line")
+	d.Flush()
+
+	got, ok := d.Load()
+	if !ok {
+		t.Fatal("expected a draft to load")
+	}
+	if got != "half-typed prompt\nwith a second line" {
+		t.Errorf("unexpected draft content: %q", got)
+	}
+}
+
+func TestDraftClearRemovesFile(t *testing.T) {
+	path := draftPath(t.TempDir(), "abc")
+	d := newDraftFile(path)
+
+	d.Save("draft")
+	d.Flush()
+	d.Clear()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected draft file removed, stat err: %v", err)
+	}
+	if _, ok := d.Load(); ok {
+		t.Error("expected no draft after clear")
+	}
+}
+
+func TestDraftClearCancelsPendingWrite(t *testing.T) {
+	path := draftPath(t.TempDir(), "abc")
+	d := newDraftFile(path)
+
+	d.Save("about to be discarded")
+	d.Clear()
+	time.Sleep(2 * draftWriteDebounce)
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected debounced write cancelled, stat err: %v", err)
+	}
+}
+
+func TestCleanupStaleDrafts(t *testing.T) {
+	dir := t.TempDir()
+	stale := draftPath(dir, "old")
+	fresh := draftPath(dir, "new")
+	other := filepath.Join(dir, "sessions.json")
+	for _, path := range []string{stale, fresh, other} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+	old := time.Now().Add(-draftMaxAge - time.Hour)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+	if err := os.Chtimes(other, old, old); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+
+	cleanupStaleDrafts(dir)
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("expected stale draft removed")
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Errorf("expected fresh draft kept: %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("expected non-draft file kept: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package chat
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopus/internal/config"
+	"gopus/internal/history"
+	"gopus/internal/mcp"
+	_ "gopus/internal/mcp/builtin" // registers the builtin tool servers
+	"gopus/internal/openai"
+	"gopus/internal/testutil"
+)
+
+// newE2EToolChatLoop wires a chat loop to the fake API server and a
+// manager hosting the default builtin tools, so tool-call turns execute
+// real in-process tools.
+func newE2EToolChatLoop(t *testing.T, baseURL string) *ChatLoop {
+	t.Helper()
+	loop := newTestChatLoop(t, baseURL)
+
+	manager := mcp.NewManager()
+	if err := manager.AddBuiltinServer(context.Background(), &mcp.BuiltinServer{}, mcp.Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+	t.Cleanup(func() { manager.Close() })
+
+	loop.mcpManager = manager
+	return loop
+}
+
+// loadSessionFile reads a session's JSON straight from disk, so the
+// assertions cover what actually persisted, not just in-memory state.
+func loadSessionFile(t *testing.T, loop *ChatLoop) *history.Session {
+	t.Helper()
+
+	id := loop.historyManager.Current().ID
+	data, err := os.ReadFile(filepath.Join(loop.historyManager.SessionsDir(), id+".json"))
+	if err != nil {
+		t.Fatalf("failed to read session file: %v", err)
+	}
+	var session history.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		t.Fatalf("session file is not valid JSON: %v", err)
+	}
+	return &session
+}
+
+// TestRunEndToEnd drives a full Run loop turn: scripted stdin, a fake
+// API server, and the session file on disk at the end.
+func TestRunEndToEnd(t *testing.T) {
+	srv := testutil.NewServer(t, []testutil.Response{
+		{Body: testutil.CompletionBody(`"hi there"`, "stop")},
+	})
+
+	loop := newTestChatLoop(t, srv.URL)
+	loop.Run(context.Background(), bufio.NewScanner(strings.NewReader("hello\n")))
+
+	session := loadSessionFile(t, loop)
+	if len(session.Messages) != 2 {
+		t.Fatalf("expected 2 persisted messages, got %d", len(session.Messages))
+	}
+	if session.Messages[0].Content != "hello" || session.Messages[1].Content != "hi there" {
+		t.Errorf("unexpected persisted conversation: %+v", session.Messages)
+	}
+	if session.Name != "hello" {
+		t.Errorf("expected the session title from the first message, got %q", session.Name)
+	}
+}
+
+// TestRunEndToEndToolCall runs a tool-call turn against the builtin echo
+// tool and asserts the tool result reached the second API request.
+func TestRunEndToEndToolCall(t *testing.T) {
+	srv := testutil.NewServer(t, []testutil.Response{
+		{Body: testutil.ToolCallBody("call_1", "echo", `{"message": "ping"}`)},
+		{Body: testutil.CompletionBody(`"the tool answered"`, "stop")},
+	})
+
+	loop := newE2EToolChatLoop(t, srv.URL)
+	loop.Run(context.Background(), bufio.NewScanner(strings.NewReader("use the tool\n")))
+
+	session := loadSessionFile(t, loop)
+	if len(session.Messages) != 2 {
+		t.Fatalf("expected 2 persisted messages, got %d", len(session.Messages))
+	}
+	if session.Messages[1].Content != "the tool answered" {
+		t.Errorf("unexpected final reply: %q", session.Messages[1].Content)
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 API requests, got %d", len(requests))
+	}
+	if !strings.Contains(requests[1], `"tool"`) || !strings.Contains(requests[1], "Echo: ping") {
+		t.Errorf("expected the echo result in the follow-up request, got %s", requests[1])
+	}
+}
+
+// TestRunEndToEndErrorRollsBack verifies that a failed request leaves no
+// half-finished turn in the persisted session.
+func TestRunEndToEndErrorRollsBack(t *testing.T) {
+	srv := testutil.NewServer(t, []testutil.Response{
+		{Status: http.StatusInternalServerError, Body: testutil.ErrorBody("backend exploded")},
+	})
+
+	loop := newTestChatLoop(t, srv.URL)
+	loop.Run(context.Background(), bufio.NewScanner(strings.NewReader("hello\n")))
+
+	session := loadSessionFile(t, loop)
+	if len(session.Messages) != 0 {
+		t.Errorf("expected the failed turn to be rolled back, got %+v", session.Messages)
+	}
+}
+
+// TestToolConfirmationScriptedStdin drives the confirmation prompt with
+// scripted stdin (see withStdin in redact_test.go) in "always" mode.
+func TestToolConfirmationScriptedStdin(t *testing.T) {
+	toolCalls := []openai.ChatCompletionMessageToolCall{
+		{Id: "call_1", Type: openai.ChatCompletionMessageToolCallTypeFunction},
+	}
+
+	loop := newTestChatLoop(t, "http://unused")
+	loop.config.MCP.ToolConfirmation = config.ToolConfirmationAlways
+
+	withStdin(t, "y\n")
+	if !loop.confirmToolExecution(toolCalls) {
+		t.Error("expected 'y' to confirm the tool calls")
+	}
+
+	withStdin(t, "nope\n")
+	if loop.confirmToolExecution(toolCalls) {
+		t.Error("expected anything but yes to decline the tool calls")
+	}
+}
@@ -0,0 +1,71 @@
+package chat
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// pendingWork collects descriptions of work an immediate exit would lose or
+// cut short, one per subsystem that reports any.
+func (c *ChatLoop) pendingWork() []string {
+	var work []string
+	if c.draft != nil {
+		if desc, ok := c.draft.PendingWork(); ok {
+			work = append(work, desc)
+		}
+	}
+	if desc, ok := c.historyManager.PendingWork(); ok {
+		work = append(work, desc)
+	}
+	if c.mcpManager != nil {
+		if desc, ok := c.mcpManager.PendingWork(); ok {
+			work = append(work, desc)
+		}
+	}
+	if desc, ok := c.hooks.PendingWork(); ok {
+		work = append(work, desc)
+	}
+	return work
+}
+
+// confirmExit warns about pending work before quitting and asks for
+// confirmation. "--force" skips the check entirely.
+func (c *ChatLoop) confirmExit(args string) bool {
+	if strings.TrimSpace(args) == "--force" {
+		return true
+	}
+	work := c.pendingWork()
+	if len(work) == 0 {
+		return true
+	}
+
+	fmt.Println("Still pending:")
+	for _, desc := range work {
+		fmt.Printf("  - %s\n", desc)
+	}
+	return confirmPrompt("Quit anyway? [y/N]: ")
+}
+
+// finishPendingWork flushes what can still be flushed on the way out:
+// the input draft, debounced session and index writes, and quick in-flight
+// hooks. Tool calls in flight are abandoned; their results would have
+// nowhere to go.
+func (c *ChatLoop) finishPendingWork() {
+	if c.draft != nil {
+		c.draft.Flush()
+	}
+	if err := c.historyManager.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write session history: %v\n", err)
+
+		// Last resort: dump the session into the temp dir so nothing is
+		// lost even when the sessions directory is unwritable
+		if path, dumpErr := c.historyManager.DumpRecovery(os.TempDir()); dumpErr == nil {
+			fmt.Fprintf(os.Stderr, "Session dumped to %s; move it into the sessions directory to recover it.\n", path)
+		} else {
+			fmt.Fprintf(os.Stderr, "Recovery dump failed as well: %v\n", dumpErr)
+		}
+	}
+	c.hooks.Wait()
+	c.historyManager.ReleaseSessionLock()
+}
@@ -0,0 +1,65 @@
+package chat
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gopus/internal/history"
+	"gopus/internal/hooks"
+)
+
+func TestPendingWorkReportsDraftIndexAndHooks(t *testing.T) {
+	loop := newTestChatLoop(t, "http://localhost:0")
+
+	if work := loop.pendingWork(); len(work) != 0 {
+		t.Fatalf("expected no pending work initially, got %v", work)
+	}
+
+	// A saved-but-not-flushed draft is pending until the debounce fires
+	loop.draft = newDraftFile(filepath.Join(t.TempDir(), "draft-test.txt"))
+	loop.draft.Save("half-typed message")
+
+	// Two rapid saves leave the second index update waiting on the debounce
+	if err := loop.historyManager.AddMessage(history.RoleUser, "one"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+	if err := loop.historyManager.AddMessage(history.RoleAssistant, "two"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+
+	// An in-flight hook also counts; finishPendingWork waits for it
+	loop.hooks = hooks.NewRunner(map[string]string{
+		hooks.EventTurnCompleted: "sleep 0.2",
+	}, time.Second, nil)
+	loop.hooks.Fire(hooks.EventTurnCompleted, hooks.TurnPayload{Event: hooks.EventTurnCompleted})
+
+	work := loop.pendingWork()
+	if len(work) < 2 {
+		t.Errorf("expected draft and index work reported, got %v", work)
+	}
+
+	loop.finishPendingWork()
+
+	if work := loop.pendingWork(); len(work) != 0 {
+		t.Errorf("expected nothing pending after finishPendingWork, got %v", work)
+	}
+}
+
+func TestConfirmExitForceSkipsCheck(t *testing.T) {
+	loop := newTestChatLoop(t, "http://localhost:0")
+	loop.draft = newDraftFile(filepath.Join(t.TempDir(), "draft-test.txt"))
+	loop.draft.Save("pending")
+
+	// --force must not touch stdin even with work pending
+	if !loop.confirmExit("--force") {
+		t.Error("expected --force to confirm the exit")
+	}
+}
+
+func TestConfirmExitWithoutPendingWork(t *testing.T) {
+	loop := newTestChatLoop(t, "http://localhost:0")
+	if !loop.confirmExit("") {
+		t.Error("expected a clean exit without pending work")
+	}
+}
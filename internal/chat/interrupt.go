@@ -0,0 +1,107 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+	"gopus/internal/signal"
+)
+
+// toolCancelledMsg is recorded as the tool result for calls stopped by
+// Ctrl+C. Every tool call ID still gets a result message, so the
+// conversation history stays valid for the API.
+const toolCancelledMsg = "Tool execution cancelled by user."
+
+// executeToolCallsInterruptible runs the approved tool calls under the tool
+// spinner while listening for Ctrl+C. An interrupt cancels the context the
+// calls run under: in-flight handlers are expected to return promptly, and
+// calls still waiting for a worker slot are skipped. Reports whether an
+// interrupt occurred.
+//
+// mcp-go does not expose the JSON-RPC request ID of an in-flight call, so a
+// spec-level notifications/cancelled cannot be addressed at the request;
+// cancellation relies on the context closing our side of the call.
+func (c *ChatLoop) executeToolCallsInterruptible(ctx context.Context, toolCalls []openai.ChatCompletionMessageToolCall) ([]toolOutcome, bool) {
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+
+	interrupts, release := signal.InterceptInterrupt()
+	defer release()
+
+	var interrupted atomic.Bool
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-interrupts:
+			interrupted.Store(true)
+			cancelRun()
+		case <-stop:
+		}
+	}()
+
+	outcomes := c.withToolSpinner(toolRunLabel(toolCalls), func() []toolOutcome {
+		return c.executeToolCalls(runCtx, toolCalls)
+	})
+	return outcomes, interrupted.Load()
+}
+
+// offerSkippedToolCalls handles the queued tool calls an interrupt left
+// unexecuted: the user may run the remainder now or skip them (the
+// default). Skipped calls keep their cancelled outcome so every tool call
+// still gets a result message.
+func (c *ChatLoop) offerSkippedToolCalls(ctx context.Context, toolCalls []openai.ChatCompletionMessageToolCall, outcomes []toolOutcome) []toolOutcome {
+	var indices []int
+	for _, outcome := range outcomes {
+		if outcome.skipped {
+			indices = append(indices, outcome.index)
+		}
+	}
+	if len(indices) == 0 {
+		return outcomes
+	}
+
+	if !confirmPrompt(fmt.Sprintf("%d queued tool call(s) did not run. Run them now? [y/N]: ", len(indices))) {
+		return outcomes
+	}
+
+	remaining := make([]openai.ChatCompletionMessageToolCall, len(indices))
+	for i, idx := range indices {
+		remaining[i] = toolCalls[idx]
+	}
+	rerun, interrupted := c.executeToolCallsInterruptible(ctx, remaining)
+	for i, outcome := range rerun {
+		outcome.index = indices[i]
+		outcomes[outcome.index] = outcome
+	}
+	if interrupted {
+		// A second Ctrl+C skips whatever is still left without asking again
+		fmt.Printf("%s[Tool execution cancelled]%s\n", printer.ColorYellow, printer.ColorReset)
+	}
+	return outcomes
+}
+
+// toolRunLabel is the spinner label for a batch of tool calls.
+func toolRunLabel(toolCalls []openai.ChatCompletionMessageToolCall) string {
+	if len(toolCalls) == 1 {
+		return fmt.Sprintf("running tool %s…", toolCalls[0].Function.Name)
+	}
+	return fmt.Sprintf("running %d tool(s)…", len(toolCalls))
+}
+
+// toolResultContent renders an outcome as the tool result recorded in the
+// conversation. Calls cancelled or skipped by an interrupt report the
+// cancellation rather than a raw context error.
+func toolResultContent(outcome toolOutcome) string {
+	switch {
+	case outcome.skipped || errors.Is(outcome.err, context.Canceled):
+		return toolCancelledMsg
+	case outcome.err != nil:
+		return fmt.Sprintf("Error: %v", outcome.err)
+	}
+	return outcome.content
+}
@@ -0,0 +1,140 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"gopus/internal/mcp"
+	"gopus/internal/openai"
+)
+
+// registerBlockingTool registers a tool that blocks until its context is
+// cancelled, closing started once the handler is running.
+func registerBlockingTool(name string, started chan<- struct{}) {
+	mcp.DefaultToolRegistry.Register(
+		mcplib.NewTool(name),
+		func(deps mcp.Deps) mcp.ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				started <- struct{}{}
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+		},
+	)
+}
+
+// interruptWhenStarted raises SIGINT once the blocking tool reports in.
+func interruptWhenStarted(t *testing.T, started <-chan struct{}) {
+	t.Helper()
+	go func() {
+		select {
+		case <-started:
+			syscall.Kill(os.Getpid(), syscall.SIGINT)
+		case <-time.After(5 * time.Second):
+			t.Error("tool handler never started")
+		}
+	}()
+}
+
+func TestInterruptCancelsInFlightToolCall(t *testing.T) {
+	started := make(chan struct{}, 1)
+	registerBlockingTool("block_interrupt_test", started)
+	loop := newToolTestChatLoop(t)
+
+	interruptWhenStarted(t, started)
+	outcomes, interrupted := loop.executeToolCallsInterruptible(context.Background(), []openai.ChatCompletionMessageToolCall{
+		{Id: "call_1", Function: openai.ChatCompletionMessageToolCallFunction{Name: "block_interrupt_test", Arguments: "{}"}},
+	})
+
+	if !interrupted {
+		t.Fatal("expected the run to report an interrupt")
+	}
+	if !errors.Is(outcomes[0].err, context.Canceled) {
+		t.Errorf("expected the handler's context to be cancelled, got err %v", outcomes[0].err)
+	}
+	if got := toolResultContent(outcomes[0]); got != toolCancelledMsg {
+		t.Errorf("expected result %q, got %q", toolCancelledMsg, got)
+	}
+}
+
+func TestInterruptSkipsQueuedToolCall(t *testing.T) {
+	started := make(chan struct{}, 1)
+	registerBlockingTool("block_queued_test", started)
+	loop := newToolTestChatLoop(t)
+	loop.config.MCP.MaxParallelTools = 1
+
+	// Both calls block, so whichever wins the single worker slot is the one
+	// interrupted in flight; the other must be skipped without running.
+	calls := []openai.ChatCompletionMessageToolCall{
+		{Id: "call_1", Function: openai.ChatCompletionMessageToolCallFunction{Name: "block_queued_test", Arguments: "{}"}},
+		{Id: "call_2", Function: openai.ChatCompletionMessageToolCallFunction{Name: "block_queued_test", Arguments: "{}"}},
+	}
+
+	interruptWhenStarted(t, started)
+	outcomes, interrupted := loop.executeToolCallsInterruptible(context.Background(), calls)
+
+	if !interrupted {
+		t.Fatal("expected the run to report an interrupt")
+	}
+	skipped, cancelled := 0, 0
+	for _, outcome := range outcomes {
+		if outcome.skipped {
+			skipped++
+		} else if errors.Is(outcome.err, context.Canceled) {
+			cancelled++
+		}
+		if got := toolResultContent(outcome); got != toolCancelledMsg {
+			t.Errorf("call %d: expected result %q, got %q", outcome.index, toolCancelledMsg, got)
+		}
+	}
+	if skipped != 1 || cancelled != 1 {
+		t.Errorf("expected 1 skipped and 1 cancelled call, got %d skipped, %d cancelled", skipped, cancelled)
+	}
+}
+
+func TestOfferSkippedToolCalls(t *testing.T) {
+	mcp.DefaultToolRegistry.Register(
+		mcplib.NewTool("echo_skipped_test"),
+		func(deps mcp.Deps) mcp.ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				return mcplib.NewToolResultText("ran late"), nil
+			}
+		},
+	)
+	loop := newToolTestChatLoop(t)
+
+	calls := []openai.ChatCompletionMessageToolCall{
+		{Id: "call_1", Function: openai.ChatCompletionMessageToolCallFunction{Name: "echo_skipped_test", Arguments: "{}"}},
+		{Id: "call_2", Function: openai.ChatCompletionMessageToolCallFunction{Name: "echo_skipped_test", Arguments: "{}"}},
+	}
+	outcomes := []toolOutcome{
+		{index: 0, name: "echo_skipped_test", err: context.Canceled},
+		{index: 1, name: "echo_skipped_test", skipped: true, err: context.Canceled},
+	}
+
+	// Declining the prompt keeps the cancelled outcomes
+	withStdin(t, "n\n")
+	kept := loop.offerSkippedToolCalls(context.Background(), calls, outcomes)
+	if !kept[1].skipped {
+		t.Error("expected the skipped call to stay skipped after declining")
+	}
+
+	// Accepting runs only the skipped call and replaces its outcome
+	withStdin(t, "y\n")
+	rerun := loop.offerSkippedToolCalls(context.Background(), calls, outcomes)
+	if rerun[1].skipped || rerun[1].err != nil {
+		t.Fatalf("expected the skipped call to run, got skipped=%v err=%v", rerun[1].skipped, rerun[1].err)
+	}
+	if rerun[1].content != "ran late" || rerun[1].index != 1 {
+		t.Errorf("unexpected rerun outcome: %+v", rerun[1])
+	}
+	if !errors.Is(rerun[0].err, context.Canceled) {
+		t.Errorf("expected the in-flight call's outcome to be untouched, got %+v", rerun[0])
+	}
+}
@@ -0,0 +1,223 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+)
+
+// handleJSON processes the /json command: "on" forces json_object output,
+// "schema <file>" loads a JSON schema and switches to structured output,
+// "off" returns to free-form text, and no argument shows the current mode.
+// The mode persists with the session.
+func (c *ChatLoop) handleJSON(args string) {
+	session := c.historyManager.Current()
+	if session == nil {
+		fmt.Println("No current session.")
+		return
+	}
+
+	fields := strings.Fields(args)
+	switch {
+	case len(fields) == 0:
+		fmt.Printf("JSON output: %s\n", jsonModeDescription(session))
+		return
+	case fields[0] == "on" && len(fields) == 1:
+		session.ResponseFormat = "json_object"
+		session.ResponseSchemaName = ""
+		session.ResponseSchema = nil
+		fmt.Println("JSON output on: responses must be valid JSON objects.")
+	case fields[0] == "off" && len(fields) == 1:
+		session.ResponseFormat = ""
+		session.ResponseSchemaName = ""
+		session.ResponseSchema = nil
+		fmt.Println("JSON output off.")
+	case fields[0] == "schema" && len(fields) == 2:
+		name, schema, err := loadResponseSchema(fields[1])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		session.ResponseFormat = "json_schema"
+		session.ResponseSchemaName = name
+		session.ResponseSchema = schema
+		fmt.Printf("JSON output on with schema %q: responses must match the schema.\n", name)
+	default:
+		fmt.Println("Usage: /json [on|off|schema <file>]")
+		return
+	}
+
+	if err := c.historyManager.SaveCurrent(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
+	}
+}
+
+// jsonModeDescription summarizes a session's JSON output mode for display.
+func jsonModeDescription(session *history.Session) string {
+	switch session.ResponseFormat {
+	case "json_object":
+		return "on (json_object)"
+	case "json_schema":
+		return fmt.Sprintf("on (json_schema %q)", session.ResponseSchemaName)
+	default:
+		return "off"
+	}
+}
+
+// loadResponseSchema reads and parses a JSON schema file. The schema name
+// sent to the API is the file name without its extension.
+func loadResponseSchema(path string) (string, map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return "", nil, fmt.Errorf("schema file is not a JSON object: %w", err)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return name, schema, nil
+}
+
+// responseFormatFor translates a session's JSON output mode into the
+// request's response_format, or nil for free-form text.
+func responseFormatFor(session *history.Session) *openai.ResponseFormat {
+	switch session.ResponseFormat {
+	case "json_object":
+		return &openai.ResponseFormat{Type: openai.ResponseFormatTypeJsonObject}
+	case "json_schema":
+		strict := true
+		return &openai.ResponseFormat{
+			Type: openai.ResponseFormatTypeJsonSchema,
+			JsonSchema: &openai.ResponseFormatJSONSchema{
+				Name:   session.ResponseSchemaName,
+				Schema: session.ResponseSchema,
+				Strict: &strict,
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// validateJSONResponse checks an assistant reply against the session's JSON
+// output mode. It returns the text to display (pretty-printed when the reply
+// is valid JSON) and, when the reply is invalid, a corrective follow-up
+// message to send to the model. An empty correction means the reply passed.
+func (c *ChatLoop) validateJSONResponse(content string) (display, correction string) {
+	session := c.historyManager.Current()
+	if session == nil || session.ResponseFormat == "" {
+		return content, ""
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &value); err != nil {
+		return content, fmt.Sprintf("The previous reply was not valid JSON (%v). Respond again with only valid JSON and no surrounding text.", err)
+	}
+	if session.ResponseFormat == "json_schema" && session.ResponseSchema != nil {
+		if err := validateAgainstSchema(value, session.ResponseSchema, "$"); err != nil {
+			return content, fmt.Sprintf("The previous reply did not match the required JSON schema (%v). Respond again with JSON that matches the schema exactly.", err)
+		}
+	}
+
+	pretty, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return content, ""
+	}
+	return string(pretty), ""
+}
+
+// validateAgainstSchema checks a decoded JSON value against a schema. It
+// covers the subset of JSON Schema that structured-output schemas use in
+// practice: type, enum, properties, required, and items. Anything the
+// validator does not understand passes, so an elaborate schema never
+// rejects a response the API accepted.
+func validateAgainstSchema(value any, schema map[string]any, path string) error {
+	if typ, ok := schema["type"].(string); ok {
+		if err := checkSchemaType(value, typ, path); err != nil {
+			return err
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		matched := false
+		for _, allowed := range enum {
+			if fmt.Sprintf("%v", allowed) == fmt.Sprintf("%v", value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: value %v is not one of the allowed values", path, value)
+		}
+	}
+
+	if obj, ok := value.(map[string]any); ok {
+		if required, ok := schema["required"].([]any); ok {
+			for _, name := range required {
+				key, _ := name.(string)
+				if _, present := obj[key]; !present {
+					return fmt.Errorf("%s: missing required property %q", path, key)
+				}
+			}
+		}
+		if properties, ok := schema["properties"].(map[string]any); ok {
+			for key, sub := range properties {
+				subSchema, ok := sub.(map[string]any)
+				if !ok {
+					continue
+				}
+				child, present := obj[key]
+				if !present {
+					continue
+				}
+				if err := validateAgainstSchema(child, subSchema, path+"."+key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if arr, ok := value.([]any); ok {
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, child := range arr {
+				if err := validateAgainstSchema(child, items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSchemaType verifies a decoded JSON value against a schema type name.
+func checkSchemaType(value any, typ, path string) error {
+	ok := true
+	switch typ {
+	case "object":
+		_, ok = value.(map[string]any)
+	case "array":
+		_, ok = value.([]any)
+	case "string":
+		_, ok = value.(string)
+	case "boolean":
+		_, ok = value.(bool)
+	case "number":
+		_, ok = value.(float64)
+	case "integer":
+		f, isNumber := value.(float64)
+		ok = isNumber && f == float64(int64(f))
+	case "null":
+		ok = value == nil
+	}
+	if !ok {
+		return fmt.Errorf("%s: expected %s, got %T", path, typ, value)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package chat
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopus/internal/openai"
+)
+
+// TestValidateAgainstSchema exercises the supported JSON Schema subset.
+func TestValidateAgainstSchema(t *testing.T) {
+	schema := map[string]any{
+		"type":     "object",
+		"required": []any{"name", "count"},
+		"properties": map[string]any{
+			"name":  map[string]any{"type": "string"},
+			"count": map[string]any{"type": "integer"},
+			"tags": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": "string"},
+			},
+			"status": map[string]any{"enum": []any{"open", "closed"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		value   any
+		wantErr string // substring of the expected error, "" for valid
+	}{
+		{"valid", map[string]any{"name": "a", "count": float64(2)}, ""},
+		{"missing required", map[string]any{"name": "a"}, "missing required property"},
+		{"wrong type", map[string]any{"name": "a", "count": "two"}, "expected integer"},
+		{"fractional integer", map[string]any{"name": "a", "count": 2.5}, "expected integer"},
+		{"bad array item", map[string]any{"name": "a", "count": float64(1), "tags": []any{"x", float64(3)}}, "expected string"},
+		{"enum violation", map[string]any{"name": "a", "count": float64(1), "status": "pending"}, "not one of the allowed values"},
+		{"not an object", "just text", "expected object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAgainstSchema(tt.value, schema, "$")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected valid, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestValidateJSONResponsePrettyPrints verifies that a valid reply in JSON
+// mode is re-indented for display.
+func TestValidateJSONResponsePrettyPrints(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused.invalid")
+	loop.historyManager.Current().ResponseFormat = "json_object"
+
+	display, correction := loop.validateJSONResponse(`{"a":1,"b":[2,3]}`)
+	if correction != "" {
+		t.Fatalf("expected no correction, got %q", correction)
+	}
+	want := "{\n  \"a\": 1,\n  \"b\": [\n    2,\n    3\n  ]\n}"
+	if display != want {
+		t.Errorf("display = %q, want %q", display, want)
+	}
+}
+
+// TestValidateJSONResponseOffPassesThrough verifies that free-form text is
+// untouched when JSON mode is off.
+func TestValidateJSONResponseOffPassesThrough(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused.invalid")
+
+	display, correction := loop.validateJSONResponse("not json at all")
+	if display != "not json at all" || correction != "" {
+		t.Errorf("expected pass-through, got display %q correction %q", display, correction)
+	}
+}
+
+// TestProcessConversationJSONModeRetries verifies that an unparseable reply
+// in JSON mode triggers one corrective round trip.
+func TestProcessConversationJSONModeRetries(t *testing.T) {
+	srv := scriptedServer(t, []scriptedResponse{
+		{http.StatusOK, completionBody(`"not json"`, "stop")},
+		{http.StatusOK, completionBody(`"{\"answer\": 42}"`, "stop")},
+	})
+	defer srv.Close()
+
+	loop := newTestChatLoop(t, srv.URL)
+	loop.historyManager.Current().ResponseFormat = "json_object"
+	chatHistory := userMessages("extract the answer")
+
+	if err := loop.processConversation(context.Background(), &chatHistory); err != nil {
+		t.Fatalf("expected corrective retry to recover, got error: %v", err)
+	}
+
+	last := chatHistory[len(chatHistory)-1]
+	content, ok := openai.ContentAsString(last.Content)
+	if last.Role != openai.RoleAssistant || !ok || content != `{"answer": 42}` {
+		t.Errorf("expected valid JSON reply from retry, got %+v", last)
+	}
+
+	// The corrective follow-up stays in the request history
+	foundCorrection := false
+	for _, msg := range chatHistory {
+		if content, ok := openai.ContentAsString(msg.Content); ok && msg.Role == openai.RoleUser && strings.Contains(content, "not valid JSON") {
+			foundCorrection = true
+		}
+	}
+	if !foundCorrection {
+		t.Error("expected a corrective user message in the request history")
+	}
+}
+
+// TestHandleJSONSchemaFile verifies loading a schema file switches the
+// session to structured-output mode.
+func TestHandleJSONSchemaFile(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused.invalid")
+	path := filepath.Join(t.TempDir(), "invoice.json")
+	schema := `{"type": "object", "required": ["total"]}`
+	if err := os.WriteFile(path, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loop.handleJSON("schema " + path)
+
+	session := loop.historyManager.Current()
+	if session.ResponseFormat != "json_schema" {
+		t.Errorf("ResponseFormat = %q, want json_schema", session.ResponseFormat)
+	}
+	if session.ResponseSchemaName != "invoice" {
+		t.Errorf("ResponseSchemaName = %q, want invoice", session.ResponseSchemaName)
+	}
+	if session.ResponseSchema["type"] != "object" {
+		t.Errorf("ResponseSchema = %v, want parsed schema", session.ResponseSchema)
+	}
+
+	rf := responseFormatFor(session)
+	if rf == nil || rf.Type != openai.ResponseFormatTypeJsonSchema || rf.JsonSchema == nil || rf.JsonSchema.Name != "invoice" {
+		t.Errorf("responseFormatFor = %+v, want json_schema format", rf)
+	}
+
+	loop.handleJSON("off")
+	if session.ResponseFormat != "" || session.ResponseSchema != nil {
+		t.Errorf("expected /json off to clear the mode, got %q %v", session.ResponseFormat, session.ResponseSchema)
+	}
+}
@@ -0,0 +1,168 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"gopus/internal/config"
+)
+
+// handleMCP manages MCP servers at runtime. Newly added servers register
+// their tools immediately; the model sees them on its very next turn because
+// the tool list is re-read per request. With --save the change is also
+// persisted back into the config file.
+func (c *ChatLoop) handleMCP(ctx context.Context, args string) {
+	if c.mcpManager == nil {
+		fmt.Println("MCP support is not available.")
+		return
+	}
+
+	fields := strings.Fields(args)
+	save := false
+	rest := fields[:0]
+	for _, f := range fields {
+		if f == "--save" {
+			save = true
+			continue
+		}
+		rest = append(rest, f)
+	}
+	if len(rest) == 0 {
+		printMCPUsage()
+		return
+	}
+
+	switch rest[0] {
+	case "add":
+		if len(rest) < 3 {
+			fmt.Println("Usage: /mcp add <name> <command> [args...] [--save]")
+			return
+		}
+		name, command, cmdArgs := rest[1], rest[2], rest[3:]
+		if err := c.mcpManager.AddServer(ctx, name, command, nil, cmdArgs...); err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding MCP server: %v\n", err)
+			return
+		}
+		fmt.Printf("Added MCP server %s; its tools are available from the next turn.\n", name)
+		if save {
+			c.saveServerConfig(name, command, cmdArgs, false)
+		}
+	case "remove":
+		if len(rest) != 2 {
+			fmt.Println("Usage: /mcp remove <name> [--save]")
+			return
+		}
+		name := rest[1]
+		if err := c.mcpManager.RemoveServer(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error removing MCP server: %v\n", err)
+			return
+		}
+		fmt.Printf("Removed MCP server %s.\n", name)
+		if save {
+			c.saveServerConfig(name, "", nil, true)
+		}
+	case "restart":
+		if len(rest) != 2 {
+			fmt.Println("Usage: /mcp restart <name>")
+			return
+		}
+		if err := c.mcpManager.RestartServer(ctx, rest[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error restarting MCP server: %v\n", err)
+			return
+		}
+		fmt.Printf("Restarted MCP server %s.\n", rest[1])
+	case "retry":
+		if len(rest) != 2 {
+			fmt.Println("Usage: /mcp retry <name>")
+			return
+		}
+		if err := c.mcpManager.RetryServer(ctx, rest[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error retrying MCP server: %v\n", err)
+			return
+		}
+		fmt.Printf("Reconnected MCP server %s.\n", rest[1])
+	case "refresh":
+		if err := c.mcpManager.RefreshTools(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error refreshing tools: %v\n", err)
+			return
+		}
+		fmt.Printf("Refreshed tools; %d available.\n", c.mcpManager.ToolCount())
+	default:
+		printMCPUsage()
+	}
+}
+
+func printMCPUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  /mcp add <name> <command> [args...] [--save]")
+	fmt.Println("  /mcp remove <name> [--save]")
+	fmt.Println("  /mcp restart <name>")
+	fmt.Println("  /mcp retry <name>")
+	fmt.Println("  /mcp refresh")
+}
+
+// saveServerConfig persists a runtime server change back into the config
+// file. The file is parsed and rewritten, so comments are lost; the write is
+// atomic (temp file plus rename) so a crash cannot leave it half-written.
+func (c *ChatLoop) saveServerConfig(name, command string, cmdArgs []string, remove bool) {
+	data, err := os.ReadFile(c.configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading config file: %v\n", err)
+		return
+	}
+	var cfg config.Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return
+	}
+
+	servers := cfg.MCP.Servers[:0]
+	for _, s := range cfg.MCP.Servers {
+		if s.Name != name {
+			servers = append(servers, s)
+		}
+	}
+	if !remove {
+		servers = append(servers, config.MCPServerConfig{
+			Name:    name,
+			Command: command,
+			Args:    cmdArgs,
+			Enabled: true,
+		})
+	}
+	cfg.MCP.Servers = servers
+
+	out, err := yaml.Marshal(&cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error serializing config: %v\n", err)
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.configPath), ".config-*.yaml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing config file: %v\n", err)
+		return
+	}
+	if _, err := tmp.Write(out); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		fmt.Fprintf(os.Stderr, "Error writing config file: %v\n", err)
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		fmt.Fprintf(os.Stderr, "Error writing config file: %v\n", err)
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.configPath); err != nil {
+		os.Remove(tmp.Name())
+		fmt.Fprintf(os.Stderr, "Error writing config file: %v\n", err)
+		return
+	}
+	fmt.Printf("Saved to %s (comments are not preserved).\n", c.configPath)
+}
@@ -0,0 +1,129 @@
+package chat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+)
+
+// memoryHeader opens the injected memory block, so the model knows where
+// the facts come from.
+const memoryHeader = "User preferences remembered across sessions:"
+
+// handleRemember processes the /remember command: it appends a fact to the
+// global memory, which every session injects into its requests.
+func (c *ChatLoop) handleRemember(args string) {
+	fact := strings.TrimSpace(args)
+	if fact == "" {
+		fmt.Println("Usage: /remember <fact>")
+		return
+	}
+	if c.memory == nil {
+		fmt.Println("Memory is not available.")
+		return
+	}
+	if err := c.memory.Add(fact); err != nil {
+		printer.PrintError("Failed to remember: %v", err)
+		return
+	}
+	fmt.Println("Remembered. The fact is included in every session's requests (see /memory).")
+}
+
+// handleMemory processes the /memory command: it lists the remembered
+// facts with the numbers /forget takes.
+func (c *ChatLoop) handleMemory() {
+	if c.memory == nil {
+		fmt.Println("Memory is not available.")
+		return
+	}
+	facts, err := c.memory.Facts()
+	if err != nil {
+		printer.PrintError("Failed to read memory: %v", err)
+		return
+	}
+	if len(facts) == 0 {
+		fmt.Println("Nothing remembered yet. Add a fact with /remember <fact>.")
+		return
+	}
+	fmt.Printf("\nRemembered facts (shared across sessions, /forget <n> removes one):\n")
+	for i, fact := range facts {
+		fmt.Printf("  %d. %s\n", i+1, fact.Text)
+	}
+	fmt.Println()
+}
+
+// handleForget processes the /forget command: it removes one remembered
+// fact by its /memory number.
+func (c *ChatLoop) handleForget(args string) {
+	number := strings.TrimSpace(args)
+	if number == "" {
+		fmt.Println("Usage: /forget <n> (run /memory to see the numbering)")
+		return
+	}
+	if c.memory == nil {
+		fmt.Println("Memory is not available.")
+		return
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 {
+		fmt.Printf("Invalid entry number %q.\n", number)
+		return
+	}
+	if err := c.memory.Remove(n - 1); err != nil {
+		printer.PrintError("Failed to forget: %v", err)
+		return
+	}
+	fmt.Printf("Forgot entry %d.\n", n)
+}
+
+// memoryMessages renders the global memory as a system message injected
+// into every request, like the recall block and context set. Facts beyond
+// chat.memory_budget_tokens are left out of the payload newest-last — the
+// file itself is never trimmed.
+func (c *ChatLoop) memoryMessages() []openai.ChatCompletionRequestMessage {
+	if c.memory == nil {
+		return nil
+	}
+	facts, err := c.memory.Facts()
+	if err != nil {
+		printer.PrintWarning("Memory unavailable this turn: %v", err)
+		return nil
+	}
+	if len(facts) == 0 {
+		return nil
+	}
+
+	// Keep the newest facts that fit the budget, so the oldest are the
+	// ones left out when the memory outgrows it
+	budget := c.config.Chat.MemoryBudgetTokens
+	used := openai.EstimateTokens(memoryHeader) + messageOverheadTokens
+	keepFrom := len(facts)
+	for i := len(facts) - 1; i >= 0; i-- {
+		cost := openai.EstimateTokens(facts[i].Text) + 1
+		if used+cost > budget {
+			break
+		}
+		used += cost
+		keepFrom = i
+	}
+	if keepFrom > 0 {
+		printer.PrintWarning("Memory over its ~%d token budget; the oldest %d fact(s) were left out of the request (see /memory)", budget, keepFrom)
+	}
+	if keepFrom == len(facts) {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString(memoryHeader)
+	for _, fact := range facts[keepFrom:] {
+		b.WriteString("\n- ")
+		b.WriteString(fact.Text)
+	}
+	return []openai.ChatCompletionRequestMessage{{
+		Role:    openai.RoleSystem,
+		Content: openai.StringContent(b.String()),
+	}}
+}
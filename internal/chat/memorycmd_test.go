@@ -0,0 +1,116 @@
+package chat
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopus/internal/memory"
+	"gopus/internal/openai"
+)
+
+func newTestMemory(t *testing.T, c *ChatLoop) *memory.Store {
+	t.Helper()
+	store := memory.NewStore(filepath.Join(t.TempDir(), "memory.json"))
+	c.memory = store
+	return store
+}
+
+func TestMemoryMessages(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	c.config.Chat.MemoryBudgetTokens = 1000
+	store := newTestMemory(t, c)
+
+	if msgs := c.memoryMessages(); msgs != nil {
+		t.Fatalf("expected no injection for an empty memory, got %+v", msgs)
+	}
+
+	if err := store.Add("answer tersely"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := store.Add("the project is called gopus"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	msgs := c.memoryMessages()
+	if len(msgs) != 1 {
+		t.Fatalf("expected a single system message, got %d", len(msgs))
+	}
+	if msgs[0].Role != openai.RoleSystem {
+		t.Errorf("expected a system message, got role %q", msgs[0].Role)
+	}
+	text := messageText(t, msgs[0])
+	if !strings.Contains(text, memoryHeader) {
+		t.Errorf("expected the memory header, got %q", text)
+	}
+	if !strings.Contains(text, "- answer tersely") || !strings.Contains(text, "- the project is called gopus") {
+		t.Errorf("expected both facts as bullets, got %q", text)
+	}
+}
+
+func TestMemoryMessagesBudgetDropsOldest(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	store := newTestMemory(t, c)
+
+	old := strings.Repeat("the oldest fact ", 20)
+	if err := store.Add(old); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := store.Add("the newest fact"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	// Room for the newest fact only: the oldest is dropped from the
+	// payload, but stays in the file
+	c.config.Chat.MemoryBudgetTokens = openai.EstimateTokens(memoryHeader) +
+		messageOverheadTokens + openai.EstimateTokens("the newest fact") + 1
+
+	msgs := c.memoryMessages()
+	if len(msgs) != 1 {
+		t.Fatalf("expected a single system message, got %d", len(msgs))
+	}
+	text := messageText(t, msgs[0])
+	if strings.Contains(text, "the oldest fact") {
+		t.Errorf("expected the oldest fact to be dropped, got %q", text)
+	}
+	if !strings.Contains(text, "- the newest fact") {
+		t.Errorf("expected the newest fact to be kept, got %q", text)
+	}
+
+	if facts, _ := store.Facts(); len(facts) != 2 {
+		t.Errorf("the budget must never trim the file, got %d fact(s)", len(facts))
+	}
+}
+
+func TestMemoryMessagesNilStore(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	c.memory = nil
+	if msgs := c.memoryMessages(); msgs != nil {
+		t.Errorf("expected no injection without a store, got %+v", msgs)
+	}
+}
+
+func TestHandleRememberAndForget(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	store := newTestMemory(t, c)
+
+	c.handleRemember("answer tersely")
+	c.handleRemember("the project is called gopus")
+	facts, err := store.Facts()
+	if err != nil || len(facts) != 2 {
+		t.Fatalf("expected 2 remembered facts, got %v, %v", facts, err)
+	}
+
+	c.handleForget("1")
+	facts, _ = store.Facts()
+	if len(facts) != 1 || facts[0].Text != "the project is called gopus" {
+		t.Errorf("expected /forget 1 to remove the oldest fact, got %+v", facts)
+	}
+
+	// Out-of-range and malformed numbers leave the memory alone
+	c.handleForget("9")
+	c.handleForget("zero")
+	if facts, _ := store.Facts(); len(facts) != 1 {
+		t.Errorf("expected bad /forget arguments to be rejected, got %+v", facts)
+	}
+}
@@ -0,0 +1,39 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"gopus/internal/notify"
+)
+
+func TestMaybeNotifyThreshold(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused.invalid")
+	fake := &notify.Fake{}
+	loop.notifier = fake
+	loop.config.Chat.NotifyAfterSeconds = 20
+
+	// Below threshold: no notification
+	loop.maybeNotify(5 * time.Second)
+	if len(fake.Notifications) != 0 {
+		t.Errorf("expected no notification below threshold, got %v", fake.Notifications)
+	}
+
+	// Above threshold: one notification
+	loop.maybeNotify(25 * time.Second)
+	if len(fake.Notifications) != 1 {
+		t.Fatalf("expected one notification above threshold, got %v", fake.Notifications)
+	}
+}
+
+func TestMaybeNotifyDisabled(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused.invalid")
+	fake := &notify.Fake{}
+	loop.notifier = fake
+	loop.config.Chat.NotifyAfterSeconds = 0
+
+	loop.maybeNotify(time.Hour)
+	if len(fake.Notifications) != 0 {
+		t.Errorf("expected no notification when disabled, got %v", fake.Notifications)
+	}
+}
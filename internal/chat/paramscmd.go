@@ -0,0 +1,208 @@
+package chat
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// handleParams processes the /params command: with no arguments it shows
+// the generation parameters in effect for the next request, "<name> <value>"
+// sets a session override (validated against the parameter's allowed range),
+// and "<name> reset" reverts to the config value. Overrides persist with the
+// session, so a re-run of the same session keeps e.g. its seed.
+func (c *ChatLoop) handleParams(args string) {
+	fields := strings.Fields(args)
+
+	switch len(fields) {
+	case 0:
+		c.printParams()
+	case 2:
+		if err := c.setParam(fields[0], fields[1]); err != nil {
+			fmt.Println(err)
+			return
+		}
+		if err := c.historyManager.SaveCurrent(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
+		}
+	default:
+		fmt.Println("Usage: /params [top_p|presence_penalty|frequency_penalty|seed <value|reset>]")
+	}
+}
+
+// printParams shows every generation parameter with its effective value and
+// where it comes from, so what was actually sent can be verified.
+func (c *ChatLoop) printParams() {
+	session := c.historyManager.Current()
+	var topP, presencePenalty, frequencyPenalty *float64
+	var seed *int
+	if session != nil {
+		topP, presencePenalty, frequencyPenalty, seed =
+			session.TopP, session.PresencePenalty, session.FrequencyPenalty, session.Seed
+	}
+
+	fmt.Println("\nGeneration parameters for the next request:")
+	fmt.Printf("  model:             %s\n", c.config.OpenAI.DisplayModel())
+	if session != nil && session.MaxTokens > 0 {
+		fmt.Printf("  max_tokens:        %d (session override)\n", session.MaxTokens)
+	} else {
+		fmt.Printf("  max_tokens:        %d\n", c.config.OpenAI.MaxTokens)
+	}
+	fmt.Printf("  temperature:       %g\n", c.config.OpenAI.Temperature)
+	fmt.Printf("  top_p:             %s\n", formatFloatParam(topP, c.config.OpenAI.TopP))
+	fmt.Printf("  presence_penalty:  %s\n", formatFloatParam(presencePenalty, c.config.OpenAI.PresencePenalty))
+	fmt.Printf("  frequency_penalty: %s\n", formatFloatParam(frequencyPenalty, c.config.OpenAI.FrequencyPenalty))
+	fmt.Printf("  seed:              %s\n", formatSeedParam(seed, c.config.OpenAI.Seed))
+	fmt.Println()
+}
+
+// effectiveParamsLine summarizes the generation parameters that will
+// accompany the next request on one line, marking session overrides.
+// Parameters that are neither configured nor overridden are omitted.
+func (c *ChatLoop) effectiveParamsLine() string {
+	session := c.historyManager.Current()
+	var topP, presencePenalty, frequencyPenalty *float64
+	var seed *int
+	maxTokens := fmt.Sprintf("max_tokens=%d", c.config.OpenAI.MaxTokens)
+	if session != nil {
+		topP, presencePenalty, frequencyPenalty, seed =
+			session.TopP, session.PresencePenalty, session.FrequencyPenalty, session.Seed
+		if session.MaxTokens > 0 {
+			maxTokens = fmt.Sprintf("max_tokens=%d*", session.MaxTokens)
+		}
+	}
+
+	parts := []string{
+		maxTokens,
+		fmt.Sprintf("temperature=%g", c.config.OpenAI.Temperature),
+	}
+	addFloat := func(name string, override, configured *float64) {
+		switch {
+		case override != nil:
+			parts = append(parts, fmt.Sprintf("%s=%g*", name, *override))
+		case configured != nil:
+			parts = append(parts, fmt.Sprintf("%s=%g", name, *configured))
+		}
+	}
+	addFloat("top_p", topP, c.config.OpenAI.TopP)
+	addFloat("presence_penalty", presencePenalty, c.config.OpenAI.PresencePenalty)
+	addFloat("frequency_penalty", frequencyPenalty, c.config.OpenAI.FrequencyPenalty)
+	switch {
+	case seed != nil:
+		parts = append(parts, fmt.Sprintf("seed=%d*", *seed))
+	case c.config.OpenAI.Seed != nil:
+		parts = append(parts, fmt.Sprintf("seed=%d", *c.config.OpenAI.Seed))
+	}
+
+	line := strings.Join(parts, " ")
+	if strings.Contains(line, "*") {
+		line += " (* = session override)"
+	}
+	return line
+}
+
+// setParam applies one "/params <name> <value|reset>" change to the current
+// session, enforcing the parameter's allowed range.
+func (c *ChatLoop) setParam(name, value string) error {
+	session := c.historyManager.Current()
+	if session == nil {
+		return fmt.Errorf("no current session")
+	}
+	reset := value == "reset"
+
+	switch name {
+	case "top_p":
+		if reset {
+			session.TopP = nil
+			fmt.Println("top_p reset to the config value.")
+			return nil
+		}
+		v, err := parseFloatParam(name, value, 0, 1)
+		if err != nil {
+			return err
+		}
+		if v == 0 {
+			return fmt.Errorf("top_p must be greater than 0 and at most 1, got %g", v)
+		}
+		session.TopP = &v
+		fmt.Printf("top_p set to %g for this session.\n", v)
+	case "presence_penalty":
+		if reset {
+			session.PresencePenalty = nil
+			fmt.Println("presence_penalty reset to the config value.")
+			return nil
+		}
+		v, err := parseFloatParam(name, value, -2, 2)
+		if err != nil {
+			return err
+		}
+		session.PresencePenalty = &v
+		fmt.Printf("presence_penalty set to %g for this session.\n", v)
+	case "frequency_penalty":
+		if reset {
+			session.FrequencyPenalty = nil
+			fmt.Println("frequency_penalty reset to the config value.")
+			return nil
+		}
+		v, err := parseFloatParam(name, value, -2, 2)
+		if err != nil {
+			return err
+		}
+		session.FrequencyPenalty = &v
+		fmt.Printf("frequency_penalty set to %g for this session.\n", v)
+	case "seed":
+		if reset {
+			session.Seed = nil
+			fmt.Println("seed reset to the config value.")
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("seed must be an integer, got %q", value)
+		}
+		session.Seed = &n
+		fmt.Printf("seed set to %d for this session.\n", n)
+	default:
+		return fmt.Errorf("unknown parameter %q (top_p, presence_penalty, frequency_penalty, or seed)", name)
+	}
+	return nil
+}
+
+// parseFloatParam parses a float parameter value and checks it against the
+// inclusive range the API accepts.
+func parseFloatParam(name, value string, low, high float64) (float64, error) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number, got %q", name, value)
+	}
+	if v < low || v > high {
+		return 0, fmt.Errorf("%s must be between %g and %g, got %g", name, low, high, v)
+	}
+	return v, nil
+}
+
+// formatFloatParam renders the effective value of an optional float
+// parameter with its source.
+func formatFloatParam(override, configured *float64) string {
+	switch {
+	case override != nil:
+		return fmt.Sprintf("%g (session override)", *override)
+	case configured != nil:
+		return fmt.Sprintf("%g", *configured)
+	default:
+		return "(unset)"
+	}
+}
+
+// formatSeedParam renders the effective seed with its source.
+func formatSeedParam(override, configured *int) string {
+	switch {
+	case override != nil:
+		return fmt.Sprintf("%d (session override)", *override)
+	case configured != nil:
+		return fmt.Sprintf("%d", *configured)
+	default:
+		return "(unset)"
+	}
+}
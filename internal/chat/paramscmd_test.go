@@ -0,0 +1,89 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSetParamValidation exercises /params value parsing and range checks.
+func TestSetParamValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   string
+		value   string
+		wantErr string // substring of the expected error, "" for success
+	}{
+		{"top_p valid", "top_p", "0.9", ""},
+		{"top_p zero", "top_p", "0", "greater than 0"},
+		{"top_p too large", "top_p", "1.5", "between 0 and 1"},
+		{"top_p not a number", "top_p", "high", "must be a number"},
+		{"presence_penalty valid", "presence_penalty", "-1.5", ""},
+		{"presence_penalty out of range", "presence_penalty", "2.5", "between -2 and 2"},
+		{"frequency_penalty valid", "frequency_penalty", "2", ""},
+		{"frequency_penalty out of range", "frequency_penalty", "-3", "between -2 and 2"},
+		{"seed valid", "seed", "42", ""},
+		{"seed not an integer", "seed", "4.2", "must be an integer"},
+		{"unknown parameter", "seeed", "1", "unknown parameter"},
+	}
+
+	loop := newTestChatLoop(t, "http://unused.invalid")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loop.setParam(tt.param, tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("setParam(%q, %q) = %v, want success", tt.param, tt.value, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("setParam(%q, %q) = %v, want error containing %q", tt.param, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestSetParamResetClearsOverride verifies "reset" reverts a session
+// override to the config value.
+func TestSetParamResetClearsOverride(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused.invalid")
+	session := loop.historyManager.Current()
+
+	if err := loop.setParam("top_p", "0.5"); err != nil {
+		t.Fatalf("setParam: %v", err)
+	}
+	if session.TopP == nil || *session.TopP != 0.5 {
+		t.Fatalf("expected session top_p override 0.5, got %v", session.TopP)
+	}
+	if err := loop.setParam("top_p", "reset"); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if session.TopP != nil {
+		t.Errorf("expected top_p override cleared, got %v", *session.TopP)
+	}
+}
+
+// TestEffectiveParamsLine checks the /context summary line, including the
+// session-override marker.
+func TestEffectiveParamsLine(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused.invalid")
+	loop.config.OpenAI.MaxTokens = 1000
+	loop.config.OpenAI.Temperature = 0.7
+	topP := 0.9
+	loop.config.OpenAI.TopP = &topP
+
+	line := loop.effectiveParamsLine()
+	want := "max_tokens=1000 temperature=0.7 top_p=0.9"
+	if line != want {
+		t.Errorf("effectiveParamsLine() = %q, want %q", line, want)
+	}
+
+	if err := loop.setParam("seed", "42"); err != nil {
+		t.Fatalf("setParam: %v", err)
+	}
+	line = loop.effectiveParamsLine()
+	want = "max_tokens=1000 temperature=0.7 top_p=0.9 seed=42* (* = session override)"
+	if line != want {
+		t.Errorf("effectiveParamsLine() = %q, want %q", line, want)
+	}
+}
@@ -0,0 +1,90 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"gopus/internal/animator"
+	"gopus/internal/mcp"
+	"gopus/internal/printer"
+	"gopus/internal/spinner"
+)
+
+// withToolSpinner runs action under a spinner that progress notifications
+// from the executing tools may relabel, e.g. "[read_dataset: 45% — parsing
+// rows]". When output is not a terminal the label is printed once and
+// progress updates are dropped.
+func (c *ChatLoop) withToolSpinner(label string, action func() []toolOutcome) []toolOutcome {
+	if !printer.ColorsEnabled() || !spinner.Enabled() {
+		fmt.Println(label)
+		return action()
+	}
+
+	spin := spinner.NewWithLabel(label)
+	anim := animator.NewAnimator(spin)
+	c.toolSpinnerMu.Lock()
+	c.toolSpinner = spin
+	c.toolAnimator = anim
+	c.toolSpinnerMu.Unlock()
+	defer func() {
+		c.toolSpinnerMu.Lock()
+		c.toolSpinner = nil
+		c.toolAnimator = nil
+		c.toolSpinnerMu.Unlock()
+	}()
+
+	anim.Start()
+	// Stop clears the spinner line, so the last progress message does not
+	// linger after the call finishes
+	defer anim.Stop()
+
+	return action()
+}
+
+// printToolStatus prints a status line while tools may be executing. With
+// an active tool animator the line goes through its synchronized Println,
+// which clears the spinner frame, prints, and redraws; otherwise the line
+// prints directly.
+func (c *ChatLoop) printToolStatus(line string) {
+	c.toolSpinnerMu.Lock()
+	anim := c.toolAnimator
+	c.toolSpinnerMu.Unlock()
+
+	if anim != nil {
+		anim.Println(line)
+		return
+	}
+	fmt.Println(line)
+}
+
+// showToolProgress updates the running tool spinner with a progress
+// notification. It fires on a notification goroutine; when no tool spinner
+// is active (the call already finished) the update is dropped.
+func (c *ChatLoop) showToolProgress(u mcp.ProgressUpdate) {
+	c.toolSpinnerMu.Lock()
+	spinner := c.toolSpinner
+	c.toolSpinnerMu.Unlock()
+
+	if spinner == nil {
+		return
+	}
+	spinner.SetLabel(formatToolProgress(u))
+}
+
+// formatToolProgress renders a progress update as a compact status label,
+// e.g. "[read_dataset: 45% — parsing rows]".
+func formatToolProgress(u mcp.ProgressUpdate) string {
+	var parts []string
+	if u.Total > 0 {
+		parts = append(parts, fmt.Sprintf("%d%%", int(u.Progress/u.Total*100+0.5)))
+	} else if u.Progress > 0 {
+		parts = append(parts, fmt.Sprintf("%g", u.Progress))
+	}
+	if u.Message != "" {
+		parts = append(parts, u.Message)
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("[%s: working…]", u.Tool)
+	}
+	return fmt.Sprintf("[%s: %s]", u.Tool, strings.Join(parts, " — "))
+}
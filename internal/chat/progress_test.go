@@ -0,0 +1,43 @@
+package chat
+
+import (
+	"testing"
+
+	"gopus/internal/mcp"
+)
+
+func TestFormatToolProgress(t *testing.T) {
+	tests := []struct {
+		name   string
+		update mcp.ProgressUpdate
+		want   string
+	}{
+		{
+			"percentage and message",
+			mcp.ProgressUpdate{Tool: "read_dataset", Progress: 45, Total: 100, Message: "parsing rows"},
+			"[read_dataset: 45% — parsing rows]",
+		},
+		{
+			"percentage only",
+			mcp.ProgressUpdate{Tool: "fetch_url", Progress: 3, Total: 4},
+			"[fetch_url: 75%]",
+		},
+		{
+			"unknown total",
+			mcp.ProgressUpdate{Tool: "scan", Progress: 12, Message: "still going"},
+			"[scan: 12 — still going]",
+		},
+		{
+			"no details",
+			mcp.ProgressUpdate{Tool: "slow_tool"},
+			"[slow_tool: working…]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatToolProgress(tt.update); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+)
+
+// offerQueue asks whether a message that failed with a network-level error
+// should go into the session's offline queue instead of being discarded.
+// Called after the failed message was rolled back from both histories.
+func (c *ChatLoop) offerQueue(input string) {
+	if !confirmPrompt("The API looks unreachable. Queue the message and send it with /flush? [y/N]: ") {
+		return
+	}
+	if err := c.historyManager.Enqueue(input); err != nil {
+		fmt.Fprintf(os.Stderr, "Error queueing message: %v\n", err)
+		return
+	}
+	fmt.Printf("%s[queued]%s %s\n", printer.ColorYellow, printer.ColorReset, input)
+	fmt.Println("Run /flush when you are back online.")
+}
+
+// handleFlush processes the /flush command: it sends the session's queued
+// messages in order, interleaving each assistant reply after its message.
+// Every entry's sent flag hits the disk before its request goes out, so a
+// restart mid-flush never double-sends; already-sent entries are skipped,
+// making the flush idempotent.
+func (c *ChatLoop) handleFlush(ctx context.Context, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	queue := c.historyManager.QueuedMessages()
+	if len(queue) == 0 {
+		fmt.Println("No queued messages.")
+		return
+	}
+
+	sent := 0
+	for i, entry := range queue {
+		if entry.Sent {
+			continue
+		}
+
+		printer.PrintMessage(string(history.RoleUser)+" [queued]", entry.Content, false)
+
+		if err := c.historyManager.AddMessage(history.RoleUser, entry.Content); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving message: %v\n", err)
+			return
+		}
+		if err := c.historyManager.MarkQueued(i, true); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating queue: %v\n", err)
+			c.rollbackLastSessionMessage()
+			return
+		}
+		*chatHistory = append(*chatHistory, openai.ChatCompletionRequestMessage{
+			Role:    openai.RoleUser,
+			Content: openai.StringContent(entry.Content),
+		})
+
+		if err := c.processConversation(ctx, chatHistory); err != nil {
+			printer.PrintError("Error: %v", err)
+
+			// Put the entry back and stop: the message returns to the
+			// queue and the rest stays untouched for the next /flush
+			*chatHistory = (*chatHistory)[:len(*chatHistory)-1]
+			c.rollbackLastSessionMessage()
+			if markErr := c.historyManager.MarkQueued(i, false); markErr != nil {
+				fmt.Fprintf(os.Stderr, "Error updating queue: %v\n", markErr)
+			}
+			fmt.Printf("Flush stopped; %d message(s) still queued.\n", c.historyManager.PendingQueued())
+			return
+		}
+		sent++
+	}
+
+	if err := c.historyManager.CompactQueue(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error compacting queue: %v\n", err)
+	}
+	if sent > 0 {
+		fmt.Printf("Sent %d queued message(s).\n", sent)
+	} else {
+		fmt.Println("All queued messages were already sent.")
+	}
+}
+
+// rollbackLastSessionMessage removes the newest message from the session,
+// mirroring the main loop's cleanup after a failed request.
+func (c *ChatLoop) rollbackLastSessionMessage() {
+	if err := c.historyManager.TruncateLastMessage(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error removing failed message: %v\n", err)
+	}
+}
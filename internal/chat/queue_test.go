@@ -0,0 +1,112 @@
+package chat
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+)
+
+func TestHandleFlushSendsInOrder(t *testing.T) {
+	srv := scriptedServer(t, []scriptedResponse{
+		{http.StatusOK, completionBody(`"reply one"`, "stop")},
+		{http.StatusOK, completionBody(`"reply two"`, "stop")},
+	})
+	defer srv.Close()
+
+	loop := newTestChatLoop(t, srv.URL)
+	if err := loop.historyManager.Enqueue("question one"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := loop.historyManager.Enqueue("question two"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	chatHistory := []openai.ChatCompletionRequestMessage{}
+	loop.handleFlush(context.Background(), &chatHistory)
+
+	// Replies interleave after their questions in the session history
+	session := loop.historyManager.Current()
+	if len(session.Messages) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(session.Messages))
+	}
+	want := []string{"question one", "reply one", "question two", "reply two"}
+	for i, content := range want {
+		if session.Messages[i].Content != content {
+			t.Errorf("message %d = %q, want %q", i, session.Messages[i].Content, content)
+		}
+	}
+
+	if len(loop.historyManager.QueuedMessages()) != 0 {
+		t.Errorf("expected the queue to be compacted away, got %+v", loop.historyManager.QueuedMessages())
+	}
+}
+
+func TestHandleFlushStopsOnErrorAndKeepsQueue(t *testing.T) {
+	srv := scriptedServer(t, []scriptedResponse{
+		{http.StatusInternalServerError, `{"error": {"message": "boom", "type": "server_error"}}`},
+	})
+	defer srv.Close()
+
+	loop := newTestChatLoop(t, srv.URL)
+	if err := loop.historyManager.Enqueue("question one"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := loop.historyManager.Enqueue("question two"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	chatHistory := []openai.ChatCompletionRequestMessage{}
+	loop.handleFlush(context.Background(), &chatHistory)
+
+	if n := len(loop.historyManager.Current().Messages); n != 0 {
+		t.Errorf("expected the failed message to be rolled back, got %d messages", n)
+	}
+	if len(chatHistory) != 0 {
+		t.Errorf("expected an empty chat history, got %d entries", len(chatHistory))
+	}
+	if pending := loop.historyManager.PendingQueued(); pending != 2 {
+		t.Errorf("expected both entries to stay queued, got %d", pending)
+	}
+}
+
+func TestHandleFlushSkipsAlreadySentEntries(t *testing.T) {
+	srv := scriptedServer(t, []scriptedResponse{
+		{http.StatusOK, completionBody(`"reply two"`, "stop")},
+	})
+	defer srv.Close()
+
+	loop := newTestChatLoop(t, srv.URL)
+	if err := loop.historyManager.Enqueue("question one"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := loop.historyManager.Enqueue("question two"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	// Simulate a restart after the first entry went out but before the
+	// queue was compacted
+	if err := loop.historyManager.AddMessage(history.RoleUser, "question one"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+	if err := loop.historyManager.MarkQueued(0, true); err != nil {
+		t.Fatalf("MarkQueued failed: %v", err)
+	}
+
+	chatHistory := userMessages("question one")
+	loop.handleFlush(context.Background(), &chatHistory)
+
+	// Only the unsent entry was sent; the scripted server would fail the
+	// test on a second request
+	session := loop.historyManager.Current()
+	if len(session.Messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(session.Messages))
+	}
+	if session.Messages[2].Content != "reply two" {
+		t.Errorf("unexpected final message: %q", session.Messages[2].Content)
+	}
+	if len(loop.historyManager.QueuedMessages()) != 0 {
+		t.Errorf("expected the queue to be compacted away, got %+v", loop.historyManager.QueuedMessages())
+	}
+}
@@ -0,0 +1,85 @@
+package chat
+
+import (
+	"context"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+)
+
+// archiveForRecall embeds the messages a summarization pass is about to
+// discard and stores them in the session's vector sidecar. Best effort: a
+// failure only means those messages cannot be recalled later, so
+// summarization proceeds regardless.
+func (c *ChatLoop) archiveForRecall(ctx context.Context, sessionID string, messages []history.Message) {
+	if c.recaller == nil {
+		return
+	}
+	tiers := c.summarizer.ClassifyTiers(messages)
+	dropped := append(append([]history.Message{}, tiers.ToCompress...), tiers.ToCondense...)
+	if len(dropped) == 0 {
+		return
+	}
+	if err := c.recaller.Archive(ctx, sessionID, dropped); err != nil {
+		c.async.Printf("Embeddings archive failed; these messages will not be recallable: %v", err)
+	}
+}
+
+// recallContext returns the labeled block of archived messages relevant to
+// the latest user prompt, or "" when recall is disabled, empty, or failing.
+// On failure the turn degrades to plain summaries.
+func (c *ChatLoop) recallContext(ctx context.Context, chatHistory []openai.ChatCompletionRequestMessage) string {
+	if c.recaller == nil {
+		return ""
+	}
+	session := c.historyManager.Current()
+	if session == nil {
+		return ""
+	}
+	prompt := lastUserContent(chatHistory)
+	if prompt == "" {
+		return ""
+	}
+	block, err := c.recaller.Recall(ctx, session.ID, prompt)
+	if err != nil {
+		printer.PrintWarning("Embeddings recall unavailable, continuing without it: %v", err)
+		return ""
+	}
+	return block
+}
+
+// lastUserContent returns the text of the most recent user message.
+func lastUserContent(chatHistory []openai.ChatCompletionRequestMessage) string {
+	for i := len(chatHistory) - 1; i >= 0; i-- {
+		if chatHistory[i].Role != openai.RoleUser {
+			continue
+		}
+		if content, ok := openai.ContentAsString(chatHistory[i].Content); ok {
+			return content
+		}
+		return ""
+	}
+	return ""
+}
+
+// withRecallContext returns the request messages with the recalled block
+// inserted as a system message after the leading system prompt, if any. The
+// input slice is not modified: the block is rebuilt per turn and must never
+// persist into the session history.
+func withRecallContext(messages []openai.ChatCompletionRequestMessage, block string) []openai.ChatCompletionRequestMessage {
+	if block == "" {
+		return messages
+	}
+	pos := 0
+	if len(messages) > 0 && messages[0].Role == openai.RoleSystem {
+		pos = 1
+	}
+	out := make([]openai.ChatCompletionRequestMessage, 0, len(messages)+1)
+	out = append(out, messages[:pos]...)
+	out = append(out, openai.ChatCompletionRequestMessage{
+		Role:    openai.RoleSystem,
+		Content: openai.StringContent(block),
+	})
+	return append(out, messages[pos:]...)
+}
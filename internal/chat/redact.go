@@ -0,0 +1,77 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"gopus/internal/config"
+	"gopus/internal/printer"
+	"gopus/internal/redact"
+)
+
+// checkOutgoingMessage scans a user message for secrets according to the
+// configured redaction mode. It returns the text to send (possibly with
+// [REDACTED:<type>] markers) and whether to send it at all. The redacted
+// form is what goes into the session too, so secrets never hit disk.
+func (c *ChatLoop) checkOutgoingMessage(input string) (string, bool) {
+	if c.redactor == nil {
+		return input, true
+	}
+
+	switch c.config.Redaction.Mode {
+	case config.RedactionModeConfirm:
+		findings := c.redactor.Scan(input)
+		if len(findings) == 0 {
+			return input, true
+		}
+		fmt.Printf("%sPossible secret(s) in your message:%s\n", printer.ColorYellow, printer.ColorReset)
+		for _, f := range findings {
+			fmt.Printf("  - %s: %s\n", f.Type, redact.Preview(f.Match))
+		}
+		if !confirmPrompt("Send anyway? [y/N]: ") {
+			fmt.Println("Message not sent.")
+			return "", false
+		}
+		return input, true
+
+	case config.RedactionModeRedact:
+		redacted, findings := c.redactor.Redact(input)
+		if len(findings) == 0 {
+			return input, true
+		}
+		fmt.Printf("%s[Redacted %d possible secret(s) before sending: %s]%s\n",
+			printer.ColorYellow, len(findings), strings.Join(redact.Types(findings), ", "), printer.ColorReset)
+		return redacted, true
+	}
+
+	return input, true
+}
+
+// redactToolResult scans a tool result before it goes back to the model.
+// Tool output is always auto-redacted when redaction is on, even in confirm
+// mode: the call has already run, so there is nothing left to decline.
+func (c *ChatLoop) redactToolResult(content string) string {
+	if c.redactor == nil {
+		return content
+	}
+	redacted, findings := c.redactor.Redact(content)
+	if len(findings) > 0 {
+		fmt.Printf("%s[Redacted %d possible secret(s) in tool result: %s]%s\n",
+			printer.ColorYellow, len(findings), strings.Join(redact.Types(findings), ", "), printer.ColorReset)
+	}
+	return redacted
+}
+
+// newRedactor builds the secret scanner for the given config, or nil when
+// redaction is off. Invalid custom patterns are caught by config validation,
+// so a failure here only loses the custom detectors.
+func newRedactor(cfg config.RedactionConfig) *redact.Scanner {
+	if cfg.Mode == "" || cfg.Mode == config.RedactionModeOff {
+		return nil
+	}
+	scanner, err := redact.NewScanner(cfg.Patterns, cfg.HighEntropy)
+	if err != nil {
+		scanner, _ = redact.NewScanner(nil, cfg.HighEntropy)
+	}
+	return scanner
+}
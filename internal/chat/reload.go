@@ -0,0 +1,208 @@
+package chat
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopus/internal/config"
+	"gopus/internal/history"
+	"gopus/internal/printer"
+	"gopus/internal/spinner"
+)
+
+// handleReload re-reads the config file and applies whatever can change
+// while running: model parameters, summarization settings, tool confirmation
+// policy, and the MCP server list. Settings baked into the HTTP client
+// (base_url, api_key) require a restart and are reported as such.
+// A failed parse leaves the running config fully in effect.
+func (c *ChatLoop) handleReload(ctx context.Context) {
+	newCfg, err := config.LoadQuiet(c.configPath)
+	if err != nil {
+		c.async.Printf("Reload failed, keeping current configuration: %v", err)
+		return
+	}
+
+	old := c.config
+	var applied []string
+
+	if newCfg.OpenAI.BaseURL != old.OpenAI.BaseURL || newCfg.OpenAI.APIKey != old.OpenAI.APIKey {
+		c.async.Printf("Note: base_url/api_key changes require a restart and were not applied.")
+		newCfg.OpenAI.BaseURL = old.OpenAI.BaseURL
+		newCfg.OpenAI.APIKey = old.OpenAI.APIKey
+	}
+
+	// Safe mode never turns off on a reload; --safe stays in force.
+	newCfg.Chat.SafeMode = newCfg.Chat.SafeMode || old.Chat.SafeMode
+
+	// A changed sessions directory can cancel the whole reload, so resolve
+	// it before anything else is applied
+	if newCfg.History.SessionsDir != old.History.SessionsDir {
+		changed, ok := c.resolveSessionsDirChange(newCfg.History.SessionsDir)
+		if !ok {
+			c.async.Printf("Reload cancelled, keeping current configuration.")
+			return
+		}
+		if changed {
+			applied = append(applied, "sessions directory")
+		}
+	}
+
+	if !reflect.DeepEqual(newCfg.OpenAI, old.OpenAI) {
+		c.client.UpdateSettings(newCfg.OpenAI)
+		applied = append(applied, "model parameters")
+	}
+	if !reflect.DeepEqual(newCfg.Summarization, old.Summarization) {
+		c.summarizer.SetConfig(newCfg.Summarization)
+		applied = append(applied, "summarization")
+	}
+	if newCfg.MCP.ToolConfirmation != old.MCP.ToolConfirmation {
+		applied = append(applied, "tool confirmation")
+	}
+	if !reflect.DeepEqual(newCfg.Redaction, old.Redaction) {
+		c.redactor = newRedactor(newCfg.Redaction)
+		applied = append(applied, "redaction")
+	}
+	if newCfg.UI.Spinner != old.UI.Spinner {
+		spinner.SetStyle(spinner.Style(newCfg.UI.Spinner))
+		applied = append(applied, "spinner style")
+	}
+	if newCfg.UI.ShowTimestamps != old.UI.ShowTimestamps {
+		printer.SetShowTimestamps(newCfg.UI.ShowTimestamps)
+		applied = append(applied, "timestamps")
+	}
+	if changes := c.reloadMCPServers(ctx, old.MCP.Servers, newCfg.MCP.Servers); changes > 0 {
+		applied = append(applied, fmt.Sprintf("%d MCP server(s)", changes))
+	}
+
+	// Swap the config in place so every holder of the pointer sees it
+	*c.config = *newCfg
+
+	if len(applied) == 0 {
+		c.async.Printf("Configuration reloaded (no live changes).")
+		return
+	}
+	c.async.Printf("Configuration reloaded, applied: %s.", strings.Join(applied, ", "))
+}
+
+// resolveSessionsDirChange handles a changed history.sessions_dir while a
+// session is open. The user chooses whether to keep the current session in
+// the old directory (switching for new sessions only), move its file to the
+// new directory, or cancel the reload. Returns whether anything changed and
+// whether the reload may proceed.
+func (c *ChatLoop) resolveSessionsDirChange(configured string) (changed, ok bool) {
+	newDir := configured
+	if newDir == "" {
+		var err error
+		if newDir, err = history.DefaultSessionsDir(); err != nil {
+			c.async.Printf("Cannot resolve default sessions directory: %v", err)
+			return false, false
+		}
+	}
+	oldDir := c.historyManager.SessionsDir()
+	if newDir == oldDir {
+		return false, true
+	}
+
+	// An unsaved session has nothing on disk to keep or move; just switch
+	current := c.historyManager.Current()
+	if current == nil || len(current.Messages) == 0 {
+		if err := c.historyManager.SetSessionsDir(newDir); err != nil {
+			c.async.Printf("Failed to switch sessions directory: %v", err)
+			return false, false
+		}
+		return true, true
+	}
+
+	fmt.Printf("The sessions directory changed from %s to %s.\n", oldDir, newDir)
+	fmt.Println("  [k] keep the current session in the old directory (new sessions use the new one)")
+	fmt.Println("  [m] move the current session file to the new directory")
+	fmt.Println("  [c] cancel the reload")
+	switch promptChoice("Choice [k/m/c]: ") {
+	case "k":
+		c.pendingSessionsDir = newDir
+		fmt.Println("Keeping the current session; the next new session switches directories.")
+		return true, true
+	case "m":
+		if err := c.historyManager.Relocate(current.ID, newDir); err != nil {
+			c.async.Printf("Failed to move session: %v", err)
+			return false, false
+		}
+		if err := c.historyManager.SetSessionsDir(newDir); err != nil {
+			c.async.Printf("Failed to switch sessions directory: %v", err)
+			return false, false
+		}
+		fmt.Println("Moved the current session to the new directory.")
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// promptChoice reads a one-letter answer from stdin, trimmed and lowercased.
+func promptChoice(prompt string) string {
+	fmt.Print(prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(line))
+}
+
+// reloadMCPServers diffs the configured MCP servers and applies the changes:
+// newly enabled servers are started, removed or disabled ones are stopped,
+// and changed entries are restarted. Returns the number of servers touched.
+func (c *ChatLoop) reloadMCPServers(ctx context.Context, oldServers, newServers []config.MCPServerConfig) int {
+	oldByName := make(map[string]config.MCPServerConfig, len(oldServers))
+	for _, s := range oldServers {
+		oldByName[s.Name] = s
+	}
+	newByName := make(map[string]config.MCPServerConfig, len(newServers))
+	for _, s := range newServers {
+		newByName[s.Name] = s
+	}
+
+	changes := 0
+	if c.mcpManager == nil {
+		if !reflect.DeepEqual(oldByName, newByName) {
+			c.async.Printf("Note: MCP manager unavailable; server changes were not applied.")
+		}
+		return 0
+	}
+
+	// Stop servers that were removed, disabled, or changed
+	for name, oldServer := range oldByName {
+		newServer, stillThere := newByName[name]
+		if oldServer.Enabled && (!stillThere || !newServer.Enabled || !reflect.DeepEqual(oldServer, newServer)) {
+			if err := c.mcpManager.RemoveServer(name); err != nil {
+				c.async.Printf("Failed to stop MCP server %q: %v", name, err)
+				continue
+			}
+			c.async.Printf("Stopped MCP server: %s", name)
+			changes++
+		}
+	}
+
+	// Start servers that are new, re-enabled, or changed
+	for name, newServer := range newByName {
+		if !newServer.Enabled {
+			continue
+		}
+		oldServer, wasThere := oldByName[name]
+		if wasThere && oldServer.Enabled && reflect.DeepEqual(oldServer, newServer) {
+			continue // unchanged
+		}
+
+		if err := c.mcpManager.AddServerWithConfig(ctx, newServer); err != nil {
+			c.async.Printf("Failed to start MCP server %q: %v", name, err)
+			continue
+		}
+		c.async.Printf("Started MCP server: %s", name)
+		changes++
+	}
+
+	return changes
+}
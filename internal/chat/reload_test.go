@@ -0,0 +1,85 @@
+package chat
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeConfig writes a config file into a temp dir and returns its path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestReloadAppliesLiveSettings(t *testing.T) {
+	loop := newTestChatLoop(t, "http://localhost:0")
+	loop.configPath = writeConfig(t, `
+openai:
+  api_key: test-key
+  model: new-model
+  temperature: 1.5
+`)
+
+	loop.handleReload(context.Background())
+
+	if got := loop.config.OpenAI.Model; got != "new-model" {
+		t.Errorf("expected model applied, got %q", got)
+	}
+	if got := loop.config.OpenAI.Temperature; got != 1.5 {
+		t.Errorf("expected temperature applied, got %g", got)
+	}
+}
+
+func TestReloadKeepsOldConfigOnParseError(t *testing.T) {
+	loop := newTestChatLoop(t, "http://localhost:0")
+	oldModel := loop.config.OpenAI.Model
+	loop.configPath = writeConfig(t, "openai: [not valid\n")
+
+	loop.handleReload(context.Background())
+
+	if got := loop.config.OpenAI.Model; got != oldModel {
+		t.Errorf("expected old config kept after parse error, got model %q", got)
+	}
+}
+
+func TestReloadDoesNotApplyBaseURLChange(t *testing.T) {
+	loop := newTestChatLoop(t, "http://localhost:0")
+	oldBaseURL := loop.config.OpenAI.BaseURL
+	loop.configPath = writeConfig(t, `
+openai:
+  api_key: other-key
+  base_url: https://example.invalid/v1
+`)
+
+	loop.handleReload(context.Background())
+
+	if got := loop.config.OpenAI.BaseURL; got != oldBaseURL {
+		t.Errorf("expected base_url unchanged, got %q", got)
+	}
+	if got := loop.config.OpenAI.APIKey; got != "test-key" {
+		t.Errorf("expected api_key unchanged, got %q", got)
+	}
+}
+
+func TestReloadPreservesSafeMode(t *testing.T) {
+	loop := newTestChatLoop(t, "http://localhost:0")
+	loop.config.Chat.SafeMode = true
+	loop.configPath = writeConfig(t, `
+openai:
+  api_key: test-key
+chat:
+  safe_mode: false
+`)
+
+	loop.handleReload(context.Background())
+
+	if !loop.config.Chat.SafeMode {
+		t.Error("expected safe mode to stay enabled across reload")
+	}
+}
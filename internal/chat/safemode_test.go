@@ -0,0 +1,49 @@
+package chat
+
+import (
+	"context"
+	"testing"
+
+	"gopus/internal/openai"
+)
+
+func TestSafeModeBlocksDestructiveCommands(t *testing.T) {
+	loop := newTestChatLoop(t, "http://localhost:0")
+	loop.config.Chat.SafeMode = true
+
+	var chatHistory []openai.ChatCompletionRequestMessage
+	for _, cmd := range []string{"/delete 1", "/prune", "/rollback"} {
+		if !loop.handleCommand(context.Background(), cmd, &chatHistory) {
+			t.Errorf("expected %s to be handled (and blocked) in safe mode", cmd)
+		}
+	}
+
+	if got := len(loop.historyManager.Current().Messages); got != 0 {
+		t.Errorf("blocked commands should not touch the session, got %d messages", got)
+	}
+}
+
+func TestSafeModeBlocksShellEscape(t *testing.T) {
+	loop := newTestChatLoop(t, "http://localhost:0")
+	loop.config.Chat.SafeMode = true
+
+	var chatHistory []openai.ChatCompletionRequestMessage
+	loop.handleShellEscape(context.Background(), "!echo should not run", &chatHistory)
+
+	if loop.lastShellCommand != "" {
+		t.Errorf("expected the command not to run in safe mode, got %q", loop.lastShellCommand)
+	}
+	if got := len(loop.historyManager.Current().Messages); got != 0 {
+		t.Errorf("blocked shell escapes should not touch the session, got %d messages", got)
+	}
+}
+
+func TestSafeModeForcesToolConfirmation(t *testing.T) {
+	loop := newTestChatLoop(t, "http://localhost:0")
+	loop.config.Chat.SafeMode = true
+	// Even with confirmation disabled, safe mode must prompt; with no
+	// interactive input available the prompt is declined.
+	if loop.confirmToolExecution(nil) {
+		t.Error("expected safe mode to require explicit confirmation")
+	}
+}
@@ -0,0 +1,89 @@
+package chat
+
+import (
+	"testing"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+)
+
+func TestSwitchSessionReplacesChatHistory(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	sessions := listTestSessions(t, loop, "other work")
+
+	other := sessions[1]
+	other.Messages = append(other.Messages,
+		history.Message{Role: history.RoleUser, Content: "earlier question"},
+		history.Message{Role: history.RoleAssistant, Content: "earlier answer"},
+	)
+	if err := loop.historyManager.Save(other); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	chatHistory := []openai.ChatCompletionRequestMessage{
+		{Role: openai.RoleUser, Content: openai.StringContent("current")},
+	}
+	loop.handleSwitch("other work", &chatHistory)
+
+	if current := loop.historyManager.Current(); current.ID != other.ID {
+		t.Errorf("expected current session %s, got %s", other.ID, current.ID)
+	}
+	if len(chatHistory) != 2 {
+		t.Errorf("expected chat history rebuilt from the switched session, got %d messages", len(chatHistory))
+	}
+}
+
+func TestSwitchToCurrentSessionIsNoop(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	sessions := listTestSessions(t, loop)
+	sessions[0].Name = "current"
+	loop.listed = sessions
+
+	chatHistory := []openai.ChatCompletionRequestMessage{}
+	loop.handleSwitch("current", &chatHistory)
+
+	if current := loop.historyManager.Current(); current.ID != sessions[0].ID {
+		t.Errorf("expected to stay in session %s, got %s", sessions[0].ID, current.ID)
+	}
+}
+
+func TestRenameSessionPersists(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+
+	loop.handleRename("better name")
+
+	current := loop.historyManager.Current()
+	if current.Name != "better name" {
+		t.Errorf("expected renamed session, got %q", current.Name)
+	}
+	loaded, err := loop.historyManager.LoadSessionByID(current.ID)
+	if err != nil {
+		t.Fatalf("LoadSessionByID: %v", err)
+	}
+	if loaded.Name != "better name" {
+		t.Errorf("expected the rename saved to disk, got %q", loaded.Name)
+	}
+}
+
+func TestNewSessionKeepsOldOneOnDisk(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	old := loop.historyManager.Current()
+	if err := loop.historyManager.AddMessage(history.RoleUser, "keep me"); err != nil {
+		t.Fatalf("AddMessage: %v", err)
+	}
+
+	chatHistory := []openai.ChatCompletionRequestMessage{
+		{Role: openai.RoleUser, Content: openai.StringContent("keep me")},
+	}
+	loop.handleNew("", &chatHistory)
+
+	if current := loop.historyManager.Current(); current.ID == old.ID {
+		t.Error("expected a fresh session")
+	}
+	if len(chatHistory) != 0 {
+		t.Errorf("expected an empty chat history, got %d messages", len(chatHistory))
+	}
+	if _, err := loop.historyManager.LoadSessionByID(old.ID); err != nil {
+		t.Errorf("expected the old session to stay on disk: %v", err)
+	}
+}
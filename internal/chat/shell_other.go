@@ -0,0 +1,10 @@
+//go:build !unix
+
+package chat
+
+import "os/exec"
+
+// setupProcessGroup is a no-op where process groups are unavailable; the
+// context still kills the direct sh child, and runShell's wait delay
+// keeps orphaned grandchildren from wedging the chat loop.
+func setupProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,20 @@
+//go:build unix
+
+package chat
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setupProcessGroup runs the command in its own process group and kills
+// the whole group — not just the direct sh child — when the context
+// fires, so a timed-out pipeline's grandchildren cannot keep writing to
+// the inherited terminal.
+func setupProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		// The negative PID addresses the process group
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,205 @@
+package chat
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"gopus/internal/history"
+	"gopus/internal/i18n"
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+)
+
+const (
+	// shellTimeout bounds a `!` command, so one that never exits cannot
+	// wedge the chat loop; its whole process group is killed when it
+	// fires (see setupProcessGroup).
+	shellTimeout = 2 * time.Minute
+
+	// shellShareLimit caps how much captured output can be shared with the
+	// model. The terminal always gets the full stream.
+	shellShareLimit = 16 * 1024
+)
+
+// handleShellEscape runs a `!command` line locally and offers to share the
+// output with the model. The human typed the command, so no tool
+// confirmation applies; the safety valves are the timeout and the size cap
+// on what reaches the API. `!!` reruns the previous command.
+func (c *ChatLoop) handleShellEscape(ctx context.Context, input string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	if c.config.Chat.SafeMode {
+		fmt.Println("The ! shell escape is disabled in safe mode.")
+		return
+	}
+	command := strings.TrimSpace(strings.TrimPrefix(input, "!"))
+	if command == "!" {
+		command = c.lastShellCommand
+		if command == "" {
+			printer.PrintError("No previous shell command to rerun.")
+			return
+		}
+		fmt.Printf("$ %s\n", command)
+	}
+	if command == "" {
+		fmt.Println("Usage: !<command> (or !! to rerun the previous command)")
+		return
+	}
+	c.lastShellCommand = command
+
+	// Stream to the terminal while capturing a capped copy for sharing.
+	// Stdin stays closed: a command waiting for input sees EOF instead of
+	// stealing keystrokes from the prompt.
+	captured := &cappedBuffer{limit: shellShareLimit}
+	exitCode, timedOut, err := runShell(ctx, command,
+		io.MultiWriter(os.Stdout, captured), io.MultiWriter(os.Stderr, captured))
+	switch {
+	case timedOut:
+		printer.PrintWarning("Command killed after %s.", shellTimeout)
+	case err != nil:
+		printer.PrintError("Failed to run command: %v", err)
+		return
+	case exitCode != 0:
+		fmt.Printf("%s[exit status %d]%s\n", printer.ColorYellow, exitCode, printer.ColorReset)
+	}
+
+	if !confirmPrompt(i18n.T(i18n.ConfirmShareOutput)) {
+		return
+	}
+
+	message := shellMessage(command, captured, exitCode, timedOut)
+	if err := c.historyManager.AddMessage(history.RoleUser, message); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving message: %v\n", err)
+		return
+	}
+	*chatHistory = append(*chatHistory, openai.ChatCompletionRequestMessage{
+		Role:    openai.RoleUser,
+		Content: openai.StringContent(message),
+	})
+	fmt.Println("Output added to the conversation; it is sent with your next message.")
+}
+
+// runShell executes command under sh -c with the shell timeout, writing
+// the streams as they arrive. It returns the exit code (-1 when the
+// command was killed) and whether the timeout did the killing.
+func runShell(ctx context.Context, command string, stdout, stderr io.Writer) (exitCode int, timedOut bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, shellTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	setupProcessGroup(cmd)
+	// Wait gives up copying output this long after the kill, in case a
+	// surviving grandchild still holds the output pipe open
+	cmd.WaitDelay = 5 * time.Second
+
+	runErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return -1, true, nil
+	}
+	if runErr != nil {
+		var exitErr *exec.ExitError
+		if errors.As(runErr, &exitErr) {
+			return exitErr.ExitCode(), false, nil
+		}
+		return -1, false, runErr
+	}
+	return 0, false, nil
+}
+
+// shellMessage renders the command and its captured output as the user
+// message shared with the model. Binary output is replaced by a note and
+// truncation is called out, so the payload stays valid and bounded.
+func shellMessage(command string, captured *cappedBuffer, exitCode int, timedOut bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "I ran this command locally:\n\n    $ %s\n\n", command)
+	switch {
+	case timedOut:
+		fmt.Fprintf(&b, "It was killed after %s without exiting.\n", shellTimeout)
+	case exitCode != 0:
+		fmt.Fprintf(&b, "It exited with status %d.\n", exitCode)
+	}
+
+	output := captured.Bytes()
+	switch {
+	case len(output) == 0:
+		b.WriteString("It produced no output.")
+	case isBinaryOutput(output):
+		fmt.Fprintf(&b, "The output looks binary (%d bytes) and is not included.", captured.Total())
+	default:
+		b.WriteString("Output:\n\n```\n")
+		b.Write(output)
+		if !bytes.HasSuffix(output, []byte("\n")) {
+			b.WriteString("\n")
+		}
+		b.WriteString("```")
+		if captured.Truncated() {
+			fmt.Fprintf(&b, "\n(truncated; %d bytes total)", captured.Total())
+		}
+	}
+	return b.String()
+}
+
+// isBinaryOutput uses the classic heuristic: a NUL byte near the start
+// means the data is not text.
+func isBinaryOutput(data []byte) bool {
+	probe := data
+	if len(probe) > 1024 {
+		probe = probe[:1024]
+	}
+	return bytes.IndexByte(probe, 0) >= 0
+}
+
+// cappedBuffer keeps the first limit bytes written to it and counts the
+// rest, so unbounded command output cannot balloon the API payload. The
+// mutex matters: the buffer sits behind both of the command's streams,
+// and os/exec copies each through its own goroutine.
+type cappedBuffer struct {
+	limit int
+
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	total int
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.total += len(p)
+	if room := c.limit - c.buf.Len(); room > 0 {
+		if len(p) > room {
+			p = p[:room]
+		}
+		c.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+// Bytes returns the captured prefix of the output.
+func (c *cappedBuffer) Bytes() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.Bytes()
+}
+
+// Total returns how many bytes the command wrote in all.
+func (c *cappedBuffer) Total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+// Truncated reports whether output was dropped by the cap.
+func (c *cappedBuffer) Truncated() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total > c.buf.Len()
+}
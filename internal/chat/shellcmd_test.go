@@ -0,0 +1,193 @@
+package chat
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunShell(t *testing.T) {
+	tests := []struct {
+		name       string
+		command    string
+		wantExit   int
+		wantStdout string
+		wantStderr string
+	}{
+		{name: "stdout", command: "echo hello", wantExit: 0, wantStdout: "hello\n"},
+		{name: "stderr", command: "echo oops >&2", wantExit: 0, wantStderr: "oops\n"},
+		{name: "exit code", command: "exit 3", wantExit: 3},
+		{name: "stdin is closed", command: "cat", wantExit: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			exitCode, timedOut, err := runShell(context.Background(), tt.command, &stdout, &stderr)
+			if err != nil {
+				t.Fatalf("runShell failed: %v", err)
+			}
+			if timedOut {
+				t.Fatal("unexpected timeout")
+			}
+			if exitCode != tt.wantExit {
+				t.Errorf("exit code = %d, want %d", exitCode, tt.wantExit)
+			}
+			if stdout.String() != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.wantStdout)
+			}
+			if stderr.String() != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRunShellRespectsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var stdout, stderr bytes.Buffer
+	if _, _, err := runShell(ctx, "sleep 60", &stdout, &stderr); err == nil {
+		t.Error("expected an error when the context is already cancelled")
+	}
+}
+
+func TestRunShellKillsProcessGroupOnTimeout(t *testing.T) {
+	// The parent deadline stands in for shellTimeout. Without the process
+	// group kill the backgrounded grandchild keeps the output pipe open
+	// and only the wait delay unblocks the loop.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	start := time.Now()
+	_, timedOut, err := runShell(ctx, "sleep 60 & sleep 60", &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("runShell failed: %v", err)
+	}
+	if !timedOut {
+		t.Error("expected the command to be reported as timed out")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("runShell took %s to return; surviving children held it up", elapsed)
+	}
+}
+
+func TestCappedBuffer(t *testing.T) {
+	buf := &cappedBuffer{limit: 5}
+	if _, err := buf.Write([]byte("abc")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := buf.Write([]byte("defgh")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if got := string(buf.Bytes()); got != "abcde" {
+		t.Errorf("Bytes() = %q, want %q", got, "abcde")
+	}
+	if buf.Total() != 8 {
+		t.Errorf("Total() = %d, want 8", buf.Total())
+	}
+	if !buf.Truncated() {
+		t.Error("expected Truncated() after writing past the limit")
+	}
+}
+
+func TestCappedBufferConcurrentWrites(t *testing.T) {
+	// Both command streams feed one buffer, each through its own os/exec
+	// copying goroutine; the race detector flags Write without its lock
+	buf := &cappedBuffer{limit: 1024}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				buf.Write([]byte("0123456789"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if buf.Total() != 8000 {
+		t.Errorf("Total() = %d, want 8000", buf.Total())
+	}
+	if got := len(buf.Bytes()); got != 1024 {
+		t.Errorf("captured %d bytes, want the 1024-byte cap", got)
+	}
+	if !buf.Truncated() {
+		t.Error("expected Truncated() after writing past the limit")
+	}
+}
+
+func TestShellMessage(t *testing.T) {
+	capture := func(data string, limit int) *cappedBuffer {
+		buf := &cappedBuffer{limit: limit}
+		buf.Write([]byte(data))
+		return buf
+	}
+
+	tests := []struct {
+		name     string
+		captured *cappedBuffer
+		exitCode int
+		timedOut bool
+		want     []string
+		wantNot  []string
+	}{
+		{
+			name:     "plain output",
+			captured: capture("two files\n", shellShareLimit),
+			want:     []string{"$ ls", "```\ntwo files\n```"},
+			wantNot:  []string{"exited with status", "truncated"},
+		},
+		{
+			name:     "failure and no output",
+			captured: capture("", shellShareLimit),
+			exitCode: 2,
+			want:     []string{"It exited with status 2.", "It produced no output."},
+		},
+		{
+			name:     "timeout",
+			captured: capture("partial", shellShareLimit),
+			timedOut: true,
+			want:     []string{"It was killed after", "partial"},
+		},
+		{
+			name:     "truncated output",
+			captured: capture("0123456789", 4),
+			want:     []string{"```\n0123\n```", "(truncated; 10 bytes total)"},
+		},
+		{
+			name:     "binary output",
+			captured: capture("\x00\x01\x02", shellShareLimit),
+			want:     []string{"The output looks binary (3 bytes)"},
+			wantNot:  []string{"```"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shellMessage("ls", tt.captured, tt.exitCode, tt.timedOut)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("message %q does not contain %q", got, want)
+				}
+			}
+			for _, wantNot := range tt.wantNot {
+				if strings.Contains(got, wantNot) {
+					t.Errorf("message %q should not contain %q", got, wantNot)
+				}
+			}
+		})
+	}
+}
+
+func TestIsBinaryOutput(t *testing.T) {
+	if isBinaryOutput([]byte("plain text\n")) {
+		t.Error("text misdetected as binary")
+	}
+	if !isBinaryOutput([]byte{0x7f, 'E', 'L', 'F', 0x00}) {
+		t.Error("NUL-bearing data not detected as binary")
+	}
+}
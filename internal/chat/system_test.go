@@ -0,0 +1,79 @@
+package chat
+
+import (
+	"testing"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+)
+
+func TestBuildChatHistoryPrependsConfigPrompt(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	loop.config.Chat.SystemPrompt = "be terse"
+	session := loop.historyManager.Current()
+	session.Messages = append(session.Messages, history.Message{Role: history.RoleUser, Content: "hi"})
+
+	got := loop.buildChatHistory()
+
+	if len(got) != 2 {
+		t.Fatalf("expected system prompt + user message, got %d messages", len(got))
+	}
+	if got[0].Role != openai.RoleSystem {
+		t.Errorf("expected first message role system, got %q", got[0].Role)
+	}
+}
+
+func TestBuildChatHistorySessionOverrideWins(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	loop.config.Chat.SystemPrompt = "config default"
+	loop.historyManager.Current().SystemPrompt = "session override"
+
+	if got := loop.activeSystemPrompt(); got != "session override" {
+		t.Errorf("expected session override, got %q", got)
+	}
+}
+
+func TestBuildChatHistoryWithoutPrompt(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	session := loop.historyManager.Current()
+	session.Messages = append(session.Messages, history.Message{Role: history.RoleUser, Content: "hi"})
+
+	got := loop.buildChatHistory()
+
+	if len(got) != 1 {
+		t.Fatalf("expected no system message, got %d messages", len(got))
+	}
+	if got[0].Role == openai.RoleSystem {
+		t.Error("expected no system message when no prompt is configured")
+	}
+}
+
+func TestHandleSystemSetRebuildsHistory(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+	session := loop.historyManager.Current()
+	session.Messages = append(session.Messages, history.Message{Role: history.RoleUser, Content: "hi"})
+	chatHistory := loop.buildChatHistory()
+
+	loop.handleSystem("set act as a reviewer", &chatHistory)
+
+	if session.SystemPrompt != "act as a reviewer" {
+		t.Errorf("expected session override to persist, got %q", session.SystemPrompt)
+	}
+	if len(chatHistory) != 2 || chatHistory[0].Role != openai.RoleSystem {
+		t.Fatalf("expected rebuilt history with single system message, got %d messages", len(chatHistory))
+	}
+
+	// A second change must replace the first system message, not stack one
+	loop.handleSystem("set be brief", &chatHistory)
+	if len(chatHistory) != 2 {
+		t.Errorf("expected no duplicate system messages, got %d messages", len(chatHistory))
+	}
+
+	loop.handleSystem("reset", &chatHistory)
+	if session.SystemPrompt != "" {
+		t.Errorf("expected reset to clear the override, got %q", session.SystemPrompt)
+	}
+	if len(chatHistory) != 1 {
+		t.Errorf("expected system message removed after reset, got %d messages", len(chatHistory))
+	}
+}
@@ -0,0 +1,86 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"gopus/internal/history"
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+)
+
+// handleNewFromTemplate starts a new session pre-seeded from the named
+// template, replacing the chat history with the seeded messages.
+func (c *ChatLoop) handleNewFromTemplate(name string, chatHistory *[]openai.ChatCompletionRequestMessage) {
+	dir := c.config.History.TemplatesDir
+	if dir == "" {
+		fmt.Println("No templates directory configured; set history.templates_dir in the config.")
+		return
+	}
+
+	tmpl, warnings, err := history.TemplateByName(dir, name)
+	printTemplateWarnings(warnings)
+	if err != nil {
+		fmt.Printf("%v (see /templates)\n", err)
+		return
+	}
+
+	session := c.newSession()
+	if err := c.historyManager.SeedFromTemplate(tmpl); err != nil {
+		printer.PrintError("Error seeding session: %v", err)
+	}
+	*chatHistory = c.buildChatHistory()
+	c.listed = nil
+
+	fmt.Printf("Started a new session from template %q.\n", tmpl.Name)
+	for _, msg := range session.Messages {
+		printer.PrintMessageAt(msg.RoleLabel(), msg.Content, true, msg.CreatedAt)
+	}
+}
+
+// handleTemplates lists the session templates available to /new.
+func (c *ChatLoop) handleTemplates() {
+	dir := c.config.History.TemplatesDir
+	if dir == "" {
+		fmt.Println("No templates directory configured; set history.templates_dir in the config.")
+		return
+	}
+
+	templates, warnings := history.LoadTemplates(dir)
+	printTemplateWarnings(warnings)
+	if len(templates) == 0 {
+		fmt.Printf("No templates found in %s.\n", dir)
+		return
+	}
+
+	fmt.Printf("Templates in %s (start one with /new <name>):\n", dir)
+	for _, tmpl := range templates {
+		fmt.Printf("  %s — %s\n", tmpl.Name, describeTemplate(tmpl))
+	}
+}
+
+// describeTemplate summarizes what a template seeds in one line.
+func describeTemplate(tmpl history.Template) string {
+	var parts []string
+	if tmpl.SystemPrompt != "" {
+		parts = append(parts, "system prompt")
+	}
+	if n := len(tmpl.Messages); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d seed message(s)", n))
+	}
+	if tmpl.Model != "" {
+		parts = append(parts, "model "+tmpl.Model)
+	}
+	if tmpl.Temperature != nil {
+		parts = append(parts, fmt.Sprintf("temperature %g", *tmpl.Temperature))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printTemplateWarnings surfaces malformed template files without failing
+// the command that triggered the load.
+func printTemplateWarnings(warnings []string) {
+	for _, warning := range warnings {
+		printer.PrintWarning("%s", warning)
+	}
+}
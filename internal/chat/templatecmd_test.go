@@ -0,0 +1,83 @@
+package chat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopus/internal/openai"
+)
+
+func writeTestTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+}
+
+func TestHandleNewFromTemplate(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	dir := t.TempDir()
+	c.config.History.TemplatesDir = dir
+	writeTestTemplate(t, dir, "review.yaml", `
+system_prompt: You are a code reviewer.
+model: gpt-4o
+temperature: 0.3
+messages:
+  - role: user
+    content: Here are my conventions.
+`)
+
+	chatHistory := []openai.ChatCompletionRequestMessage{}
+	c.handleNew("review", &chatHistory)
+
+	session := c.historyManager.Current()
+	if session.SystemPrompt != "You are a code reviewer." {
+		t.Errorf("system prompt not applied: %q", session.SystemPrompt)
+	}
+	if len(session.Messages) != 1 || !session.Messages[0].FromTemplate {
+		t.Fatalf("expected 1 template-marked seed message, got %+v", session.Messages)
+	}
+
+	// The chat history carries the system prompt and the seed message
+	if len(chatHistory) != 2 {
+		t.Fatalf("expected 2 chat history entries, got %d", len(chatHistory))
+	}
+	if chatHistory[0].Role != openai.RoleSystem {
+		t.Errorf("expected a leading system message, got role %s", chatHistory[0].Role)
+	}
+
+	// The model and temperature overrides reach the request options
+	opts := c.requestOptions()
+	if opts == nil || opts.Model != "gpt-4o" {
+		t.Fatalf("expected a model override in the request options, got %+v", opts)
+	}
+	if opts.Temperature == nil || *opts.Temperature != 0.3 {
+		t.Errorf("expected a temperature override, got %v", opts.Temperature)
+	}
+}
+
+func TestHandleNewUnknownTemplateKeepsSession(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+	c.config.History.TemplatesDir = t.TempDir()
+
+	before := c.historyManager.Current().ID
+	chatHistory := []openai.ChatCompletionRequestMessage{}
+	c.handleNew("missing", &chatHistory)
+
+	if c.historyManager.Current().ID != before {
+		t.Error("an unknown template must not replace the current session")
+	}
+}
+
+func TestHandleNewWithoutTemplatesDir(t *testing.T) {
+	c := newTestChatLoop(t, "http://unused")
+
+	before := c.historyManager.Current().ID
+	chatHistory := []openai.ChatCompletionRequestMessage{}
+	c.handleNew("review", &chatHistory)
+
+	if c.historyManager.Current().ID != before {
+		t.Error("a template request without a configured directory must not replace the session")
+	}
+}
@@ -0,0 +1,32 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"gopus/internal/printer"
+)
+
+// handleTimestamps processes the /timestamps command: it toggles the dim
+// time stamp printed before each message. The setting also persists via
+// ui.show_timestamps in the config.
+func (c *ChatLoop) handleTimestamps(args string) {
+	switch strings.TrimSpace(args) {
+	case "":
+		state := "off"
+		if printer.TimestampsEnabled() {
+			state = "on"
+		}
+		fmt.Printf("Timestamps are %s (usage: /timestamps on|off).\n", state)
+	case "on":
+		printer.SetShowTimestamps(true)
+		c.config.UI.ShowTimestamps = true
+		fmt.Println("Timestamps on.")
+	case "off":
+		printer.SetShowTimestamps(false)
+		c.config.UI.ShowTimestamps = false
+		fmt.Println("Timestamps off.")
+	default:
+		fmt.Println("Usage: /timestamps [on|off]")
+	}
+}
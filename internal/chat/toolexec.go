@@ -0,0 +1,127 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopus/internal/hooks"
+	"gopus/internal/openai"
+	"gopus/internal/printer"
+)
+
+// toolOutcome is the result of one tool call, tagged with its position in
+// the model's request so the result messages keep the original ordering.
+type toolOutcome struct {
+	index   int
+	name    string
+	content string
+	cached  bool // result came from the tool result cache
+	skipped bool // the run was interrupted before this call started
+	err     error
+}
+
+// executeToolCalls runs the approved tool calls concurrently with a worker
+// pool bounded by mcp.max_parallel_tools, returning the outcomes in the
+// original request order. A status line is printed for each call as it
+// finishes; printToolStatus routes the lines through the running spinner's
+// animator so concurrent executions cannot interleave mid-line or splice
+// text into a frame.
+func (c *ChatLoop) executeToolCalls(ctx context.Context, toolCalls []openai.ChatCompletionMessageToolCall) []toolOutcome {
+	maxParallel := c.config.MCP.MaxParallelTools
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+
+	outcomes := make([]toolOutcome, len(toolCalls))
+	sem := make(chan struct{}, maxParallel)
+	var wg sync.WaitGroup
+
+	for i, toolCall := range toolCalls {
+		wg.Add(1)
+		go func(i int, toolCall openai.ChatCompletionMessageToolCall) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			// The run may have been interrupted while this call waited for
+			// a worker slot; record it as skipped rather than starting it
+			if ctx.Err() != nil {
+				outcomes[i] = toolOutcome{index: i, name: toolCall.Function.Name, skipped: true, err: ctx.Err()}
+				return
+			}
+
+			outcome := c.runToolCall(ctx, i, toolCall)
+			outcomes[i] = outcome
+
+			switch {
+			case errors.Is(outcome.err, context.Canceled):
+				c.printToolStatus(fmt.Sprintf("%s[Tool %s cancelled]%s", printer.ColorYellow, outcome.name, printer.ColorReset))
+			case outcome.err != nil:
+				c.printToolStatus(fmt.Sprintf("%s[Tool %s failed: %v]%s", printer.ColorRed, outcome.name, outcome.err, printer.ColorReset))
+			case outcome.cached:
+				c.printToolStatus(fmt.Sprintf("%s[Tool %s completed (cached)]%s", printer.ColorGreen, outcome.name, printer.ColorReset))
+			default:
+				c.printToolStatus(fmt.Sprintf("%s[Tool %s completed]%s", printer.ColorGreen, outcome.name, printer.ColorReset))
+			}
+
+			c.fireToolHook(outcome)
+		}(i, toolCall)
+	}
+	wg.Wait()
+
+	return outcomes
+}
+
+// fireToolHook reports a finished tool call to the tool_executed hook.
+func (c *ChatLoop) fireToolHook(outcome toolOutcome) {
+	if c.hooks == nil {
+		return
+	}
+	sessionID := ""
+	if session := c.historyManager.Current(); session != nil {
+		sessionID = session.ID
+	}
+	errMsg := ""
+	if outcome.err != nil {
+		errMsg = outcome.err.Error()
+	}
+	c.hooks.Fire(hooks.EventToolExecuted, hooks.ToolPayload{
+		Event:     hooks.EventToolExecuted,
+		SessionID: sessionID,
+		Tool:      outcome.name,
+		Cached:    outcome.cached,
+		Error:     errMsg,
+	})
+}
+
+// runToolCall executes a single tool call under its own mcp.default_timeout.
+// A panicking handler surfaces as that call's error instead of taking down
+// the other workers.
+func (c *ChatLoop) runToolCall(ctx context.Context, index int, toolCall openai.ChatCompletionMessageToolCall) (outcome toolOutcome) {
+	outcome = toolOutcome{index: index, name: toolCall.Function.Name}
+
+	defer func() {
+		if r := recover(); r != nil {
+			outcome.err = fmt.Errorf("tool handler panicked: %v", r)
+		}
+	}()
+
+	if timeout := time.Duration(c.config.MCP.DefaultTimeout) * time.Second; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	outcome.content, outcome.cached, outcome.err = c.executeToolCall(ctx, toolCall)
+
+	// A cancelled run can surface as a transport or server error rather
+	// than the context error itself; normalize so the outcome reads as a
+	// cancellation regardless of the transport.
+	if outcome.err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		outcome.err = context.Canceled
+	}
+	return outcome
+}
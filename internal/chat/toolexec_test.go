@@ -0,0 +1,142 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"gopus/internal/mcp"
+	"gopus/internal/openai"
+)
+
+// newToolTestChatLoop wires a chat loop to a manager hosting the builtin
+// tools registered by the test.
+func newToolTestChatLoop(t *testing.T) *ChatLoop {
+	t.Helper()
+	loop := newTestChatLoop(t, "http://unused")
+
+	manager := mcp.NewManager()
+	if err := manager.AddBuiltinServer(context.Background(), &mcp.BuiltinServer{}, mcp.Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+	t.Cleanup(func() { manager.Close() })
+
+	loop.mcpManager = manager
+	loop.config.MCP.MaxParallelTools = 2
+	return loop
+}
+
+func echoCall(id, text string, delayMS int) openai.ChatCompletionMessageToolCall {
+	return openai.ChatCompletionMessageToolCall{
+		Id:   id,
+		Type: openai.ChatCompletionMessageToolCallTypeFunction,
+		Function: openai.ChatCompletionMessageToolCallFunction{
+			Name:      "slow_echo_test",
+			Arguments: fmt.Sprintf(`{"text":%q,"delay_ms":%d}`, text, delayMS),
+		},
+	}
+}
+
+func TestExecuteToolCallsPreservesOrder(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	mcp.DefaultToolRegistry.Register(
+		mcplib.NewTool("slow_echo_test",
+			mcplib.WithString("text", mcplib.Required()),
+			mcplib.WithNumber("delay_ms"),
+		),
+		func(deps mcp.Deps) mcp.ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				n := inFlight.Add(1)
+				if n > maxInFlight.Load() {
+					maxInFlight.Store(n)
+				}
+				defer inFlight.Add(-1)
+
+				args, _ := req.Params.Arguments.(map[string]any)
+				if ms, ok := args["delay_ms"].(float64); ok {
+					time.Sleep(time.Duration(ms) * time.Millisecond)
+				}
+				text, _ := args["text"].(string)
+				return mcplib.NewToolResultText(text), nil
+			}
+		},
+	)
+	loop := newToolTestChatLoop(t)
+
+	// The first call finishes last, so completion order differs from the
+	// request order the outcomes must come back in.
+	calls := []openai.ChatCompletionMessageToolCall{
+		echoCall("call_1", "first", 80),
+		echoCall("call_2", "second", 10),
+		echoCall("call_3", "third", 10),
+		echoCall("call_4", "fourth", 10),
+	}
+
+	outcomes := loop.executeToolCalls(context.Background(), calls)
+
+	if len(outcomes) != len(calls) {
+		t.Fatalf("expected %d outcomes, got %d", len(calls), len(outcomes))
+	}
+	for i, want := range []string{"first", "second", "third", "fourth"} {
+		if outcomes[i].err != nil {
+			t.Errorf("call %d failed: %v", i, outcomes[i].err)
+		}
+		if outcomes[i].index != i || outcomes[i].content != want {
+			t.Errorf("outcome %d: expected %q at index %d, got %q at index %d",
+				i, want, i, outcomes[i].content, outcomes[i].index)
+		}
+	}
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("expected at most 2 concurrent executions, saw %d", got)
+	}
+}
+
+func TestExecuteToolCallsRecoversPanic(t *testing.T) {
+	mcp.DefaultToolRegistry.Register(
+		mcplib.NewTool("panic_test"),
+		func(deps mcp.Deps) mcp.ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				panic("handler bug")
+			}
+		},
+	)
+	mcp.DefaultToolRegistry.Register(
+		mcplib.NewTool("ok_test"),
+		func(deps mcp.Deps) mcp.ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				return mcplib.NewToolResultText("ok"), nil
+			}
+		},
+	)
+	loop := newToolTestChatLoop(t)
+
+	calls := []openai.ChatCompletionMessageToolCall{
+		{Id: "call_1", Function: openai.ChatCompletionMessageToolCallFunction{Name: "panic_test", Arguments: "{}"}},
+		{Id: "call_2", Function: openai.ChatCompletionMessageToolCallFunction{Name: "ok_test", Arguments: "{}"}},
+	}
+
+	outcomes := loop.executeToolCalls(context.Background(), calls)
+
+	if outcomes[0].err == nil {
+		t.Error("expected the panicking call to report an error")
+	}
+	if outcomes[1].err != nil || outcomes[1].content != "ok" {
+		t.Errorf("expected the other call to succeed, got %q, err %v", outcomes[1].content, outcomes[1].err)
+	}
+}
+
+func TestExecuteToolCallsWithoutManager(t *testing.T) {
+	loop := newTestChatLoop(t, "http://unused")
+
+	outcomes := loop.executeToolCalls(context.Background(), []openai.ChatCompletionMessageToolCall{
+		{Id: "call_1", Function: openai.ChatCompletionMessageToolCallFunction{Name: "missing", Arguments: "{}"}},
+	})
+
+	if outcomes[0].err == nil {
+		t.Error("expected an error when no MCP manager is configured")
+	}
+}
@@ -0,0 +1,135 @@
+// Package clipboard provides system clipboard access with platform-specific fallbacks.
+// It shells out to the first available clipboard utility (pbcopy/pbpaste on macOS,
+// wl-copy/wl-paste or xclip on Linux, clip.exe on Windows/WSL).
+package clipboard
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Clipboard abstracts system clipboard access so tests can substitute a fake.
+type Clipboard interface {
+	// Write puts text on the system clipboard.
+	Write(text string) error
+	// Read returns the current clipboard contents.
+	Read() (string, error)
+	// Name returns a human-readable name of the backend in use.
+	Name() string
+}
+
+// backend describes a clipboard utility pair.
+type backend struct {
+	name     string
+	writeCmd []string
+	readCmd  []string // empty if the backend is write-only
+}
+
+// backends lists known clipboard utilities in preference order.
+var backends = []backend{
+	{"pbcopy/pbpaste", []string{"pbcopy"}, []string{"pbpaste"}},
+	{"wl-copy/wl-paste", []string{"wl-copy"}, []string{"wl-paste", "--no-newline"}},
+	{"xclip", []string{"xclip", "-selection", "clipboard"}, []string{"xclip", "-selection", "clipboard", "-o"}},
+	{"xsel", []string{"xsel", "--clipboard", "--input"}, []string{"xsel", "--clipboard", "--output"}},
+	{"clip.exe", []string{"clip.exe"}, nil},
+}
+
+// commandClipboard implements Clipboard by invoking external utilities.
+type commandClipboard struct {
+	backend backend
+}
+
+// Options configures clipboard backend selection.
+type Options struct {
+	// DisableOSC52 prevents falling back to the terminal's OSC 52 clipboard
+	// for terminals that mishandle the sequence.
+	DisableOSC52 bool
+}
+
+// New returns the first available clipboard backend using default options.
+func New() (Clipboard, error) {
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions returns the first available clipboard backend. When no local
+// utility is found, it falls back to the terminal's OSC 52 clipboard if stdout
+// is a TTY (unless disabled). Otherwise the error lists what was looked for.
+func NewWithOptions(opts Options) (Clipboard, error) {
+	for _, b := range backends {
+		if _, err := exec.LookPath(b.writeCmd[0]); err == nil {
+			return &commandClipboard{backend: b}, nil
+		}
+	}
+
+	if !opts.DisableOSC52 && stdoutIsTerminal() {
+		return newOSC52Clipboard(os.Stdout), nil
+	}
+
+	var names []string
+	for _, b := range backends {
+		names = append(names, b.writeCmd[0])
+	}
+	return nil, fmt.Errorf("no clipboard utility found (looked for: %s)", strings.Join(names, ", "))
+}
+
+// Write puts text on the system clipboard via the backend's write command.
+func (c *commandClipboard) Write(text string) error {
+	cmd := exec.Command(c.backend.writeCmd[0], c.backend.writeCmd[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", c.backend.writeCmd[0], err)
+	}
+	return nil
+}
+
+// Read returns the clipboard contents via the backend's read command.
+func (c *commandClipboard) Read() (string, error) {
+	if len(c.backend.readCmd) == 0 {
+		return "", fmt.Errorf("clipboard backend %s does not support reading", c.backend.name)
+	}
+
+	cmd := exec.Command(c.backend.readCmd[0], c.backend.readCmd[1:]...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %w", c.backend.readCmd[0], err)
+	}
+	return out.String(), nil
+}
+
+// Name returns the name of the utility pair in use.
+func (c *commandClipboard) Name() string {
+	return c.backend.name
+}
+
+// Fake is an in-memory Clipboard implementation for tests.
+type Fake struct {
+	Contents string
+	WriteErr error
+	ReadErr  error
+}
+
+// Write stores text in memory.
+func (f *Fake) Write(text string) error {
+	if f.WriteErr != nil {
+		return f.WriteErr
+	}
+	f.Contents = text
+	return nil
+}
+
+// Read returns the stored text.
+func (f *Fake) Read() (string, error) {
+	if f.ReadErr != nil {
+		return "", f.ReadErr
+	}
+	return f.Contents, nil
+}
+
+// Name identifies the fake backend.
+func (f *Fake) Name() string {
+	return "fake"
+}
@@ -0,0 +1,64 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestOSC52WriteEmitsSequence(t *testing.T) {
+	var buf bytes.Buffer
+	cb := newOSC52Clipboard(&buf)
+
+	if err := cb.Write("hello"); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	want := "\033]52;c;" + base64.StdEncoding.EncodeToString([]byte("hello")) + "\a"
+	if buf.String() != want {
+		t.Errorf("OSC 52 sequence = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestOSC52WriteTruncatesLargeText(t *testing.T) {
+	var buf bytes.Buffer
+	cb := newOSC52Clipboard(&buf)
+
+	large := strings.Repeat("x", MaxOSC52Bytes+1000)
+	if err := cb.Write(large); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// The emitted payload must encode at most MaxOSC52Bytes of text
+	out := buf.String()
+	payload := strings.TrimSuffix(strings.TrimPrefix(out, "\033]52;c;"), "\a")
+	decoded, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatalf("payload is not valid base64: %v", err)
+	}
+	if len(decoded) != MaxOSC52Bytes {
+		t.Errorf("decoded payload is %d bytes, want %d", len(decoded), MaxOSC52Bytes)
+	}
+}
+
+func TestOSC52ReadUnavailable(t *testing.T) {
+	cb := newOSC52Clipboard(&bytes.Buffer{})
+	if _, err := cb.Read(); err == nil {
+		t.Error("expected Read to report unavailability")
+	}
+}
+
+func TestFakeRoundTrip(t *testing.T) {
+	f := &Fake{}
+	if err := f.Write("contents"); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := f.Read()
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got != "contents" {
+		t.Errorf("Read = %q, want %q", got, "contents")
+	}
+}
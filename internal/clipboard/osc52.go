@@ -0,0 +1,56 @@
+package clipboard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+
+	"gopus/internal/printer"
+)
+
+// MaxOSC52Bytes caps the amount of text sent via an OSC 52 sequence.
+// Many terminals silently drop larger payloads.
+const MaxOSC52Bytes = 100 * 1024
+
+// osc52Clipboard writes to the terminal's clipboard using the OSC 52 escape
+// sequence. Useful over SSH where no local clipboard utility is available.
+type osc52Clipboard struct {
+	out io.Writer
+}
+
+// newOSC52Clipboard creates an OSC 52 backend writing to the given terminal.
+func newOSC52Clipboard(out io.Writer) *osc52Clipboard {
+	return &osc52Clipboard{out: out}
+}
+
+// Write emits an OSC 52 sequence that asks the terminal to set its clipboard.
+// Text larger than MaxOSC52Bytes is truncated with a warning.
+func (c *osc52Clipboard) Write(text string) error {
+	if len(text) > MaxOSC52Bytes {
+		fmt.Fprintf(os.Stderr, "Warning: clipboard text truncated from %d to %d bytes (OSC 52 size cap)\n",
+			len(text), MaxOSC52Bytes)
+		text = text[:MaxOSC52Bytes]
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(text))
+	_, err := fmt.Fprintf(c.out, "\033]52;c;%s\a", encoded)
+	return err
+}
+
+// Read is unsupported: querying the clipboard via OSC 52 requires putting the
+// terminal into raw mode to capture the response, which would interfere with
+// the interactive input loop. Callers degrade gracefully on this error.
+func (c *osc52Clipboard) Read() (string, error) {
+	return "", fmt.Errorf("clipboard read unavailable (OSC 52 backend is write-only)")
+}
+
+// Name identifies the OSC 52 backend.
+func (c *osc52Clipboard) Name() string {
+	return "OSC 52 terminal clipboard"
+}
+
+// stdoutIsTerminal reports whether stdout is attached to a terminal.
+func stdoutIsTerminal() bool {
+	return printer.IsTerminal(os.Stdout)
+}
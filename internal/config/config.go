@@ -5,21 +5,181 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"gopus/internal/hooks"
 )
 
 // Config represents the application configuration.
 type Config struct {
-	OpenAI        OpenAIConfig        `yaml:"openai"`
-	History       HistoryConfig       `yaml:"history"`
-	Summarization SummarizationConfig `yaml:"summarization"`
-	MCP           MCPConfig           `yaml:"mcp"`
+	OpenAI        OpenAIConfig               `yaml:"openai"`
+	History       HistoryConfig              `yaml:"history"`
+	Summarization SummarizationConfig        `yaml:"summarization"`
+	MCP           MCPConfig                  `yaml:"mcp"`
+	Clipboard     ClipboardConfig            `yaml:"clipboard"`
+	Chat          ChatConfig                 `yaml:"chat"`
+	Limits        LimitsConfig               `yaml:"limits"`
+	Hooks         HooksConfig                `yaml:"hooks"`
+	Redaction     RedactionConfig            `yaml:"redaction"`
+	Embeddings    EmbeddingsConfig           `yaml:"embeddings"`
+	UI            UIConfig                   `yaml:"ui"`
+	Workspaces    map[string]WorkspaceConfig `yaml:"workspaces"`
+
+	// ActiveWorkspace is the workspace selected with --workspace or
+	// GOPUS_WORKSPACE for this run; empty when none is active. Set by
+	// ApplyWorkspace, not read from the config file.
+	ActiveWorkspace string `yaml:"-"`
+}
+
+// WorkspaceConfig describes one named workspace: a separate sessions
+// directory, so e.g. work and personal histories stay fully apart, with
+// optional per-workspace model and system prompt overrides.
+type WorkspaceConfig struct {
+	SessionsDir  string `yaml:"sessions_dir"`
+	Model        string `yaml:"model"`
+	SystemPrompt string `yaml:"system_prompt"`
+}
+
+// RedactionConfig controls scanning outgoing messages and tool results for
+// secrets before they are sent to the API.
+type RedactionConfig struct {
+	// Mode is "off" (default), "confirm" (show findings and ask before
+	// sending), or "redact" (replace findings with [REDACTED:<type>]).
+	Mode string `yaml:"mode"`
+
+	// HighEntropy also flags long random-looking strings that no specific
+	// pattern recognizes. Off by default; it has more false positives.
+	HighEntropy bool `yaml:"high_entropy"`
+
+	// Patterns adds custom detectors, mapping a name to a Go regex.
+	Patterns map[string]string `yaml:"patterns"`
+}
+
+// HooksConfig maps chat events to commands for external automation.
+// Commands receive a JSON payload on stdin and run asynchronously; the
+// hooks package documents the event names and payload schemas.
+type HooksConfig struct {
+	// AllowHooks must be explicitly enabled before any hook runs, so a
+	// copied config cannot execute commands by surprise.
+	AllowHooks bool `yaml:"allow_hooks"`
+
+	// Timeout is how many seconds a hook command may run before it is
+	// killed. Defaults to 10.
+	Timeout int `yaml:"timeout"`
+
+	// Events maps an event name (e.g. turn_completed) to the shell command
+	// run when it occurs.
+	Events map[string]string `yaml:"events"`
+}
+
+// ChatConfig contains interactive chat loop settings.
+type ChatConfig struct {
+	// Aliases maps user-defined shortcuts to full command lines, e.g.
+	// "/gs" -> "/mcp call run_command git status". Aliases expand once,
+	// before dispatch.
+	Aliases map[string]string `yaml:"aliases"`
+
+	// ShowStats prints a one-line timing/token summary after each assistant reply.
+	ShowStats bool `yaml:"show_stats"`
+
+	// NotifyAfterSeconds sends a desktop notification when a turn takes
+	// longer than this many seconds. Zero disables notifications.
+	NotifyAfterSeconds int `yaml:"notify_after_seconds"`
+
+	// SafeMode blocks destructive commands and forces explicit tool
+	// confirmation, regardless of other settings. Intended for demos.
+	// Can also be enabled per run with the --safe flag.
+	SafeMode bool `yaml:"safe_mode"`
+
+	// SystemPrompt is prepended to every API request as the system message.
+	// Sessions can override it with /system set; empty sends no system message.
+	SystemPrompt string `yaml:"system_prompt"`
+
+	// ContextBudgetTokens caps the estimated size of the session's context
+	// set (/context add) injected into each request. Entries beyond the
+	// budget are dropped oldest-first.
+	ContextBudgetTokens int `yaml:"context_budget_tokens"`
+
+	// MemoryBudgetTokens caps the estimated size of the global memory
+	// (/remember) injected into each request. Facts beyond the budget are
+	// left out of the payload oldest-first; the memory file keeps them.
+	MemoryBudgetTokens int `yaml:"memory_budget_tokens"`
+
+	// AutoDiff shows a diff against the previous assistant reply whenever
+	// a new reply mostly repeats it, e.g. a reprinted code block with one
+	// line changed. /diff does the same on demand.
+	AutoDiff bool `yaml:"auto_diff"`
+}
+
+// LimitsConfig caps API spending. Before each request the worst case
+// (estimated prompt tokens plus max_tokens, at the model's price) is
+// checked against the limits; an over-limit request needs explicit
+// confirmation, or is refused outright when Hard is set. A zero limit is
+// disabled.
+type LimitsConfig struct {
+	// PerSessionUSD caps the spending recorded on one session.
+	PerSessionUSD float64 `yaml:"per_session_usd"`
+
+	// PerDayUSD caps the spending recorded across all sessions per
+	// calendar day.
+	PerDayUSD float64 `yaml:"per_day_usd"`
+
+	// Hard refuses over-limit requests instead of asking for confirmation.
+	Hard bool `yaml:"hard"`
+}
+
+// UIConfig contains terminal presentation settings.
+type UIConfig struct {
+	// Spinner selects the loading animation style: "braille" (default),
+	// "ascii" for terminals whose fonts render braille as tofu boxes,
+	// "dots" for a growing ellipsis, or "off" for a static "…". Empty
+	// auto-detects from the locale.
+	Spinner string `yaml:"spinner"`
+
+	// Quiet suppresses the startup summary panel, for scripting. The -q
+	// flag does the same for a single run.
+	Quiet bool `yaml:"quiet"`
+
+	// ShowTimestamps prefixes printed messages with a dim time stamp;
+	// /timestamps toggles it within a session.
+	ShowTimestamps bool `yaml:"show_timestamps"`
+
+	// Language selects the interface language, e.g. "en" or "de". Empty
+	// reads the LANG-style environment variables; unknown languages fall
+	// back to English.
+	Language string `yaml:"language"`
+}
+
+// ClipboardConfig contains clipboard integration settings.
+type ClipboardConfig struct {
+	// DisableOSC52 disables the OSC 52 terminal clipboard fallback,
+	// for terminals that mishandle the sequence.
+	DisableOSC52 bool `yaml:"disable_osc52"`
 }
 
 // HistoryConfig contains chat history settings.
 type HistoryConfig struct {
 	SessionsDir string `yaml:"sessions_dir"`
+	// Encryption encrypts session files at rest with a passphrase taken
+	// from GOPUS_PASSPHRASE or prompted for at startup.
+	Encryption bool `yaml:"encryption"`
+
+	// TemplatesDir holds session template files for /new <template> and
+	// `gopus new --template`. Empty disables templates.
+	TemplatesDir string `yaml:"templates_dir"`
+
+	// Retention limits; sessions violating them are moved to a trash
+	// subdirectory at startup and by `gopus sessions prune`. The open
+	// session and pinned sessions are never pruned. Zero disables a limit.
+	MaxSessions    int `yaml:"max_sessions"`      // keep at most this many sessions
+	MaxAgeDays     int `yaml:"max_age_days"`      // prune sessions not updated within this many days
+	MaxTotalSizeMB int `yaml:"max_total_size_mb"` // prune oldest sessions until the directory fits
 }
 
 // SummarizationConfig contains settings for automatic history summarization.
@@ -31,24 +191,87 @@ type SummarizationConfig struct {
 	AutoThreshold    int    `yaml:"auto_threshold"`    // Trigger auto-summarization when message count exceeds this
 	CondensedPrompt  string `yaml:"condensed_prompt"`  // Prompt for condensed summarization
 	CompressedPrompt string `yaml:"compressed_prompt"` // Prompt for compressed summarization
+
+	// PinnedFacts are always fed into summarization prompts and never
+	// dropped; sessions can add more with /pin-fact.
+	PinnedFacts []string `yaml:"pinned_facts"`
 }
 
 // OpenAIConfig contains OpenAI API settings.
 type OpenAIConfig struct {
-	APIKey      string  `yaml:"api_key"`
-	Model       string  `yaml:"model"`
-	MaxTokens   int     `yaml:"max_tokens"`
-	Temperature float64 `yaml:"temperature"`
-	BaseURL     string  `yaml:"base_url"`
+	APIKey      string   `yaml:"api_key"`
+	Model       string   `yaml:"model"`
+	MaxTokens   int      `yaml:"max_tokens"`
+	Temperature float64  `yaml:"temperature"`
+	BaseURL     string   `yaml:"base_url"`
+	Stop        []string `yaml:"stop"` // up to 4 stop sequences sent with every request
+
+	// Organization and Project are sent as OpenAI-Organization and
+	// OpenAI-Project headers for billing attribution; empty omits them.
+	Organization string `yaml:"organization"`
+	Project      string `yaml:"project"`
+
+	// APIType selects the endpoint shape: "openai" (the default) or
+	// "azure". Azure authenticates with an api-key header and addresses a
+	// deployment-based URL with an api-version query parameter, so the
+	// two azure fields are required when it is selected. BaseURL is then
+	// the resource endpoint, e.g. https://myres.openai.azure.com.
+	APIType    string `yaml:"api_type"`
+	Deployment string `yaml:"deployment"`  // Azure deployment name
+	APIVersion string `yaml:"api_version"` // Azure api-version query parameter
+
+	// Optional generation parameters, left out of the request when unset.
+	// Pointers because zero is a meaningful value for every one of them.
+	TopP             *float64 `yaml:"top_p"`             // nucleus sampling, in (0, 1]
+	PresencePenalty  *float64 `yaml:"presence_penalty"`  // between -2 and 2
+	FrequencyPenalty *float64 `yaml:"frequency_penalty"` // between -2 and 2
+	Seed             *int     `yaml:"seed"`              // best-effort deterministic sampling
+
+	// Debug logs rate-limit headroom and retry decisions to stderr.
+	Debug bool `yaml:"debug"`
+}
+
+// DisplayModel names what requests are addressed to: the configured model,
+// or under Azure the deployment serving it.
+func (o OpenAIConfig) DisplayModel() string {
+	if o.APIType == APITypeAzure && o.Deployment != "" {
+		return fmt.Sprintf("%s (Azure deployment %s)", o.Model, o.Deployment)
+	}
+	return o.Model
+}
+
+// EmbeddingsConfig contains settings for embeddings-based history recall.
+// When enabled, messages summarized away are embedded and stored in a
+// sidecar file, and the most relevant ones are recalled into context for
+// each request.
+type EmbeddingsConfig struct {
+	Enabled   bool   `yaml:"enabled"`    // opt-in; off by default
+	Provider  string `yaml:"provider"`   // only "openai" is supported
+	Model     string `yaml:"model"`      // embeddings model
+	TopK      int    `yaml:"top_k"`      // how many archived messages to recall
+	MaxTokens int    `yaml:"max_tokens"` // token budget for the recalled block
 }
 
 // MCPConfig contains MCP client settings.
 type MCPConfig struct {
-	ToolConfirmation string            `yaml:"tool_confirmation"` // "always", "never", or "ask"
-	DefaultTimeout   int               `yaml:"default_timeout"`   // Timeout in seconds for MCP requests
-	Debug            bool              `yaml:"debug"`             // Enable debug logging for JSON-RPC messages
-	Servers          []MCPServerConfig `yaml:"servers"`           // List of MCP servers to connect to
-	Builtin          BuiltinConfig     `yaml:"builtin"`           // Configuration for builtin in-process servers
+	ToolConfirmation  string            `yaml:"tool_confirmation"`  // "always", "never", or "ask"
+	DefaultTimeout    int               `yaml:"default_timeout"`    // Timeout in seconds for MCP requests
+	MaxParallelTools  int               `yaml:"max_parallel_tools"` // Concurrent tool executions per turn
+	Debug             bool              `yaml:"debug"`              // Enable debug logging for JSON-RPC messages
+	ShutdownGrace     int               `yaml:"shutdown_grace"`     // Seconds to wait per stage when stopping server subprocesses
+	DisableValidation bool              `yaml:"disable_validation"` // Skip schema validation of tool arguments
+	Servers           []MCPServerConfig `yaml:"servers"`            // List of MCP servers to connect to
+	Builtin           BuiltinConfig     `yaml:"builtin"`            // Configuration for builtin in-process servers
+
+	// Tools holds per-tool settings keyed by tool name.
+	Tools map[string]MCPToolConfig `yaml:"tools"`
+}
+
+// MCPToolConfig contains per-tool settings.
+type MCPToolConfig struct {
+	// CacheTTL caches results for identical arguments for this many seconds.
+	// Only set it for idempotent tools; zero (the default) disables caching.
+	CacheTTL int `yaml:"cache_ttl"`
 }
 
 // MCPServerConfig defines an MCP server connection.
@@ -107,6 +330,19 @@ const (
 	ToolConfirmationAsk    = "ask"    // Ask based on tool risk level (default)
 )
 
+// API type constants for OpenAIConfig.APIType
+const (
+	APITypeOpenAI = "openai" // The regular OpenAI API shape (default)
+	APITypeAzure  = "azure"  // Azure OpenAI: api-key header, deployment URL
+)
+
+// Redaction mode constants
+const (
+	RedactionModeOff     = "off"     // No secret scanning (default)
+	RedactionModeConfirm = "confirm" // Show findings and ask before sending
+	RedactionModeRedact  = "redact"  // Replace findings with [REDACTED:<type>]
+)
+
 // DefaultConfigPath is the default path to look for the configuration file.
 const DefaultConfigPath = "config.yaml"
 
@@ -117,6 +353,12 @@ const (
 	defaultTemperature = 0.7
 	defaultBaseURL     = "https://api.openai.com/v1"
 
+	// Budget for the per-session context set injected into each request
+	defaultChatContextBudgetTokens = 2000
+
+	// Budget for the global memory injected into each request
+	defaultChatMemoryBudgetTokens = 1000
+
 	// Summarization defaults
 	defaultSummarizationEnabled        = true
 	defaultSummarizationRecentCount    = 20
@@ -127,6 +369,15 @@ const (
 	// MCP defaults
 	defaultMCPToolConfirmation = ToolConfirmationAsk
 	defaultMCPDefaultTimeout   = 30 // seconds
+	defaultMCPMaxParallelTools = 4
+	defaultMCPShutdownGrace    = 3  // seconds
+	defaultHooksTimeout        = 10 // seconds
+
+	// Embeddings recall defaults (the feature itself stays opt-in)
+	defaultEmbeddingsProvider  = "openai"
+	defaultEmbeddingsModel     = "text-embedding-3-small"
+	defaultEmbeddingsTopK      = 5
+	defaultEmbeddingsMaxTokens = 1000
 )
 
 // Default prompts for summarization.
@@ -147,10 +398,9 @@ Include only:
 Be extremely brief - this is long-term memory. Write in third person.`
 )
 
-// Load reads and parses the configuration from the specified file path.
-func Load(path string) (*Config, error) {
-	fmt.Printf("Loading configuration from %s...\n", path)
-
+// LoadQuiet reads and parses the configuration without printing anything.
+// Used by non-interactive subcommands where stdout must stay machine-readable.
+func LoadQuiet(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -163,20 +413,25 @@ func Load(path string) (*Config, error) {
 
 	// Apply defaults for optional fields
 	cfg.applyDefaults()
+	cfg.resolveWorkDirs(filepath.Dir(path))
 
 	// Validate required fields
 	if err := cfg.validate(); err != nil {
 		return nil, err
 	}
 
-	fmt.Printf("Using AI model: %s\n", cfg.OpenAI.Model)
-
 	return &cfg, nil
 }
 
 // LoadDefault loads configuration from the default path (config.yaml).
 func LoadDefault() (*Config, error) {
-	return Load(DefaultConfigPath)
+	return LoadQuiet(DefaultConfigPath)
+}
+
+// ApplyDefaults sets default values for optional configuration fields.
+// Exposed for callers that build a Config programmatically.
+func (c *Config) ApplyDefaults() {
+	c.applyDefaults()
 }
 
 // applyDefaults sets default values for optional configuration fields.
@@ -199,6 +454,34 @@ func (c *Config) applyDefaults() {
 
 	// MCP defaults
 	c.applyMCPDefaults()
+
+	if c.Chat.ContextBudgetTokens == 0 {
+		c.Chat.ContextBudgetTokens = defaultChatContextBudgetTokens
+	}
+	if c.Chat.MemoryBudgetTokens == 0 {
+		c.Chat.MemoryBudgetTokens = defaultChatMemoryBudgetTokens
+	}
+
+	if c.Hooks.Timeout == 0 {
+		c.Hooks.Timeout = defaultHooksTimeout
+	}
+
+	if c.Redaction.Mode == "" {
+		c.Redaction.Mode = RedactionModeOff
+	}
+
+	if c.Embeddings.Provider == "" {
+		c.Embeddings.Provider = defaultEmbeddingsProvider
+	}
+	if c.Embeddings.Model == "" {
+		c.Embeddings.Model = defaultEmbeddingsModel
+	}
+	if c.Embeddings.TopK == 0 {
+		c.Embeddings.TopK = defaultEmbeddingsTopK
+	}
+	if c.Embeddings.MaxTokens == 0 {
+		c.Embeddings.MaxTokens = defaultEmbeddingsMaxTokens
+	}
 }
 
 // applySummarizationDefaults sets default values for summarization config.
@@ -240,12 +523,326 @@ func (c *Config) applyMCPDefaults() {
 	if c.MCP.DefaultTimeout == 0 {
 		c.MCP.DefaultTimeout = defaultMCPDefaultTimeout
 	}
+
+	// Apply default worker pool size for tool execution
+	if c.MCP.MaxParallelTools == 0 {
+		c.MCP.MaxParallelTools = defaultMCPMaxParallelTools
+	}
+
+	// Apply default grace period for stopping server subprocesses
+	if c.MCP.ShutdownGrace == 0 {
+		c.MCP.ShutdownGrace = defaultMCPShutdownGrace
+	}
 }
 
-// validate checks that all required configuration fields are present.
+// knownBuiltinServerNames lists the builtin in-process MCP server names that
+// mcp.builtin.enabled/disabled may reference.
+var knownBuiltinServerNames = []string{"builtin"}
+
+// validate checks the configuration for problems. All problems are collected
+// and returned together as a single joined error rather than stopping at the
+// first one. Non-fatal issues (e.g. an MCP server command missing from PATH)
+// are printed as warnings to stderr instead.
 func (c *Config) validate() error {
+	var errs []error
+
 	if c.OpenAI.APIKey == "" {
-		return errors.New("openai.api_key is required in configuration")
+		errs = append(errs, errors.New("openai.api_key is required in configuration"))
+	}
+	if c.OpenAI.Temperature < 0 || c.OpenAI.Temperature > 2 {
+		errs = append(errs, fmt.Errorf("openai.temperature must be between 0 and 2, got %g", c.OpenAI.Temperature))
+	}
+	if c.OpenAI.MaxTokens <= 0 {
+		errs = append(errs, fmt.Errorf("openai.max_tokens must be positive, got %d", c.OpenAI.MaxTokens))
+	}
+	if len(c.OpenAI.Stop) > 4 {
+		errs = append(errs, fmt.Errorf("openai.stop supports at most 4 sequences, got %d", len(c.OpenAI.Stop)))
+	}
+	if p := c.OpenAI.TopP; p != nil && (*p <= 0 || *p > 1) {
+		errs = append(errs, fmt.Errorf("openai.top_p must be greater than 0 and at most 1, got %g", *p))
+	}
+	if p := c.OpenAI.PresencePenalty; p != nil && (*p < -2 || *p > 2) {
+		errs = append(errs, fmt.Errorf("openai.presence_penalty must be between -2 and 2, got %g", *p))
+	}
+	if p := c.OpenAI.FrequencyPenalty; p != nil && (*p < -2 || *p > 2) {
+		errs = append(errs, fmt.Errorf("openai.frequency_penalty must be between -2 and 2, got %g", *p))
+	}
+	switch c.OpenAI.APIType {
+	case "", APITypeOpenAI:
+		// The default shape has no extra requirements
+	case APITypeAzure:
+		if c.OpenAI.Deployment == "" {
+			errs = append(errs, errors.New("openai.deployment is required when openai.api_type is \"azure\""))
+		}
+		if c.OpenAI.APIVersion == "" {
+			errs = append(errs, errors.New("openai.api_version is required when openai.api_type is \"azure\""))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("openai.api_type must be %q or %q, got %q", APITypeOpenAI, APITypeAzure, c.OpenAI.APIType))
+	}
+
+	if c.MCP.MaxParallelTools < 0 {
+		errs = append(errs, fmt.Errorf("mcp.max_parallel_tools must not be negative, got %d", c.MCP.MaxParallelTools))
+	}
+	if c.MCP.ShutdownGrace < 0 {
+		errs = append(errs, fmt.Errorf("mcp.shutdown_grace must not be negative, got %d", c.MCP.ShutdownGrace))
+	}
+	for name, tool := range c.MCP.Tools {
+		if tool.CacheTTL < 0 {
+			errs = append(errs, fmt.Errorf("mcp.tools.%s.cache_ttl must not be negative, got %d", name, tool.CacheTTL))
+		}
+	}
+
+	switch c.MCP.ToolConfirmation {
+	case ToolConfirmationAlways, ToolConfirmationNever, ToolConfirmationAsk:
+	default:
+		errs = append(errs, fmt.Errorf("mcp.tool_confirmation must be %q, %q, or %q, got %q",
+			ToolConfirmationAlways, ToolConfirmationNever, ToolConfirmationAsk, c.MCP.ToolConfirmation))
+	}
+
+	for alias, expansion := range c.Chat.Aliases {
+		if !strings.HasPrefix(alias, "/") {
+			errs = append(errs, fmt.Errorf("chat.aliases: alias %q must start with a slash", alias))
+		}
+		if !strings.HasPrefix(expansion, "/") {
+			errs = append(errs, fmt.Errorf("chat.aliases.%s: expansion %q must be a slash command", alias, expansion))
+		}
+	}
+
+	if c.History.MaxSessions < 0 {
+		errs = append(errs, fmt.Errorf("history.max_sessions must not be negative, got %d", c.History.MaxSessions))
 	}
+	if c.History.MaxAgeDays < 0 {
+		errs = append(errs, fmt.Errorf("history.max_age_days must not be negative, got %d", c.History.MaxAgeDays))
+	}
+	if c.History.MaxTotalSizeMB < 0 {
+		errs = append(errs, fmt.Errorf("history.max_total_size_mb must not be negative, got %d", c.History.MaxTotalSizeMB))
+	}
+
+	if c.Limits.PerSessionUSD < 0 {
+		errs = append(errs, fmt.Errorf("limits.per_session_usd must not be negative, got %g", c.Limits.PerSessionUSD))
+	}
+	if c.Limits.PerDayUSD < 0 {
+		errs = append(errs, fmt.Errorf("limits.per_day_usd must not be negative, got %g", c.Limits.PerDayUSD))
+	}
+
+	if c.Hooks.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("hooks.timeout must not be negative, got %d", c.Hooks.Timeout))
+	}
+	for event := range c.Hooks.Events {
+		if !hooks.IsKnownEvent(event) {
+			errs = append(errs, fmt.Errorf("hooks.events.%s is not a known event (known: %s)",
+				event, strings.Join(hooks.KnownEvents, ", ")))
+		}
+	}
+
+	switch c.Redaction.Mode {
+	case RedactionModeOff, RedactionModeConfirm, RedactionModeRedact:
+	default:
+		errs = append(errs, fmt.Errorf("redaction.mode must be %q, %q, or %q, got %q",
+			RedactionModeOff, RedactionModeConfirm, RedactionModeRedact, c.Redaction.Mode))
+	}
+	for name, pattern := range c.Redaction.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("redaction.patterns.%s is not a valid regex: %v", name, err))
+		}
+	}
+
+	if c.Embeddings.Enabled && c.Embeddings.Provider != defaultEmbeddingsProvider {
+		errs = append(errs, fmt.Errorf("embeddings.provider must be %q, got %q",
+			defaultEmbeddingsProvider, c.Embeddings.Provider))
+	}
+	if c.Embeddings.TopK < 0 {
+		errs = append(errs, fmt.Errorf("embeddings.top_k must not be negative, got %d", c.Embeddings.TopK))
+	}
+	if c.Embeddings.MaxTokens < 0 {
+		errs = append(errs, fmt.Errorf("embeddings.max_tokens must not be negative, got %d", c.Embeddings.MaxTokens))
+	}
+
+	switch c.UI.Spinner {
+	case "", "braille", "ascii", "dots", "off":
+	default:
+		errs = append(errs, fmt.Errorf("ui.spinner must be %q, %q, %q, or %q, got %q",
+			"braille", "ascii", "dots", "off", c.UI.Spinner))
+	}
+
+	errs = append(errs, c.validateMCPServers()...)
+	errs = append(errs, c.validateSummarization()...)
+	errs = append(errs, c.validateWorkspaces()...)
+	c.warnUnknownBuiltinNames()
+
+	return errors.Join(errs...)
+}
+
+// resolveWorkDirs resolves relative MCP server working directories against
+// the directory containing the config file, so a shared config behaves the
+// same regardless of where the process itself was started.
+func (c *Config) resolveWorkDirs(configDir string) {
+	for i := range c.MCP.Servers {
+		wd := c.MCP.Servers[i].WorkDir
+		if wd != "" && !filepath.IsAbs(wd) {
+			c.MCP.Servers[i].WorkDir = filepath.Join(configDir, wd)
+		}
+	}
+}
+
+// validateMCPServers checks MCP server entries for missing or duplicate
+// fields. A command missing from PATH is only a warning, since the config
+// may be shared across machines.
+func (c *Config) validateMCPServers() []error {
+	var errs []error
+
+	seen := make(map[string]bool)
+	for i, server := range c.MCP.Servers {
+		if server.Name == "" {
+			errs = append(errs, fmt.Errorf("mcp.servers[%d]: name must not be empty", i))
+		} else if seen[server.Name] {
+			errs = append(errs, fmt.Errorf("mcp.servers[%d]: duplicate server name %q", i, server.Name))
+		}
+		seen[server.Name] = true
+
+		if server.Command == "" {
+			errs = append(errs, fmt.Errorf("mcp.servers[%d] (%s): command must not be empty", i, server.Name))
+			continue
+		}
+		if server.Enabled {
+			if _, err := exec.LookPath(server.Command); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: mcp.servers[%d] (%s): command %q not found on PATH\n",
+					i, server.Name, server.Command)
+			}
+		}
+
+		for key := range server.Env {
+			if strings.Contains(key, "=") {
+				errs = append(errs, fmt.Errorf("mcp.servers[%d] (%s): env key %q must not contain '='", i, server.Name, key))
+			}
+		}
+		if server.WorkDir != "" {
+			if info, err := os.Stat(server.WorkDir); err != nil || !info.IsDir() {
+				errs = append(errs, fmt.Errorf("mcp.servers[%d] (%s): work_dir %q is not an existing directory", i, server.Name, server.WorkDir))
+			}
+		}
+	}
+
+	return errs
+}
+
+// ApplyWorkspace overlays the named workspace onto the config: its sessions
+// directory replaces history.sessions_dir, and its model and system prompt,
+// when set, replace the global ones. The name is recorded in ActiveWorkspace
+// for display (startup banner, /workspace command).
+func (c *Config) ApplyWorkspace(name string) error {
+	ws, ok := c.Workspaces[name]
+	if !ok {
+		if len(c.Workspaces) == 0 {
+			return fmt.Errorf("workspace %q requested but no workspaces are configured", name)
+		}
+		return fmt.Errorf("unknown workspace %q (configured: %s)", name, strings.Join(c.WorkspaceNames(), ", "))
+	}
+
+	c.History.SessionsDir = ws.SessionsDir
+	if ws.Model != "" {
+		c.OpenAI.Model = ws.Model
+	}
+	if ws.SystemPrompt != "" {
+		c.Chat.SystemPrompt = ws.SystemPrompt
+	}
+	c.ActiveWorkspace = name
 	return nil
 }
+
+// WorkspaceNames returns the configured workspace names in sorted order.
+func (c *Config) WorkspaceNames() []string {
+	names := make([]string, 0, len(c.Workspaces))
+	for name := range c.Workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateWorkspaces checks that every workspace has a sessions directory
+// and that no two workspaces (or a workspace and history.sessions_dir)
+// share or nest their directories, which would mix the histories the
+// feature exists to separate.
+func (c *Config) validateWorkspaces() []error {
+	var errs []error
+
+	type dirOwner struct {
+		label string
+		dir   string
+	}
+	var owners []dirOwner
+	if c.History.SessionsDir != "" {
+		owners = append(owners, dirOwner{"history.sessions_dir", c.History.SessionsDir})
+	}
+
+	for _, name := range c.WorkspaceNames() {
+		ws := c.Workspaces[name]
+		if ws.SessionsDir == "" {
+			errs = append(errs, fmt.Errorf("workspaces.%s: sessions_dir must not be empty", name))
+			continue
+		}
+		owners = append(owners, dirOwner{"workspaces." + name, ws.SessionsDir})
+	}
+
+	for i, a := range owners {
+		for _, b := range owners[i+1:] {
+			if pathsOverlap(a.dir, b.dir) {
+				errs = append(errs, fmt.Errorf("%s and %s overlap: %q vs %q", a.label, b.label, a.dir, b.dir))
+			}
+		}
+	}
+
+	return errs
+}
+
+// pathsOverlap reports whether two directories are the same or one is
+// nested inside the other, after cleaning.
+func pathsOverlap(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if a == b {
+		return true
+	}
+	sep := string(filepath.Separator)
+	return strings.HasPrefix(a, b+sep) || strings.HasPrefix(b, a+sep)
+}
+
+// validateSummarization checks that the summarization counts are coherent.
+func (c *Config) validateSummarization() []error {
+	var errs []error
+
+	s := c.Summarization
+	if s.RecentCount < 0 {
+		errs = append(errs, fmt.Errorf("summarization.recent_count must not be negative, got %d", s.RecentCount))
+	}
+	if s.CondensedCount < 0 {
+		errs = append(errs, fmt.Errorf("summarization.condensed_count must not be negative, got %d", s.CondensedCount))
+	}
+	if s.AutoThreshold < 0 {
+		errs = append(errs, fmt.Errorf("summarization.auto_threshold must not be negative, got %d", s.AutoThreshold))
+	}
+	if s.AutoThreshold > 0 && s.RecentCount >= s.AutoThreshold {
+		errs = append(errs, fmt.Errorf("summarization.recent_count (%d) must be less than auto_threshold (%d)",
+			s.RecentCount, s.AutoThreshold))
+	}
+
+	return errs
+}
+
+// warnUnknownBuiltinNames warns about builtin enabled/disabled entries that
+// don't match any registered builtin server name. A typo here silently
+// enables or disables the wrong thing, so point it out.
+func (c *Config) warnUnknownBuiltinNames() {
+	known := make(map[string]bool, len(knownBuiltinServerNames))
+	for _, name := range knownBuiltinServerNames {
+		known[name] = true
+	}
+
+	for _, name := range append(append([]string{}, c.MCP.Builtin.Enabled...), c.MCP.Builtin.Disabled...) {
+		if !known[name] {
+			fmt.Fprintf(os.Stderr, "Warning: mcp.builtin references unknown server %q (known: %s)\n",
+				name, strings.Join(knownBuiltinServerNames, ", "))
+		}
+	}
+}
@@ -178,7 +178,8 @@ func TestValidateRequiresAPIKey(t *testing.T) {
 	}
 }
 
-// TestValidatePassesWithAPIKey verifies that validate passes when APIKey is set.
+// TestValidatePassesWithAPIKey verifies that a defaulted config with an
+// APIKey set passes validation.
 func TestValidatePassesWithAPIKey(t *testing.T) {
 	property := func(apiKey string) bool {
 		if apiKey == "" {
@@ -190,6 +191,7 @@ func TestValidatePassesWithAPIKey(t *testing.T) {
 				APIKey: apiKey,
 			},
 		}
+		c.applyDefaults()
 
 		err := c.validate()
 
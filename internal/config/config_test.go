@@ -0,0 +1,250 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// validConfig returns a defaulted config that passes validation.
+func validConfig() *Config {
+	c := &Config{
+		OpenAI: OpenAIConfig{APIKey: "test-key"},
+	}
+	c.applyDefaults()
+	return c
+}
+
+func TestValidateCollectsAllProblems(t *testing.T) {
+	c := validConfig()
+	c.OpenAI.Temperature = 3.5
+	c.OpenAI.MaxTokens = -1
+	c.MCP.ToolConfirmation = "alway"
+
+	err := c.validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{
+		"openai.temperature",
+		"openai.max_tokens",
+		"mcp.tool_confirmation",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected error to mention %s, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestValidateMCPServerEntries(t *testing.T) {
+	c := validConfig()
+	c.MCP.Servers = []MCPServerConfig{
+		{Name: "", Command: "server-cmd"},
+		{Name: "files", Command: ""},
+		{Name: "files", Command: "other-cmd"},
+	}
+
+	err := c.validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, "name must not be empty") {
+		t.Errorf("expected empty-name error, got:\n%s", msg)
+	}
+	if !strings.Contains(msg, "command must not be empty") {
+		t.Errorf("expected empty-command error, got:\n%s", msg)
+	}
+	if !strings.Contains(msg, `duplicate server name "files"`) {
+		t.Errorf("expected duplicate-name error, got:\n%s", msg)
+	}
+}
+
+func TestValidateSummarizationCounts(t *testing.T) {
+	c := validConfig()
+	c.Summarization.RecentCount = 100
+	c.Summarization.AutoThreshold = 50
+
+	err := c.validate()
+	if err == nil || !strings.Contains(err.Error(), "must be less than auto_threshold") {
+		t.Errorf("expected recent_count/auto_threshold error, got: %v", err)
+	}
+}
+
+func TestValidateStopSequenceCount(t *testing.T) {
+	c := validConfig()
+	c.OpenAI.Stop = []string{"a", "b", "c", "d", "e"}
+
+	err := c.validate()
+	if err == nil || !strings.Contains(err.Error(), "openai.stop") {
+		t.Errorf("expected stop sequence error, got: %v", err)
+	}
+}
+
+func TestValidateGenerationParameterRanges(t *testing.T) {
+	c := validConfig()
+	topP := 1.5
+	presence := -2.5
+	frequency := 3.0
+	c.OpenAI.TopP = &topP
+	c.OpenAI.PresencePenalty = &presence
+	c.OpenAI.FrequencyPenalty = &frequency
+
+	err := c.validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{
+		"openai.top_p",
+		"openai.presence_penalty",
+		"openai.frequency_penalty",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected error to mention %s, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestValidateGenerationParametersInRange(t *testing.T) {
+	c := validConfig()
+	topP := 0.9
+	penalty := 1.2
+	seed := 42
+	c.OpenAI.TopP = &topP
+	c.OpenAI.PresencePenalty = &penalty
+	c.OpenAI.FrequencyPenalty = &penalty
+	c.OpenAI.Seed = &seed
+
+	if err := c.validate(); err != nil {
+		t.Errorf("expected in-range parameters to pass, got: %v", err)
+	}
+}
+
+func TestValidatePassesOnDefaultedConfig(t *testing.T) {
+	if err := validConfig().validate(); err != nil {
+		t.Errorf("expected valid config to pass, got: %v", err)
+	}
+}
+
+func TestValidateWorkspaces(t *testing.T) {
+	c := validConfig()
+	c.History.SessionsDir = "/data/sessions"
+	c.Workspaces = map[string]WorkspaceConfig{
+		"empty":    {},
+		"work":     {SessionsDir: "/data/work"},
+		"nested":   {SessionsDir: "/data/work/inner"},
+		"personal": {SessionsDir: "/data/sessions"},
+	}
+
+	err := c.validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{
+		"workspaces.empty: sessions_dir must not be empty",
+		"workspaces.nested and workspaces.work overlap",
+		"history.sessions_dir and workspaces.personal overlap",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected error to mention %q, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestValidateWorkspacesPassesWhenSeparate(t *testing.T) {
+	c := validConfig()
+	c.Workspaces = map[string]WorkspaceConfig{
+		"work":     {SessionsDir: "/data/work"},
+		"personal": {SessionsDir: "/data/personal"},
+	}
+
+	if err := c.validate(); err != nil {
+		t.Errorf("expected separate workspace dirs to pass, got: %v", err)
+	}
+}
+
+func TestApplyWorkspace(t *testing.T) {
+	c := validConfig()
+	c.Chat.SystemPrompt = "global prompt"
+	c.Workspaces = map[string]WorkspaceConfig{
+		"work":     {SessionsDir: "/data/work", Model: "gpt-4o"},
+		"personal": {SessionsDir: "/data/personal"},
+	}
+
+	if err := c.ApplyWorkspace("work"); err != nil {
+		t.Fatalf("ApplyWorkspace: %v", err)
+	}
+	if c.History.SessionsDir != "/data/work" {
+		t.Errorf("expected sessions dir /data/work, got %q", c.History.SessionsDir)
+	}
+	if c.OpenAI.Model != "gpt-4o" {
+		t.Errorf("expected workspace model override, got %q", c.OpenAI.Model)
+	}
+	if c.Chat.SystemPrompt != "global prompt" {
+		t.Errorf("expected unset workspace prompt to keep the global one, got %q", c.Chat.SystemPrompt)
+	}
+	if c.ActiveWorkspace != "work" {
+		t.Errorf("expected active workspace to be recorded, got %q", c.ActiveWorkspace)
+	}
+}
+
+func TestApplyWorkspaceUnknown(t *testing.T) {
+	c := validConfig()
+	c.Workspaces = map[string]WorkspaceConfig{
+		"work": {SessionsDir: "/data/work"},
+	}
+
+	err := c.ApplyWorkspace("wrok")
+	if err == nil || !strings.Contains(err.Error(), `unknown workspace "wrok"`) {
+		t.Errorf("expected unknown-workspace error, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "work") {
+		t.Errorf("expected error to list configured names, got: %v", err)
+	}
+}
+
+func TestValidateAzureFields(t *testing.T) {
+	c := validConfig()
+	c.OpenAI.APIType = APITypeAzure
+
+	err := c.validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	msg := err.Error()
+	for _, want := range []string{"openai.deployment", "openai.api_version"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected error to mention %s, got:\n%s", want, msg)
+		}
+	}
+
+	c.OpenAI.Deployment = "my-deployment"
+	c.OpenAI.APIVersion = "2024-06-01"
+	if err := c.validate(); err != nil {
+		t.Errorf("expected complete azure config to validate, got %v", err)
+	}
+
+	c.OpenAI.APIType = "azzure"
+	err = c.validate()
+	if err == nil || !strings.Contains(err.Error(), "openai.api_type") {
+		t.Errorf("expected an api_type error, got %v", err)
+	}
+}
+
+func TestDisplayModel(t *testing.T) {
+	o := OpenAIConfig{Model: "gpt-4o"}
+	if got := o.DisplayModel(); got != "gpt-4o" {
+		t.Errorf("unexpected default display model %q", got)
+	}
+	o.APIType = APITypeAzure
+	o.Deployment = "my-deployment"
+	if got := o.DisplayModel(); got != "gpt-4o (Azure deployment my-deployment)" {
+		t.Errorf("unexpected azure display model %q", got)
+	}
+}
@@ -0,0 +1,182 @@
+// Package diff computes line- and word-level diffs between two texts,
+// used by the /diff command to show what changed when the model reprints
+// a block it produced earlier. The algorithm is a plain longest-common-
+// subsequence table with common prefix and suffix trimming, which is
+// plenty for chat-message-sized inputs.
+package diff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Op classifies one edit.
+type Op int
+
+const (
+	OpEqual Op = iota
+	OpDelete
+	OpInsert
+)
+
+// Edit is one diffed token: a line for Lines, a word or whitespace run
+// for Words.
+type Edit struct {
+	Op   Op
+	Text string
+}
+
+// maxTableCells bounds the LCS table; beyond it the middle of the diff
+// degrades to a whole-sale replacement instead of exhausting memory.
+const maxTableCells = 4 << 20
+
+// Lines diffs two texts line by line.
+func Lines(a, b string) []Edit {
+	return diffTokens(splitLines(a), splitLines(b))
+}
+
+// Words diffs two texts token by token, where a token is a word or a run
+// of whitespace, so joining the edit texts reconstructs either side.
+func Words(a, b string) []Edit {
+	return diffTokens(splitWords(a), splitWords(b))
+}
+
+// Similarity reports how much of the two texts' lines they share, from 0
+// (nothing) to 1 (identical). Two empty texts count as identical.
+func Similarity(a, b string) float64 {
+	la, lb := splitLines(a), splitLines(b)
+	if len(la) == 0 && len(lb) == 0 {
+		return 1
+	}
+	common := 0
+	for _, e := range diffTokens(la, lb) {
+		if e.Op == OpEqual {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(la)+len(lb))
+}
+
+// splitLines splits into lines without the newlines; an empty text has no
+// lines at all, so diffing against it marks every line of the other side.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// splitWords splits into alternating runs of non-space and space
+// characters, both kept as tokens.
+func splitWords(s string) []string {
+	var tokens []string
+	start := 0
+	inSpace := false
+	for i, r := range s {
+		isSpace := unicode.IsSpace(r)
+		if i == 0 {
+			inSpace = isSpace
+			continue
+		}
+		if isSpace != inSpace {
+			tokens = append(tokens, s[start:i])
+			start = i
+			inSpace = isSpace
+		}
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+// diffTokens produces the edit script turning a into b.
+func diffTokens(a, b []string) []Edit {
+	// Common prefix and suffix are equal edits for free and keep the
+	// quadratic table small for the typical "one line changed" case
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	middleA := a[prefix : len(a)-suffix]
+	middleB := b[prefix : len(b)-suffix]
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	for _, t := range a[:prefix] {
+		edits = append(edits, Edit{Op: OpEqual, Text: t})
+	}
+	edits = append(edits, diffMiddle(middleA, middleB)...)
+	for _, t := range a[len(a)-suffix:] {
+		edits = append(edits, Edit{Op: OpEqual, Text: t})
+	}
+	return edits
+}
+
+// diffMiddle diffs the trimmed middle with an LCS table, or falls back to
+// delete-all/insert-all when the table would be unreasonably large.
+func diffMiddle(a, b []string) []Edit {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	if len(a) > 0 && len(b) > 0 && len(a)*len(b) > maxTableCells {
+		return replaceAll(a, b)
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var edits []Edit
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, Edit{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, Edit{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			edits = append(edits, Edit{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		edits = append(edits, Edit{Op: OpDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		edits = append(edits, Edit{Op: OpInsert, Text: b[j]})
+	}
+	return edits
+}
+
+// replaceAll deletes every token of a and inserts every token of b.
+func replaceAll(a, b []string) []Edit {
+	edits := make([]Edit, 0, len(a)+len(b))
+	for _, t := range a {
+		edits = append(edits, Edit{Op: OpDelete, Text: t})
+	}
+	for _, t := range b {
+		edits = append(edits, Edit{Op: OpInsert, Text: t})
+	}
+	return edits
+}
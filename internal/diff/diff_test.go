@@ -0,0 +1,103 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+// script compacts an edit list into a string like "=a -b +c" for easy
+// comparison in tables; spaces inside tokens are shown as "·".
+func script(edits []Edit) string {
+	parts := make([]string, 0, len(edits))
+	for _, e := range edits {
+		marker := map[Op]string{OpEqual: "=", OpDelete: "-", OpInsert: "+"}[e.Op]
+		parts = append(parts, marker+strings.ReplaceAll(e.Text, " ", "·"))
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"identical", "a\nb", "a\nb", "=a =b"},
+		{"one line changed", "a\nb\nc", "a\nx\nc", "=a -b +x =c"},
+		{"line added", "a\nc", "a\nb\nc", "=a +b =c"},
+		{"line removed", "a\nb\nc", "a\nc", "=a -b =c"},
+		{"everything replaced", "a\nb", "x\ny", "-a -b +x +y"},
+		{"left side empty", "", "a\nb", "+a +b"},
+		{"right side empty", "a\nb", "", "-a -b"},
+		{"both empty", "", "", ""},
+		{"unicode lines", "héllo\n世界", "héllo\n世界!", "=héllo -世界 +世界!"},
+		{"trailing newline ignored", "a\nb\n", "a\nb", "=a =b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := script(Lines(tt.a, tt.b)); got != tt.want {
+				t.Errorf("Lines(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"one word changed", "timeout is 10s", "timeout is 30s", "=timeout =· =is =· -10s +30s"},
+		{"whitespace is its own token", "a b", "a b", "=a =· =b"},
+		{"unicode words", "héllo 世界", "héllo everyone", "=héllo =· -世界 +everyone"},
+		{"one side empty", "", "a b", "+a +· +b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := script(Words(tt.a, tt.b)); got != tt.want {
+				t.Errorf("Words(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordsReconstructsBothSides(t *testing.T) {
+	a, b := "func f() {\n\treturn 10 * time.Second\n}", "func f() {\n\treturn 30 * time.Second\n}"
+	var gotA, gotB strings.Builder
+	for _, e := range Words(a, b) {
+		if e.Op != OpInsert {
+			gotA.WriteString(e.Text)
+		}
+		if e.Op != OpDelete {
+			gotB.WriteString(e.Text)
+		}
+	}
+	if gotA.String() != a || gotB.String() != b {
+		t.Errorf("edits do not reconstruct the inputs: %q / %q", gotA.String(), gotB.String())
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"identical", "a\nb\nc", "a\nb\nc", 1},
+		{"disjoint", "a\nb", "x\ny", 0},
+		{"half shared", "a\nb", "a\nx", 0.5},
+		{"both empty", "", "", 1},
+		{"one empty", "a\nb", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Similarity(tt.a, tt.b); got != tt.want {
+				t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Package embeddings provides embeddings-based recall of archived chat
+// history. Messages that summarization would otherwise discard are embedded
+// and stored in a sidecar file next to the session; before a request, the
+// archived messages most similar to the new prompt can be recalled into a
+// labeled context block.
+package embeddings
+
+import (
+	"context"
+	"math"
+
+	"gopus/internal/openai"
+)
+
+// Embedder turns texts into embedding vectors. Implementations must return
+// one vector per input, in input order.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// openaiEmbedder embeds texts via the OpenAI embeddings endpoint.
+type openaiEmbedder struct {
+	client *openai.ChatClient
+	model  string
+}
+
+// NewOpenAIEmbedder returns an Embedder backed by the OpenAI embeddings
+// endpoint, using the given model.
+func NewOpenAIEmbedder(client *openai.ChatClient, model string) Embedder {
+	return &openaiEmbedder{client: client, model: model}
+}
+
+func (e *openaiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return e.client.Embeddings(ctx, e.model, texts)
+}
+
+// cosineSimilarity returns the cosine of the angle between two vectors, or
+// 0 when either has no magnitude or the dimensions differ.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
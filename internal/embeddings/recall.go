@@ -0,0 +1,142 @@
+package embeddings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopus/internal/config"
+	"gopus/internal/history"
+	"gopus/internal/openai"
+)
+
+// Recaller archives messages that summarization discards and recalls the
+// ones most relevant to a new prompt. It is safe to share between the chat
+// goroutine and a background summarization: each method works on the
+// sidecar file of the session it is given.
+type Recaller struct {
+	embedder  Embedder
+	dir       string // sessions directory holding the sidecar files
+	topK      int
+	maxTokens int // token budget for the recalled block
+}
+
+// NewRecaller creates a Recaller storing vectors in the given sessions
+// directory.
+func NewRecaller(embedder Embedder, sessionsDir string, cfg config.EmbeddingsConfig) *Recaller {
+	return &Recaller{
+		embedder:  embedder,
+		dir:       sessionsDir,
+		topK:      cfg.TopK,
+		maxTokens: cfg.MaxTokens,
+	}
+}
+
+// Archive embeds and stores messages about to be summarized away. Messages
+// already archived (same role and content) are not re-embedded, so repeated
+// summarization passes stay cheap.
+func (r *Recaller) Archive(ctx context.Context, sessionID string, messages []history.Message) error {
+	store := NewStore(r.dir, sessionID)
+	existing, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		seen[e.Role+"\x00"+e.Content] = true
+	}
+
+	var pending []Entry
+	var texts []string
+	for _, msg := range messages {
+		if !msg.IsMessage() || strings.TrimSpace(msg.Content) == "" {
+			continue
+		}
+		key := string(msg.Role) + "\x00" + msg.Content
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		pending = append(pending, Entry{
+			Role:      string(msg.Role),
+			Content:   msg.Content,
+			CreatedAt: msg.CreatedAt,
+		})
+		texts = append(texts, msg.Content)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	vectors, err := r.embedder.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed messages: %w", err)
+	}
+	if len(vectors) != len(pending) {
+		return fmt.Errorf("expected %d vectors, got %d", len(pending), len(vectors))
+	}
+	for i := range pending {
+		pending[i].Vector = vectors[i]
+	}
+
+	_, err = store.Append(pending)
+	return err
+}
+
+// Recall returns a labeled context block with the archived messages most
+// similar to the prompt, at most topK of them and within the token budget.
+// An empty string means there is nothing to recall.
+func (r *Recaller) Recall(ctx context.Context, sessionID, prompt string) (string, error) {
+	entries, err := NewStore(r.dir, sessionID).Load()
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 || r.topK <= 0 {
+		return "", nil
+	}
+
+	vectors, err := r.embedder.Embed(ctx, []string{prompt})
+	if err != nil {
+		return "", fmt.Errorf("failed to embed prompt: %w", err)
+	}
+	if len(vectors) != 1 {
+		return "", fmt.Errorf("expected 1 vector, got %d", len(vectors))
+	}
+	query := vectors[0]
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return cosineSimilarity(query, entries[i].Vector) > cosineSimilarity(query, entries[j].Vector)
+	})
+	if len(entries) > r.topK {
+		entries = entries[:r.topK]
+	}
+
+	return r.buildBlock(entries), nil
+}
+
+// buildBlock renders recalled entries as a labeled system context block,
+// dropping entries that would exceed the token budget.
+func (r *Recaller) buildBlock(entries []Entry) string {
+	const header = "Relevant earlier messages recalled from this session's archive (for reference; the conversation has moved on since):"
+
+	var b strings.Builder
+	b.WriteString(header)
+	used := openai.EstimateTokens(header)
+	included := 0
+	for _, e := range entries {
+		line := fmt.Sprintf("\n\n[%s] %s", e.Role, e.Content)
+		cost := openai.EstimateTokens(line)
+		if used+cost > r.maxTokens {
+			continue
+		}
+		b.WriteString(line)
+		used += cost
+		included++
+	}
+	if included == 0 {
+		return ""
+	}
+	return b.String()
+}
@@ -0,0 +1,204 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gopus/internal/config"
+	"gopus/internal/history"
+)
+
+// fakeEmbedder returns deterministic vectors: each text maps to a fixed
+// vector from the table, and unknown texts get a zero vector.
+type fakeEmbedder struct {
+	vectors map[string][]float32
+	err     error
+	calls   int
+}
+
+func (f *fakeEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		v, ok := f.vectors[text]
+		if !ok {
+			v = []float32{0, 0, 0}
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// testConfig returns an embeddings config with a generous token budget.
+func testConfig(topK int) config.EmbeddingsConfig {
+	return config.EmbeddingsConfig{Enabled: true, TopK: topK, MaxTokens: 1000}
+}
+
+func userMessage(content string) history.Message {
+	return history.Message{Role: history.RoleUser, Content: content, CreatedAt: time.Now()}
+}
+
+func TestRecallRanksBySimilarity(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"the dog chased the ball": {1, 0, 0},
+		"compilers parse tokens":  {0, 1, 0},
+		"rain is forecast today":  {0, 0, 1},
+		"what did the dog do?":    {0.9, 0.1, 0},
+	}}
+	r := NewRecaller(embedder, t.TempDir(), testConfig(2))
+	ctx := context.Background()
+
+	messages := []history.Message{
+		userMessage("the dog chased the ball"),
+		userMessage("compilers parse tokens"),
+		userMessage("rain is forecast today"),
+	}
+	if err := r.Archive(ctx, "session-1", messages); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+
+	block, err := r.Recall(ctx, "session-1", "what did the dog do?")
+	if err != nil {
+		t.Fatalf("Recall: %v", err)
+	}
+	if !strings.Contains(block, "the dog chased the ball") {
+		t.Errorf("expected the dog message recalled, got:\n%s", block)
+	}
+	if strings.Contains(block, "rain is forecast today") {
+		t.Errorf("expected the least similar message cut by top_k, got:\n%s", block)
+	}
+	if !strings.Contains(block, "recalled from this session's archive") {
+		t.Errorf("expected a labeled block, got:\n%s", block)
+	}
+}
+
+func TestArchiveSkipsAlreadyArchived(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"hello": {1, 0, 0},
+	}}
+	r := NewRecaller(embedder, t.TempDir(), testConfig(5))
+	ctx := context.Background()
+
+	messages := []history.Message{userMessage("hello")}
+	if err := r.Archive(ctx, "session-1", messages); err != nil {
+		t.Fatalf("first Archive: %v", err)
+	}
+	if err := r.Archive(ctx, "session-1", messages); err != nil {
+		t.Fatalf("second Archive: %v", err)
+	}
+	if embedder.calls != 1 {
+		t.Errorf("expected 1 embed call, got %d", embedder.calls)
+	}
+
+	entries, err := NewStore(r.dir, "session-1").Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected 1 stored entry, got %d", len(entries))
+	}
+}
+
+func TestArchiveSkipsSummariesAndNotes(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{}}
+	r := NewRecaller(embedder, t.TempDir(), testConfig(5))
+
+	messages := []history.Message{
+		{Role: history.RoleSystem, Content: "a summary", Type: history.TypeSummary},
+		{Role: history.RoleUser, Content: "a note", Type: history.TypeNote},
+		{Role: history.RoleUser, Content: "   "},
+	}
+	if err := r.Archive(context.Background(), "session-1", messages); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	if embedder.calls != 0 {
+		t.Errorf("expected no embed calls, got %d", embedder.calls)
+	}
+}
+
+func TestRecallHonorsTokenBudget(t *testing.T) {
+	long := strings.Repeat("wordy content here ", 50)
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"short answer": {1, 0, 0},
+		long:           {0.9, 0, 0},
+		"query":        {1, 0, 0},
+	}}
+	cfg := config.EmbeddingsConfig{Enabled: true, TopK: 5, MaxTokens: 60}
+	r := NewRecaller(embedder, t.TempDir(), cfg)
+	ctx := context.Background()
+
+	messages := []history.Message{userMessage(long), userMessage("short answer")}
+	if err := r.Archive(ctx, "session-1", messages); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+
+	block, err := r.Recall(ctx, "session-1", "query")
+	if err != nil {
+		t.Fatalf("Recall: %v", err)
+	}
+	if strings.Contains(block, long) {
+		t.Errorf("expected the long entry dropped by the budget, got:\n%s", block)
+	}
+	if !strings.Contains(block, "short answer") {
+		t.Errorf("expected the short entry to fit the budget, got:\n%s", block)
+	}
+}
+
+func TestRecallEmptyStoreReturnsNothing(t *testing.T) {
+	embedder := &fakeEmbedder{}
+	r := NewRecaller(embedder, t.TempDir(), testConfig(5))
+
+	block, err := r.Recall(context.Background(), "missing-session", "anything")
+	if err != nil {
+		t.Fatalf("Recall: %v", err)
+	}
+	if block != "" {
+		t.Errorf("expected empty block, got %q", block)
+	}
+	if embedder.calls != 0 {
+		t.Errorf("expected no embed call for an empty store, got %d", embedder.calls)
+	}
+}
+
+func TestRecallSurfacesEmbedderErrors(t *testing.T) {
+	dir := t.TempDir()
+	working := &fakeEmbedder{vectors: map[string][]float32{"hello": {1, 0, 0}}}
+	r := NewRecaller(working, dir, testConfig(5))
+	ctx := context.Background()
+	if err := r.Archive(ctx, "session-1", []history.Message{userMessage("hello")}); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+
+	failing := NewRecaller(&fakeEmbedder{err: errors.New("connection refused")}, dir, testConfig(5))
+	if _, err := failing.Recall(ctx, "session-1", "hello"); err == nil {
+		t.Error("expected an error from a failing embedder")
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float64
+	}{
+		{"identical", []float32{1, 2, 3}, []float32{1, 2, 3}, 1},
+		{"orthogonal", []float32{1, 0}, []float32{0, 1}, 0},
+		{"opposite", []float32{1, 0}, []float32{-1, 0}, -1},
+		{"zero vector", []float32{0, 0}, []float32{1, 0}, 0},
+		{"dimension mismatch", []float32{1}, []float32{1, 0}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cosineSimilarity(tt.a, tt.b)
+			if diff := got - tt.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("cosineSimilarity(%v, %v) = %g, want %g", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
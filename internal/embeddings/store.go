@@ -0,0 +1,83 @@
+package embeddings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Entry is one archived message with its embedding vector.
+type Entry struct {
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	Vector    []float32 `json:"vector"`
+}
+
+// Store persists archived message vectors in a sidecar file next to the
+// session JSON (<id>.vectors), keeping the session file itself lean.
+type Store struct {
+	path string
+}
+
+// NewStore returns the vector store for a session in the given sessions
+// directory.
+func NewStore(sessionsDir, sessionID string) *Store {
+	return &Store{path: filepath.Join(sessionsDir, sessionID+".vectors")}
+}
+
+// Load reads all archived entries. A missing sidecar file is an empty store.
+func (s *Store) Load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read vector store: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse vector store: %w", err)
+	}
+	return entries, nil
+}
+
+// Append adds entries to the store, skipping any whose role and content are
+// already archived so re-summarization does not duplicate vectors. Returns
+// the entries actually added.
+func (s *Store) Append(entries []Entry) ([]Entry, error) {
+	existing, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		seen[e.Role+"\x00"+e.Content] = true
+	}
+
+	var added []Entry
+	for _, e := range entries {
+		key := e.Role + "\x00" + e.Content
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		existing = append(existing, e)
+		added = append(added, e)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode vector store: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write vector store: %w", err)
+	}
+	return added, nil
+}
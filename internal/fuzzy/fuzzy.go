@@ -0,0 +1,50 @@
+// Package fuzzy implements case-insensitive subsequence matching with a
+// simple score, used by interactive pickers to rank filter results.
+package fuzzy
+
+import "strings"
+
+// Match reports whether pattern is a subsequence of text and scores the
+// match. Every matched rune counts once; runs of adjacent matches and
+// matches at the start of a word score extra, so "chat" ranks "chat log"
+// above "cheap tricks". An empty pattern matches everything with score 0.
+func Match(pattern, text string) (score int, ok bool) {
+	if pattern == "" {
+		return 0, true
+	}
+
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+
+	pi := 0
+	prevMatched := false
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if t[ti] != p[pi] {
+			prevMatched = false
+			continue
+		}
+		score++
+		if prevMatched {
+			score += 2
+		}
+		if ti == 0 || isBoundary(t[ti-1]) {
+			score++
+		}
+		prevMatched = true
+		pi++
+	}
+
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
+
+// isBoundary reports whether r separates words for the boundary bonus.
+func isBoundary(r rune) bool {
+	switch r {
+	case ' ', '-', '_', '/', '.':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package fuzzy
+
+import "testing"
+
+func TestMatchSubsequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		want    bool
+	}{
+		{"exact", "chat", "chat", true},
+		{"subsequence", "cht", "chat", true},
+		{"case insensitive", "CHAT", "Chat Log", true},
+		{"scattered", "abc", "a1b2c3", true},
+		{"empty pattern", "", "anything", true},
+		{"missing rune", "chatz", "chat", false},
+		{"out of order", "tahc", "chat", false},
+		{"empty text", "a", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Match(tt.pattern, tt.text); ok != tt.want {
+				t.Errorf("Match(%q, %q) ok = %v, want %v", tt.pattern, tt.text, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchScoring(t *testing.T) {
+	score := func(pattern, text string) int {
+		t.Helper()
+		s, ok := Match(pattern, text)
+		if !ok {
+			t.Fatalf("Match(%q, %q) unexpectedly failed", pattern, text)
+		}
+		return s
+	}
+
+	// Adjacent matches beat scattered ones
+	if adjacent, scattered := score("abc", "abcdef"), score("abc", "a1b2c3"); adjacent <= scattered {
+		t.Errorf("expected adjacent match (%d) to outscore scattered match (%d)", adjacent, scattered)
+	}
+
+	// Word-boundary matches beat mid-word ones
+	if boundary, midword := score("log", "chat log"), score("log", "catalogue"); boundary <= midword {
+		t.Errorf("expected boundary match (%d) to outscore mid-word match (%d)", boundary, midword)
+	}
+
+	if s, ok := Match("", "text"); !ok || s != 0 {
+		t.Errorf("expected the empty pattern to match with score 0, got (%d, %v)", s, ok)
+	}
+}
@@ -0,0 +1,174 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Checkpoint marks a message index within a session that can be rolled
+// back to later.
+type Checkpoint struct {
+	Label        string    `json:"label"`
+	MessageIndex int       `json:"message_index"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// archiveEntry is one rollback's worth of removed messages, kept in the
+// session's archive file so rollbacks are recoverable.
+type archiveEntry struct {
+	RemovedAt time.Time `json:"removed_at"`
+	Messages  []Message `json:"messages"`
+}
+
+// AddCheckpoint records the current message index of the current session
+// under the given label (a generated "cpN" label when empty) and saves.
+func (m *Manager) AddCheckpoint(label string) (Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return Checkpoint{}, fmt.Errorf("no current session")
+	}
+
+	if label == "" {
+		label = fmt.Sprintf("cp%d", len(m.current.Checkpoints)+1)
+	}
+	for _, cp := range m.current.Checkpoints {
+		if cp.Label == label {
+			return Checkpoint{}, fmt.Errorf("checkpoint %q already exists", label)
+		}
+	}
+
+	cp := Checkpoint{
+		Label:        label,
+		MessageIndex: len(m.current.Messages),
+		CreatedAt:    time.Now(),
+	}
+	m.current.Checkpoints = append(m.current.Checkpoints, cp)
+
+	if err := m.saveLocked(m.current); err != nil {
+		return Checkpoint{}, err
+	}
+	return cp, nil
+}
+
+// ValidCheckpoints returns the current session's checkpoints that still
+// reference a reachable message index. Summarization can shrink the message
+// list, leaving checkpoints pointing beyond it; those are dropped from the
+// session as a side effect.
+func (m *Manager) ValidCheckpoints() []Checkpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return nil
+	}
+
+	valid := m.current.Checkpoints[:0]
+	for _, cp := range m.current.Checkpoints {
+		if cp.MessageIndex <= len(m.current.Messages) {
+			valid = append(valid, cp)
+		}
+	}
+	m.current.Checkpoints = valid
+	return valid
+}
+
+// RollbackTo truncates the current session's messages to the given index,
+// archives the removed tail to the session's archive file, drops checkpoints
+// beyond the new length, and saves. Returns the removed messages.
+func (m *Manager) RollbackTo(index int) ([]Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return nil, fmt.Errorf("no current session")
+	}
+	if err := m.writableLocked(); err != nil {
+		return nil, err
+	}
+	if index < 0 || index > len(m.current.Messages) {
+		return nil, fmt.Errorf("invalid rollback index %d (session has %d messages)", index, len(m.current.Messages))
+	}
+
+	removed := append([]Message{}, m.current.Messages[index:]...)
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	if err := m.archiveMessagesLocked(removed); err != nil {
+		return nil, err
+	}
+
+	m.current.Messages = m.current.Messages[:index]
+
+	// Drop checkpoints now pointing beyond the truncated history
+	valid := m.current.Checkpoints[:0]
+	for _, cp := range m.current.Checkpoints {
+		if cp.MessageIndex <= index {
+			valid = append(valid, cp)
+		}
+	}
+	m.current.Checkpoints = valid
+
+	if err := m.saveLocked(m.current); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// ArchivedMessages returns messages removed from the current session by
+// rollbacks, oldest first. A missing or corrupted archive yields nil.
+func (m *Manager) ArchivedMessages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(m.sessionsDir, m.current.ID+".archive.json"))
+	if err != nil {
+		return nil
+	}
+	var entries []archiveEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil
+	}
+
+	var messages []Message
+	for _, entry := range entries {
+		messages = append(messages, entry.Messages...)
+	}
+	return messages
+}
+
+// archiveMessagesLocked appends removed messages to the current session's
+// archive file (<id>.archive.json) so rollbacks are recoverable by hand.
+// Callers must hold m.mu.
+func (m *Manager) archiveMessagesLocked(removed []Message) error {
+	path := filepath.Join(m.sessionsDir, m.current.ID+".archive.json")
+
+	var entries []archiveEntry
+	if data, err := os.ReadFile(path); err == nil {
+		// A corrupted archive is replaced rather than blocking the rollback
+		_ = json.Unmarshal(data, &entries)
+	}
+
+	entries = append(entries, archiveEntry{
+		RemovedAt: time.Now(),
+		Messages:  removed,
+	})
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize archive: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	return nil
+}
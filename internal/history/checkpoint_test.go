@@ -0,0 +1,91 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAddCheckpointDefaultsAndDuplicates(t *testing.T) {
+	manager := forkTestManager(t, 3)
+
+	cp, err := manager.AddCheckpoint("")
+	if err != nil {
+		t.Fatalf("AddCheckpoint: %v", err)
+	}
+	if cp.Label != "cp1" {
+		t.Errorf("expected generated label cp1, got %q", cp.Label)
+	}
+	if cp.MessageIndex != 3 {
+		t.Errorf("expected message index 3, got %d", cp.MessageIndex)
+	}
+
+	if _, err := manager.AddCheckpoint("cp1"); err == nil {
+		t.Error("expected error for duplicate label")
+	}
+}
+
+func TestRollbackArchivesRemovedTail(t *testing.T) {
+	manager := forkTestManager(t, 5)
+	session := manager.Current()
+
+	removed, err := manager.RollbackTo(2)
+	if err != nil {
+		t.Fatalf("RollbackTo: %v", err)
+	}
+	if len(removed) != 3 {
+		t.Errorf("expected 3 removed messages, got %d", len(removed))
+	}
+	if len(session.Messages) != 2 {
+		t.Errorf("expected 2 messages left, got %d", len(session.Messages))
+	}
+
+	archivePath := filepath.Join(manager.SessionsDir(), session.ID+".archive.json")
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatalf("expected archive file: %v", err)
+	}
+	var entries []archiveEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("failed to parse archive: %v", err)
+	}
+	if len(entries) != 1 || len(entries[0].Messages) != 3 {
+		t.Errorf("expected one archive entry with 3 messages, got %+v", entries)
+	}
+}
+
+func TestRollbackDropsLaterCheckpoints(t *testing.T) {
+	manager := forkTestManager(t, 2)
+	if _, err := manager.AddCheckpoint("early"); err != nil {
+		t.Fatalf("AddCheckpoint: %v", err)
+	}
+	session := manager.Current()
+	session.Messages = append(session.Messages, Message{Role: RoleUser, Content: "more"})
+	if _, err := manager.AddCheckpoint("late"); err != nil {
+		t.Fatalf("AddCheckpoint: %v", err)
+	}
+
+	if _, err := manager.RollbackTo(2); err != nil {
+		t.Fatalf("RollbackTo: %v", err)
+	}
+
+	checkpoints := manager.ValidCheckpoints()
+	if len(checkpoints) != 1 || checkpoints[0].Label != "early" {
+		t.Errorf("expected only the early checkpoint to survive, got %+v", checkpoints)
+	}
+}
+
+func TestValidCheckpointsDropsStaleIndices(t *testing.T) {
+	manager := forkTestManager(t, 4)
+	if _, err := manager.AddCheckpoint("full"); err != nil {
+		t.Fatalf("AddCheckpoint: %v", err)
+	}
+
+	// Simulate summarization shrinking the message list
+	manager.Current().Messages = manager.Current().Messages[:1]
+
+	if checkpoints := manager.ValidCheckpoints(); len(checkpoints) != 0 {
+		t.Errorf("expected stale checkpoint to be dropped, got %+v", checkpoints)
+	}
+}
@@ -0,0 +1,168 @@
+package history
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CompactResult reports what a compaction pass did.
+type CompactResult struct {
+	Rewritten   int   // session files rewritten without indentation
+	Compressed  int   // session files gzip-compressed for age
+	Skipped     int   // unparsable files left untouched
+	BytesBefore int64 // total size of the processed files before
+	BytesAfter  int64 // total size of the processed files after
+}
+
+// Saved returns how many bytes the pass reclaimed.
+func (r CompactResult) Saved() int64 {
+	return r.BytesBefore - r.BytesAfter
+}
+
+// Compact rewrites every session file without the pretty-printed
+// indentation the normal save path uses, and gzip-compresses sessions not
+// updated within olderThan (zero disables compression). The session
+// currently held open is skipped, every rewrite goes through an atomic
+// rename, and files that fail to parse are left untouched and counted, so
+// a pass is safe to run next to a live gopus and to repeat.
+func (m *Manager) Compact(olderThan time.Duration, now time.Time) (CompactResult, error) {
+	var result CompactResult
+
+	dir := m.SessionsDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return result, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+
+	m.mu.Lock()
+	currentID := ""
+	if m.current != nil {
+		currentID = m.current.ID
+	}
+	m.mu.Unlock()
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (!strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".json.gz")) {
+			continue
+		}
+		if name == indexFileName || name == "spend-ledger.json" || strings.HasSuffix(name, ".archive.json") {
+			continue
+		}
+		id := strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".json")
+		if id == currentID {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		session, err := loadSession(path)
+		if err != nil {
+			result.Skipped++
+			continue
+		}
+
+		compress := olderThan > 0 && now.Sub(session.UpdatedAt) > olderThan
+		data, err := encodeCompact(session, compress)
+		if err != nil {
+			result.Skipped++
+			continue
+		}
+
+		target := filepath.Join(dir, id+".json")
+		if compress {
+			target += ".gz"
+		}
+		mode := os.FileMode(0644)
+		if EncryptionEnabled() {
+			mode = 0600
+		}
+		if err := replaceFile(target, data, mode); err != nil {
+			return result, err
+		}
+		if target != path {
+			// The session changed form (.json <-> .json.gz); drop the old file
+			if err := os.Remove(path); err != nil {
+				return result, fmt.Errorf("failed to remove %s: %w", name, err)
+			}
+		}
+
+		result.BytesBefore += info.Size()
+		result.BytesAfter += int64(len(data))
+		if compress {
+			result.Compressed++
+		} else {
+			result.Rewritten++
+		}
+	}
+
+	return result, nil
+}
+
+// encodeCompact serializes a session the way compaction stores it:
+// unindented JSON, encrypted when at-rest encryption is enabled, and
+// gzip-compressed on request.
+func encodeCompact(session *Session, compress bool) ([]byte, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize session: %w", err)
+	}
+	if EncryptionEnabled() {
+		if data, err = encryptSession(data); err != nil {
+			return nil, err
+		}
+	}
+	if compress {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to compress session: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress session: %w", err)
+		}
+		data = buf.Bytes()
+	}
+	return data, nil
+}
+
+// replaceFile writes data through a temp file and rename, so a crash or a
+// concurrent reader never sees a half-written session.
+func replaceFile(path string, data []byte, mode os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".compact-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to replace session file: %w", err)
+	}
+	return nil
+}
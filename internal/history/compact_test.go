@@ -0,0 +1,145 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompactRoundTrips(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	session := statsSession("compact-a", time.Now())
+	if err := manager.Save(session); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	before, err := os.Stat(filepath.Join(dir, "compact-a.json"))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	// Compare JSON round trips with each other: an in-memory session
+	// differs from any reloaded one in its monotonic clock readings
+	original, err := loadSession(filepath.Join(dir, "compact-a.json"))
+	if err != nil {
+		t.Fatalf("loadSession: %v", err)
+	}
+
+	result, err := manager.Compact(0, time.Now())
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if result.Rewritten != 1 || result.Compressed != 0 || result.Skipped != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.Saved() <= 0 {
+		t.Errorf("expected dropping the indentation to save space, got %+v", result)
+	}
+
+	after, err := os.Stat(filepath.Join(dir, "compact-a.json"))
+	if err != nil {
+		t.Fatalf("stat after compact: %v", err)
+	}
+	if after.Size() >= before.Size() {
+		t.Errorf("expected a smaller file, got %d -> %d bytes", before.Size(), after.Size())
+	}
+
+	loaded, err := loadSession(filepath.Join(dir, "compact-a.json"))
+	if err != nil {
+		t.Fatalf("loadSession: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, original) {
+		t.Errorf("compaction changed the session:\nbefore: %+v\nafter:  %+v", original, loaded)
+	}
+}
+
+func TestCompactGzipsOldSessions(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	old := statsSession("compact-old", time.Now().AddDate(0, 0, -60))
+	recent := statsSession("compact-new", time.Now())
+	for _, s := range []*Session{old, recent} {
+		if err := saveSession(filepath.Join(dir, s.ID+".json"), s); err != nil {
+			t.Fatalf("saveSession: %v", err)
+		}
+	}
+	original, err := loadSession(filepath.Join(dir, "compact-old.json"))
+	if err != nil {
+		t.Fatalf("loadSession: %v", err)
+	}
+
+	result, err := manager.Compact(30*24*time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if result.Compressed != 1 || result.Rewritten != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "compact-old.json.gz")); err != nil {
+		t.Errorf("expected a gzip-compacted file: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "compact-old.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the plain file to be gone (stat err: %v)", err)
+	}
+
+	// The compressed session stays loadable, listable, and deletable
+	loaded, err := manager.LoadSessionByID("compact-old")
+	if err != nil {
+		t.Fatalf("LoadSessionByID: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, original) {
+		t.Errorf("compression changed the session:\nbefore: %+v\nafter:  %+v", original, loaded)
+	}
+	sessions, err := manager.ListSessions()
+	if err != nil || len(sessions) != 2 {
+		t.Errorf("expected both sessions listed, got %d, %v", len(sessions), err)
+	}
+
+	// A second pass finds nothing left to shrink
+	again, err := manager.Compact(30*24*time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("Compact again: %v", err)
+	}
+	if again.Saved() != 0 {
+		t.Errorf("expected an idempotent second pass, got %+v", again)
+	}
+}
+
+func TestCompactSkipsCurrentAndCorrupted(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	current := statsSession("compact-current", time.Now())
+	manager.SetCurrent(current)
+	if err := manager.Save(current); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	before, _ := os.Stat(filepath.Join(dir, "compact-current.json"))
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("not json"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	result, err := manager.Compact(0, time.Now())
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if result.Rewritten != 0 || result.Skipped != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	after, _ := os.Stat(filepath.Join(dir, "compact-current.json"))
+	if after.Size() != before.Size() {
+		t.Errorf("the open session must not be touched, got %d -> %d bytes", before.Size(), after.Size())
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "broken.json")); string(data) != "not json" {
+		t.Errorf("corrupted file must be left untouched, got %q", data)
+	}
+}
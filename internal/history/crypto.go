@@ -0,0 +1,203 @@
+package history
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// encMagic marks encrypted session files; the trailing byte is the format
+// version. An encrypted file is encMagic | salt | nonce | AES-256-GCM
+// ciphertext, with the key derived from the passphrase via scrypt.
+var encMagic = []byte("GOPUSENC\x01")
+
+const (
+	encSaltSize  = 16
+	encNonceSize = 12
+)
+
+// scrypt parameters, interactive-grade: derivation takes well under a second
+// and is cached per salt, so it happens once per process in practice.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	encMu         sync.Mutex
+	encPassphrase string            // empty means encryption is disabled
+	encSalt       []byte            // salt for newly written files
+	encKeys       map[string][]byte // derived keys, cached by salt
+)
+
+// EnableEncryption turns on at-rest encryption for session files written
+// from now on. Files encrypted under the same passphrase but a different
+// salt (e.g. from an earlier run) remain readable; plaintext files do too.
+func EnableEncryption(passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("passphrase must not be empty")
+	}
+
+	salt := make([]byte, encSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	encMu.Lock()
+	defer encMu.Unlock()
+	encPassphrase = passphrase
+	encSalt = salt
+	encKeys = make(map[string][]byte)
+	return nil
+}
+
+// EncryptionEnabled reports whether session files are encrypted on write.
+func EncryptionEnabled() bool {
+	encMu.Lock()
+	defer encMu.Unlock()
+	return encPassphrase != ""
+}
+
+// isEncrypted reports whether data is an encrypted session file.
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encMagic)
+}
+
+// deriveKeyLocked derives (or returns the cached) AES key for the given
+// salt. Callers must hold encMu.
+func deriveKeyLocked(salt []byte) ([]byte, error) {
+	if key, ok := encKeys[string(salt)]; ok {
+		return key, nil
+	}
+	key, err := scrypt.Key([]byte(encPassphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+	encKeys[string(salt)] = key
+	return key, nil
+}
+
+// newGCM builds an AES-256-GCM cipher for the given key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptSession encrypts serialized session data into the versioned
+// on-disk format.
+func encryptSession(data []byte) ([]byte, error) {
+	encMu.Lock()
+	defer encMu.Unlock()
+
+	key, err := deriveKeyLocked(encSalt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, encNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(encMagic)+encSaltSize+encNonceSize+len(data)+gcm.Overhead())
+	out = append(out, encMagic...)
+	out = append(out, encSalt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, nil), nil
+}
+
+// decryptSession decrypts an encrypted session file back to JSON. A wrong
+// passphrase fails GCM authentication and is reported as such.
+func decryptSession(data []byte) ([]byte, error) {
+	encMu.Lock()
+	defer encMu.Unlock()
+
+	if encPassphrase == "" {
+		return nil, fmt.Errorf("session file is encrypted; enable history.encryption and provide a passphrase")
+	}
+
+	rest := data[len(encMagic):]
+	if len(rest) < encSaltSize+encNonceSize {
+		return nil, fmt.Errorf("encrypted session file is truncated")
+	}
+	salt := rest[:encSaltSize]
+	nonce := rest[encSaltSize : encSaltSize+encNonceSize]
+	ciphertext := rest[encSaltSize+encNonceSize:]
+
+	key, err := deriveKeyLocked(salt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt session file: wrong passphrase or corrupted file")
+	}
+	return plain, nil
+}
+
+// EncryptAll converts plaintext session files in the sessions directory to
+// the encrypted format and returns how many were converted. Already
+// encrypted files are left alone, as are the sessions index (its metadata
+// keeps listings working without decryption) and rollback archives.
+func (m *Manager) EncryptAll() (int, error) {
+	if !EncryptionEnabled() {
+		return 0, fmt.Errorf("encryption is not enabled")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, err := os.ReadDir(m.sessionsDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+
+	converted := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") ||
+			name == indexFileName || strings.HasSuffix(name, ".archive.json") {
+			continue
+		}
+
+		path := filepath.Join(m.sessionsDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return converted, fmt.Errorf("failed to read session file: %w", err)
+		}
+		if isEncrypted(data) {
+			continue
+		}
+
+		enc, err := encryptSession(data)
+		if err != nil {
+			return converted, err
+		}
+		if err := os.WriteFile(path, enc, 0600); err != nil {
+			return converted, fmt.Errorf("failed to write session file: %w", err)
+		}
+		converted++
+	}
+	return converted, nil
+}
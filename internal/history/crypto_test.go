@@ -0,0 +1,171 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withEncryption enables at-rest encryption for one test and restores the
+// package to its plaintext default afterwards.
+func withEncryption(t *testing.T, passphrase string) {
+	t.Helper()
+	if err := EnableEncryption(passphrase); err != nil {
+		t.Fatalf("EnableEncryption: %v", err)
+	}
+	t.Cleanup(func() {
+		encMu.Lock()
+		encPassphrase, encSalt, encKeys = "", nil, nil
+		encMu.Unlock()
+	})
+}
+
+func TestEncryptedSessionRoundTrip(t *testing.T) {
+	withEncryption(t, "correct horse")
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	session := manager.NewSession()
+	if err := manager.AddMessage(RoleUser, "secret things"); err != nil {
+		t.Fatalf("AddMessage: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, session.ID+".json"))
+	if err != nil {
+		t.Fatalf("read session file: %v", err)
+	}
+	if !isEncrypted(data) {
+		t.Fatal("session file written without encryption header")
+	}
+	if strings.Contains(string(data), "secret things") {
+		t.Fatal("plaintext message content visible in encrypted file")
+	}
+
+	loaded, err := manager.LoadSessionByID(session.ID)
+	if err != nil {
+		t.Fatalf("LoadSessionByID: %v", err)
+	}
+	if len(loaded.Messages) != 1 || loaded.Messages[0].Content != "secret things" {
+		t.Errorf("unexpected decrypted content: %+v", loaded.Messages)
+	}
+}
+
+func TestLoadPlaintextSessionWithEncryptionEnabled(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	session := manager.NewSession()
+	if err := manager.AddMessage(RoleUser, "from before encryption"); err != nil {
+		t.Fatalf("AddMessage: %v", err)
+	}
+
+	withEncryption(t, "new passphrase")
+	loaded, err := manager.LoadSessionByID(session.ID)
+	if err != nil {
+		t.Fatalf("LoadSessionByID: %v", err)
+	}
+	if len(loaded.Messages) != 1 {
+		t.Errorf("expected 1 message, got %d", len(loaded.Messages))
+	}
+}
+
+func TestWrongPassphraseIsReported(t *testing.T) {
+	dir := t.TempDir()
+	withEncryption(t, "right")
+	{
+		manager, err := NewManager(dir)
+		if err != nil {
+			t.Fatalf("NewManager: %v", err)
+		}
+		manager.NewSession()
+		if err := manager.AddMessage(RoleUser, "hello"); err != nil {
+			t.Fatalf("AddMessage: %v", err)
+		}
+	}
+
+	if err := EnableEncryption("wrong"); err != nil {
+		t.Fatalf("EnableEncryption: %v", err)
+	}
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	sessions, err := manager.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("expected undecryptable sessions to be skipped, got %d", len(sessions))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == indexFileName || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		_, err := loadSession(filepath.Join(dir, entry.Name()))
+		if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+			t.Errorf("expected wrong-passphrase error, got %v", err)
+		}
+	}
+}
+
+func TestEncryptAllConvertsPlaintextFiles(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := manager.Save(benchSession(i)); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if err := manager.FlushIndex(); err != nil {
+		t.Fatalf("FlushIndex: %v", err)
+	}
+
+	withEncryption(t, "migrate me")
+	converted, err := manager.EncryptAll()
+	if err != nil {
+		t.Fatalf("EncryptAll: %v", err)
+	}
+	if converted != 3 {
+		t.Errorf("expected 3 files converted, got %d", converted)
+	}
+
+	// A second run finds nothing left to convert
+	converted, err = manager.EncryptAll()
+	if err != nil {
+		t.Fatalf("EncryptAll: %v", err)
+	}
+	if converted != 0 {
+		t.Errorf("expected 0 files converted on rerun, got %d", converted)
+	}
+
+	// The index stays plaintext so listings work without decrypting
+	indexData, err := os.ReadFile(filepath.Join(dir, indexFileName))
+	if err != nil {
+		t.Fatalf("read index: %v", err)
+	}
+	if isEncrypted(indexData) {
+		t.Error("sessions index must stay plaintext")
+	}
+
+	sessions, err := manager.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Errorf("expected 3 sessions after migration, got %d", len(sessions))
+	}
+}
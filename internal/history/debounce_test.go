@@ -0,0 +1,88 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestAddMessageCoalescesSessionWrites(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	session := manager.NewSession()
+
+	// The first save of a burst goes straight to disk; rapid follow-ups
+	// within the debounce window are held back
+	if err := manager.AddMessage(RoleUser, "hello"); err != nil {
+		t.Fatalf("AddMessage: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := manager.AddMessage(RoleAssistant, fmt.Sprintf("reply %d", i)); err != nil {
+			t.Fatalf("AddMessage: %v", err)
+		}
+	}
+
+	loaded, err := manager.LoadSessionByID(session.ID)
+	if err != nil {
+		t.Fatalf("LoadSessionByID: %v", err)
+	}
+	if len(loaded.Messages) != 1 {
+		t.Errorf("expected only the first message on disk mid-burst, got %d", len(loaded.Messages))
+	}
+	if desc, ok := manager.PendingWork(); !ok {
+		t.Errorf("expected pending work mid-burst, got none (%q)", desc)
+	}
+
+	if err := manager.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	loaded, err = manager.LoadSessionByID(session.ID)
+	if err != nil {
+		t.Fatalf("LoadSessionByID: %v", err)
+	}
+	if len(loaded.Messages) != 6 {
+		t.Errorf("expected 6 messages after flush, got %d", len(loaded.Messages))
+	}
+	if _, ok := manager.PendingWork(); ok {
+		t.Error("expected no pending work after flush")
+	}
+}
+
+func TestAddMessageConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	session := manager.NewSession()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if err := manager.AddMessage(RoleUser, fmt.Sprintf("msg %d/%d", i, j)); err != nil {
+					t.Errorf("AddMessage: %v", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := manager.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if got := len(session.Messages); got != 80 {
+		t.Errorf("expected 80 messages, got %d", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, session.ID+".json")); err != nil {
+		t.Fatalf("expected session file on disk after flush: %v", err)
+	}
+}
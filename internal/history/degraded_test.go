@@ -0,0 +1,134 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// breakSessionsDir replaces the sessions directory with a regular file, so
+// every write into it fails regardless of the user running the tests (a
+// chmod-based setup is a no-op for root).
+func breakSessionsDir(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if err := os.WriteFile(dir, []byte("not a directory"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func restoreSessionsDir(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.Remove(dir); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+}
+
+func TestSaveRecreatesMissingSessionsDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sessions")
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	session := manager.NewSession()
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+
+	if err := manager.AddMessage(RoleUser, "hello"); err != nil {
+		t.Fatalf("expected the save to recreate the directory, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, session.ID+".json")); err != nil {
+		t.Errorf("expected the session file back on disk: %v", err)
+	}
+}
+
+func TestDegradedModeBuffersAndFlushRecovers(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sessions")
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	session := manager.NewSession()
+	if err := manager.AddMessage(RoleUser, "first"); err != nil {
+		t.Fatalf("AddMessage: %v", err)
+	}
+
+	breakSessionsDir(t, dir)
+
+	// The first failures surface; from the threshold on the manager goes
+	// quiet and keeps buffering
+	for i := 1; i < saveFailureThreshold; i++ {
+		if err := manager.SaveCurrent(); err == nil {
+			t.Fatalf("failure %d: expected a save error", i)
+		}
+	}
+	if err := manager.SaveCurrent(); err != nil {
+		t.Fatalf("expected degraded mode to swallow the error, got %v", err)
+	}
+	if err := manager.AddMessage(RoleAssistant, "second"); err != nil {
+		t.Fatalf("expected buffered appends to report success, got %v", err)
+	}
+	if err := manager.AddMessage(RoleUser, "third"); err != nil {
+		t.Fatalf("expected buffered appends to report success, got %v", err)
+	}
+
+	restoreSessionsDir(t, dir)
+
+	// A late recovery at shutdown persists the whole buffer in order
+	if err := manager.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	loaded, err := loadSession(filepath.Join(dir, session.ID+".json"))
+	if err != nil {
+		t.Fatalf("loadSession: %v", err)
+	}
+	want := []string{"first", "second", "third"}
+	if len(loaded.Messages) != len(want) {
+		t.Fatalf("expected %d messages after recovery, got %d", len(want), len(loaded.Messages))
+	}
+	for i, content := range want {
+		if loaded.Messages[i].Content != content {
+			t.Errorf("message %d: expected %q, got %q", i, content, loaded.Messages[i].Content)
+		}
+	}
+}
+
+func TestDegradedModeRetriesInBackground(t *testing.T) {
+	origInterval := saveRetryInterval
+	saveRetryInterval = 10 * time.Millisecond
+	defer func() { saveRetryInterval = origInterval }()
+
+	dir := filepath.Join(t.TempDir(), "sessions")
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	session := manager.NewSession()
+	if err := manager.AddMessage(RoleUser, "hello"); err != nil {
+		t.Fatalf("AddMessage: %v", err)
+	}
+
+	breakSessionsDir(t, dir)
+	for range saveFailureThreshold {
+		_ = manager.SaveCurrent()
+	}
+	restoreSessionsDir(t, dir)
+
+	// The background retry alone should bring the session back to disk
+	path := filepath.Join(dir, session.ID+".json")
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the background retry to rewrite the session file")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
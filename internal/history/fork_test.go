@@ -0,0 +1,88 @@
+package history
+
+import "testing"
+
+// forkTestManager returns a manager with a current session holding n messages.
+func forkTestManager(t *testing.T, n int) *Manager {
+	t.Helper()
+	manager, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	session := manager.NewSession()
+	session.Name = "original"
+	for i := 0; i < n; i++ {
+		role := RoleUser
+		if i%2 == 1 {
+			role = RoleAssistant
+		}
+		session.Messages = append(session.Messages, Message{Role: role, Content: "msg"})
+	}
+	if err := manager.Save(session); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return manager
+}
+
+func TestForkCopiesAllMessages(t *testing.T) {
+	manager := forkTestManager(t, 4)
+	originalID := manager.Current().ID
+
+	fork, err := manager.Fork("", 0)
+	if err != nil {
+		t.Fatalf("Fork: %v", err)
+	}
+
+	if fork.ID == originalID {
+		t.Error("expected fork to have a new ID")
+	}
+	if fork.ParentID != originalID {
+		t.Errorf("expected ParentID %q, got %q", originalID, fork.ParentID)
+	}
+	if fork.Name != "original (fork)" {
+		t.Errorf("expected default fork name, got %q", fork.Name)
+	}
+	if len(fork.Messages) != 4 {
+		t.Errorf("expected 4 messages copied, got %d", len(fork.Messages))
+	}
+	if manager.Current() != fork {
+		t.Error("expected manager to switch to the fork")
+	}
+}
+
+func TestForkTruncatesAtN(t *testing.T) {
+	manager := forkTestManager(t, 6)
+
+	fork, err := manager.Fork("early branch", 2)
+	if err != nil {
+		t.Fatalf("Fork: %v", err)
+	}
+
+	if len(fork.Messages) != 2 {
+		t.Errorf("expected 2 messages, got %d", len(fork.Messages))
+	}
+	if fork.Name != "early branch" {
+		t.Errorf("expected given name, got %q", fork.Name)
+	}
+}
+
+func TestForkLeavesOriginalUntouched(t *testing.T) {
+	manager := forkTestManager(t, 3)
+	originalID := manager.Current().ID
+
+	fork, err := manager.Fork("", 1)
+	if err != nil {
+		t.Fatalf("Fork: %v", err)
+	}
+
+	// Appending to the fork must not leak into the original's slice
+	fork.Messages = append(fork.Messages, Message{Role: RoleUser, Content: "new direction"})
+
+	original, err := manager.LoadSessionByID(originalID)
+	if err != nil {
+		t.Fatalf("LoadSessionByID: %v", err)
+	}
+	if len(original.Messages) != 3 {
+		t.Errorf("expected original to keep 3 messages, got %d", len(original.Messages))
+	}
+}
@@ -2,29 +2,126 @@
 package history
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+
+	"gopus/internal/printer"
+	"gopus/internal/text"
 )
 
+// sessionWriteDebounce is how long rapid successive auto-saves of the
+// current session may coalesce before it is rewritten. Long sessions grow
+// into multi-megabyte files, so rewriting on every appended message adds up.
+const sessionWriteDebounce = 500 * time.Millisecond
+
+// saveFailureThreshold is how many consecutive failed writes of the current
+// session it takes before the manager stops surfacing the error for every
+// message and switches to degraded mode.
+const saveFailureThreshold = 3
+
+// saveRetryInterval is how often degraded mode retries the write in the
+// background. A variable so tests do not have to wait out the real interval.
+var saveRetryInterval = 15 * time.Second
+
 // Session represents a chat session with its history.
 type Session struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Messages  []Message `json:"messages"`
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ParentID     string `json:"parent_id,omitempty"`     // session this one was forked from
+	SystemPrompt string `json:"system_prompt,omitempty"` // per-session override of the config system prompt
+	MaxTokens    int    `json:"max_tokens,omitempty"`    // per-session override of openai.max_tokens; 0 uses the default
+
+	// Per-session model and temperature overrides, set by templates; the
+	// zero value uses the config default.
+	Model       string   `json:"model,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+
+	// Per-session generation parameter overrides set with /params; nil
+	// uses the config value (which may itself be unset).
+	TopP             *float64 `json:"top_p,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+	Seed             *int     `json:"seed,omitempty"`
+
+	// JSON output mode set with /json; empty means free-form text.
+	ResponseFormat     string         `json:"response_format,omitempty"`      // "json_object" or "json_schema"
+	ResponseSchemaName string         `json:"response_schema_name,omitempty"` // name sent alongside the schema
+	ResponseSchema     map[string]any `json:"response_schema,omitempty"`      // schema for json_schema mode
+
+	// ContextSet lists files and notes injected into every request with
+	// their current contents (/context add). Only paths and note text are
+	// stored; file contents are re-read each turn.
+	ContextSet []ContextEntry `json:"context_set,omitempty"`
+
+	// SpentUSD accumulates the estimated API cost of this session's
+	// requests, checked against limits.per_session_usd.
+	SpentUSD float64 `json:"spent_usd,omitempty"`
+
+	// Queue holds user messages composed while the API was unreachable,
+	// sent in order by /flush.
+	Queue []QueuedMessage `json:"queue,omitempty"`
+
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	Messages    []Message    `json:"messages"`
+	Checkpoints []Checkpoint `json:"checkpoints,omitempty"`  // rollback points within the session
+	PinnedFacts []string     `json:"pinned_facts,omitempty"` // facts summarization must always preserve
+	Pinned      bool         `json:"pinned,omitempty"`       // protected from retention pruning
 }
 
-// Manager handles session lifecycle and persistence.
+// ContextEntry is one member of a session's context set: either a file
+// path whose current contents are injected into each request, or a literal
+// note injected verbatim.
+type ContextEntry struct {
+	Path string `json:"path,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// Label returns a short display label for the entry.
+func (e ContextEntry) Label() string {
+	if e.Path != "" {
+		return e.Path
+	}
+	return "note: " + text.TruncateWidth(e.Text, 40)
+}
+
+// Manager handles session lifecycle and persistence. Its methods are safe
+// for concurrent use; like the sessions index, auto-saves are coalesced so
+// a burst of appended messages produces one write instead of a whole-file
+// rewrite each. Callers that reach into Session fields directly are not
+// covered by the Manager's locking.
 type Manager struct {
+	mu          sync.Mutex // guards current, sessionsDir, and the save debounce state
 	sessionsDir string
 	current     *Session
+	index       *sessionIndex
+
+	dirty     bool // the current session has changes not yet on disk
+	lastWrite time.Time
+	saveTimer *time.Timer
+
+	// Degraded-save state, for when the sessions directory disappears at
+	// runtime (a network mount or synced folder dropping out). Messages
+	// keep accumulating in the in-memory session; the session file holds
+	// them all, so the first successful rewrite recovers everything in
+	// order.
+	saveFailures int // consecutive failed writes of the current session
+	degraded     bool
+	retryTimer   *time.Timer
+
+	// Advisory session locking, so two gopus instances cannot clobber the
+	// same session file. lockedID names the session whose lock this
+	// manager holds; readOnly is set when another live instance holds it.
+	lockedID string
+	readOnly bool
 }
 
 // DefaultSessionsDir returns the default directory for storing sessions.
@@ -53,13 +150,27 @@ func NewManager(sessionsDir string) (*Manager, error) {
 		return nil, fmt.Errorf("failed to create sessions directory: %w", err)
 	}
 
-	return &Manager{
+	m := &Manager{
 		sessionsDir: sessionsDir,
-	}, nil
+		index:       newSessionIndex(sessionsDir),
+	}
+
+	// Upgrade session files left behind by the legacy chat loop
+	if n := m.migrateLegacySessions(); n > 0 {
+		fmt.Printf("Migrated %d legacy session file(s) to the current format.\n", n)
+	}
+
+	return m, nil
 }
 
-// NewSession creates a new session with a generated ID.
+// NewSession creates a new session with a generated ID. A pending save of
+// the previous session is flushed before switching away.
 func (m *Manager) NewSession() *Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_ = m.flushSessionLocked()
+
 	now := time.Now()
 	session := &Session{
 		ID:        uuid.New().String(),
@@ -69,22 +180,35 @@ func (m *Manager) NewSession() *Session {
 		Messages:  []Message{},
 	}
 	m.current = session
+	m.lastWrite = time.Time{}
+	m.moveLockLocked()
 	return session
 }
 
 // Current returns the currently active session.
 func (m *Manager) Current() *Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.current
 }
 
-// SetCurrent sets the current session.
+// SetCurrent sets the current session, flushing a pending save of the
+// previous one first.
 func (m *Manager) SetCurrent(session *Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_ = m.flushSessionLocked()
 	m.current = session
+	m.lastWrite = time.Time{}
+	m.moveLockLocked()
 }
 
 // ListSessions returns all available sessions sorted by last updated (most recent first).
 func (m *Manager) ListSessions() ([]*Session, error) {
-	entries, err := os.ReadDir(m.sessionsDir)
+	dir := m.SessionsDir()
+
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return []*Session{}, nil
@@ -94,11 +218,11 @@ func (m *Manager) ListSessions() ([]*Session, error) {
 
 	var sessions []*Session
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+		if entry.IsDir() || (!strings.HasSuffix(entry.Name(), ".json") && !strings.HasSuffix(entry.Name(), ".json.gz")) {
 			continue
 		}
 
-		sessionPath := filepath.Join(m.sessionsDir, entry.Name())
+		sessionPath := filepath.Join(dir, entry.Name())
 		session, err := loadSession(sessionPath)
 		if err != nil {
 			// Skip corrupted session files
@@ -115,56 +239,339 @@ func (m *Manager) ListSessions() ([]*Session, error) {
 	return sessions, nil
 }
 
-// LoadSessionByID loads a session by its ID.
+// LoadSessionByID loads a session by its ID and makes it current, flushing
+// a pending save of the previous session first.
 func (m *Manager) LoadSessionByID(id string) (*Session, error) {
-	sessionPath := filepath.Join(m.sessionsDir, id+".json")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessionPath := findSessionFile(m.sessionsDir, id)
 	session, err := loadSession(sessionPath)
 	if err != nil {
 		return nil, err
 	}
+
+	_ = m.flushSessionLocked()
 	m.current = session
+	m.lastWrite = time.Time{}
+	m.moveLockLocked()
 	return session, nil
 }
 
-// SaveCurrent saves the current session to disk.
+// SaveCurrent writes the current session to disk immediately, bypassing the
+// auto-save debounce.
 func (m *Manager) SaveCurrent() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if m.current == nil {
 		return fmt.Errorf("no current session to save")
 	}
-	return m.Save(m.current)
+	return m.saveLocked(m.current)
+}
+
+// TruncateLastMessage removes the newest message from the current session
+// and saves, undoing a message whose request failed. A missing or empty
+// session is a no-op.
+func (m *Manager) TruncateLastMessage() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil || len(m.current.Messages) == 0 {
+		return nil
+	}
+	if err := m.writableLocked(); err != nil {
+		return err
+	}
+	m.current.Messages = m.current.Messages[:len(m.current.Messages)-1]
+	return m.saveLocked(m.current)
+}
+
+// ReplaceMessages swaps the current session's messages wholesale — how
+// summarization lands its compressed history — and saves.
+func (m *Manager) ReplaceMessages(messages []Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("no current session")
+	}
+	if err := m.writableLocked(); err != nil {
+		return err
+	}
+	m.current.Messages = messages
+	return m.saveLocked(m.current)
 }
 
-// Save saves a session to disk.
+// Save saves a session to disk and updates the sessions index.
 func (m *Manager) Save(session *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.saveLocked(session)
+}
+
+// saveLocked writes session and updates the index. Writing the current
+// session clears the dirty flag and restarts the debounce window; a failed
+// write of the current session feeds the degraded-save state. Callers must
+// hold m.mu.
+func (m *Manager) saveLocked(session *Session) error {
 	session.UpdatedAt = time.Now()
 	sessionPath := filepath.Join(m.sessionsDir, session.ID+".json")
-	return saveSession(sessionPath, session)
+	if err := m.writeSessionLocked(sessionPath, session); err != nil {
+		if session == m.current {
+			return m.noteSaveFailureLocked(err)
+		}
+		return err
+	}
+	m.index.update(session)
+
+	// A save supersedes any gzip-compacted copy of the same session
+	_ = os.Remove(sessionPath + ".gz")
+
+	if session == m.current {
+		m.noteSaveSuccessLocked()
+		m.dirty = false
+		m.lastWrite = time.Now()
+	}
+	return nil
+}
+
+// writeSessionLocked writes the session file, recreating the sessions
+// directory first when it has disappeared. Callers must hold m.mu.
+func (m *Manager) writeSessionLocked(path string, session *Session) error {
+	err := saveSession(path, session)
+	if err == nil {
+		return nil
+	}
+	if mkErr := os.MkdirAll(m.sessionsDir, 0755); mkErr != nil {
+		return err
+	}
+	return saveSession(path, session)
+}
+
+// noteSaveFailureLocked counts a failed write of the current session. The
+// first failures surface their error to the caller; from the threshold on,
+// the manager warns once, keeps buffering in memory, and retries in the
+// background instead of spamming an error after every turn. Callers must
+// hold m.mu.
+func (m *Manager) noteSaveFailureLocked(err error) error {
+	m.saveFailures++
+	m.dirty = true
+
+	if m.degraded {
+		m.scheduleRetryLocked()
+		return nil
+	}
+	if m.saveFailures >= saveFailureThreshold {
+		m.degraded = true
+		printer.PrintWarning(
+			"Saving the session keeps failing (%v); messages are kept in memory and saving is retried in the background.", err)
+		m.scheduleRetryLocked()
+		return nil
+	}
+	return err
+}
+
+// noteSaveSuccessLocked resets the failure count after a successful write of
+// the current session, announcing the recovery when it ends a degraded
+// stretch. Callers must hold m.mu.
+func (m *Manager) noteSaveSuccessLocked() {
+	if m.degraded {
+		printer.PrintWarning("Session saving recovered; all buffered messages are on disk.")
+	}
+	m.degraded = false
+	m.saveFailures = 0
+	if m.retryTimer != nil {
+		m.retryTimer.Stop()
+		m.retryTimer = nil
+	}
+}
+
+// scheduleRetryLocked arms the degraded-mode background retry, one timer at
+// a time. Callers must hold m.mu.
+func (m *Manager) scheduleRetryLocked() {
+	if m.retryTimer != nil {
+		return
+	}
+	m.retryTimer = time.AfterFunc(saveRetryInterval, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.retryTimer = nil
+		if m.degraded && m.dirty && m.current != nil {
+			_ = m.saveLocked(m.current)
+		}
+	})
+}
+
+// scheduleSaveLocked coalesces auto-saves of the current session the same
+// way the sessions index does: the first save in a burst is written
+// immediately, later ones arm a short timer so rapid message appends
+// produce a single rewrite. Callers must hold m.mu.
+func (m *Manager) scheduleSaveLocked() error {
+	m.dirty = true
+
+	if time.Since(m.lastWrite) >= sessionWriteDebounce {
+		return m.saveLocked(m.current)
+	}
+
+	if m.saveTimer == nil {
+		m.saveTimer = time.AfterFunc(sessionWriteDebounce, func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.saveTimer = nil
+			if m.dirty && m.current != nil {
+				_ = m.saveLocked(m.current)
+			}
+		})
+	}
+	return nil
+}
+
+// flushSessionLocked writes a pending debounced save immediately. Callers
+// must hold m.mu.
+func (m *Manager) flushSessionLocked() error {
+	if !m.dirty || m.current == nil {
+		return nil
+	}
+	return m.saveLocked(m.current)
+}
+
+// Flush writes any pending debounced session save and index update
+// immediately. Called at shutdown so a quick exit after a message cannot
+// lose the tail of the conversation — and so a sessions directory that
+// came back late still gets the messages buffered while it was gone.
+func (m *Manager) Flush() error {
+	m.mu.Lock()
+	err := m.flushSessionLocked()
+	m.mu.Unlock()
+
+	if indexErr := m.FlushIndex(); err == nil {
+		err = indexErr
+	}
+	return err
+}
+
+// Dirty reports whether the current session has changes not yet on disk.
+func (m *Manager) Dirty() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.dirty
+}
+
+// DumpRecovery writes the current session's JSON to a recovery file in
+// dir, the last resort when the sessions directory itself cannot be
+// written at exit. It returns the file's path.
+func (m *Manager) DumpRecovery(dir string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return "", fmt.Errorf("no current session")
+	}
+	data, err := json.MarshalIndent(m.current, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	path := filepath.Join(dir, "gopus-recovery-"+m.current.ID+".json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write recovery file: %w", err)
+	}
+	return path, nil
 }
 
 // DeleteSession deletes a session by its ID.
 func (m *Manager) DeleteSession(id string) error {
-	sessionPath := filepath.Join(m.sessionsDir, id+".json")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessionPath := findSessionFile(m.sessionsDir, id)
 	if err := os.Remove(sessionPath); err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
+	m.index.remove(id)
+
+	// Drop the lock with the session, whether we or a dead process held it
+	if m.lockedID == id {
+		m.releaseLockLocked()
+	} else {
+		_ = os.Remove(lockPath(m.sessionsDir, id))
+	}
 
 	// Clear current if it was the deleted session
 	if m.current != nil && m.current.ID == id {
 		m.current = nil
+		m.dirty = false
+		m.readOnly = false
 	}
 
 	return nil
 }
 
+// Fork copies the current session's messages into a brand-new session and
+// switches to it, leaving the original untouched on disk. upTo limits the
+// copy to the first N messages; zero or out-of-range copies everything.
+// name defaults to "<original> (fork)". The fork records the original's ID
+// as ParentID so lineage can be shown in the session tree.
+func (m *Manager) Fork(name string, upTo int) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return nil, fmt.Errorf("no current session to fork")
+	}
+	src := m.current
+
+	if upTo <= 0 || upTo > len(src.Messages) {
+		upTo = len(src.Messages)
+	}
+	if name == "" {
+		name = src.Name + " (fork)"
+	}
+
+	now := time.Now()
+	fork := &Session{
+		ID:        uuid.New().String(),
+		Name:      name,
+		ParentID:  src.ID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Messages:  append([]Message{}, src.Messages[:upTo]...),
+	}
+
+	if err := m.saveLocked(fork); err != nil {
+		return nil, fmt.Errorf("failed to save forked session: %w", err)
+	}
+
+	_ = m.flushSessionLocked()
+	m.current = fork
+	m.lastWrite = time.Time{}
+	return fork, nil
+}
+
 // AddMessage adds a message to the current session and saves it.
 func (m *Manager) AddMessage(role Role, content string) error {
+	return m.AddMessageWithMeta(role, content, nil)
+}
+
+// AddMessageWithMeta adds a message with response metadata to the current
+// session and saves it.
+func (m *Manager) AddMessageWithMeta(role Role, content string, meta *Meta) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if m.current == nil {
 		return fmt.Errorf("no current session")
 	}
+	if err := m.writableLocked(); err != nil {
+		return err
+	}
 
 	m.current.Messages = append(m.current.Messages, Message{
-		Role:    role,
-		Content: content,
+		Role:      role,
+		Content:   content,
+		CreatedAt: time.Now(),
+		Meta:      meta,
 	})
 
 	// Set session name from first user message if not set
@@ -172,8 +579,211 @@ func (m *Manager) AddMessage(role Role, content string) error {
 		m.current.Name = generateSessionName(content)
 	}
 
-	// Auto-save after each message
-	return m.SaveCurrent()
+	// Auto-save after each message (coalesced for rapid appends)
+	return m.scheduleSaveLocked()
+}
+
+// ExtendLastAssistant appends a continuation to the most recent message,
+// which must be an assistant response, and folds the continuation's
+// metadata into the existing turn metadata. Used when a truncated response
+// is resumed: the continuation belongs to the same logical message.
+func (m *Manager) ExtendLastAssistant(content string, meta *Meta) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("no current session")
+	}
+	if err := m.writableLocked(); err != nil {
+		return err
+	}
+	if len(m.current.Messages) == 0 {
+		return fmt.Errorf("no messages in session")
+	}
+	last := &m.current.Messages[len(m.current.Messages)-1]
+	if last.Role != RoleAssistant {
+		return fmt.Errorf("last message is not an assistant response")
+	}
+
+	last.Content += content
+	if meta != nil {
+		if last.Meta == nil {
+			last.Meta = &Meta{}
+		}
+		last.Meta.DurationMS += meta.DurationMS
+		last.Meta.PromptTokens += meta.PromptTokens
+		last.Meta.CompletionTokens += meta.CompletionTokens
+		if meta.Model != "" {
+			last.Meta.Model = meta.Model
+		}
+		last.Meta.FinishReason = meta.FinishReason
+	}
+
+	return m.scheduleSaveLocked()
+}
+
+// AddImageMessage adds a user message with an attached local image and saves it.
+// The image is stored as a file reference; see Message.ImagePath.
+func (m *Manager) AddImageMessage(content, imagePath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("no current session")
+	}
+	if err := m.writableLocked(); err != nil {
+		return err
+	}
+
+	m.current.Messages = append(m.current.Messages, Message{
+		Role:      RoleUser,
+		Content:   content,
+		CreatedAt: time.Now(),
+		ImagePath: imagePath,
+	})
+
+	if m.current.Name == "" {
+		m.current.Name = generateSessionName(content)
+	}
+
+	return m.scheduleSaveLocked()
+}
+
+// AddSystemNote adds a system-authored note to the current session and saves it.
+// Like user notes, system notes are displayed but excluded from API requests.
+func (m *Manager) AddSystemNote(content string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("no current session")
+	}
+	if err := m.writableLocked(); err != nil {
+		return err
+	}
+
+	m.current.Messages = append(m.current.Messages, Message{
+		Role:      RoleSystem,
+		Content:   content,
+		Type:      TypeNote,
+		CreatedAt: time.Now(),
+	})
+
+	return m.scheduleSaveLocked()
+}
+
+// AddNote adds a private note to the current session and saves it.
+// Notes are shown in history but excluded from API requests and summarization.
+func (m *Manager) AddNote(content string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("no current session")
+	}
+	if err := m.writableLocked(); err != nil {
+		return err
+	}
+
+	m.current.Messages = append(m.current.Messages, Message{
+		Role:      RoleUser,
+		Content:   content,
+		Type:      TypeNote,
+		CreatedAt: time.Now(),
+	})
+
+	return m.scheduleSaveLocked()
+}
+
+// PinFact records a fact on the current session that summarization must
+// always feed into its prompts, so it survives any number of compressions.
+func (m *Manager) PinFact(text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("no current session")
+	}
+
+	m.current.PinnedFacts = append(m.current.PinnedFacts, text)
+	return m.scheduleSaveLocked()
+}
+
+// PinnedFacts returns a copy of the current session's pinned facts.
+func (m *Manager) PinnedFacts() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return nil
+	}
+	return append([]string{}, m.current.PinnedFacts...)
+}
+
+// AddSpend adds an estimated request cost to the current session's total.
+func (m *Manager) AddSpend(usd float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("no current session")
+	}
+
+	m.current.SpentUSD += usd
+	return m.scheduleSaveLocked()
+}
+
+// SpentUSD returns the current session's accumulated spending.
+func (m *Manager) SpentUSD() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return 0
+	}
+	return m.current.SpentUSD
+}
+
+// AddContextEntry appends an entry to the current session's context set.
+func (m *Manager) AddContextEntry(entry ContextEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("no current session")
+	}
+
+	m.current.ContextSet = append(m.current.ContextSet, entry)
+	return m.scheduleSaveLocked()
+}
+
+// ContextEntries returns a copy of the current session's context set.
+func (m *Manager) ContextEntries() []ContextEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return nil
+	}
+	return append([]ContextEntry{}, m.current.ContextSet...)
+}
+
+// RemoveContextEntry removes the entry at the given zero-based index from
+// the current session's context set and returns it.
+func (m *Manager) RemoveContextEntry(index int) (ContextEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return ContextEntry{}, fmt.Errorf("no current session")
+	}
+	if index < 0 || index >= len(m.current.ContextSet) {
+		return ContextEntry{}, fmt.Errorf("no context entry %d", index+1)
+	}
+
+	entry := m.current.ContextSet[index]
+	m.current.ContextSet = append(m.current.ContextSet[:index], m.current.ContextSet[index+1:]...)
+	return entry, m.scheduleSaveLocked()
 }
 
 // generateSessionName creates a session name from the first user message.
@@ -186,15 +796,13 @@ func generateSessionName(content string) string {
 	name = strings.ReplaceAll(name, "\n", " ")
 	name = strings.ReplaceAll(name, "\r", "")
 
-	// Truncate if too long
-	if len(name) > maxLength {
-		name = name[:maxLength-3] + "..."
-	}
-
-	return name
+	// Truncate if too long, without splitting multi-byte runes
+	return text.TruncateWidth(name, maxLength)
 }
 
 // SessionsDir returns the sessions directory path.
 func (m *Manager) SessionsDir() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.sessionsDir
 }
@@ -0,0 +1,260 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// indexFileName is the name of the sessions index file inside the sessions
+// directory. The index summarizes sessions so listings don't have to parse
+// every session file.
+const indexFileName = "index.json"
+
+// indexWriteDebounce is how long rapid successive saves (the normal chat
+// auto-save path) may coalesce before the index is written.
+const indexWriteDebounce = 500 * time.Millisecond
+
+// IndexEntry summarizes one session in the sessions index.
+type IndexEntry struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	ParentID     string    `json:"parent_id,omitempty"`
+	MessageCount int       `json:"message_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Stats carries the session's aggregate figures for the stats
+	// dashboard, so it never has to parse message bodies.
+	Stats *SessionStats `json:"stats,omitempty"`
+}
+
+// indexEntryOf builds the index entry describing a session's current state.
+func indexEntryOf(session *Session) IndexEntry {
+	return IndexEntry{
+		ID:           session.ID,
+		Name:         session.Name,
+		ParentID:     session.ParentID,
+		MessageCount: len(session.Messages),
+		CreatedAt:    session.CreatedAt,
+		UpdatedAt:    session.UpdatedAt,
+		Stats:        sessionStatsOf(session),
+	}
+}
+
+// sessionIndex tracks index state for a Manager. Writes are coalesced:
+// during a batch nothing is written until the batch commits, and the
+// normal save path debounces so rapid auto-saves produce one write.
+type sessionIndex struct {
+	mu        sync.Mutex
+	path      string
+	entries   map[string]IndexEntry
+	batching  bool
+	dirty     bool
+	lastWrite time.Time
+	timer     *time.Timer
+}
+
+// newSessionIndex loads the index file if present, or starts empty.
+func newSessionIndex(sessionsDir string) *sessionIndex {
+	idx := &sessionIndex{
+		path:    filepath.Join(sessionsDir, indexFileName),
+		entries: make(map[string]IndexEntry),
+	}
+
+	data, err := os.ReadFile(idx.path)
+	if err != nil {
+		return idx
+	}
+
+	var list []IndexEntry
+	if err := json.Unmarshal(data, &list); err != nil {
+		// A corrupted index is not fatal; it will be rebuilt on reindex
+		return idx
+	}
+	for _, e := range list {
+		idx.entries[e.ID] = e
+	}
+	return idx
+}
+
+// update records the current state of a session in the index and schedules
+// a write.
+func (idx *sessionIndex) update(session *Session) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.entries[session.ID] = indexEntryOf(session)
+	idx.scheduleWriteLocked()
+}
+
+// remove deletes a session from the index and schedules a write.
+func (idx *sessionIndex) remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	delete(idx.entries, id)
+	idx.scheduleWriteLocked()
+}
+
+// replaceAll swaps the whole index contents (used by reindexing).
+func (idx *sessionIndex) replaceAll(entries map[string]IndexEntry) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.entries = entries
+	idx.scheduleWriteLocked()
+}
+
+// beginBatch suspends index writes until commitBatch is called.
+func (idx *sessionIndex) beginBatch() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.batching = true
+}
+
+// commitBatch ends the batch and writes the index once if anything changed.
+func (idx *sessionIndex) commitBatch() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.batching = false
+	if !idx.dirty {
+		return nil
+	}
+	return idx.writeLocked()
+}
+
+// scheduleWriteLocked coalesces index writes. Must be called with mu held.
+// During a batch nothing is written. Otherwise the index is written
+// immediately if the last write is old enough, or a short timer is armed
+// so bursts of saves produce a single write.
+func (idx *sessionIndex) scheduleWriteLocked() {
+	idx.dirty = true
+
+	if idx.batching {
+		return
+	}
+
+	if time.Since(idx.lastWrite) >= indexWriteDebounce {
+		_ = idx.writeLocked()
+		return
+	}
+
+	if idx.timer == nil {
+		idx.timer = time.AfterFunc(indexWriteDebounce, func() {
+			idx.mu.Lock()
+			defer idx.mu.Unlock()
+			idx.timer = nil
+			if idx.dirty && !idx.batching {
+				_ = idx.writeLocked()
+			}
+		})
+	}
+}
+
+// writeLocked writes the index file sorted by UpdatedAt descending.
+// Must be called with mu held.
+func (idx *sessionIndex) writeLocked() error {
+	list := make([]IndexEntry, 0, len(idx.entries))
+	for _, e := range idx.entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].UpdatedAt.After(list[j].UpdatedAt)
+	})
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize sessions index: %w", err)
+	}
+	if err := os.WriteFile(idx.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write sessions index: %w", err)
+	}
+
+	idx.dirty = false
+	idx.lastWrite = time.Now()
+	return nil
+}
+
+// BeginBatch suspends sessions-index writes so bulk operations (imports,
+// reindexing, pruning) can save many sessions without rewriting the index
+// for each one. Pair with CommitBatch.
+func (m *Manager) BeginBatch() {
+	m.index.beginBatch()
+}
+
+// CommitBatch ends a batch started with BeginBatch and writes the index once.
+func (m *Manager) CommitBatch() error {
+	return m.index.commitBatch()
+}
+
+// PendingWork reports debounced session or index writes that have not
+// reached disk yet.
+func (m *Manager) PendingWork() (string, bool) {
+	m.mu.Lock()
+	sessionDirty := m.dirty
+	m.mu.Unlock()
+
+	m.index.mu.Lock()
+	indexDirty := m.index.dirty
+	m.index.mu.Unlock()
+
+	switch {
+	case sessionDirty && indexDirty:
+		return "unwritten session and index updates", true
+	case sessionDirty:
+		return "an unwritten session save", true
+	case indexDirty:
+		return "an unwritten sessions index update", true
+	}
+	return "", false
+}
+
+// FlushIndex writes a pending sessions-index update immediately instead of
+// waiting for the debounce timer. Called on exit so a quick quit after a
+// save does not leave the index behind.
+func (m *Manager) FlushIndex() error {
+	m.index.mu.Lock()
+	defer m.index.mu.Unlock()
+	if !m.index.dirty || m.index.batching {
+		return nil
+	}
+	return m.index.writeLocked()
+}
+
+// Reindex rebuilds the sessions index from the session files on disk.
+func (m *Manager) Reindex() error {
+	sessions, err := m.ListSessions()
+	if err != nil {
+		return err
+	}
+
+	entries := make(map[string]IndexEntry, len(sessions))
+	for _, s := range sessions {
+		entries[s.ID] = indexEntryOf(s)
+	}
+
+	m.index.replaceAll(entries)
+	return nil
+}
+
+// IndexEntries returns the current index contents sorted by UpdatedAt
+// descending.
+func (m *Manager) IndexEntries() []IndexEntry {
+	m.index.mu.Lock()
+	defer m.index.mu.Unlock()
+
+	list := make([]IndexEntry, 0, len(m.index.entries))
+	for _, e := range m.index.entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].UpdatedAt.After(list[j].UpdatedAt)
+	})
+	return list
+}
@@ -0,0 +1,146 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func benchSession(i int) *Session {
+	now := time.Now()
+	return &Session{
+		ID:        fmt.Sprintf("bench-session-%04d", i),
+		Name:      fmt.Sprintf("imported conversation %d", i),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Messages: []Message{
+			{Role: RoleUser, Content: "hello"},
+			{Role: RoleAssistant, Content: "hi there"},
+		},
+	}
+}
+
+func TestBatchCoalescesIndexWrites(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	manager.BeginBatch()
+	for i := 0; i < 10; i++ {
+		if err := manager.Save(benchSession(i)); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	indexPath := filepath.Join(dir, indexFileName)
+	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Fatalf("index written during batch (stat err: %v)", err)
+	}
+
+	if err := manager.CommitBatch(); err != nil {
+		t.Fatalf("CommitBatch: %v", err)
+	}
+	if _, err := os.Stat(indexPath); err != nil {
+		t.Fatalf("index missing after commit: %v", err)
+	}
+
+	entries := manager.IndexEntries()
+	if len(entries) != 10 {
+		t.Errorf("expected 10 index entries, got %d", len(entries))
+	}
+}
+
+func TestDeleteRemovesIndexEntry(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	session := benchSession(0)
+	if err := manager.Save(session); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := manager.DeleteSession(session.ID); err != nil {
+		t.Fatalf("DeleteSession: %v", err)
+	}
+
+	if entries := manager.IndexEntries(); len(entries) != 0 {
+		t.Errorf("expected empty index after delete, got %d entries", len(entries))
+	}
+}
+
+func TestReindexRebuildsFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := manager.Save(benchSession(i)); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	// A fresh manager with a deleted index should recover via Reindex
+	if err := os.Remove(filepath.Join(dir, indexFileName)); err != nil {
+		t.Fatalf("remove index: %v", err)
+	}
+	fresh, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if err := fresh.Reindex(); err != nil {
+		t.Fatalf("Reindex: %v", err)
+	}
+
+	if entries := fresh.IndexEntries(); len(entries) != 3 {
+		t.Errorf("expected 3 index entries after reindex, got %d", len(entries))
+	}
+}
+
+// BenchmarkBulkSaveBatched measures importing many sessions with index
+// writes batched; total time should scale near-linearly with session count.
+func BenchmarkBulkSaveBatched(b *testing.B) {
+	dir := b.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		b.Fatalf("NewManager: %v", err)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		manager.BeginBatch()
+		for i := 0; i < 1000; i++ {
+			if err := manager.Save(benchSession(i)); err != nil {
+				b.Fatalf("Save: %v", err)
+			}
+		}
+		if err := manager.CommitBatch(); err != nil {
+			b.Fatalf("CommitBatch: %v", err)
+		}
+	}
+}
+
+// BenchmarkBulkSaveUnbatched is the same workload without batching, for
+// comparison against BenchmarkBulkSaveBatched.
+func BenchmarkBulkSaveUnbatched(b *testing.B) {
+	dir := b.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		b.Fatalf("NewManager: %v", err)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 1000; i++ {
+			if err := manager.Save(benchSession(i)); err != nil {
+				b.Fatalf("Save: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopus/internal/printer"
+)
+
+// ErrReadOnlySession is returned by message-adding methods while the
+// session is open read-only because another gopus instance holds its lock.
+var ErrReadOnlySession = errors.New("session is read-only: another gopus instance has it open (restart and take over the lock to write)")
+
+// LockInfo identifies the process holding a session lock file.
+type LockInfo struct {
+	PID      int    `json:"pid"`
+	Hostname string `json:"hostname"`
+}
+
+// lockPath returns the advisory lock file guarding a session. Lock files
+// are a portable protocol — no flock — so two instances on any platform
+// agree on who owns a session.
+func lockPath(dir, id string) string {
+	return filepath.Join(dir, id+".lock")
+}
+
+// acquireLock tries to take the lock for a session. It returns (nil, nil)
+// on success, the holder when a live process has the session open, and an
+// error when the lock file cannot be written at all. A lock left behind
+// by a dead process on this host is cleaned up and taken over.
+func acquireLock(dir, id string) (*LockInfo, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(lockPath(dir, id), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			hostname, _ := os.Hostname()
+			data, _ := json.Marshal(LockInfo{PID: os.Getpid(), Hostname: hostname})
+			_, writeErr := file.Write(data)
+			if closeErr := file.Close(); writeErr == nil {
+				writeErr = closeErr
+			}
+			if writeErr != nil {
+				os.Remove(lockPath(dir, id))
+				return nil, fmt.Errorf("failed to write lock file: %w", writeErr)
+			}
+			return nil, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		holder, stale := readLock(dir, id)
+		if !stale {
+			return holder, nil
+		}
+		// Stale: the lock is from a dead process; clean it and retry once
+		if err := os.Remove(lockPath(dir, id)); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale lock file: %w", err)
+		}
+	}
+	return nil, fmt.Errorf("failed to acquire session lock")
+}
+
+// readLock reads an existing lock file and decides whether it is stale.
+// An unreadable or garbled lock counts as stale; a lock from another host
+// cannot be probed and counts as live.
+func readLock(dir, id string) (holder *LockInfo, stale bool) {
+	data, err := os.ReadFile(lockPath(dir, id))
+	if err != nil {
+		return nil, true
+	}
+	var info LockInfo
+	if err := json.Unmarshal(data, &info); err != nil || info.PID <= 0 {
+		return nil, true
+	}
+	hostname, _ := os.Hostname()
+	if info.Hostname == hostname && !processAlive(info.PID) {
+		return &info, true
+	}
+	return &info, false
+}
+
+// moveLockLocked follows the current session: it releases the previously
+// held lock and takes the new session's. When another live instance holds
+// it, the manager goes read-only and says so. Callers must hold m.mu.
+func (m *Manager) moveLockLocked() {
+	if m.current == nil {
+		m.releaseLockLocked()
+		m.readOnly = false
+		return
+	}
+	if m.lockedID == m.current.ID {
+		return
+	}
+	m.releaseLockLocked()
+
+	holder, err := acquireLock(m.sessionsDir, m.current.ID)
+	switch {
+	case holder != nil:
+		m.readOnly = true
+		printer.PrintWarning(
+			"Session is already open by gopus process %d on %s; continuing read-only. New messages are refused until that instance exits.",
+			holder.PID, holder.Hostname)
+	case err != nil:
+		// No lock protocol available (e.g. unwritable directory); carry
+		// on unlocked rather than blocking the chat
+		m.readOnly = false
+	default:
+		m.lockedID = m.current.ID
+		m.readOnly = false
+	}
+}
+
+// releaseLockLocked removes the lock file this manager holds, if any.
+// Callers must hold m.mu.
+func (m *Manager) releaseLockLocked() {
+	if m.lockedID == "" {
+		return
+	}
+	_ = os.Remove(lockPath(m.sessionsDir, m.lockedID))
+	m.lockedID = ""
+}
+
+// ReleaseSessionLock releases the held session lock, for shutdown.
+func (m *Manager) ReleaseSessionLock() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.releaseLockLocked()
+}
+
+// ForceSessionLock takes over the current session's lock regardless of
+// who holds it, for when the user chooses takeover over read-only mode.
+func (m *Manager) ForceSessionLock() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return nil
+	}
+	if err := os.Remove(lockPath(m.sessionsDir, m.current.ID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove lock file: %w", err)
+	}
+	holder, err := acquireLock(m.sessionsDir, m.current.ID)
+	if err != nil {
+		return err
+	}
+	if holder != nil {
+		return fmt.Errorf("session was immediately re-locked by process %d on %s", holder.PID, holder.Hostname)
+	}
+	m.lockedID = m.current.ID
+	m.readOnly = false
+	return nil
+}
+
+// ReadOnly reports whether the current session is open read-only because
+// another instance holds its lock.
+func (m *Manager) ReadOnly() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.readOnly
+}
+
+// writableLocked refuses writes while the session is read-only. Callers
+// must hold m.mu.
+func (m *Manager) writableLocked() error {
+	if m.readOnly {
+		return ErrReadOnlySession
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package history
+
+import "os"
+
+// processAlive reports whether a PID belongs to a running process. On
+// non-unix platforms FindProcess itself fails for a dead PID.
+func processAlive(pid int) bool {
+	_, err := os.FindProcess(pid)
+	return err == nil
+}
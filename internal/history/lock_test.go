@@ -0,0 +1,157 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+// writeLockFile plants a lock file as another process would have left it.
+func writeLockFile(t *testing.T, dir, id string, info LockInfo) {
+	t.Helper()
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("failed to marshal lock info: %v", err)
+	}
+	if err := os.WriteFile(lockPath(dir, id), data, 0644); err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+}
+
+func TestSessionLockFollowsCurrent(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	first := manager.NewSession()
+	if _, err := os.Stat(lockPath(dir, first.ID)); err != nil {
+		t.Fatalf("expected a lock file for the current session: %v", err)
+	}
+
+	second := manager.NewSession()
+	if _, err := os.Stat(lockPath(dir, first.ID)); !os.IsNotExist(err) {
+		t.Errorf("expected the first session's lock to be released, got %v", err)
+	}
+	if _, err := os.Stat(lockPath(dir, second.ID)); err != nil {
+		t.Errorf("expected a lock file for the new session: %v", err)
+	}
+
+	if err := manager.AddMessage(RoleUser, "to be deleted"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+	if err := manager.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if err := manager.DeleteSession(second.ID); err != nil {
+		t.Fatalf("DeleteSession failed: %v", err)
+	}
+	if _, err := os.Stat(lockPath(dir, second.ID)); !os.IsNotExist(err) {
+		t.Errorf("expected the deleted session's lock to be removed, got %v", err)
+	}
+}
+
+func TestSessionLockConflictGoesReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	session := manager.NewSession()
+	if err := manager.AddMessage(RoleUser, "hello"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+	if err := manager.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	manager.ReleaseSessionLock()
+
+	// Our own PID is certainly alive, so this lock reads as held by a
+	// live process on this host
+	hostname, _ := os.Hostname()
+	writeLockFile(t, dir, session.ID, LockInfo{PID: os.Getpid(), Hostname: hostname})
+
+	other, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if _, err := other.LoadSessionByID(session.ID); err != nil {
+		t.Fatalf("LoadSessionByID failed: %v", err)
+	}
+	if !other.ReadOnly() {
+		t.Fatal("expected read-only mode while another live process holds the lock")
+	}
+	if err := other.AddMessage(RoleUser, "refused"); !errors.Is(err, ErrReadOnlySession) {
+		t.Errorf("AddMessage = %v, want ErrReadOnlySession", err)
+	}
+	if err := other.AddNote("refused"); !errors.Is(err, ErrReadOnlySession) {
+		t.Errorf("AddNote = %v, want ErrReadOnlySession", err)
+	}
+	if err := other.TruncateLastMessage(); !errors.Is(err, ErrReadOnlySession) {
+		t.Errorf("TruncateLastMessage = %v, want ErrReadOnlySession", err)
+	}
+	if err := other.ReplaceMessages(nil); !errors.Is(err, ErrReadOnlySession) {
+		t.Errorf("ReplaceMessages = %v, want ErrReadOnlySession", err)
+	}
+	if _, err := other.RollbackTo(0); !errors.Is(err, ErrReadOnlySession) {
+		t.Errorf("RollbackTo = %v, want ErrReadOnlySession", err)
+	}
+
+	// Takeover clears read-only mode and makes writes work again
+	if err := other.ForceSessionLock(); err != nil {
+		t.Fatalf("ForceSessionLock failed: %v", err)
+	}
+	if other.ReadOnly() {
+		t.Error("expected writable mode after takeover")
+	}
+	if err := other.AddMessage(RoleUser, "accepted"); err != nil {
+		t.Errorf("AddMessage after takeover failed: %v", err)
+	}
+}
+
+func TestSessionLockStaleIsCleaned(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	session := manager.NewSession()
+	if err := manager.AddMessage(RoleUser, "hello"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+	if err := manager.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	manager.ReleaseSessionLock()
+
+	// A lock from a dead PID on this host must be swept aside silently.
+	// PID 1 is init and always alive, so use an implausibly large one.
+	hostname, _ := os.Hostname()
+	writeLockFile(t, dir, session.ID, LockInfo{PID: 1 << 30, Hostname: hostname})
+
+	other, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if _, err := other.LoadSessionByID(session.ID); err != nil {
+		t.Fatalf("LoadSessionByID failed: %v", err)
+	}
+	if other.ReadOnly() {
+		t.Fatal("expected a stale lock to be taken over, not read-only mode")
+	}
+	if err := other.AddMessage(RoleUser, "works"); err != nil {
+		t.Errorf("AddMessage failed: %v", err)
+	}
+}
+
+func TestSessionLockGarbledIsStale(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(lockPath(dir, "broken"), []byte("not json"), 0644); err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+	if _, stale := readLock(dir, "broken"); !stale {
+		t.Error("expected a garbled lock file to count as stale")
+	}
+}
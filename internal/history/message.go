@@ -2,6 +2,7 @@
 package history
 
 import (
+	"fmt"
 	"time"
 
 	"gopus/internal/openai"
@@ -23,6 +24,7 @@ type MessageType string
 const (
 	TypeMessage MessageType = "message"
 	TypeSummary MessageType = "summary"
+	TypeNote    MessageType = "note"
 )
 
 // SummaryLevel indicates the compression level of a summary.
@@ -33,6 +35,18 @@ const (
 	LevelCompressed SummaryLevel = "compressed" // High compression
 )
 
+// Meta holds response metadata recorded for assistant messages.
+// For turns involving tool calls, the values are accumulated across all
+// API calls in the turn.
+type Meta struct {
+	DurationMS       int64  `json:"duration_ms,omitempty"`       // total wall time for the turn
+	PromptTokens     int    `json:"prompt_tokens,omitempty"`     // total prompt tokens across API calls
+	CompletionTokens int    `json:"completion_tokens,omitempty"` // total completion tokens across API calls
+	Model            string `json:"model,omitempty"`             // model reported by the API
+	FinishReason     string `json:"finish_reason,omitempty"`     // finish reason of the final response
+	ToolsExecuted    int    `json:"tools_executed,omitempty"`    // number of tools executed in the turn
+}
+
 // ToolCall represents a tool call made by the assistant.
 type ToolCall struct {
 	ID        string `json:"id"`
@@ -52,6 +66,18 @@ type Message struct {
 	// Tool-related fields
 	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`   // for assistant messages with tool calls
 	ToolCallID string     `json:"tool_call_id,omitempty"` // for tool result messages
+
+	// Meta records response metadata (timing, token usage) for assistant messages.
+	Meta *Meta `json:"meta,omitempty"`
+
+	// ImagePath references a local image attached to this message.
+	// The image is stored as a file reference rather than embedding
+	// base64 data in the session JSON; it is re-encoded at request time.
+	ImagePath string `json:"image_path,omitempty"`
+
+	// FromTemplate marks seed messages a session template supplied, so
+	// they render dimly and are excluded from auto-title generation.
+	FromTemplate bool `json:"from_template,omitempty"`
 }
 
 // IsSummary returns true if this message is a summary.
@@ -59,17 +85,103 @@ func (m Message) IsSummary() bool {
 	return m.Type == TypeSummary
 }
 
-// IsMessage returns true if this message is a regular message (not a summary).
+// IsMessage returns true if this message is a regular message (not a summary or note).
 func (m Message) IsMessage() bool {
 	return m.Type == "" || m.Type == TypeMessage
 }
 
+// IsNote returns true if this message is a private user note.
+// Notes are persisted and displayed but never sent to the API.
+func (m Message) IsNote() bool {
+	return m.Type == TypeNote
+}
+
+// Model returns the model that produced this message, or "unknown" for
+// assistant messages persisted before model attribution was recorded.
+func (m Message) Model() string {
+	if m.Meta != nil && m.Meta.Model != "" {
+		return m.Meta.Model
+	}
+	return "unknown"
+}
+
+// RoleLabel returns the display label for this message's role. Notes are
+// labelled "note", assistant messages carry a subtle model attribution
+// (e.g. "assistant · gpt-4o-mini"), and summaries show their compression
+// level and how many messages they cover.
+func (m Message) RoleLabel() string {
+	if m.IsNote() {
+		return "note"
+	}
+	if m.IsSummary() {
+		label := "summary"
+		if m.SummaryLevel != "" {
+			label += " · " + string(m.SummaryLevel)
+		}
+		if m.MessageCount > 0 {
+			label += fmt.Sprintf(" · %d messages", m.MessageCount)
+		}
+		return label
+	}
+	if m.FromTemplate {
+		return string(m.Role) + " · template"
+	}
+	if m.Role == RoleAssistant {
+		return string(RoleAssistant) + " · " + m.Model()
+	}
+	return string(m.Role)
+}
+
+// ModelUsage aggregates assistant messages by the model that produced them.
+func ModelUsage(messages []Message) map[string]int {
+	usage := make(map[string]int)
+	for _, msg := range messages {
+		if msg.Role == RoleAssistant && !msg.IsNote() {
+			usage[msg.Model()]++
+		}
+	}
+	return usage
+}
+
+// TurnLatencies measures the time from each user message to the following
+// assistant reply, using the recorded timestamps. Turns whose messages
+// predate timestamp recording (zero CreatedAt) are skipped.
+func TurnLatencies(messages []Message) []time.Duration {
+	var latencies []time.Duration
+	var userAt time.Time
+	for _, msg := range messages {
+		if msg.IsNote() || msg.IsSummary() {
+			continue
+		}
+		switch msg.Role {
+		case RoleUser:
+			userAt = msg.CreatedAt
+		case RoleAssistant:
+			if !userAt.IsZero() && msg.CreatedAt.After(userAt) {
+				latencies = append(latencies, msg.CreatedAt.Sub(userAt))
+			}
+			userAt = time.Time{}
+		}
+	}
+	return latencies
+}
+
 // ToOpenAI converts a Message to the OpenAI API message format.
 func (m Message) ToOpenAI() openai.ChatCompletionRequestMessage {
-	content := m.Content
 	msg := openai.ChatCompletionRequestMessage{
 		Role:    openai.ChatCompletionRequestMessageRole(m.Role),
-		Content: &content,
+		Content: openai.StringContent(m.Content),
+	}
+
+	// Attached images become multi-part content (text + image data URI).
+	// If the referenced file is gone, degrade to the text content alone.
+	if m.ImagePath != "" {
+		if dataURI, err := openai.ImageDataURI(m.ImagePath); err == nil {
+			msg.Content = openai.PartsContent([]openai.ChatCompletionContentPart{
+				openai.NewTextPart(m.Content),
+				openai.NewImagePart(dataURI),
+			})
+		}
 	}
 
 	// Handle tool calls (for assistant messages)
@@ -97,10 +209,17 @@ func (m Message) ToOpenAI() openai.ChatCompletionRequestMessage {
 }
 
 // MessageFromOpenAI creates a Message from an OpenAI API message.
+// Multi-part content is flattened to its text parts.
 func MessageFromOpenAI(msg openai.ChatCompletionRequestMessage) Message {
 	content := ""
-	if msg.Content != nil {
-		content = *msg.Content
+	if s, ok := openai.ContentAsString(msg.Content); ok {
+		content = s
+	} else if parts, ok := openai.ContentAsParts(msg.Content); ok {
+		for _, part := range parts {
+			if part.Type == openai.Text && part.Text != nil {
+				content += *part.Text
+			}
+		}
 	}
 
 	m := Message{
@@ -129,10 +248,14 @@ func MessageFromOpenAI(msg openai.ChatCompletionRequestMessage) Message {
 }
 
 // MessagesToOpenAI converts a slice of Messages to OpenAI API format.
+// Notes are skipped: they are local-only and must never reach the API.
 func MessagesToOpenAI(messages []Message) []openai.ChatCompletionRequestMessage {
-	result := make([]openai.ChatCompletionRequestMessage, len(messages))
-	for i, m := range messages {
-		result[i] = m.ToOpenAI()
+	result := make([]openai.ChatCompletionRequestMessage, 0, len(messages))
+	for _, m := range messages {
+		if m.IsNote() {
+			continue
+		}
+		result = append(result, m.ToOpenAI())
 	}
 	return result
 }
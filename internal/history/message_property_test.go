@@ -82,11 +82,10 @@ func TestMessagesFromOpenAILengthPreservation(t *testing.T) {
 	property := func(count uint8) bool {
 		// Create a slice of OpenAI messages
 		messages := make([]openai.ChatCompletionRequestMessage, int(count))
-		testContent := "test"
 		for i := range messages {
 			messages[i] = openai.ChatCompletionRequestMessage{
 				Role:    openai.ChatCompletionRequestMessageRoleUser,
-				Content: &testContent,
+				Content: openai.StringContent("test"),
 			}
 		}
 
@@ -186,8 +185,9 @@ func TestToOpenAIContentPreservation(t *testing.T) {
 
 		openaiMsg := m.ToOpenAI()
 
-		// Property: Content is preserved (dereference pointer)
-		return openaiMsg.Content != nil && *openaiMsg.Content == content
+		// Property: Content is preserved (extract from the union form)
+		s, ok := openai.ContentAsString(openaiMsg.Content)
+		return ok && s == content
 	}
 
 	if err := quick.Check(property, nil); err != nil {
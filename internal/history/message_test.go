@@ -0,0 +1,125 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoleLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  Message
+		want string
+	}{
+		{"user", Message{Role: RoleUser}, "user"},
+		{"note", Message{Role: RoleUser, Type: TypeNote}, "note"},
+		{"assistant with model", Message{Role: RoleAssistant, Meta: &Meta{Model: "gpt-4o-mini"}}, "assistant · gpt-4o-mini"},
+		{"assistant without meta", Message{Role: RoleAssistant}, "assistant · unknown"},
+		{"assistant with empty model", Message{Role: RoleAssistant, Meta: &Meta{}}, "assistant · unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.RoleLabel(); got != tt.want {
+				t.Errorf("RoleLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoleLabelSummary(t *testing.T) {
+	msg := Message{
+		Role:         RoleSystem,
+		Type:         TypeSummary,
+		SummaryLevel: LevelCondensed,
+		MessageCount: 12,
+	}
+	if got := msg.RoleLabel(); got != "summary · condensed · 12 messages" {
+		t.Errorf("expected summary label with level and range, got %q", got)
+	}
+}
+
+func TestModelUsage(t *testing.T) {
+	messages := []Message{
+		{Role: RoleUser, Content: "hi"},
+		{Role: RoleAssistant, Meta: &Meta{Model: "gpt-4o"}},
+		{Role: RoleAssistant, Meta: &Meta{Model: "gpt-4o"}},
+		{Role: RoleAssistant}, // persisted before attribution existed
+		{Role: RoleSystem, Type: TypeNote},
+	}
+
+	usage := ModelUsage(messages)
+
+	if got := usage["gpt-4o"]; got != 2 {
+		t.Errorf("expected 2 gpt-4o replies, got %d", got)
+	}
+	if got := usage["unknown"]; got != 1 {
+		t.Errorf("expected 1 unknown reply, got %d", got)
+	}
+	if len(usage) != 2 {
+		t.Errorf("expected 2 models, got %d: %v", len(usage), usage)
+	}
+}
+
+func TestTurnLatencies(t *testing.T) {
+	base := time.Date(2026, 8, 31, 12, 0, 0, 0, time.UTC)
+	messages := []Message{
+		{Role: RoleUser, CreatedAt: base},
+		{Role: RoleAssistant, CreatedAt: base.Add(3 * time.Second)},
+		// A turn from before timestamps were recorded is skipped
+		{Role: RoleUser},
+		{Role: RoleAssistant},
+		// Notes between the pair do not break the pairing
+		{Role: RoleUser, CreatedAt: base.Add(time.Minute)},
+		{Role: RoleUser, Type: TypeNote, Content: "aside", CreatedAt: base.Add(time.Minute)},
+		{Role: RoleAssistant, CreatedAt: base.Add(time.Minute + 5*time.Second)},
+	}
+
+	latencies := TurnLatencies(messages)
+	if len(latencies) != 2 {
+		t.Fatalf("expected 2 measured turns, got %d: %v", len(latencies), latencies)
+	}
+	if latencies[0] != 3*time.Second || latencies[1] != 5*time.Second {
+		t.Errorf("unexpected latencies: %v", latencies)
+	}
+}
+
+func TestTruncateAndReplaceMessages(t *testing.T) {
+	manager, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	manager.NewSession()
+	if err := manager.AddMessage(RoleUser, "question"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+	if err := manager.AddMessage(RoleAssistant, "answer"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+
+	if err := manager.TruncateLastMessage(); err != nil {
+		t.Fatalf("TruncateLastMessage failed: %v", err)
+	}
+	messages := manager.Current().Messages
+	if len(messages) != 1 || messages[0].Content != "question" {
+		t.Fatalf("expected only the first message to remain, got %v", messages)
+	}
+
+	if err := manager.ReplaceMessages([]Message{{Role: RoleSystem, Content: "summary", Type: TypeSummary}}); err != nil {
+		t.Fatalf("ReplaceMessages failed: %v", err)
+	}
+	messages = manager.Current().Messages
+	if len(messages) != 1 || messages[0].Content != "summary" {
+		t.Fatalf("expected the replacement message, got %v", messages)
+	}
+
+	// Truncating down to and past empty is a no-op, not an error
+	for i := 0; i < 2; i++ {
+		if err := manager.TruncateLastMessage(); err != nil {
+			t.Fatalf("TruncateLastMessage on shrinking session failed: %v", err)
+		}
+	}
+	if got := len(manager.Current().Messages); got != 0 {
+		t.Errorf("expected an empty session, got %d messages", got)
+	}
+}
@@ -0,0 +1,110 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Sessions written by the original single-file chat loop stored messages as
+// bare role/content pairs: no type, no timestamps, no tool fields. Those
+// files still parse, but summaries cannot be told apart from messages and
+// nothing has a creation time. migrateLegacySessions upgrades them in place
+// at startup, keeping a backup of each original.
+
+// legacyBackupSuffix is appended to the original file name before a legacy
+// session is rewritten. The suffix keeps backups out of ListSessions, which
+// only considers .json files.
+const legacyBackupSuffix = ".legacy"
+
+// isLegacySession reports whether every message in the session predates the
+// current schema, i.e. carries nothing beyond a role and content.
+func isLegacySession(session *Session) bool {
+	if len(session.Messages) == 0 {
+		return false
+	}
+	for _, msg := range session.Messages {
+		if msg.Type != "" || !msg.CreatedAt.IsZero() || msg.ToolCallID != "" ||
+			len(msg.ToolCalls) > 0 || msg.Meta != nil || msg.ImagePath != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// migrateLegacySessions upgrades all legacy session files in the sessions
+// directory and returns how many were migrated. Files that cannot be
+// migrated are left alone with a warning; they are no worse off than before.
+func (m *Manager) migrateLegacySessions() int {
+	entries, err := os.ReadDir(m.sessionsDir)
+	if err != nil {
+		return 0
+	}
+
+	migrated := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		done, err := migrateLegacySessionFile(filepath.Join(m.sessionsDir, entry.Name()))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to migrate legacy session %s: %v\n", entry.Name(), err)
+			continue
+		}
+		if done {
+			migrated++
+		}
+	}
+	return migrated
+}
+
+// migrateLegacySessionFile rewrites one legacy session file in the current
+// schema, backing up the original first. Messages get Type=message and
+// synthesized CreatedAt timestamps spaced evenly between the session's
+// creation time and the file's mtime. Returns false for files that are not
+// legacy (including ones that do not parse; ListSessions skips those too).
+func migrateLegacySessionFile(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return false, nil
+	}
+	if !isLegacySession(&session) {
+		return false, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat session file: %w", err)
+	}
+
+	// Spread timestamps evenly up to the file's last write. Without a usable
+	// session creation time, assume one message per minute before that.
+	n := len(session.Messages)
+	end := info.ModTime()
+	start := session.CreatedAt
+	if start.IsZero() || !start.Before(end) {
+		start = end.Add(-time.Duration(n) * time.Minute)
+	}
+	step := end.Sub(start) / time.Duration(n)
+
+	for i := range session.Messages {
+		session.Messages[i].Type = TypeMessage
+		session.Messages[i].CreatedAt = start.Add(time.Duration(i+1) * step)
+	}
+
+	if err := os.WriteFile(path+legacyBackupSuffix, data, 0644); err != nil {
+		return false, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := saveSession(path, &session); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,134 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// copyFixture copies a testdata file into dir under name and returns its path.
+func copyFixture(t *testing.T, fixture, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write fixture copy: %v", err)
+	}
+	return path
+}
+
+func TestMigrateLegacySession(t *testing.T) {
+	dir := t.TempDir()
+	path := copyFixture(t, "legacy-session.json", dir, "legacy-1.json")
+
+	mtime := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("failed to set mtime: %v", err)
+	}
+
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	session, err := manager.LoadSessionByID("legacy-1")
+	if err != nil {
+		t.Fatalf("failed to load migrated session: %v", err)
+	}
+	if len(session.Messages) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(session.Messages))
+	}
+
+	// Timestamps are spaced evenly between the session's creation time and
+	// the file's mtime, so the last message lands on the mtime.
+	for i, msg := range session.Messages {
+		if msg.Type != TypeMessage {
+			t.Errorf("message %d: expected type %q, got %q", i, TypeMessage, msg.Type)
+		}
+		if msg.CreatedAt.IsZero() {
+			t.Errorf("message %d: expected a synthesized timestamp", i)
+		}
+		if i > 0 && !msg.CreatedAt.After(session.Messages[i-1].CreatedAt) {
+			t.Errorf("message %d: timestamps must be increasing", i)
+		}
+	}
+	if last := session.Messages[3].CreatedAt; !last.Equal(mtime) {
+		t.Errorf("expected last message timestamp %v, got %v", mtime, last)
+	}
+
+	// The original file must survive as a backup
+	backup, err := os.ReadFile(path + legacyBackupSuffix)
+	if err != nil {
+		t.Fatalf("expected a backup of the original file: %v", err)
+	}
+	original, _ := os.ReadFile(filepath.Join("testdata", "legacy-session.json"))
+	if string(backup) != string(original) {
+		t.Error("backup must match the original file byte for byte")
+	}
+}
+
+func TestMigrateSkipsCurrentFormatSessions(t *testing.T) {
+	dir := t.TempDir()
+
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	manager.NewSession()
+	manager.Current().ID = "modern-1"
+	if err := manager.AddMessage(RoleUser, "hello"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+
+	// Re-opening the directory must leave the file untouched
+	before, _ := os.ReadFile(filepath.Join(dir, "modern-1.json"))
+	if _, err := NewManager(dir); err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	after, _ := os.ReadFile(filepath.Join(dir, "modern-1.json"))
+	if string(before) != string(after) {
+		t.Error("current-format session must not be rewritten")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "modern-1.json"+legacyBackupSuffix)); !os.IsNotExist(err) {
+		t.Error("current-format session must not get a backup")
+	}
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	dir := t.TempDir()
+	path := copyFixture(t, "legacy-session.json", dir, "legacy-1.json")
+
+	if _, err := NewManager(dir); err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	migrated, _ := os.ReadFile(path)
+
+	// A second startup must not touch the already-migrated file
+	if _, err := NewManager(dir); err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	again, _ := os.ReadFile(path)
+	if string(migrated) != string(again) {
+		t.Error("migration must not rewrite an already-migrated session")
+	}
+}
+
+func TestMigrateSkipsCorruptedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if _, err := NewManager(dir); err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "not json" {
+		t.Error("corrupted files must be left alone")
+	}
+}
@@ -0,0 +1,194 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/term"
+
+	"gopus/internal/fuzzy"
+	"gopus/internal/printer"
+	"gopus/internal/table"
+)
+
+// pickerMaxRows limits how many filtered sessions the picker shows at once.
+const pickerMaxRows = 10
+
+// matchTarget is the text a session is fuzzy-matched against: its name
+// plus a snippet of the first message, so a session can be found by what
+// it was about even if it was never renamed.
+func matchTarget(s *Session) string {
+	target := s.Name
+	if len(s.Messages) > 0 {
+		snippet := s.Messages[0].Content
+		if len(snippet) > 60 {
+			snippet = snippet[:60]
+		}
+		target += " " + snippet
+	}
+	return target
+}
+
+// filterSessions returns the sessions matching query, best score first.
+// sessions must be sorted most recently updated first; the stable sort
+// then ranks recent sessions higher on equal scores.
+func filterSessions(sessions []*Session, query string) []*Session {
+	type scored struct {
+		session *Session
+		score   int
+	}
+	var matches []scored
+	for _, s := range sessions {
+		if score, ok := fuzzy.Match(query, matchTarget(s)); ok {
+			matches = append(matches, scored{s, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	result := make([]*Session, len(matches))
+	for i, m := range matches {
+		result[i] = m.session
+	}
+	return result
+}
+
+// pickerTable renders sessions for the picker; unlike SessionTable it has
+// no number column, since selection happens with the highlight.
+func pickerTable(sessions []*Session) *table.Table {
+	tbl := table.New(
+		table.Column{Header: "Name", MinWidth: 4, MaxWidth: 40, Align: table.AlignLeft},
+		table.Column{Header: "Msgs", MinWidth: 4, Align: table.AlignRight},
+		table.Column{Header: "Last Updated", Align: table.AlignLeft},
+	)
+	for _, session := range sessions {
+		name := session.Name
+		if name == "" {
+			name = "(unnamed)"
+		}
+		tbl.AddRow(name, fmt.Sprintf("%d", len(session.Messages)), session.UpdatedAt.Format("2006-01-02 15:04"))
+	}
+	return tbl
+}
+
+// renderPicker redraws the picker frame in place and returns how many
+// terminal lines it occupies. prevLines is the previous frame's height;
+// the terminal is in raw mode, so lines end with \r\n.
+func renderPicker(filtered []*Session, query string, highlight, prevLines int) int {
+	var sb strings.Builder
+	sb.WriteString("\r")
+	if prevLines > 0 {
+		fmt.Fprintf(&sb, "\x1b[%dA", prevLines)
+	}
+	sb.WriteString("\x1b[J")
+
+	fmt.Fprintf(&sb, "Filter: %s▌  %s(type to filter, ↑/↓ move, Enter select, Esc new session)%s\r\n",
+		query, printer.ColorDim, printer.ColorReset)
+	lines := 1
+
+	shown := filtered
+	if len(shown) > pickerMaxRows {
+		shown = shown[:pickerMaxRows]
+	}
+	if len(shown) == 0 {
+		sb.WriteString("  (no matching sessions — Enter starts a new one)\r\n")
+		lines++
+	} else {
+		tbl := pickerTable(shown)
+		sb.WriteString("  " + tbl.RenderHeader() + "\r\n")
+		lines++
+		for i := range shown {
+			if i == highlight {
+				fmt.Fprintf(&sb, "%s> %s%s\r\n", printer.ColorCyan, tbl.RenderRow(i), printer.ColorReset)
+			} else {
+				fmt.Fprintf(&sb, "  %s\r\n", tbl.RenderRow(i))
+			}
+			lines++
+		}
+		if len(filtered) > pickerMaxRows {
+			fmt.Fprintf(&sb, "  … %d more match(es) — keep typing to narrow\r\n", len(filtered)-pickerMaxRows)
+			lines++
+		}
+	}
+
+	fmt.Print(sb.String())
+	return lines
+}
+
+// clearPicker removes the picker frame before normal output resumes.
+func clearPicker(lines int) {
+	if lines > 0 {
+		fmt.Printf("\r\x1b[%dA\x1b[J", lines)
+	}
+}
+
+// pickSessionInteractive runs the incremental filter picker. It returns
+// the chosen session, or nil for "start a new session". An error means
+// the terminal cannot be driven interactively (not a TTY, raw mode
+// failed); callers fall back to the numeric menu.
+func pickSessionInteractive(sessions []*Session) (*Session, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("stdin is not a terminal")
+	}
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	defer term.Restore(fd, oldState)
+
+	query := ""
+	highlight := 0
+	renderedLines := 0
+	buf := make([]byte, 8)
+
+	for {
+		filtered := filterSessions(sessions, query)
+		if highlight > len(filtered)-1 {
+			highlight = len(filtered) - 1
+		}
+		if highlight < 0 {
+			highlight = 0
+		}
+		renderedLines = renderPicker(filtered, query, highlight, renderedLines)
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			clearPicker(renderedLines)
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+
+		switch {
+		case n == 1 && (buf[0] == 27 || buf[0] == 3 || buf[0] == 4): // Esc, Ctrl+C, Ctrl+D
+			clearPicker(renderedLines)
+			return nil, nil
+		case n == 1 && buf[0] == '0' && query == "":
+			clearPicker(renderedLines)
+			return nil, nil
+		case n == 1 && (buf[0] == '\r' || buf[0] == '\n'):
+			clearPicker(renderedLines)
+			if len(filtered) == 0 {
+				return nil, nil
+			}
+			return filtered[highlight], nil
+		case n == 1 && (buf[0] == 127 || buf[0] == 8): // Backspace
+			if query != "" {
+				query = query[:len(query)-1]
+			}
+		case n >= 3 && buf[0] == 27 && buf[1] == '[':
+			switch buf[2] {
+			case 'A':
+				if highlight > 0 {
+					highlight--
+				}
+			case 'B':
+				if highlight < len(filtered)-1 {
+					highlight++
+				}
+			}
+		case n == 1 && buf[0] >= 32 && buf[0] < 127:
+			query += string(buf[0])
+		}
+	}
+}
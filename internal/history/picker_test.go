@@ -0,0 +1,57 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func pickerSession(id, name, firstMessage string, updated time.Time) *Session {
+	s := &Session{ID: id, Name: name, UpdatedAt: updated}
+	if firstMessage != "" {
+		s.Messages = []Message{{Role: RoleUser, Content: firstMessage}}
+	}
+	return s
+}
+
+func TestFilterSessionsNarrowsByNameAndSnippet(t *testing.T) {
+	now := time.Now()
+	sessions := []*Session{
+		pickerSession("1", "refactor plan", "let's restructure the parser", now),
+		pickerSession("2", "", "debug the websocket timeout", now.Add(-time.Hour)),
+		pickerSession("3", "groceries", "milk and eggs", now.Add(-2*time.Hour)),
+	}
+
+	filtered := filterSessions(sessions, "websocket")
+	if len(filtered) != 1 || filtered[0].ID != "2" {
+		t.Errorf("expected the snippet match only, got %d results", len(filtered))
+	}
+
+	filtered = filterSessions(sessions, "refactor")
+	if len(filtered) != 1 || filtered[0].ID != "1" {
+		t.Errorf("expected the name match only, got %d results", len(filtered))
+	}
+
+	if filtered = filterSessions(sessions, "zzz"); len(filtered) != 0 {
+		t.Errorf("expected no matches, got %d", len(filtered))
+	}
+}
+
+func TestFilterSessionsPrefersRecentOnEqualScores(t *testing.T) {
+	now := time.Now()
+	// Identical names score identically; order must stay newest first
+	sessions := []*Session{
+		pickerSession("newer", "standup notes", "", now),
+		pickerSession("older", "standup notes", "", now.Add(-24*time.Hour)),
+	}
+
+	filtered := filterSessions(sessions, "standup")
+	if len(filtered) != 2 || filtered[0].ID != "newer" {
+		t.Errorf("expected the newer session ranked first, got %v", filtered)
+	}
+
+	// The empty query keeps every session in recency order
+	filtered = filterSessions(sessions, "")
+	if len(filtered) != 2 || filtered[0].ID != "newer" {
+		t.Errorf("expected all sessions newest first for the empty query, got %v", filtered)
+	}
+}
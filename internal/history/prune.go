@@ -0,0 +1,165 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TrashDirName is the subdirectory of the sessions directory that pruned
+// session files are moved into. ListSessions skips directories, so trashed
+// sessions disappear from every listing; restoring one is a file move back.
+const TrashDirName = "trash"
+
+// RetentionPolicy limits what the sessions directory keeps. Zero values
+// disable the corresponding limit.
+type RetentionPolicy struct {
+	MaxSessions    int // keep at most this many sessions
+	MaxAgeDays     int // prune sessions not updated within this many days
+	MaxTotalSizeMB int // prune oldest sessions until the directory fits
+}
+
+// Enabled reports whether any retention limit is set.
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxSessions > 0 || p.MaxAgeDays > 0 || p.MaxTotalSizeMB > 0
+}
+
+// PruneCandidate names a session that violates the retention policy and
+// records why, so dry runs and prune logs can show the exact reasoning.
+type PruneCandidate struct {
+	ID        string
+	Name      string
+	UpdatedAt time.Time
+	Size      int64
+	Reason    string
+}
+
+// PruneCandidates returns the sessions a prune pass would move to the trash
+// directory, oldest first. The currently open session and pinned sessions
+// are never candidates, even when they violate the policy; they still count
+// toward the session and size limits.
+func (m *Manager) PruneCandidates(policy RetentionPolicy, now time.Time) ([]PruneCandidate, error) {
+	if !policy.Enabled() {
+		return nil, nil
+	}
+
+	// Newest first, like ListSessions sorts them
+	sessions, err := m.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	currentID := ""
+	if cur := m.Current(); cur != nil {
+		currentID = cur.ID
+	}
+
+	type record struct {
+		session *Session
+		size    int64
+		reason  string
+	}
+	records := make([]*record, 0, len(sessions))
+	var totalSize int64
+	for _, s := range sessions {
+		info, err := os.Stat(filepath.Join(m.SessionsDir(), s.ID+".json"))
+		if err != nil {
+			continue
+		}
+		records = append(records, &record{session: s, size: info.Size()})
+		totalSize += info.Size()
+	}
+
+	protected := func(r *record) bool {
+		return r.session.Pinned || r.session.ID == currentID
+	}
+
+	if policy.MaxAgeDays > 0 {
+		cutoff := now.AddDate(0, 0, -policy.MaxAgeDays)
+		for _, r := range records {
+			if r.reason == "" && !protected(r) && r.session.UpdatedAt.Before(cutoff) {
+				r.reason = fmt.Sprintf("not updated since %s (max_age_days: %d)",
+					r.session.UpdatedAt.Format("2006-01-02"), policy.MaxAgeDays)
+			}
+		}
+	}
+
+	if policy.MaxSessions > 0 {
+		kept := 0
+		for _, r := range records {
+			if r.reason != "" {
+				continue
+			}
+			if kept < policy.MaxSessions || protected(r) {
+				kept++
+				continue
+			}
+			r.reason = fmt.Sprintf("over the %d session limit (max_sessions)", policy.MaxSessions)
+		}
+	}
+
+	if policy.MaxTotalSizeMB > 0 {
+		budget := int64(policy.MaxTotalSizeMB) * 1024 * 1024
+		remaining := totalSize
+		for _, r := range records {
+			if r.reason != "" {
+				remaining -= r.size
+			}
+		}
+		// Drop oldest first until the survivors fit the budget
+		for i := len(records) - 1; i >= 0 && remaining > budget; i-- {
+			r := records[i]
+			if r.reason != "" || protected(r) {
+				continue
+			}
+			r.reason = fmt.Sprintf("directory over %d MB (max_total_size_mb)", policy.MaxTotalSizeMB)
+			remaining -= r.size
+		}
+	}
+
+	var candidates []PruneCandidate
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		if r.reason == "" {
+			continue
+		}
+		candidates = append(candidates, PruneCandidate{
+			ID:        r.session.ID,
+			Name:      r.session.Name,
+			UpdatedAt: r.session.UpdatedAt,
+			Size:      r.size,
+			Reason:    r.reason,
+		})
+	}
+	return candidates, nil
+}
+
+// Prune moves every session violating the retention policy into the trash
+// subdirectory and drops it from the index. It returns the sessions it
+// archived, oldest first.
+func (m *Manager) Prune(policy RetentionPolicy, now time.Time) ([]PruneCandidate, error) {
+	candidates, err := m.PruneCandidates(policy, now)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+
+	trashDir := filepath.Join(m.SessionsDir(), TrashDirName)
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create trash directory: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	archived := make([]PruneCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		name := c.ID + ".json"
+		if err := os.Rename(filepath.Join(m.sessionsDir, name), filepath.Join(trashDir, name)); err != nil {
+			return archived, fmt.Errorf("failed to archive session %s: %w", c.ID, err)
+		}
+		m.index.remove(c.ID)
+		archived = append(archived, c)
+	}
+	return archived, nil
+}
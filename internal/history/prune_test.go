@@ -0,0 +1,164 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pruneNow is the fixed reference time retention tests measure ages against.
+var pruneNow = time.Date(2026, time.August, 31, 12, 0, 0, 0, time.UTC)
+
+// writePrunableSession writes a session file directly, bypassing the
+// manager so UpdatedAt stays at the given age. padding inflates the file
+// size for the size-limit tests.
+func writePrunableSession(t *testing.T, dir, id string, ageDays int, padding int, pinned bool) *Session {
+	t.Helper()
+	session := &Session{
+		ID:        id,
+		Name:      "session " + id,
+		Pinned:    pinned,
+		CreatedAt: pruneNow.AddDate(0, 0, -ageDays),
+		UpdatedAt: pruneNow.AddDate(0, 0, -ageDays),
+	}
+	if padding > 0 {
+		session.Messages = []Message{{Role: RoleUser, Content: strings.Repeat("x", padding)}}
+	}
+	if err := saveSession(filepath.Join(dir, id+".json"), session); err != nil {
+		t.Fatalf("saveSession: %v", err)
+	}
+	return session
+}
+
+func candidateIDs(candidates []PruneCandidate) []string {
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestPruneCandidatesMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	writePrunableSession(t, dir, "fresh", 1, 0, false)
+	writePrunableSession(t, dir, "stale", 120, 0, false)
+	writePrunableSession(t, dir, "pinned-stale", 120, 0, true)
+
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	candidates, err := manager.PruneCandidates(RetentionPolicy{MaxAgeDays: 90}, pruneNow)
+	if err != nil {
+		t.Fatalf("PruneCandidates: %v", err)
+	}
+	if ids := candidateIDs(candidates); len(ids) != 1 || ids[0] != "stale" {
+		t.Errorf("expected only the stale unpinned session, got %v", ids)
+	}
+	if !strings.Contains(candidates[0].Reason, "max_age_days") {
+		t.Errorf("expected an age reason, got %q", candidates[0].Reason)
+	}
+}
+
+func TestPruneCandidatesMaxSessions(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 5; i++ {
+		writePrunableSession(t, dir, fmt.Sprintf("s%d", i), i+1, 0, false)
+	}
+
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	candidates, err := manager.PruneCandidates(RetentionPolicy{MaxSessions: 3}, pruneNow)
+	if err != nil {
+		t.Fatalf("PruneCandidates: %v", err)
+	}
+	// Oldest first: s4 then s3 fall past the three newest
+	if ids := candidateIDs(candidates); len(ids) != 2 || ids[0] != "s4" || ids[1] != "s3" {
+		t.Errorf("expected the two oldest sessions, got %v", ids)
+	}
+}
+
+func TestPruneCandidatesMaxTotalSize(t *testing.T) {
+	dir := t.TempDir()
+	// Each session file is padded past 1 MB, so a 2 MB budget keeps
+	// at most one of them
+	writePrunableSession(t, dir, "new", 1, 1<<20, false)
+	writePrunableSession(t, dir, "old", 10, 1<<20, false)
+
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	candidates, err := manager.PruneCandidates(RetentionPolicy{MaxTotalSizeMB: 2}, pruneNow)
+	if err != nil {
+		t.Fatalf("PruneCandidates: %v", err)
+	}
+	if ids := candidateIDs(candidates); len(ids) != 1 || ids[0] != "old" {
+		t.Errorf("expected the oldest session to be dropped first, got %v", ids)
+	}
+}
+
+func TestPruneCandidatesSkipsCurrentSession(t *testing.T) {
+	dir := t.TempDir()
+	stale := writePrunableSession(t, dir, "stale-current", 120, 0, false)
+
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	loaded, err := manager.LoadSessionByID(stale.ID)
+	if err != nil {
+		t.Fatalf("LoadSessionByID: %v", err)
+	}
+	manager.SetCurrent(loaded)
+
+	candidates, err := manager.PruneCandidates(RetentionPolicy{MaxAgeDays: 90}, pruneNow)
+	if err != nil {
+		t.Fatalf("PruneCandidates: %v", err)
+	}
+	if len(candidates) != 0 {
+		t.Errorf("expected the open session to be protected, got %v", candidateIDs(candidates))
+	}
+}
+
+func TestPruneMovesFilesToTrash(t *testing.T) {
+	dir := t.TempDir()
+	writePrunableSession(t, dir, "fresh", 1, 0, false)
+	writePrunableSession(t, dir, "stale", 120, 0, false)
+
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	archived, err := manager.Prune(RetentionPolicy{MaxAgeDays: 90}, pruneNow)
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != "stale" {
+		t.Fatalf("expected the stale session archived, got %v", candidateIDs(archived))
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "stale.json")); !os.IsNotExist(err) {
+		t.Error("expected the stale session file to be gone from the sessions directory")
+	}
+	if _, err := os.Stat(filepath.Join(dir, TrashDirName, "stale.json")); err != nil {
+		t.Errorf("expected the stale session file in the trash directory: %v", err)
+	}
+
+	sessions, err := manager.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "fresh" {
+		t.Errorf("expected only the fresh session to remain, got %d sessions", len(sessions))
+	}
+}
@@ -0,0 +1,114 @@
+package history
+
+import (
+	"fmt"
+	"time"
+
+	"gopus/internal/printer"
+)
+
+// printQueuedMessages shows a session's unsent offline queue in dim
+// colors, so a resumed session reminds the user what /flush will send.
+func printQueuedMessages(session *Session) {
+	for _, entry := range session.Queue {
+		if entry.Sent {
+			continue
+		}
+		printer.PrintMessageAt(string(RoleUser)+" [queued]", entry.Content, true, entry.QueuedAt)
+	}
+}
+
+// QueuedMessage is a user message composed while the API was unreachable,
+// waiting in the session's offline queue for /flush. Sent is persisted
+// before the entry goes out, so a restart mid-flush cannot double-send it.
+type QueuedMessage struct {
+	Content  string    `json:"content"`
+	QueuedAt time.Time `json:"queued_at"`
+	Sent     bool      `json:"sent,omitempty"`
+}
+
+// Enqueue appends a message to the current session's offline queue.
+func (m *Manager) Enqueue(content string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("no current session")
+	}
+
+	m.current.Queue = append(m.current.Queue, QueuedMessage{
+		Content:  content,
+		QueuedAt: time.Now(),
+	})
+	return m.scheduleSaveLocked()
+}
+
+// QueuedMessages returns a copy of the current session's offline queue.
+func (m *Manager) QueuedMessages() []QueuedMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return nil
+	}
+	return append([]QueuedMessage{}, m.current.Queue...)
+}
+
+// PendingQueued returns how many queued messages are still unsent.
+func (m *Manager) PendingQueued() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range m.current.Queue {
+		if !entry.Sent {
+			count++
+		}
+	}
+	return count
+}
+
+// MarkQueued sets one queue entry's sent flag and writes the session
+// immediately, so the flag is durable before the request goes out.
+func (m *Manager) MarkQueued(index int, sent bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("no current session")
+	}
+	if index < 0 || index >= len(m.current.Queue) {
+		return fmt.Errorf("no queued message %d", index+1)
+	}
+
+	m.current.Queue[index].Sent = sent
+	return m.saveLocked(m.current)
+}
+
+// CompactQueue drops the sent entries from the current session's queue.
+func (m *Manager) CompactQueue() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("no current session")
+	}
+
+	remaining := m.current.Queue[:0]
+	for _, entry := range m.current.Queue {
+		if !entry.Sent {
+			remaining = append(remaining, entry)
+		}
+	}
+	if len(remaining) == len(m.current.Queue) {
+		return nil
+	}
+	if len(remaining) == 0 {
+		remaining = nil
+	}
+	m.current.Queue = remaining
+	return m.scheduleSaveLocked()
+}
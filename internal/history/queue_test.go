@@ -0,0 +1,54 @@
+package history
+
+import "testing"
+
+func TestQueueLifecycle(t *testing.T) {
+	manager, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	session := manager.NewSession()
+
+	if err := manager.Enqueue("first"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := manager.Enqueue("second"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if manager.PendingQueued() != 2 {
+		t.Fatalf("expected 2 pending, got %d", manager.PendingQueued())
+	}
+
+	queue := manager.QueuedMessages()
+	if len(queue) != 2 || queue[0].Content != "first" || queue[1].Content != "second" {
+		t.Fatalf("unexpected queue order: %+v", queue)
+	}
+
+	// The sent flag is written through to disk immediately, so a restart
+	// mid-flush sees it
+	if err := manager.MarkQueued(0, true); err != nil {
+		t.Fatalf("MarkQueued failed: %v", err)
+	}
+	if manager.PendingQueued() != 1 {
+		t.Errorf("expected 1 pending after marking, got %d", manager.PendingQueued())
+	}
+	loaded, err := manager.LoadSessionByID(session.ID)
+	if err != nil {
+		t.Fatalf("failed to load session: %v", err)
+	}
+	if len(loaded.Queue) != 2 || !loaded.Queue[0].Sent || loaded.Queue[1].Sent {
+		t.Errorf("sent flag not persisted: %+v", loaded.Queue)
+	}
+
+	if err := manager.CompactQueue(); err != nil {
+		t.Fatalf("CompactQueue failed: %v", err)
+	}
+	queue = manager.QueuedMessages()
+	if len(queue) != 1 || queue[0].Content != "second" {
+		t.Errorf("expected only the unsent entry to survive, got %+v", queue)
+	}
+
+	if err := manager.MarkQueued(5, true); err == nil {
+		t.Error("expected an error for an out-of-range index")
+	}
+}
@@ -0,0 +1,67 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestDirtyTracksDebouncedSaves(t *testing.T) {
+	manager, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	manager.NewSession()
+
+	if manager.Dirty() {
+		t.Error("a fresh session must not start dirty")
+	}
+
+	// The first save in a burst is written through; the second lands in
+	// the debounce window and leaves the manager dirty
+	if err := manager.AddMessage(RoleUser, "one"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+	if err := manager.AddMessage(RoleUser, "two"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+	if !manager.Dirty() {
+		t.Error("expected unsaved changes after a rapid second message")
+	}
+
+	if err := manager.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if manager.Dirty() {
+		t.Error("expected a flush to clear the dirty flag")
+	}
+}
+
+func TestDumpRecovery(t *testing.T) {
+	manager, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	session := manager.NewSession()
+	if err := manager.AddMessage(RoleUser, "precious"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	path, err := manager.DumpRecovery(dir)
+	if err != nil {
+		t.Fatalf("DumpRecovery failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read recovery file: %v", err)
+	}
+	var recovered Session
+	if err := json.Unmarshal(data, &recovered); err != nil {
+		t.Fatalf("recovery file is not valid session JSON: %v", err)
+	}
+	if recovered.ID != session.ID || len(recovered.Messages) != 1 {
+		t.Errorf("unexpected recovered session: %+v", recovered)
+	}
+}
@@ -0,0 +1,88 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// renameSessionFile is os.Rename, swappable in tests to simulate a
+// cross-device move where rename fails with EXDEV.
+var renameSessionFile = os.Rename
+
+// Relocate moves one session's file into newDir, e.g. after the configured
+// sessions directory changed on a reload. It prefers an atomic rename and
+// falls back to copy+fsync+remove when the directories are on different
+// filesystems. The manager itself keeps pointing at its current directory;
+// pair with SetSessionsDir to switch.
+func (m *Manager) Relocate(sessionID, newDir string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(newDir, 0755); err != nil {
+		return fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+
+	oldPath := filepath.Join(m.sessionsDir, sessionID+".json")
+	newPath := filepath.Join(newDir, sessionID+".json")
+	if oldPath == newPath {
+		return nil
+	}
+
+	if err := renameSessionFile(oldPath, newPath); err != nil {
+		// Rename cannot cross filesystems; copy first, remove the
+		// original only once the copy is safely on disk
+		if copyErr := copySessionFile(oldPath, newPath); copyErr != nil {
+			return fmt.Errorf("failed to move session file: %w", copyErr)
+		}
+		if rmErr := os.Remove(oldPath); rmErr != nil {
+			return fmt.Errorf("failed to remove old session file: %w", rmErr)
+		}
+	}
+
+	m.index.remove(sessionID)
+	return nil
+}
+
+// SetSessionsDir points the manager at a different sessions directory. The
+// current session is unaffected; it saves into the new directory from now on.
+func (m *Manager) SetSessionsDir(dir string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+
+	// A pending save still belongs in the old directory; the next save
+	// should reach the new one immediately rather than wait out the window
+	_ = m.flushSessionLocked()
+	m.sessionsDir = dir
+	m.index = newSessionIndex(dir)
+	m.lastWrite = time.Time{}
+	return nil
+}
+
+// copySessionFile copies src to dst and fsyncs the copy, so the original can
+// be removed without a window where a crash loses the session.
+func copySessionFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create session file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync session file: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,99 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// relocateTestManager returns a manager with one saved session.
+func relocateTestManager(t *testing.T) (*Manager, *Session) {
+	t.Helper()
+	manager, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	session := manager.NewSession()
+	if err := manager.AddMessage(RoleUser, "hello"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+	return manager, session
+}
+
+func TestRelocateSameDevice(t *testing.T) {
+	manager, session := relocateTestManager(t)
+	oldPath := filepath.Join(manager.SessionsDir(), session.ID+".json")
+	newDir := t.TempDir()
+
+	if err := manager.Relocate(session.ID, newDir); err != nil {
+		t.Fatalf("Relocate failed: %v", err)
+	}
+
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Error("expected old session file removed")
+	}
+	moved, err := loadSession(filepath.Join(newDir, session.ID+".json"))
+	if err != nil {
+		t.Fatalf("expected session file in new directory: %v", err)
+	}
+	if moved.ID != session.ID || len(moved.Messages) != 1 {
+		t.Errorf("moved session does not match the original: %+v", moved)
+	}
+}
+
+func TestRelocateCrossDevice(t *testing.T) {
+	// Simulate directories on different filesystems by making rename fail,
+	// forcing the copy+fsync+remove fallback
+	original := renameSessionFile
+	renameSessionFile = func(oldpath, newpath string) error {
+		return fmt.Errorf("rename %s %s: invalid cross-device link", oldpath, newpath)
+	}
+	defer func() { renameSessionFile = original }()
+
+	manager, session := relocateTestManager(t)
+	oldPath := filepath.Join(manager.SessionsDir(), session.ID+".json")
+	before, _ := os.ReadFile(oldPath)
+	newDir := t.TempDir()
+
+	if err := manager.Relocate(session.ID, newDir); err != nil {
+		t.Fatalf("Relocate failed: %v", err)
+	}
+
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Error("expected old session file removed")
+	}
+	after, err := os.ReadFile(filepath.Join(newDir, session.ID+".json"))
+	if err != nil {
+		t.Fatalf("expected session file in new directory: %v", err)
+	}
+	if string(before) != string(after) {
+		t.Error("copied session file must match the original byte for byte")
+	}
+}
+
+func TestRelocateMissingSessionFails(t *testing.T) {
+	manager, _ := relocateTestManager(t)
+	if err := manager.Relocate("no-such-session", t.TempDir()); err == nil {
+		t.Error("expected an error relocating a nonexistent session")
+	}
+}
+
+func TestSetSessionsDirSavesIntoNewDirectory(t *testing.T) {
+	manager, session := relocateTestManager(t)
+	newDir := filepath.Join(t.TempDir(), "sessions")
+
+	if err := manager.SetSessionsDir(newDir); err != nil {
+		t.Fatalf("SetSessionsDir failed: %v", err)
+	}
+	if got := manager.SessionsDir(); got != newDir {
+		t.Errorf("expected sessions dir %q, got %q", newDir, got)
+	}
+
+	if err := manager.AddMessage(RoleAssistant, "hi"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(newDir, session.ID+".json")); err != nil {
+		t.Errorf("expected current session saved into the new directory: %v", err)
+	}
+}
@@ -7,12 +7,13 @@ import (
 	"strconv"
 	"strings"
 
+	"gopus/internal/i18n"
 	"gopus/internal/printer"
 	"gopus/internal/table"
 )
 
-// buildSessionTable creates a table displaying the given sessions.
-func buildSessionTable(sessions []*Session) *table.Table {
+// SessionTable creates a table displaying the given sessions.
+func SessionTable(sessions []*Session) *table.Table {
 	tbl := table.New(
 		table.Column{Header: "#", MinWidth: 3, Align: table.AlignLeft},
 		table.Column{Header: "Name", MinWidth: 4, MaxWidth: 40, Align: table.AlignLeft},
@@ -33,7 +34,9 @@ func buildSessionTable(sessions []*Session) *table.Table {
 	return tbl
 }
 
-// SelectSession displays available sessions and lets the user choose one or create a new one.
+// SelectSession lets the user choose an existing session or create a new
+// one. On a terminal it runs the incremental fuzzy picker; when stdin is
+// not a TTY or raw mode is unavailable it falls back to the numeric menu.
 func SelectSession(manager *Manager, scanner *bufio.Scanner) error {
 	sessions, err := manager.ListSessions()
 	if err != nil {
@@ -46,10 +49,34 @@ func SelectSession(manager *Manager, scanner *bufio.Scanner) error {
 		return nil
 	}
 
+	selected, err := pickSessionInteractive(sessions)
+	if err != nil {
+		return selectSessionNumeric(manager, scanner, sessions)
+	}
+	if selected == nil {
+		fmt.Println("Starting a new session.")
+		manager.NewSession()
+		return nil
+	}
+
+	manager.SetCurrent(selected)
+	fmt.Println(i18n.T(i18n.PickerContinuing, selected.Name))
+
+	// Display loaded messages in dim colors to distinguish from new messages
+	for _, msg := range selected.Messages {
+		printer.PrintMessageAt(msg.RoleLabel(), msg.Content, true, msg.CreatedAt)
+	}
+	printQueuedMessages(selected)
+	return nil
+}
+
+// selectSessionNumeric is the non-interactive fallback: a numbered table
+// and a typed selection, with d# to delete.
+func selectSessionNumeric(manager *Manager, scanner *bufio.Scanner, sessions []*Session) error {
 	// Print table with highlighted first column (row numbers in yellow)
 	opts := table.DefaultPrintOptions()
 	opts.HighlightColumn = 0
-	buildSessionTable(sessions).Print(opts)
+	SessionTable(sessions).Print(opts)
 
 	// Determine default selection based on number of sessions
 	// If there are saved sessions, default to the most recent one (1)
@@ -60,7 +87,7 @@ func SelectSession(manager *Manager, scanner *bufio.Scanner) error {
 	}
 
 	for {
-		fmt.Printf("Select a session (0 for new, d# to delete, or number) [%s]: ", defaultSelection)
+		fmt.Print(i18n.T(i18n.PickerSelect, defaultSelection))
 		if !scanner.Scan() {
 			return fmt.Errorf("failed to read input")
 		}
@@ -77,13 +104,13 @@ func SelectSession(manager *Manager, scanner *bufio.Scanner) error {
 			numStr := after
 			num, err := strconv.Atoi(numStr)
 			if err != nil || num < 1 || num > len(sessions) {
-				fmt.Printf("Please enter d followed by a number between 1 and %d.\n", len(sessions))
+				fmt.Println(i18n.T(i18n.PickerInvalidDelete, len(sessions)))
 				continue
 			}
 
 			sessionToDelete := sessions[num-1]
 			// Confirm deletion
-			fmt.Printf("Delete session '%s'? (y/N): ", sessionToDelete.Name)
+			fmt.Print(i18n.T(i18n.PickerDeleteConfirm, sessionToDelete.Name))
 			if !scanner.Scan() {
 				return fmt.Errorf("failed to read input")
 			}
@@ -101,7 +128,7 @@ func SelectSession(manager *Manager, scanner *bufio.Scanner) error {
 						return nil
 					}
 					// Reprint the table
-					buildSessionTable(sessions).Print(opts)
+					SessionTable(sessions).Print(opts)
 				}
 			}
 			continue
@@ -130,8 +157,9 @@ func SelectSession(manager *Manager, scanner *bufio.Scanner) error {
 
 		// Display loaded messages in dim colors to distinguish from new messages
 		for _, msg := range selectedSession.Messages {
-			printer.PrintMessage(string(msg.Role), msg.Content, true)
+			printer.PrintMessageAt(msg.RoleLabel(), msg.Content, true, msg.CreatedAt)
 		}
+		printQueuedMessages(selectedSession)
 
 		return nil
 	}
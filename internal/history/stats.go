@@ -0,0 +1,286 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopus/internal/canvas"
+	"gopus/internal/table"
+)
+
+// dayFormat keys per-day message counts by calendar day in local time.
+const dayFormat = "2006-01-02"
+
+// SessionStats aggregates one session's figures for the stats dashboard.
+// It is stored in the sessions index, so `/stats --all` and `gopus stats`
+// can cover the whole directory without parsing message bodies.
+type SessionStats struct {
+	MessagesByRole   map[string]int `json:"messages_by_role,omitempty"`
+	MessagesByDay    map[string]int `json:"messages_by_day,omitempty"` // "2006-01-02" -> messages
+	ToolCalls        map[string]int `json:"tool_calls,omitempty"`      // tool name -> calls
+	PromptTokens     int            `json:"prompt_tokens,omitempty"`
+	CompletionTokens int            `json:"completion_tokens,omitempty"`
+	SpentUSD         float64        `json:"spent_usd,omitempty"`
+}
+
+// sessionStatsOf computes a session's aggregate figures from its messages.
+func sessionStatsOf(session *Session) *SessionStats {
+	stats := &SessionStats{
+		MessagesByRole: make(map[string]int),
+		MessagesByDay:  make(map[string]int),
+		ToolCalls:      make(map[string]int),
+		SpentUSD:       session.SpentUSD,
+	}
+	for _, msg := range session.Messages {
+		if !msg.IsMessage() {
+			continue
+		}
+		stats.MessagesByRole[string(msg.Role)]++
+		if !msg.CreatedAt.IsZero() {
+			stats.MessagesByDay[msg.CreatedAt.Local().Format(dayFormat)]++
+		}
+		for _, call := range msg.ToolCalls {
+			stats.ToolCalls[call.Name]++
+		}
+		if msg.Meta != nil {
+			stats.PromptTokens += msg.Meta.PromptTokens
+			stats.CompletionTokens += msg.Meta.CompletionTokens
+		}
+	}
+	return stats
+}
+
+// DirectoryStats aggregates every session in the sessions directory.
+type DirectoryStats struct {
+	Sessions         int
+	SkippedFiles     int // session files that could not be parsed
+	Messages         int
+	MessagesByRole   map[string]int
+	MessagesByDay    map[string]int
+	ToolCalls        map[string]int
+	PromptTokens     int
+	CompletionTokens int
+	SpentUSD         float64
+}
+
+// add folds one session's figures into the directory totals.
+func (d *DirectoryStats) add(entry IndexEntry) {
+	d.Sessions++
+	d.Messages += entry.MessageCount
+	if entry.Stats == nil {
+		return
+	}
+	for role, n := range entry.Stats.MessagesByRole {
+		d.MessagesByRole[role] += n
+	}
+	for day, n := range entry.Stats.MessagesByDay {
+		d.MessagesByDay[day] += n
+	}
+	for tool, n := range entry.Stats.ToolCalls {
+		d.ToolCalls[tool] += n
+	}
+	d.PromptTokens += entry.Stats.PromptTokens
+	d.CompletionTokens += entry.Stats.CompletionTokens
+	d.SpentUSD += entry.Stats.SpentUSD
+}
+
+// DirectoryStats walks the sessions directory and aggregates usage figures
+// across every session. Sessions already described by the index are read
+// from it; only files the index does not cover are parsed, and what they
+// yield is folded back into the index, so repeated runs stay fast however
+// many sessions there are. Unparsable files are skipped and counted.
+func (m *Manager) DirectoryStats() (DirectoryStats, error) {
+	stats := DirectoryStats{
+		MessagesByRole: make(map[string]int),
+		MessagesByDay:  make(map[string]int),
+		ToolCalls:      make(map[string]int),
+	}
+
+	dir := m.SessionsDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stats, nil
+		}
+		return stats, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+
+	m.BeginBatch()
+	defer m.CommitBatch()
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (!strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".json.gz")) {
+			continue
+		}
+		// The sessions directory also holds the index and the spend ledger
+		if name == indexFileName || name == "spend-ledger.json" {
+			continue
+		}
+
+		id := strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".json")
+		m.index.mu.Lock()
+		indexed, ok := m.index.entries[id]
+		m.index.mu.Unlock()
+		if ok && indexed.Stats != nil {
+			stats.add(indexed)
+			continue
+		}
+
+		session, err := loadSession(filepath.Join(dir, name))
+		if err != nil {
+			stats.SkippedFiles++
+			continue
+		}
+		m.index.update(session)
+		stats.add(indexEntryOf(session))
+	}
+
+	return stats, nil
+}
+
+// DayCount is one day's message count, used for the busiest-days ranking.
+type DayCount struct {
+	Day      string
+	Messages int
+}
+
+// BusiestDays returns the n days with the most messages, busiest first;
+// ties go to the more recent day.
+func (d DirectoryStats) BusiestDays(n int) []DayCount {
+	days := make([]DayCount, 0, len(d.MessagesByDay))
+	for day, count := range d.MessagesByDay {
+		days = append(days, DayCount{Day: day, Messages: count})
+	}
+	sort.Slice(days, func(i, j int) bool {
+		if days[i].Messages != days[j].Messages {
+			return days[i].Messages > days[j].Messages
+		}
+		return days[i].Day > days[j].Day
+	})
+	if len(days) > n {
+		days = days[:n]
+	}
+	return days
+}
+
+// MessagesPerDayChart renders messages per day over the trailing days as a
+// braille bar chart, newest day rightmost. It returns "" when the window
+// has no messages at all.
+func MessagesPerDayChart(stats DirectoryStats, days int, now time.Time) string {
+	counts := make([]int, days)
+	max := 0
+	for i := range counts {
+		day := now.AddDate(0, 0, i-(days-1)).Format(dayFormat)
+		counts[i] = stats.MessagesByDay[day]
+		if counts[i] > max {
+			max = counts[i]
+		}
+	}
+	if max == 0 {
+		return ""
+	}
+
+	const (
+		barWidth    = 2  // pixels per day: one braille cell column pair
+		chartHeight = 16 // pixels: four braille rows
+	)
+	c := canvas.New(days*barWidth, chartHeight)
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		height := count * chartHeight / max
+		if height == 0 {
+			height = 1 // a day with any messages gets a visible bar
+		}
+		x := i * barWidth
+		c.FillRect(x, chartHeight-height, x+barWidth-1, chartHeight-1)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Messages per day, last %d days (peak %d):\n", days, max)
+	b.WriteString(c.String())
+	return b.String()
+}
+
+// RenderDirectoryStats renders the directory-wide dashboard: an overview
+// table, per-role and per-tool breakdowns, busiest days, and the
+// messages-per-day chart.
+func RenderDirectoryStats(stats DirectoryStats, now time.Time) string {
+	var b strings.Builder
+
+	overview := table.New(
+		table.Column{Header: "Metric", MinWidth: 8, Align: table.AlignLeft},
+		table.Column{Header: "Value", MinWidth: 5, Align: table.AlignRight},
+	)
+	overview.AddRow("Sessions", fmt.Sprintf("%d", stats.Sessions))
+	overview.AddRow("Messages", fmt.Sprintf("%d", stats.Messages))
+	if stats.Sessions > 0 {
+		overview.AddRow("Avg messages/session", fmt.Sprintf("%.1f", float64(stats.Messages)/float64(stats.Sessions)))
+	}
+	if stats.PromptTokens > 0 || stats.CompletionTokens > 0 {
+		overview.AddRow("Prompt tokens", fmt.Sprintf("%d", stats.PromptTokens))
+		overview.AddRow("Completion tokens", fmt.Sprintf("%d", stats.CompletionTokens))
+	}
+	if stats.SpentUSD > 0 {
+		overview.AddRow("Estimated spend", fmt.Sprintf("$%.4f", stats.SpentUSD))
+	}
+	if stats.SkippedFiles > 0 {
+		overview.AddRow("Skipped files", fmt.Sprintf("%d", stats.SkippedFiles))
+	}
+	b.WriteString(overview.Render())
+	b.WriteString("\n")
+
+	if len(stats.MessagesByRole) > 0 {
+		roles := make([]string, 0, len(stats.MessagesByRole))
+		for role := range stats.MessagesByRole {
+			roles = append(roles, role)
+		}
+		sort.Strings(roles)
+		tbl := table.New(
+			table.Column{Header: "Role", MinWidth: 4, Align: table.AlignLeft},
+			table.Column{Header: "Messages", MinWidth: 8, Align: table.AlignRight},
+		)
+		for _, role := range roles {
+			tbl.AddRow(role, fmt.Sprintf("%d", stats.MessagesByRole[role]))
+		}
+		b.WriteString("\n")
+		b.WriteString(tbl.Render())
+		b.WriteString("\n")
+	}
+
+	if len(stats.ToolCalls) > 0 {
+		tbl := table.New(
+			table.Column{Header: "Tool", MinWidth: 4, MaxWidth: 40, Align: table.AlignLeft},
+			table.Column{Header: "Calls", MinWidth: 5, Align: table.AlignRight},
+		)
+		for tool, n := range stats.ToolCalls {
+			tbl.AddRow(tool, fmt.Sprintf("%d", n))
+		}
+		tbl.SortBy(1, false, true)
+		b.WriteString("\n")
+		b.WriteString(tbl.Render())
+		b.WriteString("\n")
+	}
+
+	if busiest := stats.BusiestDays(3); len(busiest) > 0 {
+		parts := make([]string, 0, len(busiest))
+		for _, day := range busiest {
+			parts = append(parts, fmt.Sprintf("%s (%d)", day.Day, day.Messages))
+		}
+		fmt.Fprintf(&b, "\nBusiest days: %s\n", strings.Join(parts, ", "))
+	}
+
+	if chart := MessagesPerDayChart(stats, 30, now); chart != "" {
+		b.WriteString("\n")
+		b.WriteString(chart)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func statsSession(id string, day time.Time) *Session {
+	return &Session{
+		ID:        id,
+		Name:      id,
+		CreatedAt: day,
+		UpdatedAt: day,
+		SpentUSD:  0.10,
+		Messages: []Message{
+			{Role: RoleUser, Content: "hello", CreatedAt: day},
+			{
+				Role:      RoleAssistant,
+				Content:   "hi there",
+				CreatedAt: day.Add(2 * time.Second),
+				ToolCalls: []ToolCall{{ID: "1", Name: "run_command", Arguments: "{}"}},
+				Meta:      &Meta{PromptTokens: 100, CompletionTokens: 20},
+			},
+			{Role: RoleUser, Content: "a private note", Type: TypeNote, CreatedAt: day},
+		},
+	}
+}
+
+func TestDirectoryStats(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	today := time.Now()
+	yesterday := today.AddDate(0, 0, -1)
+	if err := manager.Save(statsSession("stats-a", today)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := manager.Save(statsSession("stats-b", yesterday)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	stats, err := manager.DirectoryStats()
+	if err != nil {
+		t.Fatalf("DirectoryStats: %v", err)
+	}
+	if stats.Sessions != 2 {
+		t.Errorf("expected 2 sessions, got %d", stats.Sessions)
+	}
+	if stats.Messages != 6 {
+		t.Errorf("expected 6 messages in total, got %d", stats.Messages)
+	}
+	// Notes are not conversation messages and stay out of the role counts
+	if stats.MessagesByRole["user"] != 2 || stats.MessagesByRole["assistant"] != 2 {
+		t.Errorf("unexpected role counts: %v", stats.MessagesByRole)
+	}
+	if stats.ToolCalls["run_command"] != 2 {
+		t.Errorf("expected 2 run_command calls, got %v", stats.ToolCalls)
+	}
+	if stats.PromptTokens != 200 || stats.CompletionTokens != 40 {
+		t.Errorf("unexpected token totals: %d/%d", stats.PromptTokens, stats.CompletionTokens)
+	}
+	if stats.SpentUSD != 0.20 {
+		t.Errorf("expected $0.20 spent, got %v", stats.SpentUSD)
+	}
+	if stats.MessagesByDay[today.Format(dayFormat)] != 2 {
+		t.Errorf("unexpected per-day counts: %v", stats.MessagesByDay)
+	}
+
+	busiest := stats.BusiestDays(1)
+	if len(busiest) != 1 || busiest[0].Messages != 2 {
+		t.Errorf("unexpected busiest days: %v", busiest)
+	}
+}
+
+func TestDirectoryStatsSkipsCorruptedFiles(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if err := manager.Save(statsSession("stats-ok", time.Now())); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("not json"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	stats, err := manager.DirectoryStats()
+	if err != nil {
+		t.Fatalf("DirectoryStats: %v", err)
+	}
+	if stats.Sessions != 1 {
+		t.Errorf("expected 1 session, got %d", stats.Sessions)
+	}
+	if stats.SkippedFiles != 1 {
+		t.Errorf("expected 1 skipped file, got %d", stats.SkippedFiles)
+	}
+}
+
+func TestDirectoryStatsUsesIndexForUnloadedSessions(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if err := manager.Save(statsSession("stats-idx", time.Now())); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := manager.DirectoryStats(); err != nil {
+		t.Fatalf("DirectoryStats: %v", err)
+	}
+	if err := manager.FlushIndex(); err != nil {
+		t.Fatalf("FlushIndex: %v", err)
+	}
+
+	// A fresh manager sees the aggregates through the index alone, even
+	// when the session file itself has become unreadable
+	if err := os.WriteFile(filepath.Join(dir, "stats-idx.json"), []byte("not json"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	other, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	stats, err := other.DirectoryStats()
+	if err != nil {
+		t.Fatalf("DirectoryStats: %v", err)
+	}
+	if stats.Sessions != 1 || stats.SkippedFiles != 0 {
+		t.Errorf("expected the index to cover the session, got %+v", stats)
+	}
+	if stats.ToolCalls["run_command"] != 1 {
+		t.Errorf("expected indexed tool counts, got %v", stats.ToolCalls)
+	}
+}
+
+func TestMessagesPerDayChart(t *testing.T) {
+	stats := DirectoryStats{MessagesByDay: map[string]int{}}
+	now := time.Now()
+	if chart := MessagesPerDayChart(stats, 30, now); chart != "" {
+		t.Errorf("expected no chart without messages, got %q", chart)
+	}
+
+	stats.MessagesByDay[now.Format(dayFormat)] = 12
+	stats.MessagesByDay[now.AddDate(0, 0, -3).Format(dayFormat)] = 4
+	stats.MessagesByDay[now.AddDate(0, 0, -60).Format(dayFormat)] = 99 // outside the window
+
+	chart := MessagesPerDayChart(stats, 30, now)
+	if !strings.Contains(chart, "peak 12") {
+		t.Errorf("expected the in-window peak in the title, got %q", chart)
+	}
+	if lines := strings.Split(chart, "\n"); len(lines) != 5 {
+		t.Errorf("expected a title and four braille rows, got %d lines", len(lines))
+	}
+}
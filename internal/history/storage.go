@@ -2,18 +2,38 @@
 package history
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 )
 
-// loadSession loads a session from a JSON file.
+// loadSession loads a session from a JSON file, transparently
+// decompressing gzip-compacted files and decrypting encrypted ones, so
+// sessions in every on-disk form keep working side by side.
 func loadSession(path string) (*Session, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read session file: %w", err)
 	}
 
+	if isGzipped(data) {
+		data, err = gunzip(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress session file: %w", err)
+		}
+	}
+
+	if isEncrypted(data) {
+		data, err = decryptSession(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var session Session
 	if err := json.Unmarshal(data, &session); err != nil {
 		return nil, fmt.Errorf("failed to parse session file: %w", err)
@@ -22,16 +42,55 @@ func loadSession(path string) (*Session, error) {
 	return &session, nil
 }
 
-// saveSession saves a session to a JSON file.
+// saveSession saves a session to a JSON file, encrypted when at-rest
+// encryption is enabled.
 func saveSession(path string, session *Session) error {
 	data, err := json.MarshalIndent(session, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to serialize session: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	mode := os.FileMode(0644)
+	if EncryptionEnabled() {
+		if data, err = encryptSession(data); err != nil {
+			return err
+		}
+		mode = 0600
+	}
+
+	if err := os.WriteFile(path, data, mode); err != nil {
 		return fmt.Errorf("failed to write session file: %w", err)
 	}
 
 	return nil
 }
+
+// isGzipped reports whether data starts with the gzip magic bytes.
+func isGzipped(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// gunzip decompresses gzip data in memory.
+func gunzip(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// findSessionFile returns the session file for id, preferring the plain
+// JSON form over the gzip-compacted one. When neither exists, the plain
+// path is returned so callers report a sensible not-found error.
+func findSessionFile(dir, id string) string {
+	plain := filepath.Join(dir, id+".json")
+	if _, err := os.Stat(plain); err == nil {
+		return plain
+	}
+	gz := plain + ".gz"
+	if _, err := os.Stat(gz); err == nil {
+		return gz
+	}
+	return plain
+}
@@ -0,0 +1,163 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template describes a reusable session setup: a system prompt, optional
+// seed messages, and optional generation overrides. Templates live as YAML
+// or JSON files in the directory configured under history.templates_dir.
+type Template struct {
+	// Name identifies the template; defaults to the filename without its
+	// extension when the file does not set one.
+	Name string `yaml:"name" json:"name"`
+
+	// SystemPrompt becomes the new session's system prompt override.
+	SystemPrompt string `yaml:"system_prompt" json:"system_prompt"`
+
+	// Model and Temperature, when set, override the configured defaults
+	// for sessions created from this template.
+	Model       string   `yaml:"model" json:"model"`
+	Temperature *float64 `yaml:"temperature" json:"temperature"`
+
+	// Messages pre-populate the session, e.g. a worked example exchange.
+	Messages []TemplateMessage `yaml:"messages" json:"messages"`
+}
+
+// TemplateMessage is one seed message of a template.
+type TemplateMessage struct {
+	Role    string `yaml:"role" json:"role"`
+	Content string `yaml:"content" json:"content"`
+}
+
+// templateExtensions are the file extensions scanned for templates.
+var templateExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// LoadTemplates reads every template file in dir, sorted by name. Malformed
+// files are skipped and reported in the returned warnings — one bad file
+// must not take the whole directory down. A missing or empty dir yields no
+// templates and no warnings.
+func LoadTemplates(dir string) ([]Template, []string) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []string{fmt.Sprintf("failed to read templates directory: %v", err)}
+	}
+
+	var templates []Template
+	var warnings []string
+	for _, entry := range entries {
+		if entry.IsDir() || !templateExtensions[filepath.Ext(entry.Name())] {
+			continue
+		}
+		tmpl, err := loadTemplateFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipping template %s: %v", entry.Name(), err))
+			continue
+		}
+		templates = append(templates, tmpl)
+	}
+
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, warnings
+}
+
+// loadTemplateFile parses and validates one template file. YAML is a
+// superset of JSON here, so both formats go through the same parser.
+func loadTemplateFile(path string) (Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Template{}, err
+	}
+
+	var tmpl Template
+	if err := yaml.Unmarshal(data, &tmpl); err != nil {
+		return Template{}, err
+	}
+
+	if tmpl.Name == "" {
+		base := filepath.Base(path)
+		tmpl.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if tmpl.SystemPrompt == "" && len(tmpl.Messages) == 0 {
+		return Template{}, fmt.Errorf("template has neither a system prompt nor messages")
+	}
+	if tmpl.Temperature != nil && (*tmpl.Temperature < 0 || *tmpl.Temperature > 2) {
+		return Template{}, fmt.Errorf("temperature %g is outside 0.0-2.0", *tmpl.Temperature)
+	}
+	for i, msg := range tmpl.Messages {
+		if msg.Role != string(RoleUser) && msg.Role != string(RoleAssistant) {
+			return Template{}, fmt.Errorf("message %d has role %q, want user or assistant", i+1, msg.Role)
+		}
+		if strings.TrimSpace(msg.Content) == "" {
+			return Template{}, fmt.Errorf("message %d has no content", i+1)
+		}
+	}
+	return tmpl, nil
+}
+
+// TemplateByName finds a template by name in dir. The warnings from loading
+// are returned either way, so callers can surface malformed files alongside
+// a "not found" error.
+func TemplateByName(dir, name string) (*Template, []string, error) {
+	templates, warnings := LoadTemplates(dir)
+	for i := range templates {
+		if templates[i].Name == name {
+			return &templates[i], warnings, nil
+		}
+	}
+	return nil, warnings, fmt.Errorf("no template named %q in %s", name, dir)
+}
+
+// SeedFromTemplate applies a template to the current session and saves it.
+// Seed messages are marked as coming from the template, so they render
+// dimly and do not become the session's auto-generated title.
+func (m *Manager) SeedFromTemplate(tmpl *Template) error {
+	m.mu.Lock()
+	session := m.current
+	if session == nil {
+		m.mu.Unlock()
+		return fmt.Errorf("no current session")
+	}
+
+	session.SystemPrompt = tmpl.SystemPrompt
+	session.Model = tmpl.Model
+	session.Temperature = tmpl.Temperature
+
+	now := time.Now()
+	for _, msg := range tmpl.Messages {
+		session.Messages = append(session.Messages, Message{
+			Role:         Role(msg.Role),
+			Content:      msg.Content,
+			CreatedAt:    now,
+			FromTemplate: true,
+		})
+	}
+	m.mu.Unlock()
+
+	if err := m.SaveCurrent(); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// NewSessionFromTemplate starts a new session pre-seeded from the template.
+func (m *Manager) NewSessionFromTemplate(tmpl *Template) (*Session, error) {
+	session := m.NewSession()
+	if err := m.SeedFromTemplate(tmpl); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
@@ -0,0 +1,169 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "review.yaml", `
+name: code-review
+system_prompt: You are a code reviewer for Go.
+model: gpt-4o
+temperature: 0.3
+messages:
+  - role: user
+    content: Here are my conventions.
+  - role: assistant
+    content: Understood.
+`)
+	writeTemplate(t, dir, "brainstorm.json", `{
+		"system_prompt": "You brainstorm product ideas.",
+		"temperature": 1.2
+	}`)
+	writeTemplate(t, dir, "notes.txt", "not a template") // ignored extension
+
+	templates, warnings := LoadTemplates(dir)
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	if len(templates) != 2 {
+		t.Fatalf("expected 2 templates, got %d", len(templates))
+	}
+
+	// Sorted by name; the JSON one takes its name from the filename
+	if templates[0].Name != "brainstorm" || templates[1].Name != "code-review" {
+		t.Errorf("unexpected names: %s, %s", templates[0].Name, templates[1].Name)
+	}
+	if templates[0].Temperature == nil || *templates[0].Temperature != 1.2 {
+		t.Errorf("unexpected temperature: %v", templates[0].Temperature)
+	}
+	if len(templates[1].Messages) != 2 || templates[1].Model != "gpt-4o" {
+		t.Errorf("unexpected template: %+v", templates[1])
+	}
+}
+
+func TestLoadTemplatesSkipsMalformed(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "good.yaml", "system_prompt: fine\n")
+	writeTemplate(t, dir, "broken.yaml", "system_prompt: [unclosed\n")
+	writeTemplate(t, dir, "badrole.yaml", `
+system_prompt: x
+messages:
+  - role: wizard
+    content: hi
+`)
+	writeTemplate(t, dir, "empty.yaml", "name: nothing\n")
+
+	templates, warnings := LoadTemplates(dir)
+	if len(templates) != 1 || templates[0].Name != "good" {
+		t.Fatalf("expected only the good template, got %+v", templates)
+	}
+	if len(warnings) != 3 {
+		t.Fatalf("expected 3 warnings, got %v", warnings)
+	}
+	for _, file := range []string{"broken.yaml", "badrole.yaml", "empty.yaml"} {
+		found := false
+		for _, warning := range warnings {
+			if strings.Contains(warning, file) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("no warning names %s: %v", file, warnings)
+		}
+	}
+}
+
+func TestLoadTemplatesMissingDir(t *testing.T) {
+	templates, warnings := LoadTemplates(filepath.Join(t.TempDir(), "nope"))
+	if templates != nil || warnings != nil {
+		t.Errorf("expected a missing directory to be silent, got %v, %v", templates, warnings)
+	}
+}
+
+func TestTemplateByName(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "review.yaml", "system_prompt: review\n")
+
+	if tmpl, _, err := TemplateByName(dir, "review"); err != nil || tmpl.SystemPrompt != "review" {
+		t.Errorf("expected to find the template, got %+v, %v", tmpl, err)
+	}
+	if _, _, err := TemplateByName(dir, "missing"); err == nil {
+		t.Error("expected an error for an unknown template")
+	}
+}
+
+func TestNewSessionFromTemplate(t *testing.T) {
+	manager, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+
+	temperature := 0.3
+	tmpl := &Template{
+		Name:         "code-review",
+		SystemPrompt: "You are a code reviewer.",
+		Model:        "gpt-4o",
+		Temperature:  &temperature,
+		Messages: []TemplateMessage{
+			{Role: "user", Content: "Here are my conventions."},
+			{Role: "assistant", Content: "Understood."},
+		},
+	}
+
+	session, err := manager.NewSessionFromTemplate(tmpl)
+	if err != nil {
+		t.Fatalf("NewSessionFromTemplate failed: %v", err)
+	}
+	if session.SystemPrompt != tmpl.SystemPrompt || session.Model != "gpt-4o" {
+		t.Errorf("overrides not applied: %+v", session)
+	}
+	if session.Temperature == nil || *session.Temperature != 0.3 {
+		t.Errorf("temperature override not applied: %v", session.Temperature)
+	}
+	if len(session.Messages) != 2 {
+		t.Fatalf("expected 2 seed messages, got %d", len(session.Messages))
+	}
+	for i, msg := range session.Messages {
+		if !msg.FromTemplate {
+			t.Errorf("message %d not marked as from the template", i)
+		}
+	}
+	if label := session.Messages[0].RoleLabel(); label != "user · template" {
+		t.Errorf("RoleLabel = %q, want %q", label, "user · template")
+	}
+
+	// Seed messages must not become the title; the first real user message does
+	if session.Name != "" {
+		t.Errorf("expected no auto-title from seed messages, got %q", session.Name)
+	}
+	if err := manager.AddMessage(RoleUser, "Review this diff please"); err != nil {
+		t.Fatalf("AddMessage failed: %v", err)
+	}
+	if session.Name != "Review this diff please" {
+		t.Errorf("expected the title from the first real message, got %q", session.Name)
+	}
+
+	// The seeded session survives a round trip to disk
+	if err := manager.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	loaded, err := manager.LoadSessionByID(session.ID)
+	if err != nil {
+		t.Fatalf("failed to load session: %v", err)
+	}
+	if len(loaded.Messages) != 3 || !loaded.Messages[0].FromTemplate {
+		t.Errorf("template marking lost on reload: %+v", loaded.Messages)
+	}
+}
@@ -0,0 +1,115 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxTreeDepth caps how deep the session tree is rendered; deeper branches
+// are shown with a "…" continuation line.
+const maxTreeDepth = 8
+
+// RenderSessionTree renders the parent/fork relationships between sessions
+// as an indented tree using box-drawing characters. Sessions whose parent
+// no longer exists are grouped under a placeholder node.
+func RenderSessionTree(sessions []*Session) string {
+	byID := make(map[string]*Session, len(sessions))
+	for _, s := range sessions {
+		byID[s.ID] = s
+	}
+
+	// Group children by parent; sessions with a missing parent are grouped
+	// under the (deleted) parent's ID so the family stays together.
+	children := make(map[string][]*Session)
+	var roots []*Session
+	missingParents := make(map[string]bool)
+
+	for _, s := range sessions {
+		if s.ParentID == "" {
+			roots = append(roots, s)
+			continue
+		}
+		children[s.ParentID] = append(children[s.ParentID], s)
+		if _, ok := byID[s.ParentID]; !ok {
+			missingParents[s.ParentID] = true
+		}
+	}
+
+	sortSessions := func(list []*Session) {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		})
+	}
+	sortSessions(roots)
+	for _, list := range children {
+		sortSessions(list)
+	}
+
+	var sb strings.Builder
+
+	var render func(s *Session, prefix string, isLast bool, depth int)
+	render = func(s *Session, prefix string, isLast bool, depth int) {
+		connector := "├─ "
+		childPrefix := prefix + "│  "
+		if isLast {
+			connector = "└─ "
+			childPrefix = prefix + "   "
+		}
+		if depth == 0 {
+			connector = ""
+			childPrefix = ""
+		}
+
+		sb.WriteString(fmt.Sprintf("%s%s%s\n", prefix, connector, describeSession(s)))
+
+		kids := children[s.ID]
+		if len(kids) == 0 {
+			return
+		}
+		if depth+1 > maxTreeDepth {
+			sb.WriteString(fmt.Sprintf("%s└─ … (%d more)\n", childPrefix, len(kids)))
+			return
+		}
+		for i, kid := range kids {
+			render(kid, childPrefix, i == len(kids)-1, depth+1)
+		}
+	}
+
+	for _, root := range roots {
+		render(root, "", true, 0)
+	}
+
+	// Families whose parent was deleted hang under a placeholder node
+	var missing []string
+	for id := range missingParents {
+		missing = append(missing, id)
+	}
+	sort.Strings(missing)
+	for _, id := range missing {
+		sb.WriteString(fmt.Sprintf("(deleted session %s)\n", shortID(id)))
+		kids := children[id]
+		for i, kid := range kids {
+			render(kid, "", i == len(kids)-1, 1)
+		}
+	}
+
+	return sb.String()
+}
+
+// describeSession formats one tree node with name, date, and message count.
+func describeSession(s *Session) string {
+	name := s.Name
+	if name == "" {
+		name = "(unnamed)"
+	}
+	return fmt.Sprintf("%s (%d msgs, %s)", name, len(s.Messages), s.UpdatedAt.Format("2006-01-02 15:04"))
+}
+
+// shortID abbreviates a session ID for display.
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,62 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func treeSession(id, parentID, name string, created time.Time) *Session {
+	return &Session{
+		ID:        id,
+		ParentID:  parentID,
+		Name:      name,
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+}
+
+func TestRenderSessionTreeNesting(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sessions := []*Session{
+		treeSession("root", "", "root session", base),
+		treeSession("child-a", "root", "first fork", base.Add(time.Hour)),
+		treeSession("child-b", "root", "second fork", base.Add(2*time.Hour)),
+		treeSession("grandchild", "child-a", "deep fork", base.Add(3*time.Hour)),
+	}
+
+	got := RenderSessionTree(sessions)
+
+	lines := strings.Split(strings.TrimRight(got, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d:\n%s", len(lines), got)
+	}
+	if !strings.HasPrefix(lines[0], "root session") {
+		t.Errorf("expected root first, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "├─ first fork") {
+		t.Errorf("expected first fork as branch, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "└─ deep fork") {
+		t.Errorf("expected deep fork nested under first fork, got %q", lines[2])
+	}
+	if !strings.Contains(lines[3], "└─ second fork") {
+		t.Errorf("expected second fork last, got %q", lines[3])
+	}
+}
+
+func TestRenderSessionTreeMissingParent(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sessions := []*Session{
+		treeSession("orphan", "deleted-parent-id", "orphaned fork", base),
+	}
+
+	got := RenderSessionTree(sessions)
+
+	if !strings.Contains(got, "(deleted session deleted-") {
+		t.Errorf("expected placeholder for missing parent, got:\n%s", got)
+	}
+	if !strings.Contains(got, "orphaned fork") {
+		t.Errorf("expected orphan under placeholder, got:\n%s", got)
+	}
+}
@@ -0,0 +1,164 @@
+// Package hooks runs user-configured commands when chat events occur, so
+// external automation (webhooks, scripts) can react to what happens in a
+// session. Hooks receive a JSON payload on stdin and run asynchronously;
+// failures are logged but never block or fail the chat.
+//
+// The payload structs double as the wire format for the serve-mode API, so
+// a hook script and an API consumer see the same shapes.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event names a hook can be registered for.
+const (
+	EventTurnCompleted     = "turn_completed"
+	EventToolExecuted      = "tool_executed"
+	EventSummarizationDone = "summarization_done"
+	EventSessionCreated    = "session_created"
+)
+
+// KnownEvents lists every event name a hook can be registered for, in the
+// order they typically occur.
+var KnownEvents = []string{
+	EventSessionCreated,
+	EventToolExecuted,
+	EventTurnCompleted,
+	EventSummarizationDone,
+}
+
+// IsKnownEvent reports whether name is a valid hook event.
+func IsKnownEvent(name string) bool {
+	for _, e := range KnownEvents {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultTimeout is how long a hook command may run before it is killed.
+const DefaultTimeout = 10 * time.Second
+
+// TurnPayload is sent for turn_completed: the model produced its final
+// response for a user message.
+type TurnPayload struct {
+	Event            string `json:"event"`
+	SessionID        string `json:"session_id"`
+	SessionName      string `json:"session_name"`
+	AssistantMessage string `json:"assistant_message"`
+	ToolsExecuted    int    `json:"tools_executed"`
+	DurationMS       int64  `json:"duration_ms"`
+}
+
+// ToolPayload is sent for tool_executed, once per finished tool call.
+type ToolPayload struct {
+	Event     string `json:"event"`
+	SessionID string `json:"session_id"`
+	Tool      string `json:"tool"`
+	Cached    bool   `json:"cached"`
+	Error     string `json:"error,omitempty"`
+}
+
+// SummarizationPayload is sent for summarization_done.
+type SummarizationPayload struct {
+	Event          string `json:"event"`
+	SessionID      string `json:"session_id"`
+	MessagesBefore int    `json:"messages_before"`
+	MessagesAfter  int    `json:"messages_after"`
+}
+
+// SessionPayload is sent for session_created.
+type SessionPayload struct {
+	Event       string `json:"event"`
+	SessionID   string `json:"session_id"`
+	SessionName string `json:"session_name"`
+}
+
+// Runner dispatches events to configured hook commands. A nil Runner is
+// valid and does nothing, which is how hooks stay disabled by default.
+type Runner struct {
+	commands map[string]string
+	timeout  time.Duration
+	logf     func(format string, args ...any)
+	wg       sync.WaitGroup
+	active   atomic.Int32 // hooks currently running
+}
+
+// NewRunner creates a runner for the given event-to-command mapping.
+// Commands run through "sh -c". logf receives failure reports and may be
+// nil to discard them.
+func NewRunner(commands map[string]string, timeout time.Duration, logf func(format string, args ...any)) *Runner {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	return &Runner{commands: commands, timeout: timeout, logf: logf}
+}
+
+// Fire runs the hook registered for event, if any, with the payload's JSON
+// encoding on stdin. The command runs asynchronously under the runner's
+// timeout; Fire itself never blocks.
+func (r *Runner) Fire(event string, payload any) {
+	if r == nil {
+		return
+	}
+	command, ok := r.commands[event]
+	if !ok || command == "" {
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		r.logf("hook %s: failed to encode payload: %v", event, err)
+		return
+	}
+
+	r.wg.Add(1)
+	r.active.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer r.active.Add(-1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Stdin = bytes.NewReader(data)
+		if err := cmd.Run(); err != nil {
+			r.logf("hook %s failed: %v", event, err)
+		}
+	}()
+}
+
+// PendingWork reports hooks that are still running.
+func (r *Runner) PendingWork() (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	n := r.active.Load()
+	if n == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%d running hook command(s)", n), true
+}
+
+// Wait blocks until all in-flight hooks have finished. Called on shutdown
+// so quick hooks are not cut off mid-run; a hung hook still dies with its
+// timeout.
+func (r *Runner) Wait() {
+	if r == nil {
+		return
+	}
+	r.wg.Wait()
+}
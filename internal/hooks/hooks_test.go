@@ -0,0 +1,95 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFireRunsCommandWithPayloadOnStdin(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "payload.json")
+	r := NewRunner(map[string]string{
+		EventTurnCompleted: fmt.Sprintf("cat > %s", out),
+	}, time.Second, nil)
+
+	r.Fire(EventTurnCompleted, TurnPayload{
+		Event:     EventTurnCompleted,
+		SessionID: "s1",
+	})
+	r.Wait()
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("expected the hook to write its stdin: %v", err)
+	}
+	var payload TurnPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+	if payload.Event != EventTurnCompleted || payload.SessionID != "s1" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestFireIgnoresUnregisteredEvents(t *testing.T) {
+	r := NewRunner(map[string]string{EventToolExecuted: "false"}, time.Second, nil)
+	r.Fire(EventTurnCompleted, TurnPayload{Event: EventTurnCompleted})
+	r.Wait()
+}
+
+func TestFireLogsFailures(t *testing.T) {
+	logged := make(chan string, 1)
+	r := NewRunner(map[string]string{EventToolExecuted: "exit 3"}, time.Second, func(format string, args ...any) {
+		logged <- fmt.Sprintf(format, args...)
+	})
+
+	r.Fire(EventToolExecuted, ToolPayload{Event: EventToolExecuted})
+	r.Wait()
+
+	select {
+	case msg := <-logged:
+		if msg == "" {
+			t.Error("expected a failure message")
+		}
+	default:
+		t.Error("expected the failure to be logged")
+	}
+}
+
+func TestFireKillsHungHooks(t *testing.T) {
+	logged := make(chan string, 1)
+	r := NewRunner(map[string]string{EventSessionCreated: "sleep 10"}, 50*time.Millisecond, func(format string, args ...any) {
+		logged <- fmt.Sprintf(format, args...)
+	})
+
+	start := time.Now()
+	r.Fire(EventSessionCreated, SessionPayload{Event: EventSessionCreated})
+	r.Wait()
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the hook to be killed by its timeout, took %v", elapsed)
+	}
+	if len(logged) == 0 {
+		t.Error("expected the timeout to be logged as a failure")
+	}
+}
+
+func TestNilRunnerIsSafe(t *testing.T) {
+	var r *Runner
+	r.Fire(EventTurnCompleted, TurnPayload{})
+	r.Wait()
+}
+
+func TestIsKnownEvent(t *testing.T) {
+	for _, event := range KnownEvents {
+		if !IsKnownEvent(event) {
+			t.Errorf("expected %q to be known", event)
+		}
+	}
+	if IsKnownEvent("no_such_event") {
+		t.Error("expected unknown event to be rejected")
+	}
+}
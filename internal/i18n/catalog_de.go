@@ -0,0 +1,69 @@
+package i18n
+
+// german is the German catalog. Confirmation answers stay y/N: what
+// isAffirmative accepts is not localized (yet).
+var german = map[Key]string{
+	HelpHeader: "=== Verfügbare Befehle ===",
+	HelpAlias:  " (Alias: %s)",
+
+	ConfirmExecuteTools:  "Diese Tools ausführen? [y/N]: ",
+	ConfirmDeleteSession: "Sitzung %q löschen? [y/N]: ",
+	ConfirmDeleteCurrent: "Aktuelle Sitzung %q löschen? [y/N]: ",
+	ConfirmRollback:      "%d Nachricht(en) nach Checkpoint %q entfernen? [y/N]: ",
+	ConfirmRestoreDraft:  "Wiederherstellen und senden? [y/N]: ",
+	ConfirmShareOutput:   "Ausgabe mit dem Assistenten teilen? [y/N]: ",
+
+	HelpShellEscape: "!<Befehl> führt einen Shell-Befehl lokal aus; !! wiederholt den letzten.",
+
+	HelpCmdSummarize:   "Ältere Nachrichten zusammenfassen, um die Historie zu verkleinern",
+	HelpCmdDiff:        "Änderungen zwischen den letzten beiden Antworten zeigen, oder zwischen N und M",
+	HelpCmdStats:       "Sitzungsstatistik zeigen, mit --all die Nutzung über alle Sitzungen",
+	HelpCmdContext:     "Kontextverbrauch zeigen oder Dateien/Notizen verwalten, die jeder Anfrage beiliegen",
+	HelpCmdFlush:       "Offline eingereihte Nachrichten der Reihe nach senden",
+	HelpCmdBudget:      "Heutige und sitzungsbezogene Ausgaben gegen die Limits zeigen",
+	HelpCmdNote:        "Private Notiz speichern (wird nie an das Modell gesendet)",
+	HelpCmdRemember:    "Fakt über alle Sitzungen hinweg merken (siehe /memory)",
+	HelpCmdMemory:      "Gemerkte Fakten mit den Nummern für /forget auflisten",
+	HelpCmdForget:      "Gemerkten Fakt anhand seiner /memory-Nummer entfernen",
+	HelpCmdPinFact:     "Fakt anheften, den Zusammenfassungen immer behalten müssen (ohne Text: auflisten)",
+	HelpCmdContinue:    "Eine durch max_tokens abgeschnittene Antwort fortsetzen",
+	HelpCmdPin:         "Schutz dieser Sitzung vor dem Aufräumen umschalten",
+	HelpCmdImage:       "Lokales Bild an eine Nachricht anhängen",
+	HelpCmdCopy:        "Assistenten-Nachricht (oder ihre Codeblöcke) in die Zwischenablage kopieren",
+	HelpCmdClip:        "Inhalt der Zwischenablage als nächste Nachricht senden",
+	HelpCmdTimestamps:  "Dezenten Zeitstempel vor jeder Nachricht umschalten",
+	HelpCmdTools:       "Verfügbare MCP-Tools auflisten oder eines im Detail zeigen",
+	HelpCmdCall:        "MCP-Tool selbst ausführen (--share zeigt dem Modell das Ergebnis)",
+	HelpCmdServers:     "Verbundene MCP-Server zeigen",
+	HelpCmdMCP:         "MCP-Server verwalten: add/remove/restart/retry/refresh (--save speichert)",
+	HelpCmdFork:        "Unterhaltung in eine neue Sitzung abzweigen (optional die ersten N Nachrichten)",
+	HelpCmdSystem:      "System-Prompt dieser Sitzung zeigen oder ändern",
+	HelpCmdMaxTokens:   "max_tokens für diese Sitzung zeigen oder überschreiben",
+	HelpCmdParams:      "Generierungsparameter für diese Sitzung zeigen oder überschreiben",
+	HelpCmdJSON:        "JSON-Ausgabe für diese Sitzung erzwingen, optional per Schema",
+	HelpCmdCheckpoint:  "Rollback-Punkt bei der aktuellen Nachricht setzen",
+	HelpCmdCheckpoints: "Checkpoints dieser Sitzung auflisten",
+	HelpCmdRollback:    "Sitzung auf einen Checkpoint zurücksetzen (archiviert den Rest)",
+	HelpCmdList:        "Sitzungen mit Nummern für /switch und /delete auflisten",
+	HelpCmdNew:         "Neue Sitzung starten, optional aus einer Vorlage (siehe /templates)",
+	HelpCmdTemplates:   "Sitzungsvorlagen aus history.templates_dir auflisten",
+	HelpCmdSwitch:      "Zu einer anderen Sitzung aus dem letzten /list wechseln",
+	HelpCmdRename:      "Aktuelle Sitzung umbenennen",
+	HelpCmdDelete:      "Aktuelle Sitzung löschen, oder eine andere aus dem letzten /list",
+	HelpCmdSave:        "Sitzung sofort auf die Platte schreiben, ohne Auto-Save-Verzögerung",
+	HelpCmdInfo:        "ID, Datei und Metadaten der aktuellen Sitzung zeigen",
+	HelpCmdTree:        "Abzweigungen zwischen Sitzungen als Baum zeigen",
+	HelpCmdReload:      "config.yaml neu einlesen und live Anwendbares übernehmen",
+	HelpCmdQuit:        "Beenden; warnt vor offener Arbeit, außer mit --force",
+	HelpCmdSleep:       "Animationstest (Standard: 3 Sekunden)",
+	HelpCmdVersion:     "Version und Build-Informationen zeigen",
+	HelpCmdWorkspace:   "Aktiven Workspace und die konfigurierten zeigen",
+	HelpCmdHelp:        "Diese Hilfe anzeigen",
+
+	PickerSelect:        "Sitzung wählen (0 für neu, d# zum Löschen, oder Nummer) [%s]: ",
+	PickerDeleteConfirm: "Sitzung '%s' löschen? (y/N): ",
+	PickerInvalidDelete: "Bitte d gefolgt von einer Nummer zwischen 1 und %d eingeben.",
+	PickerContinuing:    "Sitzung wird fortgesetzt: %s",
+
+	ErrorRepeated: "vorherige Meldung %d-mal wiederholt",
+}
@@ -0,0 +1,68 @@
+package i18n
+
+// english is the reference catalog; every registered key must be here.
+var english = map[Key]string{
+	HelpHeader: "=== Available Commands ===",
+	HelpAlias:  " (alias: %s)",
+
+	ConfirmExecuteTools:  "Execute these tools? [y/N]: ",
+	ConfirmDeleteSession: "Delete session %q? [y/N]: ",
+	ConfirmDeleteCurrent: "Delete the current session %q? [y/N]: ",
+	ConfirmRollback:      "Remove %d message(s) after checkpoint %q? [y/N]: ",
+	ConfirmRestoreDraft:  "Restore and send it? [y/N]: ",
+	ConfirmShareOutput:   "Share the output with the assistant? [y/N]: ",
+
+	HelpShellEscape: "!<command> runs a shell command locally; !! reruns the previous one.",
+
+	HelpCmdSummarize:   "Summarize older messages to reduce history size",
+	HelpCmdDiff:        "Show what changed between the last two assistant replies, or replies N and M",
+	HelpCmdStats:       "Show session statistics, or usage across all sessions with --all",
+	HelpCmdContext:     "Show context usage, or manage files/notes injected into every request",
+	HelpCmdFlush:       "Send messages queued while offline, in order",
+	HelpCmdBudget:      "Show today's and this session's spend against configured limits",
+	HelpCmdNote:        "Save a private note (never sent to the model)",
+	HelpCmdRemember:    "Remember a fact across all sessions (see /memory)",
+	HelpCmdMemory:      "List remembered facts with the numbers /forget takes",
+	HelpCmdForget:      "Remove a remembered fact by its /memory number",
+	HelpCmdPinFact:     "Pin a fact summaries must always keep (no text: list)",
+	HelpCmdContinue:    "Resume a response truncated by max_tokens",
+	HelpCmdPin:         "Toggle protecting this session from retention pruning",
+	HelpCmdImage:       "Attach a local image to a message",
+	HelpCmdCopy:        "Copy an assistant message (or its code blocks) to the clipboard",
+	HelpCmdClip:        "Submit the clipboard contents as the next message",
+	HelpCmdTimestamps:  "Toggle a dim time stamp before each message",
+	HelpCmdTools:       "List available MCP tools, or show one in detail",
+	HelpCmdCall:        "Run an MCP tool yourself (--share shows the model the result)",
+	HelpCmdServers:     "Show connected MCP servers",
+	HelpCmdMCP:         "Manage MCP servers: add/remove/restart/retry/refresh (--save persists)",
+	HelpCmdFork:        "Fork the conversation into a new session (optionally first N messages)",
+	HelpCmdSystem:      "Show or change the system prompt for this session",
+	HelpCmdMaxTokens:   "Show or override max_tokens for this session",
+	HelpCmdParams:      "Show or override generation parameters for this session",
+	HelpCmdJSON:        "Force JSON output for this session, optionally schema-constrained",
+	HelpCmdCheckpoint:  "Record a rollback point at the current message",
+	HelpCmdCheckpoints: "List this session's checkpoints",
+	HelpCmdRollback:    "Rewind the session to a checkpoint (archives the tail)",
+	HelpCmdList:        "List sessions with numbers usable by /switch and /delete",
+	HelpCmdNew:         "Start a fresh session, optionally from a template (see /templates)",
+	HelpCmdTemplates:   "List session templates from history.templates_dir",
+	HelpCmdSwitch:      "Switch to another session from the last /list",
+	HelpCmdRename:      "Rename the current session",
+	HelpCmdDelete:      "Delete the current session, or another from the last /list",
+	HelpCmdSave:        "Write the session to disk now, bypassing the auto-save debounce",
+	HelpCmdInfo:        "Show the current session's ID, file, and metadata",
+	HelpCmdTree:        "Show fork relationships between sessions",
+	HelpCmdReload:      "Re-read config.yaml and apply what can change live",
+	HelpCmdQuit:        "Exit; warns about pending work unless forced",
+	HelpCmdSleep:       "Test animation (default: 3 seconds)",
+	HelpCmdVersion:     "Show version and build information",
+	HelpCmdWorkspace:   "Show the active workspace and the configured ones",
+	HelpCmdHelp:        "Show this help message",
+
+	PickerSelect:        "Select a session (0 for new, d# to delete, or number) [%s]: ",
+	PickerDeleteConfirm: "Delete session '%s'? (y/N): ",
+	PickerInvalidDelete: "Please enter d followed by a number between 1 and %d.",
+	PickerContinuing:    "Continuing session: %s",
+
+	ErrorRepeated: "previous message repeated %d times",
+}
@@ -0,0 +1,207 @@
+// Package i18n translates the interface strings — prompts, help text,
+// banners — via a per-locale message catalog. The locale comes from
+// ui.language or, when that is unset, the LANG-style environment
+// variables; keys missing from a translation fall back to English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Key names one translatable message. Call sites use the typed constants
+// below, so every key in use is part of the registry the catalog tests
+// check against the English catalog.
+type Key string
+
+const (
+	HelpHeader Key = "help.header"
+	HelpAlias  Key = "help.alias"
+
+	ConfirmExecuteTools  Key = "confirm.execute_tools"
+	ConfirmDeleteSession Key = "confirm.delete_session"
+	ConfirmDeleteCurrent Key = "confirm.delete_current"
+	ConfirmRollback      Key = "confirm.rollback"
+	ConfirmRestoreDraft  Key = "confirm.restore_draft"
+	ConfirmShareOutput   Key = "confirm.share_output"
+
+	HelpShellEscape Key = "help.shell_escape"
+
+	// Per-command descriptions for /help, in the dispatch table's order.
+	HelpCmdSummarize   Key = "help.cmd.summarize"
+	HelpCmdDiff        Key = "help.cmd.diff"
+	HelpCmdStats       Key = "help.cmd.stats"
+	HelpCmdContext     Key = "help.cmd.context"
+	HelpCmdFlush       Key = "help.cmd.flush"
+	HelpCmdBudget      Key = "help.cmd.budget"
+	HelpCmdNote        Key = "help.cmd.note"
+	HelpCmdRemember    Key = "help.cmd.remember"
+	HelpCmdMemory      Key = "help.cmd.memory"
+	HelpCmdForget      Key = "help.cmd.forget"
+	HelpCmdPinFact     Key = "help.cmd.pin-fact"
+	HelpCmdContinue    Key = "help.cmd.continue"
+	HelpCmdPin         Key = "help.cmd.pin"
+	HelpCmdImage       Key = "help.cmd.image"
+	HelpCmdCopy        Key = "help.cmd.copy"
+	HelpCmdClip        Key = "help.cmd.clip"
+	HelpCmdTimestamps  Key = "help.cmd.timestamps"
+	HelpCmdTools       Key = "help.cmd.tools"
+	HelpCmdCall        Key = "help.cmd.call"
+	HelpCmdServers     Key = "help.cmd.servers"
+	HelpCmdMCP         Key = "help.cmd.mcp"
+	HelpCmdFork        Key = "help.cmd.fork"
+	HelpCmdSystem      Key = "help.cmd.system"
+	HelpCmdMaxTokens   Key = "help.cmd.max-tokens"
+	HelpCmdParams      Key = "help.cmd.params"
+	HelpCmdJSON        Key = "help.cmd.json"
+	HelpCmdCheckpoint  Key = "help.cmd.checkpoint"
+	HelpCmdCheckpoints Key = "help.cmd.checkpoints"
+	HelpCmdRollback    Key = "help.cmd.rollback"
+	HelpCmdList        Key = "help.cmd.list"
+	HelpCmdNew         Key = "help.cmd.new"
+	HelpCmdTemplates   Key = "help.cmd.templates"
+	HelpCmdSwitch      Key = "help.cmd.switch"
+	HelpCmdRename      Key = "help.cmd.rename"
+	HelpCmdDelete      Key = "help.cmd.delete"
+	HelpCmdSave        Key = "help.cmd.save"
+	HelpCmdInfo        Key = "help.cmd.info"
+	HelpCmdTree        Key = "help.cmd.tree"
+	HelpCmdReload      Key = "help.cmd.reload"
+	HelpCmdQuit        Key = "help.cmd.quit"
+	HelpCmdSleep       Key = "help.cmd.sleep"
+	HelpCmdVersion     Key = "help.cmd.version"
+	HelpCmdWorkspace   Key = "help.cmd.workspace"
+	HelpCmdHelp        Key = "help.cmd.help"
+
+	PickerSelect        Key = "picker.select"
+	PickerDeleteConfirm Key = "picker.delete_confirm"
+	PickerInvalidDelete Key = "picker.invalid_delete"
+	PickerContinuing    Key = "picker.continuing"
+
+	ErrorRepeated Key = "error.repeated"
+)
+
+// keys is the registry of every key the code uses.
+var keys = []Key{
+	HelpHeader,
+	HelpAlias,
+	ConfirmExecuteTools,
+	ConfirmDeleteSession,
+	ConfirmDeleteCurrent,
+	ConfirmRollback,
+	ConfirmRestoreDraft,
+	ConfirmShareOutput,
+	HelpShellEscape,
+	HelpCmdSummarize,
+	HelpCmdDiff,
+	HelpCmdStats,
+	HelpCmdContext,
+	HelpCmdFlush,
+	HelpCmdBudget,
+	HelpCmdNote,
+	HelpCmdRemember,
+	HelpCmdMemory,
+	HelpCmdForget,
+	HelpCmdPinFact,
+	HelpCmdContinue,
+	HelpCmdPin,
+	HelpCmdImage,
+	HelpCmdCopy,
+	HelpCmdClip,
+	HelpCmdTimestamps,
+	HelpCmdTools,
+	HelpCmdCall,
+	HelpCmdServers,
+	HelpCmdMCP,
+	HelpCmdFork,
+	HelpCmdSystem,
+	HelpCmdMaxTokens,
+	HelpCmdParams,
+	HelpCmdJSON,
+	HelpCmdCheckpoint,
+	HelpCmdCheckpoints,
+	HelpCmdRollback,
+	HelpCmdList,
+	HelpCmdNew,
+	HelpCmdTemplates,
+	HelpCmdSwitch,
+	HelpCmdRename,
+	HelpCmdDelete,
+	HelpCmdSave,
+	HelpCmdInfo,
+	HelpCmdTree,
+	HelpCmdReload,
+	HelpCmdQuit,
+	HelpCmdSleep,
+	HelpCmdVersion,
+	HelpCmdWorkspace,
+	HelpCmdHelp,
+	PickerSelect,
+	PickerDeleteConfirm,
+	PickerInvalidDelete,
+	PickerContinuing,
+	ErrorRepeated,
+}
+
+// Keys returns the registry of keys the code uses.
+func Keys() []Key {
+	return keys
+}
+
+// catalogs maps normalized language codes to their message catalogs.
+var catalogs = map[string]map[Key]string{
+	"en": english,
+	"de": german,
+}
+
+// active is the catalog T reads from; English until SetLocale says otherwise.
+var active = english
+
+// SetLocale selects the catalog for the given language ("de", "de_DE",
+// "de_DE.UTF-8" all mean German). An empty language reads the LANG-style
+// environment variables; unknown languages keep English.
+func SetLocale(lang string) {
+	if lang == "" {
+		lang = envLocale()
+	}
+	code := strings.ToLower(lang)
+	for _, sep := range []string{".", "_", "-"} {
+		if i := strings.Index(code, sep); i >= 0 {
+			code = code[:i]
+		}
+	}
+	if catalog, ok := catalogs[code]; ok {
+		active = catalog
+		return
+	}
+	active = english
+}
+
+// envLocale returns the locale the environment asks for, in POSIX
+// precedence order.
+func envLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// T returns the message for key in the active locale, formatted with args
+// when given. Keys missing from the active catalog fall back to English;
+// a key missing there too renders as its name, so it is at least visible.
+func T(key Key, args ...any) string {
+	msg, ok := active[key]
+	if !ok {
+		msg, ok = english[key]
+	}
+	if !ok {
+		msg = string(key)
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
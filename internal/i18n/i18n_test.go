@@ -0,0 +1,61 @@
+package i18n
+
+import "testing"
+
+// TestEnglishCatalogComplete fails when a key the code uses (the
+// registry) has no English message to fall back to.
+func TestEnglishCatalogComplete(t *testing.T) {
+	for _, key := range Keys() {
+		if _, ok := english[key]; !ok {
+			t.Errorf("key %q is used but missing from the English catalog", key)
+		}
+	}
+}
+
+// TestTranslationsDeclareKnownKeys fails when a translation carries a key
+// the registry does not know — usually a typo that silently never shows.
+func TestTranslationsDeclareKnownKeys(t *testing.T) {
+	registered := make(map[Key]bool, len(keys))
+	for _, key := range keys {
+		registered[key] = true
+	}
+	for lang, catalog := range catalogs {
+		for key := range catalog {
+			if !registered[key] {
+				t.Errorf("catalog %q has unregistered key %q", lang, key)
+			}
+		}
+	}
+}
+
+func TestSetLocale(t *testing.T) {
+	t.Cleanup(func() { SetLocale("en") })
+
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"de", "=== Verfügbare Befehle ==="},
+		{"de_DE.UTF-8", "=== Verfügbare Befehle ==="},
+		{"en", "=== Available Commands ==="},
+		{"fr", "=== Available Commands ==="}, // no catalog, English fallback
+	}
+	for _, tt := range tests {
+		SetLocale(tt.lang)
+		if got := T(HelpHeader); got != tt.want {
+			t.Errorf("T(HelpHeader) under %q = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestTFormatsAndFallsBack(t *testing.T) {
+	t.Cleanup(func() { SetLocale("en") })
+
+	SetLocale("de")
+	if got := T(ConfirmDeleteSession, "notes"); got != "Sitzung \"notes\" löschen? [y/N]: " {
+		t.Errorf("unexpected formatted message: %q", got)
+	}
+	if got := T(Key("missing.key")); got != "missing.key" {
+		t.Errorf("expected the key name for an unknown key, got %q", got)
+	}
+}
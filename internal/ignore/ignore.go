@@ -0,0 +1,207 @@
+// Package ignore matches paths against gitignore-style patterns, so file
+// listings and glob expansion can skip node_modules, .git internals, and
+// other junk. It reads .gitignore plus an optional .gopusignore from a
+// root directory and supports the usual syntax: `*` and `?` wildcards,
+// `**` spanning directories, trailing `/` for directories only, and `!`
+// re-includes with last-match-wins semantics.
+package ignore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Files are the ignore files read from a root, in order; later files
+// override earlier ones, so .gopusignore can re-include what .gitignore
+// hides.
+var Files = []string{".gitignore", ".gopusignore"}
+
+// pattern is one compiled ignore rule.
+type pattern struct {
+	re      *regexp.Regexp
+	negate  bool // `!` prefix: a match re-includes the path
+	dirOnly bool // trailing `/`: the rule only matches directories
+}
+
+// Matcher holds the compiled rules of a root directory. The zero value
+// matches nothing.
+type Matcher struct {
+	patterns []pattern
+}
+
+// Load reads the ignore files of a root directory. Missing files are
+// fine; a root without any yields a matcher that ignores nothing.
+func Load(root string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, name := range Files {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		m.AddPatterns(string(data))
+	}
+	return m, nil
+}
+
+// AddPatterns appends rules in gitignore syntax, one per line. Blank
+// lines, comments, and unparsable patterns are skipped, like git does.
+func (m *Matcher) AddPatterns(lines string) {
+	for _, line := range strings.Split(lines, "\n") {
+		if p, ok := parseLine(line); ok {
+			m.patterns = append(m.patterns, p)
+		}
+	}
+}
+
+// Empty reports whether the matcher has no rules at all.
+func (m *Matcher) Empty() bool {
+	return len(m.patterns) == 0
+}
+
+// Match reports whether the rules ignore the given path, which must be
+// slash-separated and relative to the root the rules were loaded from.
+// A path inside an ignored directory is ignored regardless of
+// re-includes, matching git's behavior.
+func (m *Matcher) Match(path string, isDir bool) bool {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	if path == "" || path == "." {
+		return false
+	}
+
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		if m.matchOne(strings.Join(segments[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.matchOne(path, isDir)
+}
+
+// matchOne evaluates every rule against a single path; the last rule
+// that matches decides.
+func (m *Matcher) matchOne(path string, isDir bool) bool {
+	ignored := false
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.re.MatchString(path) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// parseLine turns one ignore-file line into a rule; ok is false for
+// blanks, comments, and patterns that do not compile.
+func parseLine(line string) (pattern, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern{}, false
+	}
+
+	var p pattern
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+	// `\#` and `\!` escape the special leading characters
+	if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return pattern{}, false
+	}
+
+	re, err := compile(line)
+	if err != nil {
+		return pattern{}, false
+	}
+	p.re = re
+	return p, true
+}
+
+// compile translates a gitignore glob into a regular expression over
+// slash-separated relative paths. A pattern containing a slash is
+// anchored to the root; one without matches at any depth.
+func compile(glob string) (*regexp.Regexp, error) {
+	anchored := strings.Contains(glob, "/")
+	glob = strings.TrimPrefix(glob, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString(`(?:.*/)?`)
+	}
+	for i := 0; i < len(glob); {
+		switch c := glob[i]; c {
+		case '*':
+			atBoundary := i == 0 || glob[i-1] == '/'
+			if atBoundary && strings.HasPrefix(glob[i:], "**/") {
+				// `**/` matches zero or more leading directories
+				b.WriteString(`(?:.*/)?`)
+				i += 3
+			} else if atBoundary && glob[i:] == "**" {
+				// a trailing `/**` matches everything inside
+				b.WriteString(`.*`)
+				i += 2
+			} else {
+				b.WriteString(`[^/]*`)
+				i++
+			}
+		case '?':
+			b.WriteString(`[^/]`)
+			i++
+		case '[':
+			class, width := characterClass(glob[i:])
+			b.WriteString(class)
+			i += width
+		case '\\':
+			if i+1 < len(glob) {
+				b.WriteString(regexp.QuoteMeta(glob[i+1 : i+2]))
+				i += 2
+			} else {
+				i++
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			i++
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// characterClass consumes a `[...]` class at the start of glob and
+// returns its regexp form and how many bytes it spans. An unterminated
+// `[` is treated as a literal bracket.
+func characterClass(glob string) (string, int) {
+	j := 1
+	if j < len(glob) && (glob[j] == '!' || glob[j] == '^') {
+		j++
+	}
+	if j < len(glob) && glob[j] == ']' {
+		j++ // a leading `]` is a member, not the terminator
+	}
+	for j < len(glob) && glob[j] != ']' {
+		j++
+	}
+	if j >= len(glob) {
+		return `\[`, 1
+	}
+	class := glob[:j+1]
+	if strings.HasPrefix(class, "[!") {
+		class = "[^" + class[2:]
+	}
+	return class, j + 1
+}
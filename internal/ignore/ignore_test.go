@@ -0,0 +1,101 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func matcherOf(t *testing.T, lines string) *Matcher {
+	t.Helper()
+	m := &Matcher{}
+	m.AddPatterns(lines)
+	return m
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   string
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"plain name matches anywhere", "node_modules", "pkg/node_modules", true, true},
+		{"plain name matches files too", "secrets.env", "config/secrets.env", false, true},
+		{"unrelated path passes", "node_modules", "pkg/modules", true, false},
+		{"star stays within a segment", "*.log", "build/app.log", false, true},
+		{"star does not cross slashes", "build*.log", "build/app.log", false, false},
+		{"question mark is one character", "app?.log", "app1.log", false, true},
+		{"question mark needs a character", "app?.log", "app.log", false, false},
+		{"character class", "app[0-9].log", "app7.log", false, true},
+		{"negated character class", "app[!0-9].log", "app7.log", false, false},
+		{"slash anchors to the root", "build/app.log", "build/app.log", false, true},
+		{"anchored pattern ignores deeper copies", "build/app.log", "sub/build/app.log", false, false},
+		{"leading slash anchors too", "/top.txt", "sub/top.txt", false, false},
+		{"double star prefix matches any depth", "**/trace.out", "a/b/c/trace.out", false, true},
+		{"double star prefix matches the root", "**/trace.out", "trace.out", false, true},
+		{"double star suffix matches contents", "dist/**", "dist/js/app.js", false, true},
+		{"double star suffix spares the directory itself", "dist/**", "dist", true, false},
+		{"double star in the middle spans directories", "a/**/b", "a/x/y/b", false, true},
+		{"double star in the middle matches zero directories", "a/**/b", "a/b", false, true},
+		{"trailing slash is directories only", "cache/", "cache", true, true},
+		{"trailing slash spares a file of the same name", "cache/", "cache", false, false},
+		{"ignored directory swallows its contents", "vendor/", "vendor/lib/util.go", false, true},
+		{"negation re-includes", "*.log\n!keep.log", "keep.log", false, false},
+		{"negation only affects later matches", "!keep.log\n*.log", "keep.log", false, true},
+		{"negation cannot escape an ignored directory", "vendor/\n!vendor/keep.go", "vendor/keep.go", false, true},
+		{"comments and blanks are skipped", "# junk\n\n*.tmp", "a.tmp", false, true},
+		{"escaped hash is literal", `\#notes`, "#notes", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := matcherOf(t, tt.lines)
+			if got := m.Match(tt.path, tt.isDir); got != tt.ignored {
+				t.Errorf("Match(%q, dir=%v) with %q = %v, want %v",
+					tt.path, tt.isDir, tt.lines, got, tt.ignored)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	write(".gitignore", "*.log\nnode_modules/\n")
+	write(".gopusignore", "!important.log\nsecrets.env\n")
+
+	m, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if m.Match("debug.log", false) != true {
+		t.Error("expected .gitignore rules to apply")
+	}
+	if m.Match("secrets.env", false) != true {
+		t.Error("expected .gopusignore rules to apply")
+	}
+	// .gopusignore comes second, so its re-includes win
+	if m.Match("important.log", false) != false {
+		t.Error("expected .gopusignore to re-include important.log")
+	}
+}
+
+func TestLoadWithoutIgnoreFiles(t *testing.T) {
+	m, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !m.Empty() {
+		t.Error("expected an empty matcher for a root without ignore files")
+	}
+	if m.Match("anything.txt", false) {
+		t.Error("an empty matcher must ignore nothing")
+	}
+}
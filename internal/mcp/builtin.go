@@ -7,15 +7,24 @@ import (
 	mcplib "github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
+	"gopus/internal/history"
 	"gopus/internal/openai"
 )
 
 // ToolHandler is the function signature for MCP tool handlers.
 type ToolHandler func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error)
 
-// ToolHandlerFactory creates a tool handler with access to the OpenAI client.
-// This allows tools to use the OpenAI API while being registered at init time.
-type ToolHandlerFactory func(openaiClient *openai.ChatClient) ToolHandler
+// Deps bundles the process-level dependencies available to builtin tools.
+// Fields may be nil when the corresponding subsystem is not configured.
+type Deps struct {
+	OpenAI  *openai.ChatClient
+	History *history.Manager
+}
+
+// ToolHandlerFactory creates a tool handler with access to the process
+// dependencies. This allows tools to use the OpenAI API or session history
+// while being registered at init time.
+type ToolHandlerFactory func(deps Deps) ToolHandler
 
 // ToolRegistration holds a tool definition and its handler factory.
 type ToolRegistration struct {
@@ -91,27 +100,52 @@ func (r *ToolRegistry) Count() int {
 // Builtin tools should register themselves here using init() functions.
 var DefaultToolRegistry = NewToolRegistry()
 
-// BuiltinServer is the single in-process MCP server that hosts all builtin tools.
-// It implements the setup logic to add all tools from DefaultToolRegistry to an MCP server.
-type BuiltinServer struct{}
+// BuiltinServer is an in-process MCP server hosting a set of builtin tools.
+// The zero value serves every tool in DefaultToolRegistry under the name
+// "builtin"; NewBuiltinServer creates one backed by its own registry, for
+// embedders that register tools programmatically.
+type BuiltinServer struct {
+	// name overrides the default server name when non-empty.
+	name string
+	// registry overrides DefaultToolRegistry when non-nil.
+	registry *ToolRegistry
+}
+
+// NewBuiltinServer returns a builtin server that hosts the tools in the
+// given registry under the given name, independent of the global
+// DefaultToolRegistry.
+func NewBuiltinServer(name string, registry *ToolRegistry) *BuiltinServer {
+	return &BuiltinServer{name: name, registry: registry}
+}
 
 // Name returns the unique identifier for this server.
 func (s *BuiltinServer) Name() string {
+	if s.name != "" {
+		return s.name
+	}
 	return "builtin"
 }
 
+// Registry returns the tool registry this server hosts.
+func (s *BuiltinServer) Registry() *ToolRegistry {
+	if s.registry != nil {
+		return s.registry
+	}
+	return DefaultToolRegistry
+}
+
 // Description returns a human-readable description of the server.
 func (s *BuiltinServer) Description() string {
 	return "Built-in MCP server hosting all registered builtin tools"
 }
 
-// Setup configures the MCP server with all tools from DefaultToolRegistry.
-// The openaiClient parameter provides access to the OpenAI API for tools that need it
-// (may be nil if no OpenAI client is configured).
-func (s *BuiltinServer) Setup(srv *server.MCPServer, openaiClient *openai.ChatClient) error {
-	// Add all tools from the DefaultToolRegistry
-	for _, reg := range DefaultToolRegistry.All() {
-		handler := reg.HandlerFactory(openaiClient)
+// Setup configures the MCP server with all tools from this server's
+// registry. The deps container provides access to the OpenAI API and
+// session history for tools that need them (fields may be nil).
+func (s *BuiltinServer) Setup(srv *server.MCPServer, deps Deps) error {
+	// Add all tools from the registry
+	for _, reg := range s.Registry().All() {
+		handler := reg.HandlerFactory(deps)
 		srv.AddTool(reg.Tool, func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
 			return handler(ctx, req)
 		})
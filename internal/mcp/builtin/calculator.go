@@ -0,0 +1,390 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"gopus/internal/mcp"
+)
+
+func init() {
+	mcp.DefaultToolRegistry.Register(
+		mcplib.NewTool("calculate",
+			mcplib.WithDescription("Evaluates an arithmetic expression and returns the numeric result. Supports + - * / % ^, parentheses, scientific notation, the constants pi and e, and the functions sqrt, abs, min, max, round, floor, ceil, log, log10, exp, sin, cos, tan"),
+			mcplib.WithString("expression",
+				mcplib.Required(),
+				mcplib.Description("The expression to evaluate, e.g. \"sqrt(2) * (1 + 3e-2)\""),
+			),
+			mcplib.WithNumber("precision",
+				mcplib.Description("Number of decimal places in the result (optional; full precision when omitted)"),
+			),
+		),
+		func(deps mcp.Deps) mcp.ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				expression, err := GetRequiredStringArg(req, "expression")
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := evaluateExpression(expression)
+				if err != nil {
+					return mcplib.NewToolResultError(err.Error()), nil
+				}
+
+				precision := -1
+				if args, err := GetArgs(req); err == nil {
+					if p, ok := args["precision"].(float64); ok && p >= 0 {
+						precision = int(p)
+					}
+				}
+
+				var text string
+				if precision >= 0 {
+					text = strconv.FormatFloat(value, 'f', precision, 64)
+				} else {
+					text = strconv.FormatFloat(value, 'g', -1, 64)
+				}
+				return mcplib.NewToolResultText(text), nil
+			}
+		},
+	)
+}
+
+// evalError reports a problem at a byte offset in the expression.
+type evalError struct {
+	Pos int
+	Msg string
+}
+
+func (e *evalError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
+
+// errAt builds an evalError at the given position.
+func errAt(pos int, format string, args ...any) error {
+	return &evalError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// checkFinite rejects results that overflowed to Inf or NaN.
+func checkFinite(v float64, pos int) error {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return errAt(pos, "result is not a finite number")
+	}
+	return nil
+}
+
+// calcFunction is a named function callable from an expression.
+type calcFunction struct {
+	minArgs int
+	maxArgs int // -1 means variadic
+	apply   func(args []float64) float64
+}
+
+// calcFunctions maps function names to their implementations.
+var calcFunctions = map[string]calcFunction{
+	"sqrt":  {1, 1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"abs":   {1, 1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"round": {1, 1, func(a []float64) float64 { return math.Round(a[0]) }},
+	"floor": {1, 1, func(a []float64) float64 { return math.Floor(a[0]) }},
+	"ceil":  {1, 1, func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"log":   {1, 1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"log10": {1, 1, func(a []float64) float64 { return math.Log10(a[0]) }},
+	"exp":   {1, 1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	"sin":   {1, 1, func(a []float64) float64 { return math.Sin(a[0]) }},
+	"cos":   {1, 1, func(a []float64) float64 { return math.Cos(a[0]) }},
+	"tan":   {1, 1, func(a []float64) float64 { return math.Tan(a[0]) }},
+	"min": {2, -1, func(a []float64) float64 {
+		v := a[0]
+		for _, x := range a[1:] {
+			v = math.Min(v, x)
+		}
+		return v
+	}},
+	"max": {2, -1, func(a []float64) float64 {
+		v := a[0]
+		for _, x := range a[1:] {
+			v = math.Max(v, x)
+		}
+		return v
+	}},
+}
+
+// calcConstants maps constant names to their values.
+var calcConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// exprParser is a small recursive-descent parser and evaluator for
+// arithmetic expressions. It never executes arbitrary code; the grammar is
+//
+//	expr  := term  (('+'|'-') term)*
+//	term  := unary (('*'|'/'|'%') unary)*
+//	unary := '-' unary | power
+//	power := atom ('^' unary)?
+//	atom  := number | constant | func '(' expr (',' expr)* ')' | '(' expr ')'
+type exprParser struct {
+	input string
+	pos   int
+}
+
+// evaluateExpression parses and evaluates an arithmetic expression.
+func evaluateExpression(input string) (float64, error) {
+	p := &exprParser{input: input}
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0, errAt(0, "empty expression")
+	}
+
+	v, err := p.parseExpr()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return 0, errAt(p.pos, "unexpected character %q", p.input[p.pos])
+	}
+	return v, nil
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *exprParser) parseExpr() (float64, error) {
+	v, err := p.parseTerm()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '+' && p.input[p.pos] != '-') {
+			return v, nil
+		}
+		op := p.input[p.pos]
+		opPos := p.pos
+		p.pos++
+
+		rhs, err := p.parseTerm()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			v += rhs
+		} else {
+			v -= rhs
+		}
+		if err := checkFinite(v, opPos); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (p *exprParser) parseTerm() (float64, error) {
+	v, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) {
+			return v, nil
+		}
+		op := p.input[p.pos]
+		if op != '*' && op != '/' && op != '%' {
+			return v, nil
+		}
+		opPos := p.pos
+		p.pos++
+
+		rhs, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			v *= rhs
+		case '/':
+			if rhs == 0 {
+				return 0, errAt(opPos, "division by zero")
+			}
+			v /= rhs
+		case '%':
+			if rhs == 0 {
+				return 0, errAt(opPos, "division by zero")
+			}
+			v = math.Mod(v, rhs)
+		}
+		if err := checkFinite(v, opPos); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (p *exprParser) parseUnary() (float64, error) {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == '-' {
+		p.pos++
+		v, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		return -v, nil
+	}
+	return p.parsePower()
+}
+
+func (p *exprParser) parsePower() (float64, error) {
+	v, err := p.parseAtom()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.pos >= len(p.input) || p.input[p.pos] != '^' {
+		return v, nil
+	}
+	opPos := p.pos
+	p.pos++
+
+	// Right-associative; parseUnary also permits a negative exponent
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	v = math.Pow(v, exponent)
+	if err := checkFinite(v, opPos); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+func (p *exprParser) parseAtom() (float64, error) {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0, errAt(p.pos, "unexpected end of expression")
+	}
+
+	c := p.input[p.pos]
+	switch {
+	case c == '(':
+		p.pos++
+		v, err := p.parseExpr()
+		if err != nil {
+			return 0, err
+		}
+		p.skipSpace()
+		if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return 0, errAt(p.pos, "missing closing parenthesis")
+		}
+		p.pos++
+		return v, nil
+	case c >= '0' && c <= '9' || c == '.':
+		return p.parseNumber()
+	case isIdentChar(c):
+		return p.parseCall()
+	default:
+		return 0, errAt(p.pos, "unexpected character %q", c)
+	}
+}
+
+// parseNumber scans a numeric literal, including scientific notation.
+func (p *exprParser) parseNumber() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+		p.pos++
+	}
+	// Optional exponent: e or E, optional sign, then digits
+	if p.pos < len(p.input) && (p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+		mark := p.pos
+		p.pos++
+		if p.pos < len(p.input) && (p.input[p.pos] == '+' || p.input[p.pos] == '-') {
+			p.pos++
+		}
+		if p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+			for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+				p.pos++
+			}
+		} else {
+			// Not an exponent after all (e.g. "2*e"); back off
+			p.pos = mark
+		}
+	}
+
+	v, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return 0, errAt(start, "invalid number %q", p.input[start:p.pos])
+	}
+	return v, nil
+}
+
+// parseCall scans an identifier and evaluates it as a constant or a
+// function call.
+func (p *exprParser) parseCall() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.input) && isIdentChar(p.input[p.pos]) {
+		p.pos++
+	}
+	name := strings.ToLower(p.input[start:p.pos])
+
+	if v, ok := calcConstants[name]; ok {
+		return v, nil
+	}
+
+	fn, ok := calcFunctions[name]
+	if !ok {
+		return 0, errAt(start, "unknown function or constant %q", name)
+	}
+
+	p.skipSpace()
+	if p.pos >= len(p.input) || p.input[p.pos] != '(' {
+		return 0, errAt(p.pos, "expected '(' after function %q", name)
+	}
+	p.pos++
+
+	var args []float64
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return 0, err
+		}
+		args = append(args, arg)
+
+		p.skipSpace()
+		if p.pos < len(p.input) && p.input[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+		return 0, errAt(p.pos, "missing closing parenthesis")
+	}
+	p.pos++
+
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		if fn.minArgs == fn.maxArgs {
+			return 0, errAt(start, "%s expects %d argument(s), got %d", name, fn.minArgs, len(args))
+		}
+		return 0, errAt(start, "%s expects at least %d arguments, got %d", name, fn.minArgs, len(args))
+	}
+
+	v := fn.apply(args)
+	if err := checkFinite(v, start); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+// isIdentChar reports whether c can appear in a function or constant name.
+// Digits are allowed (log10) but never start an identifier, since parseAtom
+// dispatches leading digits to parseNumber.
+func isIdentChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
@@ -0,0 +1,172 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"1 + 2", 3},
+		{"2 - 3 - 4", -5},
+		{"2 * 3 + 4", 10},
+		{"2 + 3 * 4", 14},
+		{"(2 + 3) * 4", 20},
+		{"10 / 4", 2.5},
+		{"10 % 3", 1},
+		{"2 ^ 10", 1024},
+		{"2 ^ 3 ^ 2", 512}, // right-associative
+		{"-2 ^ 2", -4},     // unary binds looser than power
+		{"2 ^ -1", 0.5},
+		{"--3", 3},
+		{"-(2 + 3)", -5},
+		{"1.5e3", 1500},
+		{"2.5E-1", 0.25},
+		{".5 + .25", 0.75},
+		{"sqrt(16)", 4},
+		{"abs(-3.5)", 3.5},
+		{"min(3, 1, 2)", 1},
+		{"max(3, 1, 2)", 3},
+		{"round(2.5)", 3},
+		{"floor(2.9)", 2},
+		{"ceil(2.1)", 3},
+		{"log(e)", 1},
+		{"log10(1000)", 3},
+		{"exp(0)", 1},
+		{"sin(0)", 0},
+		{"cos(0)", 1},
+		{"tan(0)", 0},
+		{"pi", math.Pi},
+		{"2 * pi", 2 * math.Pi},
+		{"sqrt(2) ^ 2", 2.0000000000000004},
+		{"min(1 + 2, 2 ^ 2)", 3},
+	}
+
+	for _, tt := range tests {
+		got, err := evaluateExpression(tt.expr)
+		if err != nil {
+			t.Errorf("evaluateExpression(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("evaluateExpression(%q) = %g, want %g", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateExpressionErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantMsg string
+		wantPos int
+	}{
+		{"", "empty expression", 0},
+		{"   ", "empty expression", 0},
+		{"1 +", "unexpected end of expression", 3},
+		{"(1 + 2", "missing closing parenthesis", 6},
+		{"1 / 0", "division by zero", 2},
+		{"1 % 0", "division by zero", 2},
+		{"2 & 3", "unexpected character '&'", 2},
+		{"nope(1)", `unknown function or constant "nope"`, 0},
+		{"sqrt 2", "expected '(' after function", 5},
+		{"sqrt(1, 2)", "sqrt expects 1 argument(s), got 2", 0},
+		{"min(1)", "min expects at least 2 arguments, got 1", 0},
+		{"sqrt(-1)", "not a finite number", 0},
+		{"1e400", `invalid number "1e400"`, 0},
+		{"10 ^ 1000", "not a finite number", 3},
+		{"1..2", `invalid number "1..2"`, 0},
+	}
+
+	for _, tt := range tests {
+		_, err := evaluateExpression(tt.expr)
+		if err == nil {
+			t.Errorf("evaluateExpression(%q) succeeded, want error containing %q", tt.expr, tt.wantMsg)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantMsg) {
+			t.Errorf("evaluateExpression(%q) error = %q, want it to contain %q", tt.expr, err, tt.wantMsg)
+		}
+		var evalErr *evalError
+		if !errors.As(err, &evalErr) {
+			t.Errorf("evaluateExpression(%q) error is not an evalError: %v", tt.expr, err)
+			continue
+		}
+		if evalErr.Pos != tt.wantPos {
+			t.Errorf("evaluateExpression(%q) error position = %d, want %d", tt.expr, evalErr.Pos, tt.wantPos)
+		}
+	}
+}
+
+// TestEvaluateExpressionNeverPanics feeds arbitrary input to the parser;
+// it must return an error or a value, never panic.
+func TestEvaluateExpressionNeverPanics(t *testing.T) {
+	f := func(input string) bool {
+		_, _ = evaluateExpression(input)
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCalculateTool(t *testing.T) {
+	handler := getToolHandler("calculate")
+	if handler == nil {
+		t.Fatal("calculate tool not found in registry")
+	}
+
+	call := func(args map[string]any) *mcplib.CallToolResult {
+		t.Helper()
+		req := mcplib.CallToolRequest{}
+		req.Params.Name = "calculate"
+		req.Params.Arguments = args
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("handler returned error: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"expression": "2 + 2"})
+	if result.IsError {
+		t.Fatalf("expected success, got error result: %v", result.Content)
+	}
+	if text := resultText(t, result); text != "4" {
+		t.Errorf("expected \"4\", got %q", text)
+	}
+
+	result = call(map[string]any{"expression": "10 / 3", "precision": float64(2)})
+	if text := resultText(t, result); text != "3.33" {
+		t.Errorf("expected \"3.33\", got %q", text)
+	}
+
+	result = call(map[string]any{"expression": "1 / 0"})
+	if !result.IsError {
+		t.Fatal("expected a tool error result for division by zero")
+	}
+	if text := resultText(t, result); !strings.Contains(text, "division by zero at position 2") {
+		t.Errorf("expected positioned error, got %q", text)
+	}
+}
+
+// resultText extracts the text from a single-content tool result.
+func resultText(t *testing.T, result *mcplib.CallToolResult) string {
+	t.Helper()
+	if len(result.Content) != 1 {
+		t.Fatalf("expected one content item, got %d", len(result.Content))
+	}
+	text, ok := result.Content[0].(mcplib.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	return text.Text
+}
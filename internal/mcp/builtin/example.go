@@ -10,7 +10,6 @@ import (
 	mcplib "github.com/mark3labs/mcp-go/mcp"
 
 	"gopus/internal/mcp"
-	"gopus/internal/openai"
 )
 
 func init() {
@@ -23,7 +22,7 @@ func init() {
 				mcplib.Description("The message to echo back"),
 			),
 		),
-		func(openaiClient *openai.ChatClient) mcp.ToolHandler {
+		func(deps mcp.Deps) mcp.ToolHandler {
 			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
 				message, err := GetRequiredStringArg(req, "message")
 				if err != nil {
@@ -41,7 +40,7 @@ func init() {
 				mcplib.Description("Time format (optional). Use 'unix' for Unix timestamp, 'iso' for ISO 8601, or a Go time format string. Default: RFC3339"),
 			),
 		),
-		func(openaiClient *openai.ChatClient) mcp.ToolHandler {
+		func(deps mcp.Deps) mcp.ToolHandler {
 			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
 				now := time.Now()
 
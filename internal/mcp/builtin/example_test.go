@@ -18,7 +18,7 @@ func getToolHandler(name string) mcp.ToolHandler {
 	if !ok {
 		return nil
 	}
-	return reg.HandlerFactory(nil) // Pass nil for openaiClient since these tools don't use it
+	return reg.HandlerFactory(mcp.Deps{}) // Empty deps; these tools don't use them
 }
 
 // getToolRegistration retrieves a tool registration by name.
@@ -0,0 +1,176 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"gopus/internal/history"
+	"gopus/internal/mcp"
+)
+
+const (
+	// historyDefaultLimit is the number of search matches returned when the
+	// model does not ask for a specific limit.
+	historyDefaultLimit = 10
+
+	// historyMaxResultChars caps the total size of a tool result so a long
+	// session cannot blow up the context window.
+	historyMaxResultChars = 4000
+
+	// historyMessagePreviewChars caps the content of each rendered message.
+	historyMessagePreviewChars = 300
+)
+
+func init() {
+	mcp.DefaultToolRegistry.Register(
+		mcplib.NewTool("history_search",
+			mcplib.WithDescription("Searches the current session history for messages containing a query string. Matches include archived messages removed by rollbacks. Each live match is prefixed with its message index, usable with history_get"),
+			mcplib.WithString("query",
+				mcplib.Required(),
+				mcplib.Description("Text to search for (case-insensitive)"),
+			),
+			mcplib.WithNumber("limit",
+				mcplib.Description(fmt.Sprintf("Maximum matches to return (default %d)", historyDefaultLimit)),
+			),
+		),
+		func(deps mcp.Deps) mcp.ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				if deps.History == nil || deps.History.Current() == nil {
+					return mcplib.NewToolResultError("session history is not available"), nil
+				}
+				query, err := GetRequiredStringArg(req, "query")
+				if err != nil {
+					return nil, err
+				}
+
+				limit := historyDefaultLimit
+				if args, err := GetArgs(req); err == nil {
+					if n, ok := args["limit"].(float64); ok && n > 0 {
+						limit = int(n)
+					}
+				}
+
+				return searchHistory(deps.History, query, limit), nil
+			}
+		},
+	)
+
+	mcp.DefaultToolRegistry.Register(
+		mcplib.NewTool("history_get",
+			mcplib.WithDescription("Returns messages from the current session history by index range, as reported by history_search"),
+			mcplib.WithNumber("start",
+				mcplib.Required(),
+				mcplib.Description("Index of the first message to return"),
+			),
+			mcplib.WithNumber("end",
+				mcplib.Description("Index of the last message to return (default: same as start)"),
+			),
+		),
+		func(deps mcp.Deps) mcp.ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				if deps.History == nil || deps.History.Current() == nil {
+					return mcplib.NewToolResultError("session history is not available"), nil
+				}
+				args, err := GetArgs(req)
+				if err != nil {
+					return nil, err
+				}
+				startArg, ok := args["start"].(float64)
+				if !ok {
+					return nil, fmt.Errorf("start argument is required and must be a number")
+				}
+				start := int(startArg)
+				end := start
+				if endArg, ok := args["end"].(float64); ok {
+					end = int(endArg)
+				}
+
+				return getHistoryRange(deps.History, start, end), nil
+			}
+		},
+	)
+}
+
+// searchHistory finds up to limit messages containing query, scanning the
+// live session first and then the rollback archive.
+func searchHistory(manager *history.Manager, query string, limit int) *mcplib.CallToolResult {
+	needle := strings.ToLower(query)
+	var lines []string
+
+	for i, msg := range manager.Current().Messages {
+		if len(lines) >= limit {
+			break
+		}
+		if strings.Contains(strings.ToLower(msg.Content), needle) {
+			lines = append(lines, formatHistoryMessage(fmt.Sprintf("[%d]", i), msg))
+		}
+	}
+	for _, msg := range manager.ArchivedMessages() {
+		if len(lines) >= limit {
+			break
+		}
+		if strings.Contains(strings.ToLower(msg.Content), needle) {
+			lines = append(lines, formatHistoryMessage("[archived]", msg))
+		}
+	}
+
+	if len(lines) == 0 {
+		return mcplib.NewToolResultText(fmt.Sprintf("No messages matching %q.", query))
+	}
+	return mcplib.NewToolResultText(capResult(lines))
+}
+
+// getHistoryRange renders the live messages with indices start through end.
+func getHistoryRange(manager *history.Manager, start, end int) *mcplib.CallToolResult {
+	messages := manager.Current().Messages
+	if start < 0 || start >= len(messages) {
+		return mcplib.NewToolResultError(fmt.Sprintf("start index %d out of range (session has %d messages)", start, len(messages)))
+	}
+	if end < start {
+		return mcplib.NewToolResultError(fmt.Sprintf("end index %d is before start index %d", end, start))
+	}
+	if end >= len(messages) {
+		end = len(messages) - 1
+	}
+
+	lines := make([]string, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		lines = append(lines, formatHistoryMessage(fmt.Sprintf("[%d]", i), messages[i]))
+	}
+	return mcplib.NewToolResultText(capResult(lines))
+}
+
+// formatHistoryMessage renders one message with its index marker, role
+// label, and timestamp when recorded.
+func formatHistoryMessage(marker string, msg history.Message) string {
+	label := msg.RoleLabel()
+	timestamp := ""
+	if !msg.CreatedAt.IsZero() {
+		timestamp = " (" + msg.CreatedAt.Format("2006-01-02 15:04") + ")"
+	}
+
+	content := msg.Content
+	if runes := []rune(content); len(runes) > historyMessagePreviewChars {
+		content = string(runes[:historyMessagePreviewChars]) + "…"
+	}
+	return fmt.Sprintf("%s %s%s: %s", marker, label, timestamp, content)
+}
+
+// capResult joins lines, truncating once the total exceeds the result cap.
+func capResult(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		if b.Len()+len(line) > historyMaxResultChars {
+			fmt.Fprintf(&b, "… %d more result(s) omitted", len(lines)-i)
+			break
+		}
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
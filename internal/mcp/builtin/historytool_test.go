@@ -0,0 +1,112 @@
+package builtin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"gopus/internal/history"
+	"gopus/internal/mcp"
+)
+
+// historyToolDeps returns deps with a session containing a few messages.
+func historyToolDeps(t *testing.T) mcp.Deps {
+	t.Helper()
+	manager, err := history.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	manager.NewSession()
+	for _, msg := range []struct {
+		role    history.Role
+		content string
+	}{
+		{history.RoleUser, "let's use PostgreSQL for storage"},
+		{history.RoleAssistant, "PostgreSQL works; I suggest version 16"},
+		{history.RoleUser, "what about caching?"},
+		{history.RoleAssistant, "Redis is the usual choice"},
+	} {
+		if err := manager.AddMessage(msg.role, msg.content); err != nil {
+			t.Fatalf("AddMessage: %v", err)
+		}
+	}
+	return mcp.Deps{History: manager}
+}
+
+// callHistoryTool invokes a registered history tool with the given arguments.
+func callHistoryTool(t *testing.T, deps mcp.Deps, name string, args map[string]any) *mcplib.CallToolResult {
+	t.Helper()
+	reg, ok := mcp.DefaultToolRegistry.Get(name)
+	if !ok {
+		t.Fatalf("%s tool not found in registry", name)
+	}
+	req := mcplib.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	result, err := reg.HandlerFactory(deps)(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	return result
+}
+
+func TestHistorySearch(t *testing.T) {
+	deps := historyToolDeps(t)
+
+	result := callHistoryTool(t, deps, "history_search", map[string]any{"query": "postgresql"})
+	text := resultText(t, result)
+
+	if !strings.Contains(text, "[0] user") || !strings.Contains(text, "[1] assistant") {
+		t.Errorf("expected indexed matches with role labels, got %q", text)
+	}
+	if strings.Contains(text, "Redis") {
+		t.Errorf("expected non-matching messages excluded, got %q", text)
+	}
+
+	result = callHistoryTool(t, deps, "history_search", map[string]any{"query": "kubernetes"})
+	if text := resultText(t, result); !strings.Contains(text, "No messages matching") {
+		t.Errorf("expected no-match message, got %q", text)
+	}
+}
+
+func TestHistorySearchIncludesArchive(t *testing.T) {
+	deps := historyToolDeps(t)
+	if _, err := deps.History.RollbackTo(2); err != nil {
+		t.Fatalf("RollbackTo: %v", err)
+	}
+
+	result := callHistoryTool(t, deps, "history_search", map[string]any{"query": "redis"})
+	if text := resultText(t, result); !strings.Contains(text, "[archived]") {
+		t.Errorf("expected archived match, got %q", text)
+	}
+}
+
+func TestHistoryGetRange(t *testing.T) {
+	deps := historyToolDeps(t)
+
+	result := callHistoryTool(t, deps, "history_get", map[string]any{
+		"start": float64(1), "end": float64(2),
+	})
+	text := resultText(t, result)
+
+	if !strings.Contains(text, "[1] assistant") || !strings.Contains(text, "[2] user") {
+		t.Errorf("expected messages 1-2, got %q", text)
+	}
+	if strings.Contains(text, "[0]") || strings.Contains(text, "[3]") {
+		t.Errorf("expected range respected, got %q", text)
+	}
+
+	result = callHistoryTool(t, deps, "history_get", map[string]any{"start": float64(99)})
+	if !result.IsError {
+		t.Error("expected error result for out-of-range index")
+	}
+}
+
+func TestHistoryToolsWithoutManager(t *testing.T) {
+	result := callHistoryTool(t, mcp.Deps{}, "history_search", map[string]any{"query": "x"})
+	if !result.IsError {
+		t.Error("expected error result when history is unavailable")
+	}
+}
@@ -0,0 +1,37 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"gopus/internal/mcp"
+)
+
+// TestBuiltinServerExposesAllRegisteredTools verifies that every tool in
+// the DefaultToolRegistry actually reaches Manager.ListTools when the
+// builtin server is added — registration drift (a tool registered but never
+// wired through Setup) fails here instead of surfacing as a missing tool at
+// runtime.
+func TestBuiltinServerExposesAllRegisteredTools(t *testing.T) {
+	m := mcp.NewManager()
+	defer m.Close()
+
+	if err := m.AddBuiltinServer(context.Background(), &mcp.BuiltinServer{}, mcp.Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+
+	listed := make(map[string]bool)
+	for _, tool := range m.ListTools() {
+		listed[tool.Name] = true
+	}
+
+	for _, name := range mcp.DefaultToolRegistry.Names() {
+		if !listed[name] {
+			t.Errorf("registered tool %q is not exposed by the builtin server", name)
+		}
+	}
+
+	if got, want := len(listed), mcp.DefaultToolRegistry.Count(); got != want {
+		t.Errorf("expected %d exposed tools, got %d", want, got)
+	}
+}
@@ -0,0 +1,56 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"gopus/internal/config"
+	"gopus/internal/mcp"
+)
+
+// startupSummary runs StartBuiltinServers against the real registry with
+// the given enable/disable lists.
+func startupSummary(t *testing.T, enabled, disabled []string) mcp.BuiltinStartSummary {
+	t.Helper()
+	m := mcp.NewManager()
+	t.Cleanup(func() { m.Close() })
+
+	cfg := config.BuiltinConfig{Enabled: enabled, Disabled: disabled}
+	return mcp.StartBuiltinServers(context.Background(), m, cfg, mcp.Deps{})
+}
+
+func TestStartBuiltinServersEmptyListsStartAll(t *testing.T) {
+	summary := startupSummary(t, nil, nil)
+	if len(summary.Started) != 1 || summary.Started[0] != "builtin" {
+		t.Errorf("expected the builtin server to start with empty lists, got %+v", summary)
+	}
+	if len(summary.Disabled) != 0 || len(summary.Unknown) != 0 {
+		t.Errorf("expected nothing disabled or unknown, got %+v", summary)
+	}
+}
+
+func TestStartBuiltinServersEnabledOnly(t *testing.T) {
+	summary := startupSummary(t, []string{"builtin"}, nil)
+	if len(summary.Started) != 1 {
+		t.Errorf("expected an explicitly enabled server to start, got %+v", summary)
+	}
+
+	// An Enabled list that doesn't mention the server excludes it
+	summary = startupSummary(t, []string{"other"}, nil)
+	if len(summary.Started) != 0 {
+		t.Errorf("expected no servers with a non-matching enabled list, got %+v", summary)
+	}
+	if len(summary.Unknown) != 1 || summary.Unknown[0] != "other" {
+		t.Errorf("expected %q reported as unknown, got %+v", "other", summary)
+	}
+}
+
+func TestStartBuiltinServersDisabledOverridesEnabled(t *testing.T) {
+	summary := startupSummary(t, []string{"builtin"}, []string{"builtin"})
+	if len(summary.Started) != 0 {
+		t.Errorf("expected disabled to win over enabled, got %+v", summary)
+	}
+	if len(summary.Disabled) != 1 || summary.Disabled[0] != "builtin" {
+		t.Errorf("expected the builtin server reported as disabled, got %+v", summary)
+	}
+}
@@ -25,8 +25,8 @@ func init() {
 				mcplib.Description("The search query"),
 			),
 		),
-		func(openaiClient *openai.ChatClient) mcp.ToolHandler {
-			return wikipediaToolHandler(openaiClient)
+		func(deps mcp.Deps) mcp.ToolHandler {
+			return wikipediaToolHandler(deps.OpenAI)
 		},
 	)
 }
@@ -171,11 +171,11 @@ func generateSummary(ctx context.Context, client *openai.ChatClient, title, cont
 	messages := []openai.ChatCompletionRequestMessage{
 		{
 			Role:    openai.RoleSystem,
-			Content: &systemPrompt,
+			Content: openai.StringContent(systemPrompt),
 		},
 		{
 			Role:    openai.RoleUser,
-			Content: &userPrompt,
+			Content: openai.StringContent(userPrompt),
 		},
 	}
 
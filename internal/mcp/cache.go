@@ -0,0 +1,179 @@
+package mcp
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxToolCacheEntries bounds the result cache so a long session cannot grow
+// it without limit.
+const maxToolCacheEntries = 128
+
+// cacheEntry is one cached tool result with its expiry time.
+type cacheEntry struct {
+	key     string
+	result  *mcplib.CallToolResult
+	expires time.Time
+}
+
+// toolCache is a small LRU cache for tool results. It does no locking of
+// its own; the Manager guards it with its mutex.
+type toolCache struct {
+	maxEntries int
+	order      *list.List               // front = most recently used
+	entries    map[string]*list.Element // cache key -> element in order
+}
+
+// newToolCache creates an empty cache holding at most maxEntries results.
+func newToolCache(maxEntries int) *toolCache {
+	return &toolCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// get returns the unexpired cached result for key, refreshing its recency.
+// Expired entries are dropped on access.
+func (c *toolCache) get(key string, now time.Time) (*mcplib.CallToolResult, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if now.After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.result, true
+}
+
+// put stores a result, evicting the least recently used entry when full.
+func (c *toolCache) put(key string, result *mcplib.CallToolResult, expires time.Time) {
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.result = result
+		entry.expires = expires
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, result: result, expires: expires})
+	if c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// clear drops all entries.
+func (c *toolCache) clear() {
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+// toolCacheKey builds the cache key from the tool name and the canonical
+// JSON encoding of the arguments (encoding/json sorts map keys). The second
+// return is false when the arguments cannot be encoded.
+func toolCacheKey(name string, args map[string]any) (string, bool) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	return name + "\x00" + string(data), true
+}
+
+// SetToolCacheTTL enables result caching for a tool. Results of tools
+// without a configured TTL are never cached; a non-positive TTL disables
+// caching for the tool again.
+func (m *Manager) SetToolCacheTTL(name string, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ttl <= 0 {
+		delete(m.cacheTTL, name)
+		return
+	}
+	m.cacheTTL[name] = ttl
+}
+
+// InvalidateCache drops all cached tool results.
+func (m *Manager) InvalidateCache() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cache.clear()
+}
+
+// CallToolCached is CallTool plus a flag reporting whether the result came
+// from the result cache. Only successful, non-error results of tools with a
+// configured TTL are cached.
+func (m *Manager) CallToolCached(ctx context.Context, name string, arguments map[string]any) (*mcplib.CallToolResult, bool, error) {
+	m.mu.RLock()
+	info, ok := m.tools[name]
+	ttl := m.cacheTTL[name]
+	validate := !m.validationDisabled
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, false, fmt.Errorf("tool not found: %s", name)
+	}
+
+	if validate {
+		if problems := ValidateToolArgs(info.Tool.InputSchema, arguments); len(problems) > 0 {
+			// Skip execution and hand the problems back as a tool-level
+			// error, so the model can correct itself on the next turn
+			// instead of the handler failing cryptically.
+			return mcplib.NewToolResultError(
+				fmt.Sprintf("invalid arguments for %s: %s", name, strings.Join(problems, "; ")),
+			), false, nil
+		}
+	}
+
+	key := ""
+	if ttl > 0 {
+		if k, ok := toolCacheKey(name, arguments); ok {
+			key = k
+			m.mu.Lock()
+			result, hit := m.cache.get(key, time.Now())
+			m.mu.Unlock()
+			if hit {
+				return result, true, nil
+			}
+		}
+	}
+
+	// Build the call request, with a progress token so the server's
+	// progress notifications can be routed to the display
+	callRequest := mcplib.CallToolRequest{}
+	callRequest.Params.Name = name
+	callRequest.Params.Arguments = arguments
+	token := m.registerProgressToken(name)
+	defer m.releaseProgressToken(token)
+	callRequest.Params.Meta = &mcplib.Meta{ProgressToken: token}
+
+	m.beginToolCall(info.ServerID)
+	result, err := info.Client.CallTool(ctx, callRequest)
+	m.endToolCall(info.ServerID)
+	if err != nil {
+		// A transport-level failure means the whole server is suspect;
+		// tool-level errors come back in the result and don't count.
+		m.MarkServerUnhealthy(info.ServerID, err.Error())
+		return nil, false, fmt.Errorf("failed to call tool %s: %w", name, err)
+	}
+	m.markServerHealthy(info.ServerID)
+
+	if key != "" && !result.IsError {
+		m.mu.Lock()
+		m.cache.put(key, result, time.Now().Add(ttl))
+		m.mu.Unlock()
+	}
+
+	return result, false, nil
+}
@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// countedTestTool registers a builtin tool that counts its invocations and
+// echoes its "q" argument.
+func countedTestTool(name string) *atomic.Int32 {
+	var calls atomic.Int32
+	DefaultToolRegistry.Register(
+		mcplib.NewTool(name, mcplib.WithString("q")),
+		func(deps Deps) ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				calls.Add(1)
+				args, _ := req.Params.Arguments.(map[string]any)
+				q, _ := args["q"].(string)
+				return mcplib.NewToolResultText(q), nil
+			}
+		},
+	)
+	return &calls
+}
+
+func TestCallToolCachedServesRepeatsFromCache(t *testing.T) {
+	ctx := context.Background()
+	calls := countedTestTool("cached_test")
+	m := NewManager()
+	if err := m.AddBuiltinServer(ctx, &BuiltinServer{}, Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+	m.SetToolCacheTTL("cached_test", time.Minute)
+
+	args := map[string]any{"q": "golang"}
+	if _, cached, err := m.CallToolCached(ctx, "cached_test", args); err != nil || cached {
+		t.Fatalf("first call: cached=%v, err=%v", cached, err)
+	}
+	if _, cached, err := m.CallToolCached(ctx, "cached_test", args); err != nil || !cached {
+		t.Errorf("second call: expected cache hit, got cached=%v, err=%v", cached, err)
+	}
+	if _, cached, _ := m.CallToolCached(ctx, "cached_test", map[string]any{"q": "other"}); cached {
+		t.Error("different arguments must not hit the cache")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 real invocations, got %d", got)
+	}
+
+	m.InvalidateCache()
+	if _, cached, _ := m.CallToolCached(ctx, "cached_test", args); cached {
+		t.Error("expected cache miss after InvalidateCache")
+	}
+}
+
+func TestCallToolCachedRespectsTTL(t *testing.T) {
+	ctx := context.Background()
+	calls := countedTestTool("ttl_test")
+	m := NewManager()
+	if err := m.AddBuiltinServer(ctx, &BuiltinServer{}, Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+	m.SetToolCacheTTL("ttl_test", 20*time.Millisecond)
+
+	args := map[string]any{"q": "x"}
+	m.CallToolCached(ctx, "ttl_test", args)
+	time.Sleep(30 * time.Millisecond)
+	if _, cached, _ := m.CallToolCached(ctx, "ttl_test", args); cached {
+		t.Error("expected expired entry to miss")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 real invocations after expiry, got %d", got)
+	}
+}
+
+func TestCallToolCachedSkipsToolsWithoutTTL(t *testing.T) {
+	ctx := context.Background()
+	calls := countedTestTool("uncached_test")
+	m := NewManager()
+	if err := m.AddBuiltinServer(ctx, &BuiltinServer{}, Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+
+	args := map[string]any{"q": "x"}
+	m.CallToolCached(ctx, "uncached_test", args)
+	if _, cached, _ := m.CallToolCached(ctx, "uncached_test", args); cached {
+		t.Error("tools without a TTL must never be cached")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 real invocations, got %d", got)
+	}
+}
+
+func TestToolCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newToolCache(2)
+	expires := time.Now().Add(time.Minute)
+	result := func(s string) *mcplib.CallToolResult { return mcplib.NewToolResultText(s) }
+
+	c.put("a", result("a"), expires)
+	c.put("b", result("b"), expires)
+
+	// Touch "a" so "b" becomes the eviction candidate
+	if _, ok := c.get("a", time.Now()); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	c.put("c", result("c"), expires)
+
+	if _, ok := c.get("b", time.Now()); ok {
+		t.Error("expected least recently used entry b evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.get(key, time.Now()); !ok {
+			t.Errorf("expected %s retained", key)
+		}
+	}
+	if len(c.entries) != 2 {
+		t.Errorf("expected cache bounded at 2 entries, got %d", len(c.entries))
+	}
+}
+
+func TestToolCacheKeyIsCanonical(t *testing.T) {
+	// Map iteration order must not influence the key
+	k1, ok1 := toolCacheKey("t", map[string]any{"a": 1, "b": 2, "c": 3})
+	k2, ok2 := toolCacheKey("t", map[string]any{"c": 3, "b": 2, "a": 1})
+	if !ok1 || !ok2 || k1 != k2 {
+		t.Errorf("expected identical keys for equal arguments, got %q vs %q", k1, k2)
+	}
+
+	if k3, _ := toolCacheKey("t", map[string]any{"a": 2}); k3 == k1 {
+		t.Error("expected different arguments to produce different keys")
+	}
+
+	if _, ok := toolCacheKey("t", map[string]any{"f": func() {}}); ok {
+		t.Error("expected unencodable arguments to be rejected")
+	}
+}
@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// RenderToolCatalogMarkdown renders documentation for the given tools as
+// Markdown: a table of contents linking to one section per tool, each with
+// the owning server, the description, and the input schema rendered as a
+// parameter table. policy is the configured tool confirmation setting.
+func RenderToolCatalogMarkdown(infos []ToolInfo, policy string) string {
+	var b strings.Builder
+
+	b.WriteString("# Tool Catalog\n\n")
+	fmt.Fprintf(&b, "%d tool(s) available. Tool confirmation policy: `%s`.\n\n", len(infos), policy)
+
+	// Table of contents
+	for _, info := range infos {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", info.Tool.Name, markdownAnchor(info.Tool.Name))
+	}
+	b.WriteString("\n")
+
+	for _, info := range infos {
+		fmt.Fprintf(&b, "## %s\n\n", info.Tool.Name)
+		fmt.Fprintf(&b, "**Server:** %s\n\n", info.ServerID)
+		if info.Tool.Description != "" {
+			b.WriteString(info.Tool.Description)
+			b.WriteString("\n\n")
+		}
+		b.WriteString(renderParameterTable(info))
+	}
+
+	return b.String()
+}
+
+// markdownAnchor converts a tool name to a GitHub-style heading anchor.
+func markdownAnchor(name string) string {
+	anchor := strings.ToLower(name)
+	anchor = strings.ReplaceAll(anchor, " ", "-")
+	return anchor
+}
+
+// renderParameterTable renders a tool's input schema as a Markdown table.
+// Returns a placeholder line for tools without parameters.
+func renderParameterTable(info ToolInfo) string {
+	props := info.Tool.InputSchema.Properties
+	if len(props) == 0 {
+		return "_No parameters._\n\n"
+	}
+
+	required := make(map[string]bool, len(info.Tool.InputSchema.Required))
+	for _, name := range info.Tool.InputSchema.Required {
+		required[name] = true
+	}
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("| Parameter | Type | Required | Description |\n")
+	b.WriteString("|-----------|------|----------|-------------|\n")
+	for _, name := range names {
+		typ, desc := schemaField(props[name], "type"), schemaField(props[name], "description")
+		req := "no"
+		if required[name] {
+			req = "yes"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", name, typ, req, desc)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// schemaField extracts a string field from a JSON schema property value.
+func schemaField(prop any, field string) string {
+	m, ok := prop.(map[string]any)
+	if !ok {
+		return ""
+	}
+	s, _ := m[field].(string)
+	return s
+}
+
+// RenderToolSchemaText renders a tool's input schema for terminal display:
+// one line per property with its type and required flag, the description
+// indented below, and object members expanded one level deep. Tools with a
+// missing or empty schema yield a placeholder line.
+func RenderToolSchemaText(tool mcplib.Tool) string {
+	props := tool.InputSchema.Properties
+	if len(props) == 0 {
+		return "No parameters.\n"
+	}
+
+	required := make(map[string]bool, len(tool.InputSchema.Required))
+	for _, name := range tool.InputSchema.Required {
+		required[name] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("Parameters:\n")
+	renderSchemaProperties(&b, props, required, "  ", 0)
+	return b.String()
+}
+
+// renderSchemaProperties writes one line per property in name order, plus
+// an indented description. Object properties are expanded one more level;
+// deeper nesting is cut off to keep the output readable.
+func renderSchemaProperties(b *strings.Builder, props map[string]any, required map[string]bool, indent string, depth int) {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop := props[name]
+		fmt.Fprintf(b, "%s%s (%s)\n", indent, name, schemaTypeLabel(prop, required[name]))
+		if desc := schemaField(prop, "description"); desc != "" {
+			fmt.Fprintf(b, "%s    %s\n", indent, desc)
+		}
+
+		if depth >= 1 {
+			continue
+		}
+		if m, ok := prop.(map[string]any); ok && schemaField(prop, "type") == "object" {
+			if sub, ok := m["properties"].(map[string]any); ok && len(sub) > 0 {
+				renderSchemaProperties(b, sub, schemaRequiredSet(m), indent+"  ", depth+1)
+			}
+		}
+	}
+}
+
+// schemaTypeLabel describes a property's type, unwrapping array item types
+// and marking required properties.
+func schemaTypeLabel(prop any, required bool) string {
+	typ := schemaField(prop, "type")
+	if typ == "" {
+		typ = "any"
+	}
+	if typ == "array" {
+		if m, ok := prop.(map[string]any); ok {
+			if itemType := schemaField(m["items"], "type"); itemType != "" {
+				typ = "array of " + itemType
+			}
+		}
+	}
+	if required {
+		typ += ", required"
+	}
+	return typ
+}
+
+// schemaRequiredSet extracts the required-name set of a nested schema.
+func schemaRequiredSet(schema map[string]any) map[string]bool {
+	required := make(map[string]bool)
+	list, ok := schema["required"].([]any)
+	if !ok {
+		return required
+	}
+	for _, entry := range list {
+		if name, ok := entry.(string); ok {
+			required[name] = true
+		}
+	}
+	return required
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+func catalogToolInfo(name, server, description string) ToolInfo {
+	return ToolInfo{
+		ServerID: server,
+		Tool: mcplib.Tool{
+			Name:        name,
+			Description: description,
+			InputSchema: mcplib.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]any{
+					"query": map[string]any{
+						"type":        "string",
+						"description": "Search terms",
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum results",
+					},
+				},
+				Required: []string{"query"},
+			},
+		},
+	}
+}
+
+func TestRenderToolCatalogMarkdown(t *testing.T) {
+	infos := []ToolInfo{
+		catalogToolInfo("search_wiki", "builtin", "Search Wikipedia articles."),
+	}
+
+	got := RenderToolCatalogMarkdown(infos, "ask")
+
+	for _, want := range []string{
+		"# Tool Catalog",
+		"- [search_wiki](#search_wiki)",
+		"## search_wiki",
+		"**Server:** builtin",
+		"Search Wikipedia articles.",
+		"| query | string | yes | Search terms |",
+		"| limit | integer | no | Maximum results |",
+		"Tool confirmation policy: `ask`",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderToolCatalogMarkdownNoParameters(t *testing.T) {
+	infos := []ToolInfo{
+		{ServerID: "builtin", Tool: mcplib.Tool{Name: "ping"}},
+	}
+
+	got := RenderToolCatalogMarkdown(infos, "never")
+
+	if !strings.Contains(got, "_No parameters._") {
+		t.Errorf("expected placeholder for parameterless tool, got:\n%s", got)
+	}
+}
+
+func TestRenderToolSchemaText(t *testing.T) {
+	tool := mcplib.Tool{
+		Name: "write_report",
+		InputSchema: mcplib.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"title": map[string]any{
+					"type":        "string",
+					"description": "Report title",
+				},
+				"tags": map[string]any{
+					"type":  "array",
+					"items": map[string]any{"type": "string"},
+				},
+				"options": map[string]any{
+					"type":        "object",
+					"description": "Formatting options",
+					"properties": map[string]any{
+						"wrap": map[string]any{
+							"type":        "integer",
+							"description": "Wrap column",
+						},
+					},
+					"required": []any{"wrap"},
+				},
+			},
+			Required: []string{"title"},
+		},
+	}
+
+	got := RenderToolSchemaText(tool)
+
+	for _, want := range []string{
+		"Parameters:\n",
+		"title (string, required)",
+		"Report title",
+		"tags (array of string)",
+		"options (object)",
+		"  wrap (integer, required)",
+		"Wrap column",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderToolSchemaTextEmptySchema(t *testing.T) {
+	got := RenderToolSchemaText(mcplib.Tool{Name: "bare"})
+	if got != "No parameters.\n" {
+		t.Errorf("expected a placeholder for an empty schema, got %q", got)
+	}
+}
@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"strings"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// FormatContent renders tool result content as plain text, one part per
+// line. Non-text parts are replaced with a short placeholder so the model
+// (and logs) see that something was there without the raw bytes.
+func FormatContent(content []mcplib.Content) string {
+	var parts []string
+	for _, item := range content {
+		switch c := item.(type) {
+		case mcplib.TextContent:
+			parts = append(parts, c.Text)
+		case *mcplib.TextContent:
+			parts = append(parts, c.Text)
+		case mcplib.ImageContent, *mcplib.ImageContent:
+			parts = append(parts, "[image content]")
+		case mcplib.AudioContent, *mcplib.AudioContent:
+			parts = append(parts, "[audio content]")
+		default:
+			parts = append(parts, "[unknown content]")
+		}
+	}
+	return strings.Join(parts, "\n")
+}
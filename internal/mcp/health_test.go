@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// addGhostTool registers a tool for a server that has no client, standing in
+// for an external server whose process has died.
+func addGhostTool(m *Manager, tool, server string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tools[tool] = ToolInfo{Tool: mcplib.Tool{Name: tool}, ServerID: server}
+}
+
+func TestListHealthyToolsHidesAndRestoresFlappingServer(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager()
+	addGhostTool(m, "search", "github")
+	addGhostTool(m, "create_issue", "github")
+	addGhostTool(m, "read_file", "files")
+
+	// Turn 1: everything healthy
+	tools, hidden := m.ListHealthyTools(ctx)
+	if len(tools) != 3 || len(hidden) != 0 {
+		t.Fatalf("expected 3 tools and nothing hidden, got %d tools, hidden %v", len(tools), hidden)
+	}
+
+	// Turn 2: github goes down; its tools disappear, others stay
+	m.MarkServerUnhealthy("github", "connection refused")
+	tools, hidden = m.ListHealthyTools(ctx)
+	if len(tools) != 1 || tools[0].Name != "read_file" {
+		t.Errorf("expected only read_file advertised, got %v", tools)
+	}
+	if hidden["github"] != 2 {
+		t.Errorf("expected 2 github tools hidden, got %v", hidden)
+	}
+
+	// Turn 3: github recovers; its tools are re-included automatically
+	m.markServerHealthy("github")
+	tools, hidden = m.ListHealthyTools(ctx)
+	if len(tools) != 3 || len(hidden) != 0 {
+		t.Errorf("expected all tools restored, got %d tools, hidden %v", len(tools), hidden)
+	}
+}
+
+func TestListHealthyToolsPingRecoversBuiltinServer(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager()
+	if err := m.AddBuiltinServer(ctx, &BuiltinServer{}, Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+	total := m.ToolCount()
+
+	// The in-process server answers the probe ping, so a stale unhealthy
+	// mark clears itself on the next listing.
+	m.MarkServerUnhealthy("builtin", "transient failure")
+	tools, hidden := m.ListHealthyTools(ctx)
+	if len(tools) != total || len(hidden) != 0 {
+		t.Errorf("expected builtin server to recover via ping, got %d of %d tools, hidden %v",
+			len(tools), total, hidden)
+	}
+}
+
+func TestRemoveServerClearsUnhealthyMark(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager()
+	if err := m.AddBuiltinServer(ctx, &BuiltinServer{}, Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+
+	m.MarkServerUnhealthy("builtin", "down")
+	if err := m.RemoveServer("builtin"); err != nil {
+		t.Fatalf("RemoveServer: %v", err)
+	}
+	if servers := m.UnhealthyServers(); len(servers) != 0 {
+		t.Errorf("expected no unhealthy marks after removal, got %v", servers)
+	}
+}
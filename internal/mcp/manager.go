@@ -7,21 +7,31 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
+	"os/exec"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/client/transport"
 	mcplib "github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
-	"gopus/internal/openai"
+	"gopus/internal/config"
+	"gopus/internal/version"
 )
 
 // DebugTransport wraps a transport.Interface to log JSON-RPC messages.
+// Messages go through a log.Logger rather than raw stderr writes, so each
+// entry is written atomically and cannot splice into terminal animations
+// or other concurrent output.
 type DebugTransport struct {
 	inner    transport.Interface
 	serverID string
+	log      *log.Logger
 }
 
 // NewDebugTransport creates a new debug transport wrapper.
@@ -29,6 +39,7 @@ func NewDebugTransport(inner transport.Interface, serverID string) *DebugTranspo
 	return &DebugTransport{
 		inner:    inner,
 		serverID: serverID,
+		log:      log.New(os.Stderr, "", log.LstdFlags),
 	}
 }
 
@@ -52,7 +63,7 @@ func (d *DebugTransport) SetNotificationHandler(handler func(notification mcplib
 	// Wrap the handler to log notifications
 	d.inner.SetNotificationHandler(func(notification mcplib.JSONRPCNotification) {
 		if data, err := json.Marshal(notification); err == nil {
-			fmt.Fprintf(os.Stderr, "[MCP:%s] <- NOTIFICATION: %s\n", d.serverID, string(data))
+			d.log.Printf("[MCP:%s] <- NOTIFICATION: %s", d.serverID, string(data))
 		}
 		if handler != nil {
 			handler(notification)
@@ -63,18 +74,18 @@ func (d *DebugTransport) SetNotificationHandler(handler func(notification mcplib
 // SendRequest sends a request and logs it along with the response.
 func (d *DebugTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
 	if data, err := json.Marshal(request); err == nil {
-		fmt.Fprintf(os.Stderr, "[MCP:%s] -> REQUEST: %s\n", d.serverID, string(data))
+		d.log.Printf("[MCP:%s] -> REQUEST: %s", d.serverID, string(data))
 	}
 
 	resp, err := d.inner.SendRequest(ctx, request)
 
 	if resp != nil {
 		if data, err := json.Marshal(resp); err == nil {
-			fmt.Fprintf(os.Stderr, "[MCP:%s] <- RESPONSE: %s\n", d.serverID, string(data))
+			d.log.Printf("[MCP:%s] <- RESPONSE: %s", d.serverID, string(data))
 		}
 	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "[MCP:%s] <- ERROR: %v\n", d.serverID, err)
+		d.log.Printf("[MCP:%s] <- ERROR: %v", d.serverID, err)
 	}
 
 	return resp, err
@@ -83,7 +94,7 @@ func (d *DebugTransport) SendRequest(ctx context.Context, request transport.JSON
 // SendNotification sends a notification and logs it.
 func (d *DebugTransport) SendNotification(ctx context.Context, notification mcplib.JSONRPCNotification) error {
 	if data, err := json.Marshal(notification); err == nil {
-		fmt.Fprintf(os.Stderr, "[MCP:%s] -> NOTIFICATION: %s\n", d.serverID, string(data))
+		d.log.Printf("[MCP:%s] -> NOTIFICATION: %s", d.serverID, string(data))
 	}
 	return d.inner.SendNotification(ctx, notification)
 }
@@ -95,6 +106,15 @@ type ToolInfo struct {
 	Client   *client.Client
 }
 
+// serverSpec remembers how a stdio server was launched so it can be
+// restarted on request.
+type serverSpec struct {
+	command string
+	env     []string
+	workDir string
+	args    []string
+}
+
 // Manager manages multiple MCP server connections.
 type Manager struct {
 	mu             sync.RWMutex
@@ -102,6 +122,32 @@ type Manager struct {
 	tools          map[string]ToolInfo          // tool name -> tool info
 	debug          bool                         // Enable debug logging for JSON-RPC messages
 	builtinServers map[string]*server.MCPServer // Track in-process servers for cleanup
+	unhealthy      map[string]string            // server ID -> reason it is unavailable
+	cacheTTL       map[string]time.Duration     // tool name -> result cache TTL; absent = never cached
+	cache          *toolCache                   // bounded LRU of recent tool results
+	serverSpecs    map[string]serverSpec        // stdio launch specs for restarts
+	activeCalls    map[string]int               // server ID -> tool calls in flight
+	serverTypes    map[string]string            // server ID -> transport type (stdio/builtin)
+	initResults    map[string]*mcplib.InitializeResult
+	startupErrors  map[string]string             // server ID -> error for servers that failed to start
+	procCancels    map[string]context.CancelFunc // kills a stdio server's subprocess
+	procs          map[string]*exec.Cmd          // server ID -> stdio subprocess
+	shutdownGrace  time.Duration                 // per-stage wait before escalating shutdown
+
+	validationDisabled bool // skip schema validation of tool arguments
+
+	// Notification state is guarded by its own mutex because handlers run on
+	// the client's reader goroutine, which must not block on m.mu while a
+	// request (e.g. Initialize) is in flight.
+	notifMu       sync.Mutex
+	pendingNotifs map[string][]mcplib.JSONRPCNotification // queued until the server is ready
+	notifReady    map[string]bool                         // server ID -> initialization finished
+	serverLogs    map[string][]string                     // server ID -> recent notification log
+
+	// Progress routing for in-flight tool calls, guarded by notifMu.
+	progressHandler func(ProgressUpdate)
+	progressTokens  map[string]string // progress token -> tool name
+	progressSeq     int               // source of unique progress tokens
 }
 
 // NewManager creates a new MCP manager.
@@ -110,21 +156,81 @@ func NewManager() *Manager {
 		clients:        make(map[string]*client.Client),
 		tools:          make(map[string]ToolInfo),
 		builtinServers: make(map[string]*server.MCPServer),
+		unhealthy:      make(map[string]string),
+		cacheTTL:       make(map[string]time.Duration),
+		cache:          newToolCache(maxToolCacheEntries),
+		serverSpecs:    make(map[string]serverSpec),
+		activeCalls:    make(map[string]int),
+		serverTypes:    make(map[string]string),
+		initResults:    make(map[string]*mcplib.InitializeResult),
+		startupErrors:  make(map[string]string),
+		procCancels:    make(map[string]context.CancelFunc),
+		procs:          make(map[string]*exec.Cmd),
+		shutdownGrace:  DefaultShutdownGrace,
+		pendingNotifs:  make(map[string][]mcplib.JSONRPCNotification),
+		notifReady:     make(map[string]bool),
+		serverLogs:     make(map[string][]string),
+		progressTokens: make(map[string]string),
 	}
 }
 
 // NewManagerWithDebug creates a new MCP manager with debug logging enabled.
 func NewManagerWithDebug(debug bool) *Manager {
-	return &Manager{
-		clients:        make(map[string]*client.Client),
-		tools:          make(map[string]ToolInfo),
-		builtinServers: make(map[string]*server.MCPServer),
-		debug:          debug,
-	}
+	m := NewManager()
+	m.debug = debug
+	return m
 }
 
+// serverType values reported in ServerStatuses.
+const (
+	serverTypeStdio   = "stdio"
+	serverTypeBuiltin = "builtin"
+)
+
 // AddServer connects to an MCP server via stdio and initializes it.
+// A failed server is remembered with its error and launch command, so
+// /servers can show why it is missing and /mcp retry can try again.
 func (m *Manager) AddServer(ctx context.Context, id, command string, env []string, args ...string) error {
+	return m.addServer(ctx, id, command, env, "", args)
+}
+
+// AddServerWithConfig connects an external server described by its config
+// entry: the env map becomes KEY=VALUE pairs with ${VAR} references expanded
+// from the parent environment, and the subprocess runs in work_dir.
+func (m *Manager) AddServerWithConfig(ctx context.Context, cfg config.MCPServerConfig) error {
+	env, err := buildServerEnv(cfg.Env)
+	if err != nil {
+		return fmt.Errorf("server %s: %w", cfg.Name, err)
+	}
+	return m.addServer(ctx, cfg.Name, cfg.Command, env, cfg.WorkDir, cfg.Args)
+}
+
+// buildServerEnv converts a config env map into sorted KEY=VALUE pairs,
+// expanding ${VAR} references against the parent environment. Keys
+// containing '=' are rejected here too, for configs built programmatically
+// without going through validation.
+func buildServerEnv(env map[string]string) ([]string, error) {
+	if len(env) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		if strings.Contains(key, "=") {
+			return nil, fmt.Errorf("env key %q must not contain '='", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+os.Expand(env[key], os.Getenv))
+	}
+	return pairs, nil
+}
+
+func (m *Manager) addServer(ctx context.Context, id, command string, env []string, workDir string, args []string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -133,57 +239,74 @@ func (m *Manager) AddServer(ctx context.Context, id, command string, env []strin
 		return fmt.Errorf("server %s already exists", id)
 	}
 
-	// Create the stdio client with optional debug logging
-	var c *client.Client
-	var err error
-
-	if m.debug {
-		// Create stdio transport, start it, and wrap it with debug logging
-		stdioTransport := transport.NewStdio(command, env, args...)
-		if err := stdioTransport.Start(ctx); err != nil {
-			return fmt.Errorf("failed to start stdio transport for %s: %w", id, err)
-		}
-		debugTransport := NewDebugTransport(stdioTransport, id)
-		c = client.NewClient(debugTransport)
-	} else {
-		c, err = client.NewStdioMCPClient(command, env, args...)
-		if err != nil {
-			return fmt.Errorf("failed to create client for %s: %w", id, err)
-		}
+	m.serverTypes[id] = serverTypeStdio
+	m.serverSpecs[id] = serverSpec{command: command, env: env, workDir: workDir, args: args}
+
+	// Run the subprocess under our own cancelable context so a server that
+	// fails (or hangs) during initialization can be killed outright instead
+	// of lingering half-started.
+	procCtx, procCancel := context.WithCancel(context.Background())
+	var cmd *exec.Cmd
+	stdioTransport := transport.NewStdioWithOptions(command, env, args,
+		transport.WithCommandFunc(func(_ context.Context, command string, env []string, args []string) (*exec.Cmd, error) {
+			cmd = exec.CommandContext(procCtx, command, args...)
+			cmd.Env = append(os.Environ(), env...)
+			cmd.Dir = workDir
+			return cmd, nil
+		}))
+	if err := stdioTransport.Start(ctx); err != nil {
+		procCancel()
+		err = fmt.Errorf("failed to start stdio transport for %s: %w", id, err)
+		m.startupErrors[id] = err.Error()
+		return err
 	}
 
-	// Initialize the client
-	initRequest := mcplib.InitializeRequest{}
-	initRequest.Params.ProtocolVersion = mcplib.LATEST_PROTOCOL_VERSION
-	initRequest.Params.ClientInfo = mcplib.Implementation{
-		Name:    "gopus",
-		Version: "1.0.0",
+	var t transport.Interface = stdioTransport
+	if m.debug {
+		t = NewDebugTransport(stdioTransport, id)
 	}
+	c := client.NewClient(t)
 
-	_, err = c.Initialize(ctx, initRequest)
-	if err != nil {
+	if err := m.initializeClientLocked(ctx, id, c); err != nil {
+		// Kill the subprocess first so the transport's close, which waits
+		// for the process to exit, cannot hang on a misbehaving server
+		procCancel()
 		c.Close()
-		return fmt.Errorf("failed to initialize server %s: %w", id, err)
+		err = fmt.Errorf("failed to initialize server %s: %w", id, err)
+		m.startupErrors[id] = err.Error()
+		return err
 	}
 
-	// Store the client
-	m.clients[id] = c
-
-	// Fetch and register tools
-	if err := m.fetchTools(ctx, id, c); err != nil {
-		// Non-fatal: server might not support tools
-		// Log but continue
-	}
+	m.procCancels[id] = procCancel
+	m.procs[id] = cmd
+	delete(m.startupErrors, id)
 
 	return nil
 }
 
+// RetryServer attempts to connect a server that failed at startup, using
+// the launch command recorded by AddServer.
+func (m *Manager) RetryServer(ctx context.Context, id string) error {
+	m.mu.RLock()
+	_, connected := m.clients[id]
+	spec, hasSpec := m.serverSpecs[id]
+	m.mu.RUnlock()
+
+	if connected {
+		return fmt.Errorf("server %s is already connected", id)
+	}
+	if !hasSpec {
+		return fmt.Errorf("server %s has no recorded launch command", id)
+	}
+	return m.addServer(ctx, id, spec.command, spec.env, spec.workDir, spec.args)
+}
+
 // AddBuiltinServer registers an in-process MCP server.
 // Unlike AddServer which connects to external processes via stdio,
 // this method creates an in-process server that runs within the gopus process.
-// The openaiClient parameter provides access to the OpenAI API for tools that need it
-// (may be nil if no OpenAI client is configured).
-func (m *Manager) AddBuiltinServer(ctx context.Context, builtin *BuiltinServer, openaiClient *openai.ChatClient) error {
+// The deps container provides access to process-level dependencies for tools
+// that need them (fields may be nil).
+func (m *Manager) AddBuiltinServer(ctx context.Context, builtin *BuiltinServer, deps Deps) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -194,15 +317,17 @@ func (m *Manager) AddBuiltinServer(ctx context.Context, builtin *BuiltinServer,
 		return fmt.Errorf("server %s already exists", id)
 	}
 
+	m.serverTypes[id] = serverTypeBuiltin
+
 	// Create the MCP server
 	srv := server.NewMCPServer(
 		id,
-		"1.0.0",
+		version.Version,
 		server.WithToolCapabilities(true),
 	)
 
 	// Let the builtin server configure itself (add tools, resources, etc.)
-	if err := builtin.Setup(srv, openaiClient); err != nil {
+	if err := builtin.Setup(srv, deps); err != nil {
 		return fmt.Errorf("failed to setup builtin server %s: %w", id, err)
 	}
 
@@ -221,30 +346,58 @@ func (m *Manager) AddBuiltinServer(ctx context.Context, builtin *BuiltinServer,
 		c = client.NewClient(inProcessTransport)
 	}
 
-	// Initialize the client
+	if err := m.initializeClientLocked(ctx, id, c); err != nil {
+		c.Close()
+		err = fmt.Errorf("failed to initialize builtin server %s: %w", id, err)
+		m.startupErrors[id] = err.Error()
+		return err
+	}
+
+	// Track the server for cleanup
+	m.builtinServers[id] = srv
+	delete(m.startupErrors, id)
+
+	return nil
+}
+
+// initializeClientLocked runs the shared setup sequence for a new client:
+// it hooks the notification queue, initializes the server, registers the
+// client and its tools, and finally replays notifications received during
+// initialization. Callers must hold m.mu and close the client on failure.
+func (m *Manager) initializeClientLocked(ctx context.Context, id string, c *client.Client) error {
+	// Queue notifications emitted before initialization completes
+	m.watchNotifications(id, c)
+
+	// Start wires the client's notification dispatch; it is idempotent for
+	// transports that are already running
+	if err := c.Start(ctx); err != nil {
+		return err
+	}
+
 	initRequest := mcplib.InitializeRequest{}
 	initRequest.Params.ProtocolVersion = mcplib.LATEST_PROTOCOL_VERSION
 	initRequest.Params.ClientInfo = mcplib.Implementation{
 		Name:    "gopus",
-		Version: "1.0.0",
+		Version: version.Version,
 	}
 
-	_, err := c.Initialize(ctx, initRequest)
+	initResult, err := c.Initialize(ctx, initRequest)
 	if err != nil {
-		c.Close()
-		return fmt.Errorf("failed to initialize builtin server %s: %w", id, err)
+		return err
 	}
 
-	// Store the client and server
 	m.clients[id] = c
-	m.builtinServers[id] = srv
+	m.initResults[id] = initResult
 
 	// Fetch and register tools
 	if err := m.fetchTools(ctx, id, c); err != nil {
-		// Non-fatal: server might not have tools yet
+		// Non-fatal: server might not support tools
 		// Log but continue
 	}
 
+	// Mark the server ready and replay queued notifications
+	m.dispatchPendingLocked(ctx, id, c)
+
 	return nil
 }
 
@@ -256,6 +409,13 @@ func (m *Manager) fetchTools(ctx context.Context, serverID string, c *client.Cli
 		return fmt.Errorf("failed to list tools: %w", err)
 	}
 
+	// Replace this server's registrations so removed tools disappear too
+	for name, info := range m.tools {
+		if info.ServerID == serverID {
+			delete(m.tools, name)
+		}
+	}
+
 	for _, tool := range result.Tools {
 		m.tools[tool.Name] = ToolInfo{
 			Tool:     tool,
@@ -277,6 +437,11 @@ func (m *Manager) RemoveServer(id string) error {
 		return fmt.Errorf("server %s not found", id)
 	}
 
+	// Refuse to pull the server out from under an executing tool call
+	if n := m.activeCalls[id]; n > 0 {
+		return fmt.Errorf("server %s has %d tool call(s) in flight; retry when they finish", id, n)
+	}
+
 	// Remove tools from this server
 	for name, info := range m.tools {
 		if info.ServerID == id {
@@ -284,12 +449,29 @@ func (m *Manager) RemoveServer(id string) error {
 		}
 	}
 
-	// Close the client
-	if err := c.Close(); err != nil {
+	// Close the client and make sure its subprocess is gone
+	if err := m.closeServerLocked(id, c); err != nil {
 		return fmt.Errorf("failed to close client for %s: %w", id, err)
 	}
 
 	delete(m.clients, id)
+	delete(m.unhealthy, id)
+	delete(m.serverSpecs, id)
+	delete(m.serverTypes, id)
+	delete(m.initResults, id)
+	delete(m.startupErrors, id)
+	delete(m.procs, id)
+	if cancel, ok := m.procCancels[id]; ok {
+		cancel()
+		delete(m.procCancels, id)
+	}
+
+	m.notifMu.Lock()
+	delete(m.pendingNotifs, id)
+	delete(m.notifReady, id)
+	delete(m.serverLogs, id)
+	m.notifMu.Unlock()
+
 	return nil
 }
 
@@ -305,6 +487,22 @@ func (m *Manager) ListTools() []mcplib.Tool {
 	return tools
 }
 
+// ListToolInfos returns all registered tools with their server association,
+// sorted by tool name.
+func (m *Manager) ListToolInfos() []ToolInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	infos := make([]ToolInfo, 0, len(m.tools))
+	for _, info := range m.tools {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Tool.Name < infos[j].Tool.Name
+	})
+	return infos
+}
+
 // GetTool returns a tool by name.
 func (m *Manager) GetTool(name string) (mcplib.Tool, bool) {
 	m.mu.RLock()
@@ -324,6 +522,19 @@ func (m *Manager) ToolCount() int {
 	return len(m.tools)
 }
 
+// ServerIDs returns the IDs of connected servers, sorted.
+func (m *Manager) ServerIDs() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := make([]string, 0, len(m.clients))
+	for id := range m.clients {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 // ServerCount returns the number of connected servers.
 func (m *Manager) ServerCount() int {
 	m.mu.RLock()
@@ -333,25 +544,120 @@ func (m *Manager) ServerCount() int {
 
 // CallTool executes a tool by name with the given arguments.
 func (m *Manager) CallTool(ctx context.Context, name string, arguments map[string]any) (*mcplib.CallToolResult, error) {
+	result, _, err := m.CallToolCached(ctx, name, arguments)
+	return result, err
+}
+
+// RestartServer stops a stdio server and relaunches it with its original
+// command line. Builtin servers run in-process and cannot be restarted.
+func (m *Manager) RestartServer(ctx context.Context, id string) error {
 	m.mu.RLock()
-	info, ok := m.tools[name]
+	spec, hasSpec := m.serverSpecs[id]
+	_, isBuiltin := m.builtinServers[id]
 	m.mu.RUnlock()
 
-	if !ok {
-		return nil, fmt.Errorf("tool not found: %s", name)
+	if isBuiltin {
+		return fmt.Errorf("server %s runs in-process and cannot be restarted", id)
+	}
+	if !hasSpec {
+		return fmt.Errorf("server %s not found", id)
 	}
 
-	// Build the call request
-	callRequest := mcplib.CallToolRequest{}
-	callRequest.Params.Name = name
-	callRequest.Params.Arguments = arguments
+	if err := m.RemoveServer(id); err != nil {
+		return err
+	}
+	return m.addServer(ctx, id, spec.command, spec.env, spec.workDir, spec.args)
+}
 
-	result, err := info.Client.CallTool(ctx, callRequest)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call tool %s: %w", name, err)
+// beginToolCall records an in-flight tool call against a server so
+// RemoveServer cannot pull the server out from under it.
+func (m *Manager) beginToolCall(serverID string) {
+	m.mu.Lock()
+	m.activeCalls[serverID]++
+	m.mu.Unlock()
+}
+
+// endToolCall releases the in-flight record made by beginToolCall.
+func (m *Manager) endToolCall(serverID string) {
+	m.mu.Lock()
+	if m.activeCalls[serverID]--; m.activeCalls[serverID] <= 0 {
+		delete(m.activeCalls, serverID)
+	}
+	m.mu.Unlock()
+}
+
+// PendingWork reports tool calls that are still executing.
+func (m *Manager) PendingWork() (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	total := 0
+	for _, n := range m.activeCalls {
+		total += n
+	}
+	if total == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%d tool call(s) in flight", total), true
+}
+
+// MarkServerUnhealthy records that a server is unavailable so its tools are
+// no longer advertised to the model. The server is probed again the next
+// time tools are listed.
+func (m *Manager) MarkServerUnhealthy(id, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.unhealthy[id] = reason
+}
+
+// markServerHealthy clears a server's unhealthy mark.
+func (m *Manager) markServerHealthy(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.unhealthy, id)
+}
+
+// UnhealthyServers returns the IDs of unavailable servers with the reason
+// they were marked.
+func (m *Manager) UnhealthyServers() map[string]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	servers := make(map[string]string, len(m.unhealthy))
+	for id, reason := range m.unhealthy {
+		servers[id] = reason
 	}
+	return servers
+}
 
-	return result, nil
+// ListHealthyTools returns the tools from servers that are currently
+// available, plus a count of hidden tools per unavailable server. Unhealthy
+// servers are pinged first, so a recovered server's tools reappear on the
+// next call without manual intervention.
+func (m *Manager) ListHealthyTools(ctx context.Context) ([]mcplib.Tool, map[string]int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id := range m.unhealthy {
+		c, ok := m.clients[id]
+		if !ok {
+			continue
+		}
+		if err := c.Ping(ctx); err == nil {
+			delete(m.unhealthy, id)
+		}
+	}
+
+	tools := make([]mcplib.Tool, 0, len(m.tools))
+	hidden := make(map[string]int)
+	for _, info := range m.tools {
+		if _, bad := m.unhealthy[info.ServerID]; bad {
+			hidden[info.ServerID]++
+			continue
+		}
+		tools = append(tools, info.Tool)
+	}
+	return tools, hidden
 }
 
 // Close closes all client connections.
@@ -361,7 +667,7 @@ func (m *Manager) Close() error {
 
 	var errs []error
 	for id, c := range m.clients {
-		if err := c.Close(); err != nil {
+		if err := m.closeServerLocked(id, c); err != nil {
 			errs = append(errs, fmt.Errorf("failed to close %s: %w", id, err))
 		}
 	}
@@ -369,6 +675,24 @@ func (m *Manager) Close() error {
 	m.clients = make(map[string]*client.Client)
 	m.tools = make(map[string]ToolInfo)
 	m.builtinServers = make(map[string]*server.MCPServer)
+	m.unhealthy = make(map[string]string)
+	m.serverSpecs = make(map[string]serverSpec)
+	m.activeCalls = make(map[string]int)
+	m.serverTypes = make(map[string]string)
+	m.initResults = make(map[string]*mcplib.InitializeResult)
+	m.startupErrors = make(map[string]string)
+	for _, cancel := range m.procCancels {
+		cancel()
+	}
+	m.procCancels = make(map[string]context.CancelFunc)
+	m.procs = make(map[string]*exec.Cmd)
+	m.cache.clear()
+
+	m.notifMu.Lock()
+	m.pendingNotifs = make(map[string][]mcplib.JSONRPCNotification)
+	m.notifReady = make(map[string]bool)
+	m.serverLogs = make(map[string][]string)
+	m.notifMu.Unlock()
 
 	if len(errs) > 0 {
 		return errs[0]
@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRemoveServerRefusesWhileToolInFlight(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	DefaultToolRegistry.Register(
+		mcplib.NewTool("blocking_test"),
+		func(deps Deps) ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				close(started)
+				<-release
+				return mcplib.NewToolResultText("done"), nil
+			}
+		},
+	)
+
+	m := NewManager()
+	if err := m.AddBuiltinServer(ctx, &BuiltinServer{}, Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+	defer m.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := m.CallTool(ctx, "blocking_test", nil)
+		errCh <- err
+	}()
+
+	<-started
+	err := m.RemoveServer("builtin")
+	if err == nil || !strings.Contains(err.Error(), "in flight") {
+		t.Errorf("expected in-flight removal to fail, got %v", err)
+	}
+
+	close(release)
+	if err := <-errCh; err != nil {
+		t.Fatalf("tool call failed: %v", err)
+	}
+
+	// With the call finished the server can be removed
+	if err := m.RemoveServer("builtin"); err != nil {
+		t.Errorf("expected removal to succeed after the call finished: %v", err)
+	}
+}
+
+func TestRestartServerRejectsBuiltinAndUnknown(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager()
+	if err := m.AddBuiltinServer(ctx, &BuiltinServer{}, Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+	defer m.Close()
+
+	if err := m.RestartServer(ctx, "builtin"); err == nil {
+		t.Error("expected restarting a builtin server to fail")
+	}
+	if err := m.RestartServer(ctx, "no-such-server"); err == nil {
+		t.Error("expected restarting an unknown server to fail")
+	}
+}
+
+func TestServerStatusesIncludesFailedServers(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager()
+	if err := m.AddBuiltinServer(ctx, &BuiltinServer{}, Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+	defer m.Close()
+
+	if err := m.AddServer(ctx, "broken", "/nonexistent/gopus-test-server", nil); err == nil {
+		t.Fatal("expected a startup failure for a missing command")
+	}
+
+	byID := make(map[string]ServerStatus)
+	for _, s := range m.ServerStatuses() {
+		byID[s.ID] = s
+	}
+
+	builtin, ok := byID["builtin"]
+	if !ok || !builtin.Connected || builtin.Type != "builtin" {
+		t.Errorf("unexpected builtin status: %+v", builtin)
+	}
+	if builtin.ServerName == "" || builtin.Version == "" {
+		t.Errorf("expected the initialize result to be recorded, got %+v", builtin)
+	}
+
+	broken, ok := byID["broken"]
+	if !ok {
+		t.Fatal("expected the failed server to still appear")
+	}
+	if broken.Connected || broken.Type != "stdio" || broken.LastError == "" {
+		t.Errorf("unexpected failed server status: %+v", broken)
+	}
+}
+
+func TestRetryServer(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager()
+	if err := m.AddBuiltinServer(ctx, &BuiltinServer{}, Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+	defer m.Close()
+
+	if err := m.RetryServer(ctx, "builtin"); err == nil || !strings.Contains(err.Error(), "already connected") {
+		t.Errorf("expected retrying a connected server to fail, got %v", err)
+	}
+	if err := m.RetryServer(ctx, "no-such-server"); err == nil {
+		t.Error("expected retrying an unknown server to fail")
+	}
+
+	// A failed server keeps its launch spec, so a retry is attempted (and
+	// fails again here because the command still does not exist)
+	if err := m.AddServer(ctx, "broken", "/nonexistent/gopus-test-server", nil); err == nil {
+		t.Fatal("expected a startup failure for a missing command")
+	}
+	if err := m.RetryServer(ctx, "broken"); err == nil {
+		t.Error("expected the retry to fail for a still-missing command")
+	}
+}
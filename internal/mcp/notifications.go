@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/client"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxServerLogLines caps the per-server notification log kept for /servers.
+const maxServerLogLines = 20
+
+// watchNotifications attaches a notification handler for a server. Until
+// dispatchPendingLocked marks the server ready, notifications are queued
+// instead of handled, so nothing emitted during initialization is lost.
+func (m *Manager) watchNotifications(id string, c *client.Client) {
+	c.OnNotification(func(n mcplib.JSONRPCNotification) {
+		m.notifMu.Lock()
+		if !m.notifReady[id] {
+			m.pendingNotifs[id] = append(m.pendingNotifs[id], n)
+			m.notifMu.Unlock()
+			return
+		}
+		m.notifMu.Unlock()
+		// Handle off the reader goroutine so a refetch that waits on m.mu
+		// cannot stall the client's message loop
+		go m.handleNotification(context.Background(), id, c, n)
+	})
+}
+
+// dispatchPendingLocked marks a server ready and replays notifications that
+// arrived during initialization, so e.g. a list_changed emitted right after
+// initialize still updates the tool list. Callers must hold m.mu.
+func (m *Manager) dispatchPendingLocked(ctx context.Context, id string, c *client.Client) {
+	m.notifMu.Lock()
+	pending := m.pendingNotifs[id]
+	delete(m.pendingNotifs, id)
+	m.notifReady[id] = true
+	m.notifMu.Unlock()
+
+	for _, n := range pending {
+		m.handleNotificationLocked(ctx, id, c, n)
+	}
+}
+
+// handleNotification processes one notification, taking the manager lock
+// when the tool list must be refetched.
+func (m *Manager) handleNotification(ctx context.Context, id string, c *client.Client, n mcplib.JSONRPCNotification) {
+	if n.Method == methodNotificationProgress {
+		// Routed to the progress handler, not the server log: a chatty
+		// tool would otherwise drown out everything else there
+		m.handleProgressNotification(n)
+		return
+	}
+	if n.Method == mcplib.MethodNotificationToolsListChanged {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		// Best effort; a failed refetch keeps the previous tool list
+		_ = m.fetchTools(ctx, id, c)
+		return
+	}
+	m.appendServerLog(id, n)
+}
+
+// handleNotificationLocked is handleNotification for callers already
+// holding m.mu.
+func (m *Manager) handleNotificationLocked(ctx context.Context, id string, c *client.Client, n mcplib.JSONRPCNotification) {
+	if n.Method == methodNotificationProgress {
+		m.handleProgressNotification(n)
+		return
+	}
+	if n.Method == mcplib.MethodNotificationToolsListChanged {
+		_ = m.fetchTools(ctx, id, c)
+		return
+	}
+	m.appendServerLog(id, n)
+}
+
+// appendServerLog records a non-tool notification in the server's log.
+func (m *Manager) appendServerLog(id string, n mcplib.JSONRPCNotification) {
+	line := n.Method
+	if data, err := json.Marshal(n.Params); err == nil {
+		if s := string(data); s != "{}" && s != "null" {
+			line += " " + s
+		}
+	}
+
+	m.notifMu.Lock()
+	defer m.notifMu.Unlock()
+	logs := append(m.serverLogs[id], line)
+	if len(logs) > maxServerLogLines {
+		logs = logs[len(logs)-maxServerLogLines:]
+	}
+	m.serverLogs[id] = logs
+}
+
+// ServerLogs returns the recent notification log for a server, oldest first.
+func (m *Manager) ServerLogs(id string) []string {
+	m.notifMu.Lock()
+	defer m.notifMu.Unlock()
+	return append([]string(nil), m.serverLogs[id]...)
+}
@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// fakeTransport is an in-memory transport for a server that emits a
+// tools/list_changed notification while answering initialize, before the
+// manager has had a chance to fetch any tools. Its late-added tool only
+// shows up from the second tools/list call on, so it is visible only if
+// the queued notification is replayed.
+type fakeTransport struct {
+	mu        sync.Mutex
+	handler   func(mcplib.JSONRPCNotification)
+	listCalls int
+	runtime   bool // advertise runtime_tool in tool listings
+}
+
+func (t *fakeTransport) Start(ctx context.Context) error { return nil }
+func (t *fakeTransport) Close() error                    { return nil }
+func (t *fakeTransport) GetSessionId() string            { return "" }
+
+func (t *fakeTransport) SetNotificationHandler(handler func(mcplib.JSONRPCNotification)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handler = handler
+}
+
+func (t *fakeTransport) SendNotification(ctx context.Context, n mcplib.JSONRPCNotification) error {
+	return nil
+}
+
+// emit delivers a notification to the client, as a live server would.
+func (t *fakeTransport) emit(method string) {
+	t.mu.Lock()
+	handler := t.handler
+	t.mu.Unlock()
+
+	n := mcplib.JSONRPCNotification{JSONRPC: "2.0"}
+	n.Method = method
+	if handler != nil {
+		handler(n)
+	}
+}
+
+func (t *fakeTransport) SendRequest(ctx context.Context, req transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	switch req.Method {
+	case "initialize":
+		t.emit(mcplib.MethodNotificationToolsListChanged)
+		return fakeResult(req, mcplib.InitializeResult{
+			ProtocolVersion: mcplib.LATEST_PROTOCOL_VERSION,
+			ServerInfo:      mcplib.Implementation{Name: "fake", Version: "1.0.0"},
+		})
+	case "tools/list":
+		t.mu.Lock()
+		t.listCalls++
+		tools := []mcplib.Tool{mcplib.NewTool("alpha")}
+		if t.listCalls > 1 {
+			tools = append(tools, mcplib.NewTool("late_tool"))
+		}
+		if t.runtime {
+			tools = append(tools, mcplib.NewTool("runtime_tool"))
+		}
+		t.mu.Unlock()
+		return fakeResult(req, mcplib.ListToolsResult{Tools: tools})
+	case "ping":
+		return fakeResult(req, struct{}{})
+	}
+	return nil, fmt.Errorf("unexpected method %s", req.Method)
+}
+
+// fakeResult wraps a result value in a JSON-RPC response.
+func fakeResult(req transport.JSONRPCRequest, v any) (*transport.JSONRPCResponse, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &transport.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: data}, nil
+}
+
+// addFakeServer wires a fakeTransport-backed client into the manager the
+// same way AddServer does.
+func addFakeServer(t *testing.T, m *Manager, ft *fakeTransport) {
+	t.Helper()
+	c := client.NewClient(ft)
+	m.mu.Lock()
+	err := m.initializeClientLocked(context.Background(), "fake", c)
+	m.mu.Unlock()
+	if err != nil {
+		t.Fatalf("initializeClientLocked: %v", err)
+	}
+}
+
+// waitFor polls until the condition holds or the deadline passes, since
+// post-initialization notifications are handled off the caller's goroutine.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s never happened", what)
+}
+
+func TestListChangedDuringInitializeIsReplayed(t *testing.T) {
+	m := NewManager()
+	addFakeServer(t, m, &fakeTransport{})
+
+	// The notification arrived before the first tool fetch; replaying it
+	// must surface the late-added tool without a manual refresh.
+	if _, ok := m.GetTool("late_tool"); !ok {
+		t.Error("expected late-added tool visible after initialization")
+	}
+	if _, ok := m.GetTool("alpha"); !ok {
+		t.Error("expected initial tool still registered")
+	}
+}
+
+func TestListChangedAfterInitializeRefreshesTools(t *testing.T) {
+	m := NewManager()
+	ft := &fakeTransport{}
+	addFakeServer(t, m, ft)
+
+	ft.mu.Lock()
+	ft.runtime = true
+	ft.mu.Unlock()
+	ft.emit(mcplib.MethodNotificationToolsListChanged)
+
+	waitFor(t, "runtime tool registration", func() bool {
+		_, ok := m.GetTool("runtime_tool")
+		return ok
+	})
+}
+
+func TestNonToolNotificationsAreLogged(t *testing.T) {
+	m := NewManager()
+	ft := &fakeTransport{}
+	addFakeServer(t, m, ft)
+
+	ft.emit("notifications/message")
+
+	waitFor(t, "notification logging", func() bool {
+		for _, line := range m.ServerLogs("fake") {
+			if line == "notifications/message" {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func TestServerLogsTrimOldEntries(t *testing.T) {
+	m := NewManager()
+
+	for i := 0; i < maxServerLogLines+5; i++ {
+		n := mcplib.JSONRPCNotification{JSONRPC: "2.0"}
+		n.Method = fmt.Sprintf("notifications/message/%d", i)
+		m.appendServerLog("srv", n)
+	}
+
+	logs := m.ServerLogs("srv")
+	if len(logs) != maxServerLogLines {
+		t.Fatalf("expected log capped at %d lines, got %d", maxServerLogLines, len(logs))
+	}
+	if want := fmt.Sprintf("notifications/message/%d", maxServerLogLines+4); logs[len(logs)-1] != want {
+		t.Errorf("expected newest entry %q kept, got %q", want, logs[len(logs)-1])
+	}
+}
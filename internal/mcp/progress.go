@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// methodNotificationProgress is the notification method servers use to
+// report progress on a long-running request; mcp-go defines no constant
+// for it.
+const methodNotificationProgress = "notifications/progress"
+
+// ProgressUpdate describes one progress notification tied to an in-flight
+// tool call.
+type ProgressUpdate struct {
+	Tool     string  // name of the tool being executed
+	Message  string  // human-readable status, may be empty
+	Progress float64 // progress so far
+	Total    float64 // total expected, 0 when unknown
+}
+
+// SetProgressHandler registers a callback for progress notifications from
+// in-flight tool calls. The handler runs on a notification goroutine and
+// must not block. Notifications whose progress token matches no in-flight
+// call are dropped quietly.
+func (m *Manager) SetProgressHandler(fn func(ProgressUpdate)) {
+	m.notifMu.Lock()
+	defer m.notifMu.Unlock()
+	m.progressHandler = fn
+}
+
+// registerProgressToken issues a token identifying one tool call, sent as
+// the request's progress token so the server's progress notifications can
+// be routed back to it.
+func (m *Manager) registerProgressToken(tool string) string {
+	m.notifMu.Lock()
+	defer m.notifMu.Unlock()
+
+	m.progressSeq++
+	token := fmt.Sprintf("tool-call-%d", m.progressSeq)
+	m.progressTokens[token] = tool
+	return token
+}
+
+// releaseProgressToken forgets a token once its call finished; late
+// notifications for it are dropped from then on.
+func (m *Manager) releaseProgressToken(token string) {
+	m.notifMu.Lock()
+	defer m.notifMu.Unlock()
+	delete(m.progressTokens, token)
+}
+
+// handleProgressNotification routes one progress notification to the
+// registered handler, if its token belongs to an in-flight tool call.
+func (m *Manager) handleProgressNotification(n mcplib.JSONRPCNotification) {
+	data, err := json.Marshal(n.Params)
+	if err != nil {
+		return
+	}
+	var params struct {
+		ProgressToken any     `json:"progressToken"`
+		Progress      float64 `json:"progress"`
+		Total         float64 `json:"total"`
+		Message       string  `json:"message"`
+	}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return
+	}
+	token, ok := params.ProgressToken.(string)
+	if !ok {
+		return
+	}
+
+	m.notifMu.Lock()
+	tool, known := m.progressTokens[token]
+	handler := m.progressHandler
+	m.notifMu.Unlock()
+
+	if !known || handler == nil {
+		return
+	}
+	handler(ProgressUpdate{
+		Tool:     tool,
+		Message:  params.Message,
+		Progress: params.Progress,
+		Total:    params.Total,
+	})
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"testing"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// progressNotification builds a notifications/progress message for a token.
+func progressNotification(token any, progress, total float64, message string) mcplib.JSONRPCNotification {
+	n := mcplib.JSONRPCNotification{}
+	n.Method = methodNotificationProgress
+	n.Params.AdditionalFields = map[string]any{
+		"progressToken": token,
+		"progress":      progress,
+		"total":         total,
+		"message":       message,
+	}
+	return n
+}
+
+func TestProgressNotificationsRoutedToHandler(t *testing.T) {
+	m := NewManager()
+	defer m.Close()
+
+	var got []ProgressUpdate
+	m.SetProgressHandler(func(u ProgressUpdate) { got = append(got, u) })
+
+	token := m.registerProgressToken("read_dataset")
+	m.handleProgressNotification(progressNotification(token, 45, 100, "parsing rows"))
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 update, got %d", len(got))
+	}
+	u := got[0]
+	if u.Tool != "read_dataset" || u.Progress != 45 || u.Total != 100 || u.Message != "parsing rows" {
+		t.Errorf("unexpected update: %+v", u)
+	}
+
+	// Once the call is done, late notifications for its token are dropped
+	m.releaseProgressToken(token)
+	m.handleProgressNotification(progressNotification(token, 100, 100, "done"))
+	if len(got) != 1 {
+		t.Errorf("expected late notification to be dropped, got %d updates", len(got))
+	}
+}
+
+func TestProgressNotificationUnknownTokenDropped(t *testing.T) {
+	m := NewManager()
+	defer m.Close()
+
+	called := false
+	m.SetProgressHandler(func(ProgressUpdate) { called = true })
+
+	m.handleProgressNotification(progressNotification("never-issued", 1, 0, ""))
+	m.handleProgressNotification(progressNotification(float64(42), 1, 0, ""))
+	if called {
+		t.Error("expected notifications with unknown tokens to be dropped")
+	}
+}
@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopus/internal/config"
+)
+
+func TestBuildServerEnv(t *testing.T) {
+	t.Setenv("GOPUS_TEST_BASE", "expanded")
+
+	pairs, err := buildServerEnv(map[string]string{
+		"B_VAR": "plain",
+		"A_VAR": "prefix-${GOPUS_TEST_BASE}",
+	})
+	if err != nil {
+		t.Fatalf("buildServerEnv: %v", err)
+	}
+	want := []string{"A_VAR=prefix-expanded", "B_VAR=plain"}
+	if !reflect.DeepEqual(pairs, want) {
+		t.Errorf("expected %v, got %v", want, pairs)
+	}
+
+	if pairs, err := buildServerEnv(nil); err != nil || pairs != nil {
+		t.Errorf("expected nil for an empty map, got %v, %v", pairs, err)
+	}
+
+	if _, err := buildServerEnv(map[string]string{"BAD=KEY": "x"}); err == nil {
+		t.Error("expected an error for an env key containing '='")
+	}
+}
+
+func TestAddServerWithConfigAppliesEnvAndWorkDir(t *testing.T) {
+	t.Setenv("GOPUS_TEST_BASE", "fromparent")
+
+	workDir := t.TempDir()
+	outFile := filepath.Join(t.TempDir(), "child.out")
+
+	m := NewManager()
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The script is not an MCP server, so initialization fails — but the
+	// subprocess still runs and records what it actually saw.
+	err := m.AddServerWithConfig(ctx, config.MCPServerConfig{
+		Name:    "probe",
+		Command: "sh",
+		Args:    []string{"-c", `printf '%s\n%s\n' "$PWD" "$GOPUS_CHILD_VAR" > ` + outFile},
+		Env:     map[string]string{"GOPUS_CHILD_VAR": "via-${GOPUS_TEST_BASE}"},
+		WorkDir: workDir,
+	})
+	if err == nil {
+		t.Fatal("expected initialization against a non-MCP command to fail")
+	}
+
+	// The script may outlive AddServerWithConfig's failure by a moment
+	var data []byte
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		if data, err = os.ReadFile(outFile); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("child process never wrote its output: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected child output %q", data)
+	}
+
+	wantDir, _ := filepath.EvalSymlinks(workDir)
+	gotDir, _ := filepath.EvalSymlinks(lines[0])
+	if gotDir != wantDir {
+		t.Errorf("expected child cwd %q, got %q", wantDir, gotDir)
+	}
+	if lines[1] != "via-fromparent" {
+		t.Errorf("expected expanded env value %q, got %q", "via-fromparent", lines[1])
+	}
+}
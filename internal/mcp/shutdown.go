@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+)
+
+// DefaultShutdownGrace is how long a stdio server's subprocess gets at each
+// shutdown stage before the next, harsher one is tried.
+const DefaultShutdownGrace = 3 * time.Second
+
+// Shutdown stages, in escalation order.
+const (
+	stageStdinClose = "stdin close"
+	stageSIGTERM    = "SIGTERM"
+	stageSIGKILL    = "SIGKILL"
+)
+
+// SetShutdownGrace overrides the per-stage grace period used when stopping
+// stdio server subprocesses.
+func (m *Manager) SetShutdownGrace(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.shutdownGrace = d
+}
+
+// closeServerLocked closes a server's client. For stdio servers this also
+// makes sure the subprocess actually exits: well-behaved servers quit when
+// their stdin closes, the rest get SIGTERM and finally a kill. Callers must
+// hold m.mu.
+func (m *Manager) closeServerLocked(id string, c *client.Client) error {
+	cmd := m.procs[id]
+	if cmd == nil {
+		// In-process servers have no subprocess to escalate against
+		return c.Close()
+	}
+
+	var closeErr error
+	stage := shutdownProcess(func() { closeErr = c.Close() }, cmd, m.procCancels[id], m.shutdownGrace)
+	if stage != stageStdinClose {
+		fmt.Fprintf(os.Stderr, "MCP server %s ignored stdin close; stopped it with %s\n", id, stage)
+		// The close error just reflects the signal we sent
+		return nil
+	}
+	return closeErr
+}
+
+// shutdownProcess runs closeAndWait, which closes the child's stdin and
+// waits for it to exit, and escalates if the child ignores that: SIGTERM
+// after one grace period, kill after a second. It reports which stage got
+// the child to exit.
+func shutdownProcess(closeAndWait func(), cmd *exec.Cmd, kill func(), grace time.Duration) string {
+	done := make(chan struct{})
+	go func() {
+		closeAndWait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return stageStdinClose
+	case <-time.After(grace):
+	}
+
+	if cmd.Process != nil {
+		cmd.Process.Signal(syscall.SIGTERM)
+	}
+	select {
+	case <-done:
+		return stageSIGTERM
+	case <-time.After(grace):
+	}
+
+	kill()
+	<-done
+	return stageSIGKILL
+}
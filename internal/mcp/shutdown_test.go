@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// startStubServer launches script under sh with a stdin pipe and returns the
+// command, a closeAndWait func mirroring the transport's close behavior
+// (close stdin, then wait for the child), and the kill of last resort.
+func startStubServer(t *testing.T, script string) (*exec.Cmd, func(), context.CancelFunc) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, "sh", "-c", script)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatalf("StdinPipe: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(cancel)
+
+	closeAndWait := func() {
+		stdin.Close()
+		cmd.Wait()
+	}
+	return cmd, closeAndWait, cancel
+}
+
+func TestShutdownProcessStdinCloseSuffices(t *testing.T) {
+	// cat exits as soon as its stdin closes
+	cmd, closeAndWait, cancel := startStubServer(t, "exec cat")
+	if stage := shutdownProcess(closeAndWait, cmd, cancel, time.Second); stage != stageStdinClose {
+		t.Errorf("expected stdin close to suffice, got %s", stage)
+	}
+}
+
+func TestShutdownProcessEscalatesToSIGTERM(t *testing.T) {
+	// The loop never reads stdin, so only a signal can stop it
+	cmd, closeAndWait, cancel := startStubServer(t, "while :; do sleep 0.05; done")
+	if stage := shutdownProcess(closeAndWait, cmd, cancel, 100*time.Millisecond); stage != stageSIGTERM {
+		t.Errorf("expected SIGTERM to stop the server, got %s", stage)
+	}
+}
+
+func TestShutdownProcessEscalatesToKill(t *testing.T) {
+	// Ignoring SIGTERM as well forces the kill of last resort
+	cmd, closeAndWait, cancel := startStubServer(t, "trap '' TERM; while :; do sleep 0.05; done")
+	if stage := shutdownProcess(closeAndWait, cmd, cancel, 100*time.Millisecond); stage != stageSIGKILL {
+		t.Errorf("expected the kill stage, got %s", stage)
+	}
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gopus/internal/config"
+)
+
+// BuiltinStartSummary reports what StartBuiltinServers did: which servers
+// started, which the config disabled, and which configured names matched no
+// registered server at all.
+type BuiltinStartSummary struct {
+	Started  []string
+	Disabled []string
+	Unknown  []string
+}
+
+// registeredBuiltinServers returns the in-process servers available to
+// start. There is currently one, hosting every tool in DefaultToolRegistry;
+// servers added here are automatically subject to the enable/disable lists.
+func registeredBuiltinServers() []*BuiltinServer {
+	return []*BuiltinServer{{}}
+}
+
+// StartBuiltinServers walks the registered builtin servers, filters them
+// through the configured enable/disable lists, and adds the survivors to
+// the manager. Names in the lists that match no registered server are
+// warned about, since a typo would otherwise silently enable or disable
+// the wrong thing. A server hosting no tools is skipped entirely.
+func StartBuiltinServers(ctx context.Context, m *Manager, cfg config.BuiltinConfig, deps Deps) BuiltinStartSummary {
+	var summary BuiltinStartSummary
+
+	registered := make(map[string]bool)
+	for _, builtin := range registeredBuiltinServers() {
+		name := builtin.Name()
+		registered[name] = true
+
+		if !cfg.IsServerEnabled(name) {
+			summary.Disabled = append(summary.Disabled, name)
+			continue
+		}
+		if builtin.Registry().Count() == 0 {
+			continue
+		}
+
+		if err := m.AddBuiltinServer(ctx, builtin, deps); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to initialize builtin server %s: %v\n", name, err)
+			continue
+		}
+		summary.Started = append(summary.Started, name)
+	}
+
+	for _, name := range append(append([]string{}, cfg.Enabled...), cfg.Disabled...) {
+		if !registered[name] {
+			summary.Unknown = append(summary.Unknown, name)
+			fmt.Fprintf(os.Stderr, "Warning: mcp.builtin references unknown server %q\n", name)
+		}
+	}
+
+	return summary
+}
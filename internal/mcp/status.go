@@ -0,0 +1,53 @@
+package mcp
+
+import "sort"
+
+// ServerStatus describes one MCP server for display in /servers. Servers
+// that failed at startup appear with Connected false and their error, so a
+// broken config entry is visible instead of silently absent.
+type ServerStatus struct {
+	ID         string
+	Type       string // transport type: "stdio" or "builtin"
+	Connected  bool
+	Tools      int    // number of tools registered from this server
+	ServerName string // name the server reported during initialization
+	Version    string // version the server reported during initialization
+	LastError  string // startup failure or unhealthy reason; empty when fine
+}
+
+// ServerStatuses returns the status of every known server, connected or
+// failed, sorted by ID.
+func (m *Manager) ServerStatuses() []ServerStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make(map[string]*ServerStatus, len(m.clients))
+	for id := range m.clients {
+		s := &ServerStatus{ID: id, Type: m.serverTypes[id], Connected: true}
+		if res := m.initResults[id]; res != nil {
+			s.ServerName = res.ServerInfo.Name
+			s.Version = res.ServerInfo.Version
+		}
+		if reason, bad := m.unhealthy[id]; bad {
+			s.LastError = reason
+		}
+		statuses[id] = s
+	}
+	for _, info := range m.tools {
+		if s, ok := statuses[info.ServerID]; ok {
+			s.Tools++
+		}
+	}
+	for id, errMsg := range m.startupErrors {
+		if _, ok := statuses[id]; !ok {
+			statuses[id] = &ServerStatus{ID: id, Type: m.serverTypes[id], LastError: errMsg}
+		}
+	}
+
+	list := make([]ServerStatus, 0, len(statuses))
+	for _, s := range statuses {
+		list = append(list, *s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
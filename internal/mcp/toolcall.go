@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gopus/internal/openai"
+)
+
+// OpenAITools converts the manager's MCP tools to the OpenAI function
+// format. Tools from servers currently marked unhealthy are omitted so the
+// model doesn't waste a turn on a call guaranteed to fail; they return once
+// the server recovers.
+func (m *Manager) OpenAITools(ctx context.Context) []openai.ChatCompletionTool {
+	mcpTools, _ := m.ListHealthyTools(ctx)
+	if len(mcpTools) == 0 {
+		return nil
+	}
+
+	tools := make([]openai.ChatCompletionTool, 0, len(mcpTools))
+	for _, tool := range mcpTools {
+		// Convert the MCP tool schema to OpenAI format by marshalling the
+		// InputSchema to JSON and unmarshalling into a generic map
+		schemaBytes, err := json.Marshal(tool.InputSchema)
+		if err != nil {
+			continue // Skip tools with invalid schemas
+		}
+
+		var params map[string]interface{}
+		if err := json.Unmarshal(schemaBytes, &params); err != nil {
+			continue // Skip tools with invalid schemas
+		}
+
+		tools = append(tools, openai.ChatCompletionTool{
+			Type: openai.Function,
+			Function: openai.FunctionDefinition{
+				Name:        tool.Name,
+				Description: &tool.Description,
+				Parameters:  &params,
+			},
+		})
+	}
+
+	return tools
+}
+
+// ExecuteToolCall parses the JSON arguments of a model tool call, runs the
+// tool, and renders the result as text. A tool that ran but reported an
+// error comes back as a "Tool error:" string with a nil error, so callers
+// can feed the failure to the model instead of aborting the turn.
+func (m *Manager) ExecuteToolCall(ctx context.Context, name, arguments string) (string, error) {
+	var args map[string]any
+	if arguments != "" {
+		if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+			return "", fmt.Errorf("failed to parse tool arguments: %w", err)
+		}
+	}
+
+	result, err := m.CallTool(ctx, name, args)
+	if err != nil {
+		return "", err
+	}
+
+	if result.IsError {
+		return fmt.Sprintf("Tool error: %s", FormatContent(result.Content)), nil
+	}
+	return FormatContent(result.Content), nil
+}
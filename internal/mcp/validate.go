@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// SetValidationDisabled turns schema validation of tool arguments off (or
+// back on). Validation is on by default.
+func (m *Manager) SetValidationDisabled(disabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.validationDisabled = disabled
+}
+
+// ValidateToolArgs checks arguments against the subset of JSON Schema that
+// tool input schemas commonly use: required properties, the primitive type
+// keywords (string, number, integer, boolean, array, object), and enum
+// membership. It returns one message per problem; an empty slice means the
+// arguments pass. Validation is best-effort: unknown keywords, undeclared
+// properties, and malformed schema fragments are ignored rather than
+// rejected, so a schema the validator cannot understand never blocks a call.
+func ValidateToolArgs(schema mcplib.ToolInputSchema, args map[string]any) []string {
+	var problems []string
+
+	for _, name := range schema.Required {
+		if _, ok := args[name]; !ok {
+			problems = append(problems, fmt.Sprintf("missing required field %q", name))
+		}
+	}
+
+	// Walk the declared properties in sorted order so the report is stable
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, present := args[name]
+		if !present {
+			continue // absence is only a problem for required fields
+		}
+		spec, ok := schema.Properties[name].(map[string]any)
+		if !ok {
+			continue // malformed property schema; best effort
+		}
+		if typ, ok := spec["type"].(string); ok {
+			if msg := checkArgType(name, typ, value); msg != "" {
+				problems = append(problems, msg)
+				continue // a type mismatch makes the enum check redundant
+			}
+		}
+		if enum, ok := spec["enum"].([]any); ok {
+			if msg := checkArgEnum(name, enum, value); msg != "" {
+				problems = append(problems, msg)
+			}
+		}
+	}
+
+	return problems
+}
+
+// checkArgType verifies a JSON-decoded value against a schema type keyword.
+// Unknown type keywords pass, matching the best-effort contract.
+func checkArgType(name, typ string, value any) string {
+	ok := true
+	switch typ {
+	case "string":
+		_, ok = value.(string)
+	case "number":
+		_, ok = value.(float64)
+	case "integer":
+		f, isNumber := value.(float64)
+		ok = isNumber && f == math.Trunc(f)
+	case "boolean":
+		_, ok = value.(bool)
+	case "array":
+		_, ok = value.([]any)
+	case "object":
+		_, ok = value.(map[string]any)
+	default:
+		return ""
+	}
+	if !ok {
+		return fmt.Sprintf("field %q must be of type %s, got %s", name, typ, jsonTypeName(value))
+	}
+	return ""
+}
+
+// checkArgEnum verifies the value is one of the allowed enum members.
+func checkArgEnum(name string, enum []any, value any) string {
+	for _, allowed := range enum {
+		if reflect.DeepEqual(allowed, value) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("field %q has value %v, which is not one of the allowed values %v", name, value, enum)
+}
+
+// jsonTypeName names a JSON-decoded Go value in JSON Schema terms, for
+// error messages the model can act on.
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case nil:
+		return "null"
+	default:
+		return "unknown"
+	}
+}
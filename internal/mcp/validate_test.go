@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// testSchema builds an input schema from raw property maps, so tests can
+// express malformed fragments the mcplib builders would not produce.
+func testSchema(required []string, properties map[string]any) mcplib.ToolInputSchema {
+	return mcplib.ToolInputSchema{
+		Type:       "object",
+		Properties: properties,
+		Required:   required,
+	}
+}
+
+func TestValidateToolArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  mcplib.ToolInputSchema
+		args    map[string]any
+		problem string // substring of the expected problem; "" means valid
+	}{
+		{
+			name:    "missing required field",
+			schema:  testSchema([]string{"message"}, map[string]any{"message": map[string]any{"type": "string"}}),
+			args:    map[string]any{},
+			problem: `missing required field "message"`,
+		},
+		{
+			name:    "string mismatch",
+			schema:  testSchema(nil, map[string]any{"message": map[string]any{"type": "string"}}),
+			args:    map[string]any{"message": float64(5)},
+			problem: `field "message" must be of type string, got number`,
+		},
+		{
+			name:    "number mismatch",
+			schema:  testSchema(nil, map[string]any{"amount": map[string]any{"type": "number"}}),
+			args:    map[string]any{"amount": "five"},
+			problem: `field "amount" must be of type number, got string`,
+		},
+		{
+			name:    "integer accepts integral number",
+			schema:  testSchema(nil, map[string]any{"count": map[string]any{"type": "integer"}}),
+			args:    map[string]any{"count": float64(3)},
+			problem: "",
+		},
+		{
+			name:    "integer rejects fraction",
+			schema:  testSchema(nil, map[string]any{"count": map[string]any{"type": "integer"}}),
+			args:    map[string]any{"count": 3.5},
+			problem: `field "count" must be of type integer, got number`,
+		},
+		{
+			name:    "boolean mismatch",
+			schema:  testSchema(nil, map[string]any{"force": map[string]any{"type": "boolean"}}),
+			args:    map[string]any{"force": "yes"},
+			problem: `field "force" must be of type boolean, got string`,
+		},
+		{
+			name:    "array mismatch",
+			schema:  testSchema(nil, map[string]any{"items": map[string]any{"type": "array"}}),
+			args:    map[string]any{"items": map[string]any{}},
+			problem: `field "items" must be of type array, got object`,
+		},
+		{
+			name:    "object mismatch",
+			schema:  testSchema(nil, map[string]any{"options": map[string]any{"type": "object"}}),
+			args:    map[string]any{"options": []any{}},
+			problem: `field "options" must be of type object, got array`,
+		},
+		{
+			name:    "null reported by name",
+			schema:  testSchema(nil, map[string]any{"message": map[string]any{"type": "string"}}),
+			args:    map[string]any{"message": nil},
+			problem: `field "message" must be of type string, got null`,
+		},
+		{
+			name:    "enum member passes",
+			schema:  testSchema(nil, map[string]any{"mode": map[string]any{"type": "string", "enum": []any{"fast", "slow"}}}),
+			args:    map[string]any{"mode": "fast"},
+			problem: "",
+		},
+		{
+			name:    "enum non-member fails",
+			schema:  testSchema(nil, map[string]any{"mode": map[string]any{"type": "string", "enum": []any{"fast", "slow"}}}),
+			args:    map[string]any{"mode": "sideways"},
+			problem: `field "mode" has value sideways`,
+		},
+		{
+			name:    "valid arguments pass",
+			schema:  testSchema([]string{"message"}, map[string]any{"message": map[string]any{"type": "string"}}),
+			args:    map[string]any{"message": "hello"},
+			problem: "",
+		},
+		{
+			name:    "undeclared argument ignored",
+			schema:  testSchema(nil, map[string]any{"message": map[string]any{"type": "string"}}),
+			args:    map[string]any{"extra": float64(1)},
+			problem: "",
+		},
+		{
+			name:    "unknown type keyword ignored",
+			schema:  testSchema(nil, map[string]any{"when": map[string]any{"type": "date-time"}}),
+			args:    map[string]any{"when": float64(0)},
+			problem: "",
+		},
+		{
+			name:    "malformed property schema ignored",
+			schema:  testSchema(nil, map[string]any{"message": "not a schema"}),
+			args:    map[string]any{"message": float64(5)},
+			problem: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := ValidateToolArgs(tt.schema, tt.args)
+			if tt.problem == "" {
+				if len(problems) != 0 {
+					t.Errorf("expected no problems, got %v", problems)
+				}
+				return
+			}
+			if len(problems) != 1 || !strings.Contains(problems[0], tt.problem) {
+				t.Errorf("expected one problem containing %q, got %v", tt.problem, problems)
+			}
+		})
+	}
+}
+
+// TestCallToolValidatesArguments verifies that invalid arguments are caught
+// before the handler runs, and that the check can be switched off.
+func TestCallToolValidatesArguments(t *testing.T) {
+	ctx := context.Background()
+	handlerCalls := 0
+	DefaultToolRegistry.Register(
+		mcplib.NewTool("validated_test",
+			mcplib.WithString("message", mcplib.Required()),
+		),
+		func(deps Deps) ToolHandler {
+			return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+				handlerCalls++
+				return mcplib.NewToolResultText("ran"), nil
+			}
+		},
+	)
+
+	m := NewManager()
+	if err := m.AddBuiltinServer(ctx, &BuiltinServer{}, Deps{}); err != nil {
+		t.Fatalf("AddBuiltinServer: %v", err)
+	}
+	defer m.Close()
+
+	result, err := m.CallTool(ctx, "validated_test", map[string]any{"message": float64(7)})
+	if err != nil {
+		t.Fatalf("CallTool returned a transport error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected a tool-level error for invalid arguments")
+	}
+	if handlerCalls != 0 {
+		t.Errorf("expected the handler to be skipped, ran %d times", handlerCalls)
+	}
+
+	m.SetValidationDisabled(true)
+	if _, err := m.CallTool(ctx, "validated_test", map[string]any{"message": float64(7)}); err != nil {
+		t.Fatalf("CallTool with validation disabled: %v", err)
+	}
+	if handlerCalls != 1 {
+		t.Errorf("expected the handler to run with validation disabled, ran %d times", handlerCalls)
+	}
+}
@@ -0,0 +1,127 @@
+// Package memory stores facts the user wants remembered across every
+// session — preferences like "answer tersely" or "tests are table-driven"
+// — in a single global file injected into each request as a system
+// message, so they never need re-telling.
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Fact is one remembered entry.
+type Fact struct {
+	Text    string    `json:"text"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// Store reads and writes the memory file. Writes go through an atomic
+// rename, so concurrent gopus instances can at worst lose a race, never
+// corrupt the file. The zero value is not usable; create one with NewStore.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewStore returns a store backed by the given file.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns the global memory file location, ~/.gopus/memory.json.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".gopus", "memory.json"), nil
+}
+
+// Add appends a fact to the memory file.
+func (s *Store) Add(text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	facts, err := s.load()
+	if err != nil {
+		return err
+	}
+	facts = append(facts, Fact{Text: text, AddedAt: time.Now()})
+	return s.save(facts)
+}
+
+// Facts returns every remembered fact, oldest first.
+func (s *Store) Facts() ([]Fact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Remove deletes the fact at the given zero-based index.
+func (s *Store) Remove(index int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	facts, err := s.load()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(facts) {
+		return fmt.Errorf("no memory entry %d", index+1)
+	}
+	facts = append(facts[:index], facts[index+1:]...)
+	return s.save(facts)
+}
+
+// load reads the memory file; a missing file is an empty memory.
+func (s *Store) load() ([]Fact, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory file: %w", err)
+	}
+	var facts []Fact
+	if err := json.Unmarshal(data, &facts); err != nil {
+		return nil, fmt.Errorf("failed to parse memory file %s: %w", s.path, err)
+	}
+	return facts, nil
+}
+
+// save writes the facts through a temp file and rename, so a concurrent
+// writer or a crash cannot leave a half-written file behind.
+func (s *Store) save(facts []Fact) error {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create memory directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(facts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode memory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".memory-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to replace memory file: %w", err)
+	}
+	return nil
+}
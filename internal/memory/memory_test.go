@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStoreAddListRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+
+	store := NewStore(path)
+	if facts, err := store.Facts(); err != nil || len(facts) != 0 {
+		t.Fatalf("expected a fresh store to be empty, got %v, %v", facts, err)
+	}
+
+	if err := store.Add("answer tersely"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := store.Add("tests are table-driven"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	facts, err := store.Facts()
+	if err != nil {
+		t.Fatalf("Facts failed: %v", err)
+	}
+	if len(facts) != 2 || facts[0].Text != "answer tersely" || facts[1].Text != "tests are table-driven" {
+		t.Fatalf("expected the two facts oldest first, got %+v", facts)
+	}
+	if facts[0].AddedAt.IsZero() {
+		t.Error("expected Add to stamp the fact")
+	}
+
+	if err := store.Remove(0); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	facts, _ = store.Facts()
+	if len(facts) != 1 || facts[0].Text != "tests are table-driven" {
+		t.Errorf("expected only the second fact to remain, got %+v", facts)
+	}
+
+	// A second store on the same file sees the same facts, like another
+	// gopus process would
+	other := NewStore(path)
+	facts, err = other.Facts()
+	if err != nil || len(facts) != 1 {
+		t.Errorf("expected a shared memory of 1 fact, got %v, %v", facts, err)
+	}
+}
+
+func TestStoreRemoveOutOfRange(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "memory.json"))
+	if err := store.Add("a fact"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	err := store.Remove(3)
+	if err == nil || !strings.Contains(err.Error(), "no memory entry 4") {
+		t.Errorf("expected an out-of-range error, got %v", err)
+	}
+	if facts, _ := store.Facts(); len(facts) != 1 {
+		t.Errorf("a failed Remove must not change the file, got %+v", facts)
+	}
+}
+
+func TestStoreCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	store := NewStore(path)
+	if _, err := store.Facts(); err == nil || !strings.Contains(err.Error(), "failed to parse memory file") {
+		t.Errorf("expected a parse error for a corrupt file, got %v", err)
+	}
+}
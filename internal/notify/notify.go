@@ -0,0 +1,85 @@
+// Package notify sends desktop notifications with platform-specific backends.
+// It uses osascript on macOS and notify-send on Linux, falling back to a
+// no-op notifier elsewhere so callers never need to special-case platforms.
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Notifier sends desktop notifications. Implementations must not block.
+type Notifier interface {
+	// Notify sends a desktop notification with the given title and message.
+	Notify(title, message string) error
+}
+
+// New returns a Notifier for the current platform, or a no-op notifier
+// when no notification utility is available.
+func New() Notifier {
+	switch runtime.GOOS {
+	case "darwin":
+		if path, err := exec.LookPath("osascript"); err == nil {
+			return &commandNotifier{path: path, build: osascriptArgs}
+		}
+	case "linux":
+		if path, err := exec.LookPath("notify-send"); err == nil {
+			return &commandNotifier{path: path, build: notifySendArgs}
+		}
+	}
+	return nopNotifier{}
+}
+
+// commandNotifier shells out to a platform notification utility.
+type commandNotifier struct {
+	path  string
+	build func(title, message string) []string
+}
+
+// Notify starts the notification command without waiting for it, so a slow
+// or broken utility can never block the chat loop.
+func (n *commandNotifier) Notify(title, message string) error {
+	cmd := exec.Command(n.path, n.build(title, message)...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start notifier: %w", err)
+	}
+
+	// Reap the process in the background to avoid leaving a zombie
+	go cmd.Wait()
+	return nil
+}
+
+// osascriptArgs builds arguments for macOS's osascript.
+func osascriptArgs(title, message string) []string {
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, `"`, `\"`)
+	}
+	script := fmt.Sprintf(`display notification "%s" with title "%s"`, escape(message), escape(title))
+	return []string{"-e", script}
+}
+
+// notifySendArgs builds arguments for Linux's notify-send.
+func notifySendArgs(title, message string) []string {
+	return []string{title, message}
+}
+
+// nopNotifier is the fallback for platforms without a notification utility.
+type nopNotifier struct{}
+
+// Notify does nothing.
+func (nopNotifier) Notify(title, message string) error {
+	return nil
+}
+
+// Fake is an in-memory Notifier implementation for tests.
+type Fake struct {
+	Notifications []string // "title: message" per call
+}
+
+// Notify records the notification.
+func (f *Fake) Notify(title, message string) error {
+	f.Notifications = append(f.Notifications, title+": "+message)
+	return nil
+}
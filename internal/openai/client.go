@@ -8,21 +8,89 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"gopus/internal/config"
+	"gopus/internal/version"
 )
 
 // ErrEmptyResponse is returned when the API returns no choices or empty message content.
 var ErrEmptyResponse = errors.New("empty response from API")
 
 // ChatClient wraps the generated OpenAI client with configuration defaults.
+// Its settings can change at runtime (/reload) while a background request
+// is in flight, so every request works from a point-in-time snapshot taken
+// under the mutex rather than reading the fields directly.
 type ChatClient struct {
-	client      *ClientWithResponses
+	client   *ClientWithResponses
+	debugLog *log.Logger
+
+	// mu guards the mutable settings below.
+	mu       sync.Mutex
+	settings requestSettings
+	debug    bool
+}
+
+// requestSettings is the mutable per-request configuration. Snapshot copies
+// hand each request its own values, so a concurrent UpdateSettings cannot
+// change an in-flight request's payload. The pointer fields are safe to
+// share: UpdateSettings replaces the pointers and never mutates pointees.
+type requestSettings struct {
 	model       string
 	maxTokens   int
 	temperature float32
+	stop        []string
+
+	// Optional generation parameters; nil means the field is not sent
+	topP             *float32
+	presencePenalty  *float32
+	frequencyPenalty *float32
+	seed             *int
+}
+
+// RequestOptions overrides the client defaults for a single request.
+// Zero values (nil for the pointer fields) fall back to the configured
+// defaults.
+type RequestOptions struct {
+	Model       string
+	MaxTokens   int
+	Temperature *float32
+	Stop        []string
+
+	TopP             *float64
+	PresencePenalty  *float64
+	FrequencyPenalty *float64
+	Seed             *int
+
+	// ResponseFormat forces JSON or schema-constrained output; nil sends
+	// no response_format (the default free-form text).
+	ResponseFormat *ResponseFormat
+}
+
+// maxRetryAfterWait caps how long a Retry-After header may make the client
+// wait before its single rate-limit retry; anything longer fails fast so
+// the user decides what to do with their time.
+const maxRetryAfterWait = 30 * time.Second
+
+// maxTokensLimits lists the per-request completion token limits for known
+// models, used to validate overrides. Unknown models are not validated.
+var maxTokensLimits = map[string]int{
+	"gpt-3.5-turbo": 4096,
+	"gpt-4":         8192,
+	"gpt-4o":        16384,
+	"gpt-4o-mini":   16384,
+	"gpt-4.1-mini":  32768,
+}
+
+// MaxTokensLimit returns the completion token limit for a model, or 0 if
+// the model is unknown.
+func MaxTokensLimit(model string) int {
+	return maxTokensLimits[model]
 }
 
 // NewChatClient creates a new OpenAI chat client from the provided configuration.
@@ -32,15 +100,39 @@ func NewChatClient(cfg *config.Config) (*ChatClient, error) {
 		Timeout: 60 * time.Second,
 	}
 
-	// Create request editor to add authorization header
+	// Azure addresses a deployment-based path under the resource endpoint
+	// and authenticates with an api-key header plus an api-version query
+	// parameter; the regular API uses a bearer token with optional
+	// organization/project attribution headers.
+	azure := cfg.OpenAI.APIType == config.APITypeAzure
+	baseURL := cfg.OpenAI.BaseURL
+	if azure {
+		baseURL = strings.TrimSuffix(baseURL, "/") + "/openai/deployments/" + cfg.OpenAI.Deployment
+	}
+
+	// Create request editor to add authorization and identification headers
 	authEditor := WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("User-Agent", "gopus/"+version.Version)
+		if azure {
+			req.Header.Set("api-key", cfg.OpenAI.APIKey)
+			query := req.URL.Query()
+			query.Set("api-version", cfg.OpenAI.APIVersion)
+			req.URL.RawQuery = query.Encode()
+			return nil
+		}
 		req.Header.Set("Authorization", "Bearer "+cfg.OpenAI.APIKey)
+		if cfg.OpenAI.Organization != "" {
+			req.Header.Set("OpenAI-Organization", cfg.OpenAI.Organization)
+		}
+		if cfg.OpenAI.Project != "" {
+			req.Header.Set("OpenAI-Project", cfg.OpenAI.Project)
+		}
 		return nil
 	})
 
 	// Create the generated client
 	client, err := NewClientWithResponses(
-		cfg.OpenAI.BaseURL,
+		baseURL,
 		WithHTTPClient(httpClient),
 		authEditor,
 	)
@@ -48,12 +140,59 @@ func NewChatClient(cfg *config.Config) (*ChatClient, error) {
 		return nil, fmt.Errorf("failed to create OpenAI client: %w", err)
 	}
 
-	return &ChatClient{
-		client:      client,
-		model:       cfg.OpenAI.Model,
-		maxTokens:   cfg.OpenAI.MaxTokens,
-		temperature: float32(cfg.OpenAI.Temperature),
-	}, nil
+	chatClient := &ChatClient{
+		client:   client,
+		debugLog: log.New(os.Stderr, "", log.LstdFlags),
+	}
+	chatClient.UpdateSettings(cfg.OpenAI)
+	return chatClient, nil
+}
+
+// UpdateSettings applies new model parameters to subsequent requests;
+// requests already in flight keep the snapshot they started with. Settings
+// baked into the underlying HTTP client (base_url, api_key) cannot be
+// changed here and require creating a new client.
+func (c *ChatClient) UpdateSettings(cfg config.OpenAIConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.settings = requestSettings{
+		model:            cfg.Model,
+		maxTokens:        cfg.MaxTokens,
+		temperature:      float32(cfg.Temperature),
+		stop:             cfg.Stop,
+		topP:             float32Ptr(cfg.TopP),
+		presencePenalty:  float32Ptr(cfg.PresencePenalty),
+		frequencyPenalty: float32Ptr(cfg.FrequencyPenalty),
+		seed:             cfg.Seed,
+	}
+	c.debug = cfg.Debug
+}
+
+// currentSettings returns a point-in-time copy of the request settings.
+func (c *ChatClient) currentSettings() requestSettings {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.settings
+}
+
+// debugf logs a diagnostic line when openai.debug is enabled.
+func (c *ChatClient) debugf(format string, args ...any) {
+	c.mu.Lock()
+	debug := c.debug
+	c.mu.Unlock()
+	if debug {
+		c.debugLog.Printf("[openai] "+format, args...)
+	}
+}
+
+// float32Ptr narrows an optional float64 to the float32 the generated
+// request types use, preserving nil as "not sent".
+func float32Ptr(v *float64) *float32 {
+	if v == nil {
+		return nil
+	}
+	f := float32(*v)
+	return &f
 }
 
 // RoleUser is the role constant for user messages.
@@ -72,12 +211,71 @@ func (c *ChatClient) ChatCompletion(ctx context.Context, messages []ChatCompleti
 
 // ChatCompletionWithTools sends a chat completion request with optional tools.
 func (c *ChatClient) ChatCompletionWithTools(ctx context.Context, messages []ChatCompletionRequestMessage, tools []ChatCompletionTool) (*ChatCompletionResponse, error) {
-	// Build the request
+	return c.ChatCompletionWithOptions(ctx, messages, tools, nil)
+}
+
+// ChatCompletionWithOptions sends a chat completion request with optional
+// tools and per-call overrides layered over the client defaults.
+func (c *ChatClient) ChatCompletionWithOptions(ctx context.Context, messages []ChatCompletionRequestMessage, tools []ChatCompletionTool, opts *RequestOptions) (*ChatCompletionResponse, error) {
+	settings := c.currentSettings()
+	model := settings.model
+	maxTokens := settings.maxTokens
+	temperature := settings.temperature
+	stop := settings.stop
+	topP := settings.topP
+	presencePenalty := settings.presencePenalty
+	frequencyPenalty := settings.frequencyPenalty
+	seed := settings.seed
+	if opts != nil {
+		if opts.Model != "" {
+			model = opts.Model
+		}
+		if opts.MaxTokens > 0 {
+			maxTokens = opts.MaxTokens
+		}
+		if opts.Temperature != nil {
+			temperature = *opts.Temperature
+		}
+		if len(opts.Stop) > 0 {
+			stop = opts.Stop
+		}
+		if opts.TopP != nil {
+			topP = float32Ptr(opts.TopP)
+		}
+		if opts.PresencePenalty != nil {
+			presencePenalty = float32Ptr(opts.PresencePenalty)
+		}
+		if opts.FrequencyPenalty != nil {
+			frequencyPenalty = float32Ptr(opts.FrequencyPenalty)
+		}
+		if opts.Seed != nil {
+			seed = opts.Seed
+		}
+	}
+	var responseFormat *ResponseFormat
+	if opts != nil {
+		responseFormat = opts.ResponseFormat
+	}
+
+	// Build the request; the optional generation parameters stay null
+	// when neither the config nor the caller set them
 	req := CreateChatCompletionRequest{
-		Model:       c.model,
-		Messages:    messages,
-		MaxTokens:   &c.maxTokens,
-		Temperature: &c.temperature,
+		Model:            model,
+		Messages:         messages,
+		MaxTokens:        &maxTokens,
+		Temperature:      &temperature,
+		TopP:             topP,
+		PresencePenalty:  presencePenalty,
+		FrequencyPenalty: frequencyPenalty,
+		Seed:             seed,
+		ResponseFormat:   responseFormat,
+	}
+	if len(stop) > 0 {
+		var s CreateChatCompletionRequest_Stop
+		if err := s.FromCreateChatCompletionRequestStop1(stop); err != nil {
+			return nil, fmt.Errorf("failed to encode stop sequences: %w", err)
+		}
+		req.Stop = &s
 	}
 
 	// Add tools if provided
@@ -85,32 +283,54 @@ func (c *ChatClient) ChatCompletionWithTools(ctx context.Context, messages []Cha
 		req.Tools = &tools
 	}
 
-	// Send the request using the generated client
-	resp, err := c.client.CreateChatCompletionWithResponse(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
+	// Send the request using the generated client. A rate-limited response
+	// naming a short Retry-After wait is retried once after waiting it out.
+	for attempt := 0; ; attempt++ {
+		resp, err := c.client.CreateChatCompletionWithResponse(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
 
-	// Handle error responses
-	if resp.JSON400 != nil {
-		return nil, &resp.JSON400.Error
-	}
-	if resp.JSON401 != nil {
-		return nil, &resp.JSON401.Error
-	}
-	if resp.JSON429 != nil {
-		return nil, &resp.JSON429.Error
-	}
-	if resp.JSON500 != nil {
-		return nil, &resp.JSON500.Error
-	}
+		// Handle error responses, attaching the HTTP-level context (status,
+		// request ID, rate-limit headers) the body alone does not carry
+		var errResp *ErrorResponse
+		switch {
+		case resp.JSON400 != nil:
+			errResp = resp.JSON400
+		case resp.JSON401 != nil:
+			errResp = resp.JSON401
+		case resp.JSON429 != nil:
+			errResp = resp.JSON429
+		case resp.JSON500 != nil:
+			errResp = resp.JSON500
+		}
+		if errResp != nil {
+			apiErr := newAPIRequestError(errResp.Error, resp.HTTPResponse)
+			if apiErr.StatusCode == http.StatusTooManyRequests && attempt == 0 {
+				if wait := apiErr.RateLimit.RetryAfter; wait > 0 && wait <= maxRetryAfterWait {
+					c.debugf("rate limited, retrying after %s (request id %s)", wait, apiErr.RequestID)
+					select {
+					case <-time.After(wait):
+						continue
+					case <-ctx.Done():
+						return nil, ctx.Err()
+					}
+				}
+			}
+			return nil, apiErr
+		}
 
-	// Check for successful response
-	if resp.JSON200 == nil {
-		return nil, fmt.Errorf("unexpected response status: %s", resp.Status())
-	}
+		// Check for successful response
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("unexpected response status: %s", resp.Status())
+		}
 
-	return resp.JSON200, nil
+		if rl := parseRateLimit(resp.HTTPResponse); rl.RemainingTokens >= 0 {
+			c.debugf("rate limit headroom: %d tokens, %d requests remaining",
+				rl.RemainingTokens, rl.RemainingRequests)
+		}
+		return resp.JSON200, nil
+	}
 }
 
 // Error implements the error interface for APIError.
@@ -129,19 +349,45 @@ func (c *ChatClient) ChatCompletionX(ctx context.Context, messages []ChatComplet
 	return extractMessageContent(resp)
 }
 
-// ExtractMessageContent extracts the message content from a ChatCompletionResponse.
-// Returns ErrEmptyResponse if the response has no choices or empty content.
+// extractMessageContent extracts the message content from a ChatCompletionResponse.
+// Returns ErrEmptyResponse if the response has no choices or nil/empty content,
+// which is what a pure tool-call reply carries.
 func extractMessageContent(resp *ChatCompletionResponse) (string, error) {
-	choice, err := extractFirstChoice(resp)
+	choice, err := ExtractFirstChoice(resp)
 	if err != nil {
 		return "", err
 	}
-	if choice == nil {
+	if choice.Message.Content == nil || *choice.Message.Content == "" {
 		return "", ErrEmptyResponse
 	}
 	return *choice.Message.Content, nil
 }
 
+// ExtractContentAndToolCalls returns the first choice's content and tool
+// calls, for callers prepared to handle either. Content is "" for a pure
+// tool-call reply; ErrEmptyResponse is only returned when the response
+// carries neither content nor tool calls.
+func ExtractContentAndToolCalls(resp *ChatCompletionResponse) (string, []ChatCompletionMessageToolCall, error) {
+	choice, err := ExtractFirstChoice(resp)
+	if err != nil {
+		return "", nil, err
+	}
+
+	content := ""
+	if choice.Message.Content != nil {
+		content = *choice.Message.Content
+	}
+	var toolCalls []ChatCompletionMessageToolCall
+	if choice.Message.ToolCalls != nil {
+		toolCalls = *choice.Message.ToolCalls
+	}
+
+	if content == "" && len(toolCalls) == 0 {
+		return "", nil, ErrEmptyResponse
+	}
+	return content, toolCalls, nil
+}
+
 // ChatCompletionWithToolsX is a convenience function that calls ChatCompletionWithTools and extracts
 // the first choice, handling the case of empty choices.
 // Returns ErrEmptyResponse if the response has no choices.
@@ -150,14 +396,55 @@ func (c *ChatClient) ChatCompletionWithToolsX(ctx context.Context, messages []Ch
 	if err != nil {
 		return nil, err
 	}
-	return extractFirstChoice(resp)
+	return ExtractFirstChoice(resp)
 }
 
 // ExtractFirstChoice extracts the first choice from a ChatCompletionResponse.
-// Returns ErrEmptyResponse if the response has no choices.
-func extractFirstChoice(resp *ChatCompletionResponse) (*ChatCompletionChoice, error) {
-	if len(resp.Choices) == 0 {
+// Returns ErrEmptyResponse if the response is nil or has no choices.
+func ExtractFirstChoice(resp *ChatCompletionResponse) (*ChatCompletionChoice, error) {
+	if resp == nil || len(resp.Choices) == 0 {
 		return nil, ErrEmptyResponse
 	}
 	return &resp.Choices[0], nil
 }
+
+// AssistantMessageWithToolCalls converts a response message carrying tool
+// calls back into request form, so it can be appended to the chat history
+// ahead of the tool result messages.
+func AssistantMessageWithToolCalls(message ChatCompletionResponseMessage) ChatCompletionRequestMessage {
+	var toolCalls []ChatCompletionMessageToolCall
+	if message.ToolCalls != nil {
+		for _, tc := range *message.ToolCalls {
+			toolCalls = append(toolCalls, ChatCompletionMessageToolCall{
+				Id:   tc.Id,
+				Type: ChatCompletionMessageToolCallTypeFunction,
+				Function: ChatCompletionMessageToolCallFunction{
+					Name:      tc.Function.Name,
+					Arguments: tc.Function.Arguments,
+				},
+			})
+		}
+	}
+
+	// The response content is a plain string (or null); convert to the
+	// request content form
+	var content *ChatCompletionRequestMessage_Content
+	if message.Content != nil {
+		content = StringContent(*message.Content)
+	}
+
+	return ChatCompletionRequestMessage{
+		Role:      ChatCompletionRequestMessageRoleAssistant,
+		Content:   content,
+		ToolCalls: &toolCalls,
+	}
+}
+
+// ToolResultMessage builds the tool role message answering a tool call.
+func ToolResultMessage(toolCallID, content string) ChatCompletionRequestMessage {
+	return ChatCompletionRequestMessage{
+		Role:       ChatCompletionRequestMessageRoleTool,
+		Content:    StringContent(content),
+		ToolCallId: &toolCallID,
+	}
+}
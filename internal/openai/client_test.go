@@ -0,0 +1,198 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gopus/internal/config"
+	"gopus/internal/testutil"
+)
+
+func strPtr(s string) *string { return &s }
+
+func toolCallResponse(content *string, toolCalls []ChatCompletionMessageToolCall) *ChatCompletionResponse {
+	msg := ChatCompletionResponseMessage{
+		Role:    ChatCompletionResponseMessageRoleAssistant,
+		Content: content,
+	}
+	if toolCalls != nil {
+		msg.ToolCalls = &toolCalls
+	}
+	return &ChatCompletionResponse{
+		Choices: []ChatCompletionChoice{{Index: 0, Message: msg}},
+	}
+}
+
+func sampleToolCalls() []ChatCompletionMessageToolCall {
+	return []ChatCompletionMessageToolCall{{
+		Id:       "call_1",
+		Type:     ChatCompletionMessageToolCallTypeFunction,
+		Function: ChatCompletionMessageToolCallFunction{Name: "read_file", Arguments: "{}"},
+	}}
+}
+
+func TestExtractFirstChoiceEmpty(t *testing.T) {
+	if _, err := ExtractFirstChoice(nil); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("expected ErrEmptyResponse for nil response, got %v", err)
+	}
+	if _, err := ExtractFirstChoice(&ChatCompletionResponse{}); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("expected ErrEmptyResponse for zero choices, got %v", err)
+	}
+}
+
+func TestExtractMessageContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    *ChatCompletionResponse
+		want    string
+		wantErr bool
+	}{
+		{"content present", toolCallResponse(strPtr("hello"), nil), "hello", false},
+		{"nil content", toolCallResponse(nil, nil), "", true},
+		{"empty content", toolCallResponse(strPtr(""), nil), "", true},
+		{"pure tool call", toolCallResponse(nil, sampleToolCalls()), "", true},
+		{"zero choices", &ChatCompletionResponse{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractMessageContent(tt.resp)
+			if tt.wantErr {
+				if !errors.Is(err, ErrEmptyResponse) {
+					t.Errorf("expected ErrEmptyResponse, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExtractContentAndToolCalls(t *testing.T) {
+	content, calls, err := ExtractContentAndToolCalls(toolCallResponse(nil, sampleToolCalls()))
+	if err != nil {
+		t.Fatalf("unexpected error for pure tool call: %v", err)
+	}
+	if content != "" || len(calls) != 1 || calls[0].Function.Name != "read_file" {
+		t.Errorf("unexpected result: content %q, calls %+v", content, calls)
+	}
+
+	content, calls, err = ExtractContentAndToolCalls(toolCallResponse(strPtr("partial answer"), sampleToolCalls()))
+	if err != nil {
+		t.Fatalf("unexpected error for content plus tool calls: %v", err)
+	}
+	if content != "partial answer" || len(calls) != 1 {
+		t.Errorf("unexpected result: content %q, calls %+v", content, calls)
+	}
+
+	if _, _, err := ExtractContentAndToolCalls(toolCallResponse(nil, nil)); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("expected ErrEmptyResponse when neither is present, got %v", err)
+	}
+	if _, _, err := ExtractContentAndToolCalls(&ChatCompletionResponse{}); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("expected ErrEmptyResponse for zero choices, got %v", err)
+	}
+}
+
+// recordedRequest captures the URL and headers of the last request a test
+// server received, so the exact request shape can be asserted.
+type recordedRequest struct {
+	path   string
+	query  url.Values
+	header http.Header
+}
+
+// newRecordingServer starts a server that records each request into rec
+// and answers with a minimal completion.
+func newRecordingServer(t *testing.T, rec *recordedRequest) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.path = r.URL.Path
+		rec.query = r.URL.Query()
+		rec.header = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, testutil.CompletionBody(`"ok"`, "stop"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestNewChatClientOpenAIRequestShape asserts the URL and auth headers of
+// the default endpoint shape, including the attribution headers.
+func TestNewChatClientOpenAIRequestShape(t *testing.T) {
+	var rec recordedRequest
+	srv := newRecordingServer(t, &rec)
+
+	client, err := NewChatClient(&config.Config{OpenAI: config.OpenAIConfig{
+		APIKey:       "test-key",
+		Model:        "test-model",
+		BaseURL:      srv.URL,
+		Organization: "org-123",
+		Project:      "proj_456",
+	}})
+	if err != nil {
+		t.Fatalf("NewChatClient: %v", err)
+	}
+	if _, err := client.ChatCompletion(context.Background(), nil); err != nil {
+		t.Fatalf("ChatCompletion: %v", err)
+	}
+
+	if rec.path != "/chat/completions" {
+		t.Errorf("unexpected path %q", rec.path)
+	}
+	if got := rec.header.Get("Authorization"); got != "Bearer test-key" {
+		t.Errorf("unexpected Authorization header %q", got)
+	}
+	if got := rec.header.Get("OpenAI-Organization"); got != "org-123" {
+		t.Errorf("unexpected OpenAI-Organization header %q", got)
+	}
+	if got := rec.header.Get("OpenAI-Project"); got != "proj_456" {
+		t.Errorf("unexpected OpenAI-Project header %q", got)
+	}
+	if got := rec.header.Get("api-key"); got != "" {
+		t.Errorf("api-key header must not be sent in openai mode, got %q", got)
+	}
+}
+
+// TestNewChatClientAzureRequestShape asserts the deployment-based URL,
+// api-version parameter, and api-key header of the Azure shape.
+func TestNewChatClientAzureRequestShape(t *testing.T) {
+	var rec recordedRequest
+	srv := newRecordingServer(t, &rec)
+
+	client, err := NewChatClient(&config.Config{OpenAI: config.OpenAIConfig{
+		APIKey:     "azure-key",
+		Model:      "gpt-4o",
+		BaseURL:    srv.URL,
+		APIType:    config.APITypeAzure,
+		Deployment: "my-deployment",
+		APIVersion: "2024-06-01",
+	}})
+	if err != nil {
+		t.Fatalf("NewChatClient: %v", err)
+	}
+	if _, err := client.ChatCompletion(context.Background(), nil); err != nil {
+		t.Fatalf("ChatCompletion: %v", err)
+	}
+
+	if rec.path != "/openai/deployments/my-deployment/chat/completions" {
+		t.Errorf("unexpected path %q", rec.path)
+	}
+	if got := rec.query.Get("api-version"); got != "2024-06-01" {
+		t.Errorf("unexpected api-version %q", got)
+	}
+	if got := rec.header.Get("api-key"); got != "azure-key" {
+		t.Errorf("unexpected api-key header %q", got)
+	}
+	if got := rec.header.Get("Authorization"); got != "" {
+		t.Errorf("Authorization header must not be sent in azure mode, got %q", got)
+	}
+}
@@ -0,0 +1,84 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"gopus/internal/config"
+)
+
+// TestConcurrentRequestsDuringSettingsUpdates fires chat completions from
+// several goroutines while the settings are toggled between two configs,
+// as /reload can do while a background summarization request is in flight.
+// Run with -race (make test-race); every request must also carry one of
+// the two configured temperatures — never a torn or mixed payload.
+func TestConcurrentRequestsDuringSettingsUpdates(t *testing.T) {
+	var observed sync.Map // temperature values seen in requests
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CreateChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.Temperature == nil {
+			t.Error("expected a temperature in every request")
+		} else {
+			observed.Store(*req.Temperature, true)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(completionOK))
+	}))
+	defer srv.Close()
+
+	cfgA := config.OpenAIConfig{APIKey: "test-key", Model: "test-model", BaseURL: srv.URL, MaxTokens: 100, Temperature: 0.2}
+	cfgB := cfgA
+	cfgB.Temperature = 0.9
+
+	client, err := NewChatClient(&config.Config{OpenAI: cfgA})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	const workers = 4
+	const requestsPerWorker = 20
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range requestsPerWorker {
+				if _, err := client.ChatCompletion(context.Background(), userMessages("hi")); err != nil {
+					t.Errorf("ChatCompletion: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	// Toggle the settings while the requests run
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			if i%2 == 0 {
+				client.UpdateSettings(cfgB)
+			} else {
+				client.UpdateSettings(cfgA)
+			}
+		}
+	}()
+	wg.Wait()
+	<-done
+
+	observed.Range(func(key, _ any) bool {
+		temp := key.(float32)
+		if temp != 0.2 && temp != 0.9 {
+			t.Errorf("request carried temperature %g, want 0.2 or 0.9", temp)
+		}
+		return true
+	})
+}
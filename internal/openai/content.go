@@ -0,0 +1,61 @@
+package openai
+
+// StringContent wraps plain text as request message content.
+func StringContent(s string) *ChatCompletionRequestMessage_Content {
+	var content ChatCompletionRequestMessage_Content
+	// Marshalling a string cannot fail
+	_ = content.FromChatCompletionRequestMessageContent0(s)
+	return &content
+}
+
+// PartsContent wraps multi-part content (text and image parts) as request message content.
+func PartsContent(parts []ChatCompletionContentPart) *ChatCompletionRequestMessage_Content {
+	var content ChatCompletionRequestMessage_Content
+	// Marshalling a slice of parts cannot fail
+	_ = content.FromChatCompletionRequestMessageContent1(parts)
+	return &content
+}
+
+// ContentAsString extracts the plain-text form of request message content.
+// Returns false if the content is nil or in the multi-part form.
+func ContentAsString(c *ChatCompletionRequestMessage_Content) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	s, err := c.AsChatCompletionRequestMessageContent0()
+	if err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// ContentAsParts extracts the multi-part form of request message content.
+// Returns false if the content is nil or a plain string.
+func ContentAsParts(c *ChatCompletionRequestMessage_Content) ([]ChatCompletionContentPart, bool) {
+	if c == nil {
+		return nil, false
+	}
+	parts, err := c.AsChatCompletionRequestMessageContent1()
+	if err != nil {
+		return nil, false
+	}
+	return parts, true
+}
+
+// NewTextPart creates a text content part.
+func NewTextPart(text string) ChatCompletionContentPart {
+	return ChatCompletionContentPart{
+		Type: Text,
+		Text: &text,
+	}
+}
+
+// NewImagePart creates an image_url content part from a URL or data URI.
+func NewImagePart(url string) ChatCompletionContentPart {
+	return ChatCompletionContentPart{
+		Type: ImageUrl,
+		ImageUrl: &ChatCompletionContentPartImageURL{
+			Url: url,
+		},
+	}
+}
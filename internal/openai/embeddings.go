@@ -0,0 +1,102 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// The embeddings endpoint is not part of the minimal OpenAPI spec the chat
+// client is generated from; the call is small enough to keep hand-written.
+
+// embeddingsRequest is the request body for the embeddings endpoint.
+type embeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingsResponse is the response body for the embeddings endpoint.
+// Only the fields gopus uses are decoded.
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *APIError `json:"error"`
+}
+
+// Embeddings requests embedding vectors for the given inputs, returned in
+// input order. The model is passed per call since embedding models are
+// configured separately from the chat model.
+func (c *ChatClient) Embeddings(ctx context.Context, model string, inputs []string) ([][]float32, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+	raw, ok := c.client.ClientInterface.(*Client)
+	if !ok {
+		return nil, fmt.Errorf("embeddings are not supported by this client")
+	}
+
+	body, err := json.Marshal(embeddingsRequest{Model: model, Input: inputs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode embeddings request: %w", err)
+	}
+
+	serverURL, err := url.Parse(raw.Server)
+	if err != nil {
+		return nil, err
+	}
+	queryURL, err := serverURL.Parse("./embeddings")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, queryURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := raw.applyEditors(ctx, req, nil); err != nil {
+		return nil, err
+	}
+
+	resp, err := raw.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var decoded embeddingsResponse
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode embeddings response: %w", err)
+	}
+	if decoded.Error != nil {
+		return nil, decoded.Error
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	if len(decoded.Data) != len(inputs) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(decoded.Data))
+	}
+
+	// The API documents no ordering guarantee; put vectors back in input order
+	sort.Slice(decoded.Data, func(i, j int) bool {
+		return decoded.Data[i].Index < decoded.Data[j].Index
+	})
+	vectors := make([][]float32, len(decoded.Data))
+	for i, d := range decoded.Data {
+		vectors[i] = d.Embedding
+	}
+	return vectors, nil
+}
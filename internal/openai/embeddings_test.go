@@ -0,0 +1,94 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gopus/internal/config"
+)
+
+// embeddingsTestClient returns a ChatClient pointed at a server that
+// answers the embeddings endpoint with the given handler.
+func embeddingsTestClient(t *testing.T, handler http.HandlerFunc) (*ChatClient, *httptest.Server) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := NewChatClient(&config.Config{
+		OpenAI: config.OpenAIConfig{APIKey: "test-key", Model: "test-model", BaseURL: srv.URL},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client, srv
+}
+
+func TestEmbeddingsReordersByIndex(t *testing.T) {
+	client, _ := embeddingsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer test-key" {
+			t.Errorf("unexpected Authorization header %q", auth)
+		}
+		var req embeddingsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.Model != "embed-model" || len(req.Input) != 2 {
+			t.Errorf("unexpected request %+v", req)
+		}
+		// Deliberately out of order to exercise the re-sort
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [
+			{"index": 1, "embedding": [0, 1]},
+			{"index": 0, "embedding": [1, 0]}
+		]}`))
+	})
+
+	vectors, err := client.Embeddings(context.Background(), "embed-model", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embeddings: %v", err)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Errorf("expected vectors in input order, got %v", vectors)
+	}
+}
+
+func TestEmbeddingsAPIError(t *testing.T) {
+	client, _ := embeddingsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": {"message": "invalid api key", "type": "invalid_request_error"}}`))
+	})
+
+	_, err := client.Embeddings(context.Background(), "embed-model", []string{"a"})
+	if err == nil || err.Error() != "invalid api key" {
+		t.Errorf("expected the API error message, got %v", err)
+	}
+}
+
+func TestEmbeddingsCountMismatch(t *testing.T) {
+	client, _ := embeddingsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [{"index": 0, "embedding": [1]}]}`))
+	})
+
+	if _, err := client.Embeddings(context.Background(), "embed-model", []string{"a", "b"}); err == nil {
+		t.Error("expected an error for a vector count mismatch")
+	}
+}
+
+func TestEmbeddingsNoInputs(t *testing.T) {
+	client, _ := embeddingsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request expected for empty input")
+	})
+
+	vectors, err := client.Embeddings(context.Background(), "embed-model", nil)
+	if err != nil || vectors != nil {
+		t.Errorf("expected nil, nil for empty input, got %v, %v", vectors, err)
+	}
+}
@@ -0,0 +1,79 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// IsNetworkError reports whether err is a transport-level failure (DNS,
+// refused connection, timeout) rather than an answer from the API. Such
+// requests never reached the server, so resending them is safe.
+func IsNetworkError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// RateLimit carries the rate-limit state the API reported on a response.
+// The remaining counts are -1 when the corresponding header was absent.
+type RateLimit struct {
+	RemainingRequests int           // x-ratelimit-remaining-requests
+	RemainingTokens   int           // x-ratelimit-remaining-tokens
+	RetryAfter        time.Duration // suggested wait before retrying; 0 when absent
+}
+
+// APIRequestError enriches an APIError with the HTTP-level context needed
+// to follow up on a failure: the status code, the request ID to quote to
+// support, and the rate-limit headers.
+type APIRequestError struct {
+	APIError
+	StatusCode int
+	RequestID  string
+	RateLimit  RateLimit
+}
+
+// Error returns the API's message. Server-side failures include the request
+// ID, since that is what support asks for.
+func (e *APIRequestError) Error() string {
+	if e.StatusCode >= 500 && e.RequestID != "" {
+		return fmt.Sprintf("%s (request id %s)", e.Message, e.RequestID)
+	}
+	return e.Message
+}
+
+// Unwrap exposes the inner APIError so callers matching on it with
+// errors.As keep working.
+func (e *APIRequestError) Unwrap() error { return &e.APIError }
+
+// newAPIRequestError wraps an APIError with the HTTP context of the
+// response that carried it.
+func newAPIRequestError(apiErr APIError, resp *http.Response) *APIRequestError {
+	e := &APIRequestError{APIError: apiErr, RateLimit: parseRateLimit(resp)}
+	if resp != nil {
+		e.StatusCode = resp.StatusCode
+		e.RequestID = resp.Header.Get("x-request-id")
+	}
+	return e
+}
+
+// parseRateLimit extracts the rate-limit headers from a response.
+// Retry-After is parsed as (possibly fractional) seconds.
+func parseRateLimit(resp *http.Response) RateLimit {
+	rl := RateLimit{RemainingRequests: -1, RemainingTokens: -1}
+	if resp == nil {
+		return rl
+	}
+	if v, err := strconv.Atoi(resp.Header.Get("x-ratelimit-remaining-requests")); err == nil {
+		rl.RemainingRequests = v
+	}
+	if v, err := strconv.Atoi(resp.Header.Get("x-ratelimit-remaining-tokens")); err == nil {
+		rl.RemainingTokens = v
+	}
+	if v, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil && v > 0 {
+		rl.RetryAfter = time.Duration(v * float64(time.Second))
+	}
+	return rl
+}
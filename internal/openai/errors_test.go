@@ -0,0 +1,176 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gopus/internal/config"
+)
+
+// errorBody is a minimal API error envelope.
+func errorBody(message string) string {
+	return `{"error": {"message": "` + message + `", "type": "server_error"}}`
+}
+
+// completionOK is a minimal successful completion body.
+const completionOK = `{
+	"id": "chatcmpl-test",
+	"object": "chat.completion",
+	"created": 0,
+	"model": "test-model",
+	"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]
+}`
+
+// chatTestClient returns a ChatClient pointed at a server with the given
+// handler.
+func chatTestClient(t *testing.T, handler http.HandlerFunc) *ChatClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := NewChatClient(&config.Config{
+		OpenAI: config.OpenAIConfig{APIKey: "test-key", Model: "test-model", BaseURL: srv.URL},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestServerErrorIncludesRequestID(t *testing.T) {
+	client := chatTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req_abc123")
+		w.Header().Set("X-Ratelimit-Remaining-Requests", "7")
+		w.Header().Set("X-Ratelimit-Remaining-Tokens", "4200")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(errorBody("backend exploded")))
+	})
+
+	_, err := client.ChatCompletion(context.Background(), userMessages("hi"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "request id req_abc123") {
+		t.Errorf("expected the request ID in the error, got %q", err)
+	}
+
+	var reqErr *APIRequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected an APIRequestError, got %T", err)
+	}
+	if reqErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("StatusCode = %d, want 500", reqErr.StatusCode)
+	}
+	if reqErr.RateLimit.RemainingRequests != 7 || reqErr.RateLimit.RemainingTokens != 4200 {
+		t.Errorf("RateLimit = %+v, want remaining 7 requests / 4200 tokens", reqErr.RateLimit)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "backend exploded" {
+		t.Errorf("expected the inner APIError to unwrap, got %v", apiErr)
+	}
+}
+
+func TestClientErrorOmitsRequestID(t *testing.T) {
+	client := chatTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req_abc123")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(errorBody("bad request")))
+	})
+
+	_, err := client.ChatCompletion(context.Background(), userMessages("hi"))
+	if err == nil || strings.Contains(err.Error(), "request id") {
+		t.Errorf("expected no request ID for a 4xx error, got %v", err)
+	}
+}
+
+func TestRateLimitRetriesAfterWait(t *testing.T) {
+	calls := 0
+	client := chatTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(errorBody("rate limited")))
+			return
+		}
+		w.Write([]byte(completionOK))
+	})
+
+	resp, err := client.ChatCompletion(context.Background(), userMessages("hi"))
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 requests, got %d", calls)
+	}
+	if content, err := extractMessageContent(resp); err != nil || content != "ok" {
+		t.Errorf("unexpected content %q, %v", content, err)
+	}
+}
+
+func TestRateLimitWithoutRetryAfterFailsFast(t *testing.T) {
+	calls := 0
+	client := chatTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(errorBody("rate limited")))
+	})
+
+	_, err := client.ChatCompletion(context.Background(), userMessages("hi"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Errorf("expected no retry without Retry-After, got %d requests", calls)
+	}
+}
+
+func TestParseRateLimitAbsentHeaders(t *testing.T) {
+	rl := parseRateLimit(&http.Response{Header: http.Header{}})
+	if rl.RemainingRequests != -1 || rl.RemainingTokens != -1 || rl.RetryAfter != 0 {
+		t.Errorf("expected absent headers to read as unknown, got %+v", rl)
+	}
+	if rl := parseRateLimit(nil); rl.RemainingRequests != -1 || rl.RemainingTokens != -1 {
+		t.Errorf("expected nil response to read as unknown, got %+v", rl)
+	}
+}
+
+func userMessages(text string) []ChatCompletionRequestMessage {
+	return []ChatCompletionRequestMessage{
+		{Role: RoleUser, Content: StringContent(text)},
+	}
+}
+
+func TestIsNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close() // a closed server refuses connections
+
+	client, err := NewChatClient(&config.Config{
+		OpenAI: config.OpenAIConfig{APIKey: "test-key", Model: "test-model", BaseURL: srv.URL},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.ChatCompletion(context.Background(), userMessages("hi"))
+	if err == nil {
+		t.Fatal("expected a connection error")
+	}
+	if !IsNetworkError(err) {
+		t.Errorf("expected a refused connection to be a network error: %v", err)
+	}
+
+	apiErr := &APIRequestError{APIError: APIError{Message: "bad request"}, StatusCode: 400}
+	if IsNetworkError(apiErr) {
+		t.Error("an API error must not count as a network error")
+	}
+}
@@ -0,0 +1,29 @@
+package openai
+
+// modelPrice is the USD cost per million prompt and completion tokens.
+type modelPrice struct {
+	promptPerM     float64
+	completionPerM float64
+}
+
+// modelPricing lists API prices for known models, the spending counterpart
+// of contextWindows. Unknown models are not listed, so spending limits
+// cannot be enforced for them.
+var modelPricing = map[string]modelPrice{
+	"gpt-3.5-turbo": {promptPerM: 0.50, completionPerM: 1.50},
+	"gpt-4":         {promptPerM: 30.00, completionPerM: 60.00},
+	"gpt-4o":        {promptPerM: 2.50, completionPerM: 10.00},
+	"gpt-4o-mini":   {promptPerM: 0.15, completionPerM: 0.60},
+	"gpt-4.1-mini":  {promptPerM: 0.40, completionPerM: 1.60},
+}
+
+// Cost returns the USD cost of a request with the given token counts, or
+// ok=false when the model's pricing is unknown.
+func Cost(model string, promptTokens, completionTokens int) (usd float64, ok bool) {
+	price, ok := modelPricing[model]
+	if !ok {
+		return 0, false
+	}
+	return float64(promptTokens)*price.promptPerM/1e6 +
+		float64(completionTokens)*price.completionPerM/1e6, true
+}
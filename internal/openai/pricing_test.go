@@ -0,0 +1,18 @@
+package openai
+
+import "testing"
+
+func TestCost(t *testing.T) {
+	// gpt-4o: $2.50 per million prompt tokens, $10.00 per million completion
+	usd, ok := Cost("gpt-4o", 1_000_000, 100_000)
+	if !ok {
+		t.Fatal("expected pricing for gpt-4o")
+	}
+	if usd != 3.50 {
+		t.Errorf("Cost = %v, want 3.50", usd)
+	}
+
+	if _, ok := Cost("some-unknown-model", 1000, 1000); ok {
+		t.Error("expected no pricing for an unknown model")
+	}
+}
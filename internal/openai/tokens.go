@@ -0,0 +1,27 @@
+package openai
+
+// EstimateTokens roughly estimates how many tokens text costs: about one
+// token per four bytes, which tracks English prose closely enough for
+// budgeting displays. It deliberately avoids a real tokenizer dependency.
+func EstimateTokens(s string) int {
+	if s == "" {
+		return 0
+	}
+	return (len(s) + 3) / 4
+}
+
+// contextWindows lists the total context window sizes for known models,
+// the counterpart of maxTokensLimits. Unknown models are not listed.
+var contextWindows = map[string]int{
+	"gpt-3.5-turbo": 16385,
+	"gpt-4":         8192,
+	"gpt-4o":        128000,
+	"gpt-4o-mini":   128000,
+	"gpt-4.1-mini":  1047576,
+}
+
+// ContextWindow returns the total context window for a model, or 0 if the
+// model is unknown.
+func ContextWindow(model string) int {
+	return contextWindows[model]
+}
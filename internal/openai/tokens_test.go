@@ -0,0 +1,30 @@
+package openai
+
+import "testing"
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hi", 1},
+		{"1234", 1},
+		{"12345", 2},
+		{"The quick brown fox jumps over the lazy dog", 11},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestContextWindow(t *testing.T) {
+	if got := ContextWindow("gpt-4o"); got != 128000 {
+		t.Errorf("expected the gpt-4o window, got %d", got)
+	}
+	if got := ContextWindow("some-unknown-model"); got != 0 {
+		t.Errorf("expected 0 for an unknown model, got %d", got)
+	}
+}
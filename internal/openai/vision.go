@@ -0,0 +1,71 @@
+package openai
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxImageBytes is the maximum size of a local image that can be attached
+// to a message. The API rejects larger payloads anyway, so fail locally.
+const MaxImageBytes = 20 * 1024 * 1024 // ~20MB
+
+// imageMIMETypes maps supported image file extensions to their MIME types.
+var imageMIMETypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".webp": "image/webp",
+}
+
+// ImageDataURI reads a local png/jpeg/webp image and encodes it as a
+// base64 data URI suitable for an image_url content part.
+func ImageDataURI(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	mimeType, ok := imageMIMETypes[ext]
+	if !ok {
+		return "", fmt.Errorf("unsupported image format %q (supported: png, jpg, jpeg, webp)", ext)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to access image: %w", err)
+	}
+	if info.Size() > MaxImageBytes {
+		return "", fmt.Errorf("image is %.1fMB, exceeding the %dMB limit; please downscale it first",
+			float64(info.Size())/(1024*1024), MaxImageBytes/(1024*1024))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+	return fmt.Sprintf("data:%s;base64,%s", mimeType, encoded), nil
+}
+
+// visionModelPrefixes lists model name prefixes known to accept image input.
+var visionModelPrefixes = []string{
+	"gpt-4o",
+	"gpt-4.1",
+	"gpt-4-turbo",
+	"gpt-4-vision",
+	"gpt-5",
+	"o1",
+	"o3",
+	"o4",
+}
+
+// SupportsVision reports whether the given model is known to accept image
+// content parts. Used to fail locally before sending an image request.
+func SupportsVision(model string) bool {
+	for _, prefix := range visionModelPrefixes {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
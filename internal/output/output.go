@@ -0,0 +1,52 @@
+// Package output defines stable, machine-readable output structures for CLI
+// subcommands invoked with --json. Field names are snake_case and timestamps
+// marshal as RFC3339. These types are the documented JSON schema: changing a
+// field name or type is a breaking change for scripts built around gopus.
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Session describes one stored chat session.
+type Session struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	MessageCount int       `json:"message_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Tool describes one available MCP tool.
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Server      string `json:"server"`
+}
+
+// Server describes one connected MCP server.
+type Server struct {
+	Name      string `json:"name"`
+	Builtin   bool   `json:"builtin"`
+	ToolCount int    `json:"tool_count"`
+}
+
+// DoctorReport describes the environment checks performed by `gopus doctor`.
+type DoctorReport struct {
+	ConfigOK            bool   `json:"config_ok"`
+	ConfigError         string `json:"config_error,omitempty"`
+	Model               string `json:"model,omitempty"`
+	SessionsDir         string `json:"sessions_dir"`
+	SessionsDirWritable bool   `json:"sessions_dir_writable"`
+	SessionCount        int    `json:"session_count"`
+	ClipboardBackend    string `json:"clipboard_backend,omitempty"`
+}
+
+// Encode writes v to w as indented JSON followed by a newline.
+func Encode(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Golden-JSON tests freeze the documented schema: if one of these fails,
+// a field name or type changed and scripts consuming --json output break.
+
+func encodeToString(t *testing.T, v any) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := Encode(&sb, v); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	return sb.String()
+}
+
+func TestSessionGoldenJSON(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	got := encodeToString(t, Session{
+		ID:           "abc-123",
+		Name:         "test session",
+		MessageCount: 7,
+		CreatedAt:    ts,
+		UpdatedAt:    ts,
+	})
+
+	want := `{
+  "id": "abc-123",
+  "name": "test session",
+  "message_count": 7,
+  "created_at": "2024-05-01T12:30:00Z",
+  "updated_at": "2024-05-01T12:30:00Z"
+}
+`
+	if got != want {
+		t.Errorf("Session JSON schema changed:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToolGoldenJSON(t *testing.T) {
+	got := encodeToString(t, Tool{
+		Name:        "echo",
+		Description: "Echoes back the input message",
+		Server:      "builtin",
+	})
+
+	want := `{
+  "name": "echo",
+  "description": "Echoes back the input message",
+  "server": "builtin"
+}
+`
+	if got != want {
+		t.Errorf("Tool JSON schema changed:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestServerGoldenJSON(t *testing.T) {
+	got := encodeToString(t, Server{
+		Name:      "builtin",
+		Builtin:   true,
+		ToolCount: 3,
+	})
+
+	want := `{
+  "name": "builtin",
+  "builtin": true,
+  "tool_count": 3
+}
+`
+	if got != want {
+		t.Errorf("Server JSON schema changed:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDoctorReportGoldenJSON(t *testing.T) {
+	got := encodeToString(t, DoctorReport{
+		ConfigOK:            true,
+		Model:               "gpt-4o",
+		SessionsDir:         "/tmp/.gopus/sessions",
+		SessionsDirWritable: true,
+		SessionCount:        2,
+		ClipboardBackend:    "xclip",
+	})
+
+	want := `{
+  "config_ok": true,
+  "model": "gpt-4o",
+  "sessions_dir": "/tmp/.gopus/sessions",
+  "sessions_dir_writable": true,
+  "session_count": 2,
+  "clipboard_backend": "xclip"
+}
+`
+	if got != want {
+		t.Errorf("DoctorReport JSON schema changed:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// AsyncPrinter serializes output produced while the user may be typing at a
+// prompt. Messages are printed on their own line and the prompt prefix is
+// redrawn below them, so background notices (reconnects, summarization
+// results) don't garble the input line. The typed buffer itself cannot be
+// restored without a readline integration; until that lands this implements
+// the minimal "clear line, print above, redraw prompt" pattern.
+type AsyncPrinter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	prompt string // current prompt prefix, or "" when not waiting for input
+}
+
+// NewAsyncPrinter creates an AsyncPrinter writing to out.
+func NewAsyncPrinter(out io.Writer) *AsyncPrinter {
+	return &AsyncPrinter{out: out}
+}
+
+// SetPrompt records the prompt prefix currently displayed, so it can be
+// redrawn after asynchronous messages. Call when waiting for input.
+func (p *AsyncPrinter) SetPrompt(prompt string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prompt = prompt
+}
+
+// ClearPrompt marks that no prompt is displayed. Call once input has been
+// submitted.
+func (p *AsyncPrinter) ClearPrompt() {
+	p.SetPrompt("")
+}
+
+// Printf prints a background message. When a prompt is displayed, the
+// current line is cleared first and the prompt is redrawn after the message.
+func (p *AsyncPrinter) Printf(format string, args ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	msg := fmt.Sprintf(format, args...)
+	if p.prompt == "" {
+		fmt.Fprintln(p.out, msg)
+		return
+	}
+
+	// Clear the prompt line, print the message above, redraw the prompt.
+	fmt.Fprintf(p.out, "\r\033[K%s\n%s", msg, p.prompt)
+}
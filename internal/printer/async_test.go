@@ -0,0 +1,50 @@
+package printer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAsyncPrinterWithoutPrompt(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewAsyncPrinter(&buf)
+
+	p.Printf("server %s reconnected", "wiki")
+
+	if got := buf.String(); got != "server wiki reconnected\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestAsyncPrinterRedrawsPrompt(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewAsyncPrinter(&buf)
+	p.SetPrompt("user: ")
+
+	p.Printf("summarization finished")
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "\r\033[K") {
+		t.Errorf("expected line clear before message, got %q", got)
+	}
+	if !strings.Contains(got, "summarization finished\n") {
+		t.Errorf("expected message on its own line, got %q", got)
+	}
+	if !strings.HasSuffix(got, "user: ") {
+		t.Errorf("expected prompt redrawn after message, got %q", got)
+	}
+}
+
+func TestAsyncPrinterClearPrompt(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewAsyncPrinter(&buf)
+	p.SetPrompt("user: ")
+	p.ClearPrompt()
+
+	p.Printf("late notice")
+
+	if got := buf.String(); got != "late notice\n" {
+		t.Errorf("expected plain output after ClearPrompt, got %q", got)
+	}
+}
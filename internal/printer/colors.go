@@ -0,0 +1,96 @@
+package printer
+
+import "os"
+
+// Raw ANSI escape sequences. The exported Color* variables hold these when
+// color output is enabled and empty strings otherwise, so callers can embed
+// them unconditionally.
+const (
+	ansiReset   = "\033[0m"
+	ansiDim     = "\033[2m" // Dim/faint intensity
+	ansiBold    = "\033[1m"
+	ansiRed     = "\033[31m"
+	ansiGreen   = "\033[32m"
+	ansiYellow  = "\033[33m"
+	ansiBlue    = "\033[34m"
+	ansiMagenta = "\033[35m"
+	ansiCyan    = "\033[36m"
+)
+
+// ANSI color codes for terminal output. These are empty strings when color
+// output is disabled (non-TTY stdout, NO_COLOR, or --no-color).
+var (
+	ColorReset   = ansiReset
+	ColorDim     = ansiDim
+	ColorBold    = ansiBold
+	ColorRed     = ansiRed
+	ColorGreen   = ansiGreen
+	ColorYellow  = ansiYellow
+	ColorBlue    = ansiBlue
+	ColorMagenta = ansiMagenta
+	ColorCyan    = ansiCyan
+)
+
+// colorsEnabled tracks whether ANSI output is active.
+var colorsEnabled = detectColors()
+
+func init() {
+	applyColors()
+}
+
+// ColorsEnabled reports whether ANSI colors and animations should be emitted.
+func ColorsEnabled() bool {
+	return colorsEnabled
+}
+
+// SetColors overrides automatic color detection. Used by the --no-color and
+// --force-color flags.
+func SetColors(enabled bool) {
+	colorsEnabled = enabled
+	applyColors()
+}
+
+// detectColors decides the initial color setting: colors are disabled when
+// the NO_COLOR environment variable is set (https://no-color.org) or when
+// stdout is not a terminal.
+func detectColors() bool {
+	if _, noColor := os.LookupEnv("NO_COLOR"); noColor {
+		return false
+	}
+	return IsTerminal(os.Stdout)
+}
+
+// IsTerminal reports whether the given file refers to a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// applyColors sets the exported Color* variables to the raw escape
+// sequences or to empty strings, depending on colorsEnabled.
+func applyColors() {
+	if colorsEnabled {
+		ColorReset = ansiReset
+		ColorDim = ansiDim
+		ColorBold = ansiBold
+		ColorRed = ansiRed
+		ColorGreen = ansiGreen
+		ColorYellow = ansiYellow
+		ColorBlue = ansiBlue
+		ColorMagenta = ansiMagenta
+		ColorCyan = ansiCyan
+		return
+	}
+	ColorReset = ""
+	ColorDim = ""
+	ColorBold = ""
+	ColorRed = ""
+	ColorGreen = ""
+	ColorYellow = ""
+	ColorBlue = ""
+	ColorMagenta = ""
+	ColorCyan = ""
+}
@@ -0,0 +1,25 @@
+package printer
+
+import "testing"
+
+func TestSetColorsTogglesEscapeCodes(t *testing.T) {
+	orig := ColorsEnabled()
+	defer SetColors(orig)
+
+	SetColors(false)
+	if ColorGreen != "" || ColorReset != "" {
+		t.Errorf("expected empty color codes when disabled, got %q %q", ColorGreen, ColorReset)
+	}
+
+	SetColors(true)
+	if ColorGreen != ansiGreen || ColorReset != ansiReset {
+		t.Errorf("expected raw escape codes when enabled, got %q %q", ColorGreen, ColorReset)
+	}
+}
+
+func TestDetectColorsHonorsNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if detectColors() {
+		t.Error("expected colors disabled when NO_COLOR is set")
+	}
+}
@@ -0,0 +1,108 @@
+package printer
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"gopus/internal/i18n"
+)
+
+// defaultDedupWindow is how long an identical warning/error keeps being
+// collapsed before the repeat notice is flushed.
+const defaultDedupWindow = 5 * time.Second
+
+// Patterns stripped from messages before comparison, so that the same
+// failure differing only in IDs or timestamps still counts as a repeat.
+var (
+	uuidPattern      = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+	numberPattern    = regexp.MustCompile(`\b\d+\b`)
+)
+
+// normalizeMessage reduces a message to a comparison key by replacing IDs,
+// timestamps, and numbers with placeholders.
+func normalizeMessage(msg string) string {
+	msg = uuidPattern.ReplaceAllString(msg, "<id>")
+	msg = timestampPattern.ReplaceAllString(msg, "<time>")
+	msg = numberPattern.ReplaceAllString(msg, "<n>")
+	return msg
+}
+
+// deduper collapses consecutive identical warning/error messages: repeats
+// within the window are suppressed and summarized as a single "previous
+// message repeated N times" line once a different message arrives or the
+// window closes.
+type deduper struct {
+	mu        sync.Mutex
+	window    time.Duration
+	emit      func(color, msg string)
+	lastKey   string
+	lastColor string
+	lastAt    time.Time
+	repeats   int
+	timer     *time.Timer
+}
+
+// newDeduper creates a deduper with the given suppression window and
+// output function.
+func newDeduper(window time.Duration, emit func(color, msg string)) *deduper {
+	return &deduper{window: window, emit: emit}
+}
+
+// errorDedup collapses repeated PrintError/PrintWarning output to stderr.
+var errorDedup = newDeduper(defaultDedupWindow, func(color, msg string) {
+	fmt.Fprintf(os.Stderr, "%s%s%s\n", color, msg, ColorReset)
+})
+
+// print emits msg in the given color, or suppresses it if it repeats the
+// previous message within the window.
+func (d *deduper) print(color, msg string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := normalizeMessage(msg)
+	if key == d.lastKey && time.Since(d.lastAt) < d.window {
+		d.repeats++
+		d.lastAt = time.Now()
+		d.scheduleFlushLocked()
+		return
+	}
+
+	d.flushLocked()
+	d.lastKey = key
+	d.lastColor = color
+	d.lastAt = time.Now()
+	d.emit(color, msg)
+	d.scheduleFlushLocked()
+}
+
+// flushLocked emits the pending repeat notice, if any. Callers must hold mu.
+func (d *deduper) flushLocked() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.repeats > 0 {
+		d.emit(d.lastColor, i18n.T(i18n.ErrorRepeated, d.repeats))
+		d.repeats = 0
+	}
+}
+
+// scheduleFlushLocked arranges for the repeat notice to be emitted when the
+// window closes without further output. Callers must hold mu.
+func (d *deduper) scheduleFlushLocked() {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.timer = time.AfterFunc(d.window, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if time.Since(d.lastAt) >= d.window {
+			d.flushLocked()
+			d.lastKey = ""
+		}
+	})
+}
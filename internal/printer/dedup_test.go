@@ -0,0 +1,101 @@
+package printer
+
+import (
+	"testing"
+	"time"
+)
+
+// testDeduper returns a deduper that records emitted lines.
+func testDeduper(window time.Duration) (*deduper, *[]string) {
+	var lines []string
+	d := newDeduper(window, func(color, msg string) {
+		lines = append(lines, msg)
+	})
+	return d, &lines
+}
+
+func TestDedupCollapsesRepeats(t *testing.T) {
+	d, lines := testDeduper(time.Minute)
+
+	d.print(ColorRed, "server down")
+	d.print(ColorRed, "server down")
+	d.print(ColorRed, "server down")
+	d.print(ColorRed, "something else")
+
+	want := []string{"server down", "previous message repeated 2 times", "something else"}
+	if len(*lines) != len(want) {
+		t.Fatalf("expected %d lines, got %v", len(want), *lines)
+	}
+	for i, line := range want {
+		if (*lines)[i] != line {
+			t.Errorf("line %d: expected %q, got %q", i, line, (*lines)[i])
+		}
+	}
+}
+
+func TestDedupInterleavedMessagesNotCollapsed(t *testing.T) {
+	d, lines := testDeduper(time.Minute)
+
+	d.print(ColorRed, "error a")
+	d.print(ColorRed, "error b")
+	d.print(ColorRed, "error a")
+
+	if len(*lines) != 3 {
+		t.Fatalf("expected all 3 interleaved messages, got %v", *lines)
+	}
+}
+
+func TestDedupIgnoresIDsAndTimestamps(t *testing.T) {
+	d, lines := testDeduper(time.Minute)
+
+	d.print(ColorRed, "request 123e4567-e89b-12d3-a456-426614174000 failed at 2026-08-31T10:00:01Z")
+	d.print(ColorRed, "request 00000000-0000-0000-0000-000000000000 failed at 2026-08-31T10:00:02Z")
+	d.print(ColorRed, "done")
+
+	want := "previous message repeated 1 times"
+	if len(*lines) != 3 || (*lines)[1] != want {
+		t.Fatalf("expected repeat notice %q, got %v", want, *lines)
+	}
+}
+
+func TestDedupFlushesWhenWindowCloses(t *testing.T) {
+	d, lines := testDeduper(20 * time.Millisecond)
+
+	d.print(ColorRed, "flaky")
+	d.print(ColorRed, "flaky")
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		d.mu.Lock()
+		n := len(*lines)
+		d.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected repeat notice after window closed, got %v", *lines)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if (*lines)[1] != "previous message repeated 1 times" {
+		t.Errorf("expected repeat notice, got %q", (*lines)[1])
+	}
+}
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"plain text", "plain text"},
+		{"session 123e4567-e89b-12d3-a456-426614174000 gone", "session <id> gone"},
+		{"failed at 2026-08-31 10:00:01", "failed at <time>"},
+		{"retry 3 of 5", "retry <n> of <n>"},
+	}
+	for _, tt := range tests {
+		if got := normalizeMessage(tt.input); got != tt.want {
+			t.Errorf("normalizeMessage(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
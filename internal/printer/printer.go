@@ -3,34 +3,102 @@ package printer
 
 import (
 	"fmt"
-	"os"
+	"strings"
+	"time"
 )
 
-// ANSI escape codes for terminal output
-const (
-	ColorReset  = "\033[0m"
-	ColorDim    = "\033[2m" // Dim/faint intensity
-	ColorRed    = "\033[31m"
-	ColorGreen  = "\033[32m"
-	ColorYellow = "\033[33m"
-	ColorBlue   = "\033[34m"
-	ColorCyan   = "\033[36m"
-)
+// showTimestamps toggles a dim time stamp before each message, set from
+// ui.show_timestamps and the /timestamps command.
+var showTimestamps bool
+
+// SetShowTimestamps enables or disables the time stamp prefix.
+func SetShowTimestamps(enabled bool) {
+	showTimestamps = enabled
+}
+
+// TimestampsEnabled reports whether the time stamp prefix is active.
+func TimestampsEnabled() bool {
+	return showTimestamps
+}
 
 // PrintMessage outputs a chat message with appropriate formatting based on role and history status.
-// role: the message role (user, assistant, or system)
+// role: the message role (user, assistant, system, tool, note, or summary),
+// optionally suffixed with an attribution after " · "
+// (e.g. "assistant · gpt-4o-mini" or "summary · condensed · 12 messages")
 // message: the content to display
 // isHistory: if true, uses dim intensity for historical/loaded messages
 func PrintMessage(role string, message string, isHistory bool) {
+	fmt.Print(FormatMessage(role, message, isHistory))
+}
+
+// PrintMessageAt outputs a chat message stamped with its own recorded time:
+// history replay shows how long ago it was said ("2h ago"), live messages
+// the local clock time. A zero time prints without a stamp, so sessions
+// recorded before timestamps existed display unchanged.
+func PrintMessageAt(role string, message string, isHistory bool, at time.Time) {
+	fmt.Print(messageStamp(isHistory, at) + formatMessage(role, message, isHistory))
+}
+
+// messageStamp renders the dim stamp prefix, or "" when stamps are off or
+// the time was never recorded.
+func messageStamp(isHistory bool, at time.Time) string {
+	if !showTimestamps || at.IsZero() {
+		return ""
+	}
+	stamp := at.Local().Format("15:04")
+	if isHistory {
+		stamp = RelativeTime(at, time.Now())
+	}
+	return ColorDim + "[" + stamp + "]" + ColorReset + " "
+}
+
+// RelativeTime renders how long before now t was, in the coarsest sensible
+// unit: "just now", "5m ago", "3h ago", "2d ago".
+func RelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// FormatMessage renders a chat message as a styled line, without printing it.
+// When timestamps are enabled, live (non-history) messages are prefixed with
+// the current clock time.
+func FormatMessage(role string, message string, isHistory bool) string {
+	stamp := ""
+	if !isHistory {
+		stamp = messageStamp(false, time.Now())
+	}
+	return stamp + formatMessage(role, message, isHistory)
+}
+
+// formatMessage renders the styled line itself, without any stamp.
+func formatMessage(role string, message string, isHistory bool) string {
+	// The color is chosen by the base role, ignoring any attribution suffix
+	baseRole, _, _ := strings.Cut(role, " · ")
+
 	// Select color based on role
 	var color string
-	switch role {
+	switch baseRole {
 	case "user":
 		color = ColorGreen
 	case "assistant":
 		color = ColorBlue
 	case "system":
 		color = ColorYellow
+	case "note":
+		color = ColorMagenta
+	case "tool":
+		color = ColorCyan
+	case "summary":
+		color = ColorBold + ColorCyan
 	default:
 		color = ColorCyan
 	}
@@ -41,11 +109,18 @@ func PrintMessage(role string, message string, isHistory bool) {
 		dim = ColorDim
 	}
 
-	fmt.Printf("%s%s%s%s: %s%s%s\n", dim, color, role, ColorReset, dim, message, ColorReset)
+	return fmt.Sprintf("%s%s%s%s: %s%s%s\n", dim, color, role, ColorReset, dim, message, ColorReset)
 }
 
-// PrintError outputs an error message in red to stderr.
+// PrintError outputs an error message in red to stderr. Repeated identical
+// messages (ignoring IDs and timestamps) are collapsed into a single
+// "previous message repeated N times" line.
 func PrintError(format string, args ...any) {
-	msg := fmt.Sprintf(format, args...)
-	fmt.Fprintf(os.Stderr, "%s%s%s\n", ColorRed, msg, ColorReset)
+	errorDedup.print(ColorRed, fmt.Sprintf(format, args...))
+}
+
+// PrintWarning outputs a warning message in yellow to stderr, with the same
+// repeat collapsing as PrintError.
+func PrintWarning(format string, args ...any) {
+	errorDedup.print(ColorYellow, fmt.Sprintf(format, args...))
 }
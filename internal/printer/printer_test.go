@@ -0,0 +1,122 @@
+package printer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatMessageGolden(t *testing.T) {
+	orig := ColorsEnabled()
+	defer SetColors(orig)
+	SetColors(true)
+
+	tests := []struct {
+		name      string
+		role      string
+		message   string
+		isHistory bool
+		want      string
+	}{
+		{
+			name: "user live", role: "user", message: "hi",
+			want: "\033[32muser\033[0m: hi\033[0m\n",
+		},
+		{
+			name: "user history", role: "user", message: "hi", isHistory: true,
+			want: "\033[2m\033[32muser\033[0m: \033[2mhi\033[0m\n",
+		},
+		{
+			name: "assistant live", role: "assistant · gpt-4o-mini", message: "hello",
+			want: "\033[34massistant · gpt-4o-mini\033[0m: hello\033[0m\n",
+		},
+		{
+			name: "assistant history", role: "assistant · gpt-4o-mini", message: "hello", isHistory: true,
+			want: "\033[2m\033[34massistant · gpt-4o-mini\033[0m: \033[2mhello\033[0m\n",
+		},
+		{
+			name: "system live", role: "system", message: "note to self",
+			want: "\033[33msystem\033[0m: note to self\033[0m\n",
+		},
+		{
+			name: "system history", role: "system", message: "note to self", isHistory: true,
+			want: "\033[2m\033[33msystem\033[0m: \033[2mnote to self\033[0m\n",
+		},
+		{
+			name: "note live", role: "note", message: "private",
+			want: "\033[35mnote\033[0m: private\033[0m\n",
+		},
+		{
+			name: "note history", role: "note", message: "private", isHistory: true,
+			want: "\033[2m\033[35mnote\033[0m: \033[2mprivate\033[0m\n",
+		},
+		{
+			name: "tool live", role: "tool", message: "42",
+			want: "\033[36mtool\033[0m: 42\033[0m\n",
+		},
+		{
+			name: "tool history", role: "tool", message: "42", isHistory: true,
+			want: "\033[2m\033[36mtool\033[0m: \033[2m42\033[0m\n",
+		},
+		{
+			name: "summary live", role: "summary · condensed · 12 messages", message: "we discussed X",
+			want: "\033[1m\033[36msummary · condensed · 12 messages\033[0m: we discussed X\033[0m\n",
+		},
+		{
+			name: "summary history", role: "summary · condensed · 12 messages", message: "we discussed X", isHistory: true,
+			want: "\033[2m\033[1m\033[36msummary · condensed · 12 messages\033[0m: \033[2mwe discussed X\033[0m\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatMessage(tt.role, tt.message, tt.isHistory); got != tt.want {
+				t.Errorf("FormatMessage(%q, %q, %v) = %q, want %q",
+					tt.role, tt.message, tt.isHistory, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2026, 8, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{3 * time.Hour, "3h ago"},
+		{49 * time.Hour, "2d ago"},
+	}
+	for _, tt := range tests {
+		if got := RelativeTime(now.Add(-tt.ago), now); got != tt.want {
+			t.Errorf("RelativeTime(-%s) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
+
+func TestMessageStamp(t *testing.T) {
+	origColors := ColorsEnabled()
+	defer SetColors(origColors)
+	SetColors(false)
+	defer SetShowTimestamps(TimestampsEnabled())
+
+	SetShowTimestamps(false)
+	if got := messageStamp(false, time.Now()); got != "" {
+		t.Errorf("expected no stamp while disabled, got %q", got)
+	}
+
+	SetShowTimestamps(true)
+	at := time.Now().Add(-2 * time.Hour)
+	if got := messageStamp(true, at); got != "[2h ago] " {
+		t.Errorf("unexpected history stamp %q", got)
+	}
+	if got := messageStamp(false, at); got != "["+at.Local().Format("15:04")+"] " {
+		t.Errorf("unexpected live stamp %q", got)
+	}
+	// Messages recorded before timestamps existed carry a zero time and
+	// must render without a stamp
+	if got := messageStamp(true, time.Time{}); got != "" {
+		t.Errorf("expected no stamp for a zero time, got %q", got)
+	}
+}
@@ -0,0 +1,19 @@
+package printer
+
+import "gopus/internal/term"
+
+// NarrowWidth is the terminal width in columns below which output switches
+// to narrow mode: stacked tables, a collapsed prompt, and no spinner label.
+const NarrowWidth = 40
+
+// TerminalWidth returns the terminal width in columns, falling back to 80
+// when stdout is not a terminal. The size tracker in internal/term keeps
+// the value current across resizes, so it is fine to call on every render.
+func TerminalWidth() int {
+	return term.Width()
+}
+
+// IsNarrow reports whether the terminal is too narrow for full-width output.
+func IsNarrow() bool {
+	return TerminalWidth() < NarrowWidth
+}
@@ -0,0 +1,129 @@
+package printer
+
+import (
+	"fmt"
+	"strings"
+
+	"gopus/internal/term"
+	"gopus/internal/text"
+)
+
+// WrapOptions configures PrintWrapped.
+type WrapOptions struct {
+	// Margin is subtracted from the detected terminal width before
+	// wrapping, leaving breathing room at the right edge.
+	Margin int
+	// CodeGutter prefixes every line of a fenced code block with a dim
+	// "│ " gutter so verbatim blocks stand out from wrapped prose.
+	CodeGutter bool
+}
+
+// DefaultWrapOptions returns the wrap options used for assistant messages.
+func DefaultWrapOptions() WrapOptions {
+	return WrapOptions{Margin: 2}
+}
+
+// PrintWrapped outputs a chat message like PrintMessage, soft-wrapping the
+// content at the terminal width minus the configured margin. Hard newlines
+// are preserved, continuation lines hang under list bullets and numbered
+// items, and fenced code blocks print verbatim. When stdout is not a
+// terminal or the width cannot be detected the message prints unwrapped.
+func PrintWrapped(role, message string, opts WrapOptions) {
+	fmt.Print(FormatWrapped(role, message, opts))
+}
+
+// FormatWrapped renders the wrapped message as PrintWrapped prints it,
+// without printing.
+func FormatWrapped(role, message string, opts WrapOptions) string {
+	width, ok := term.WidthOK()
+	if !ok {
+		return FormatMessage(role, message, false)
+	}
+	width -= opts.Margin
+	if width < NarrowWidth {
+		return FormatMessage(role, message, false)
+	}
+	return FormatMessage(role, wrapMessage(message, width, opts.CodeGutter), false)
+}
+
+// wrapMessage wraps message to width display columns. Every existing
+// newline is kept; only lines wider than the width are broken further.
+func wrapMessage(message string, width int, codeGutter bool) string {
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			out = append(out, gutter(line, codeGutter))
+			continue
+		}
+		if inFence {
+			// Code is whitespace-sensitive; never rewrap it
+			out = append(out, gutter(line, codeGutter))
+			continue
+		}
+		out = append(out, wrapProseLine(line, width)...)
+	}
+	return strings.Join(out, "\n")
+}
+
+// gutter prefixes a verbatim code line with a dim gutter when enabled.
+func gutter(line string, enabled bool) string {
+	if !enabled {
+		return line
+	}
+	return ColorDim + "│ " + ColorReset + line
+}
+
+// wrapProseLine breaks one prose line at word boundaries. Continuation
+// lines hang under the line's leading whitespace and list marker, so
+// bullets and numbered items stay aligned. A single word wider than the
+// width overflows on its own line rather than breaking mid-word.
+func wrapProseLine(line string, width int) []string {
+	if text.Width(line) <= width {
+		return []string{line}
+	}
+
+	trimmed := strings.TrimLeft(line, " \t")
+	lead := line[:len(line)-len(trimmed)]
+	marker := listMarker(trimmed)
+	prefix := lead + marker
+	base := text.Width(prefix)
+	hang := strings.Repeat(" ", base)
+
+	var lines []string
+	current, currentWidth := prefix, base
+	for _, word := range strings.Fields(trimmed[len(marker):]) {
+		wordWidth := text.Width(word)
+		if currentWidth > base && currentWidth+1+wordWidth > width {
+			lines = append(lines, current)
+			current, currentWidth = hang, base
+		}
+		if currentWidth > base {
+			current += " "
+			currentWidth++
+		}
+		current += word
+		currentWidth += wordWidth
+	}
+	return append(lines, current)
+}
+
+// listMarker returns the list marker at the start of an already-trimmed
+// line — a bullet like "- " or a numbered item like "12. " — or "" for a
+// plain line.
+func listMarker(s string) string {
+	for _, m := range []string{"- ", "* ", "+ ", "• "} {
+		if strings.HasPrefix(s, m) {
+			return m
+		}
+	}
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i > 0 && i+1 < len(s) && (s[i] == '.' || s[i] == ')') && s[i+1] == ' ' {
+		return s[:i+2]
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+
+	"gopus/internal/text"
+)
+
+func TestWrapMessageBreaksAtWordBoundaries(t *testing.T) {
+	msg := "a reply that is clearly too long to fit on one short line"
+
+	lines := strings.Split(wrapMessage(msg, 20, false), "\n")
+
+	if len(lines) < 3 {
+		t.Fatalf("expected several wrapped lines, got %q", lines)
+	}
+	for _, line := range lines {
+		if w := text.Width(line); w > 20 {
+			t.Errorf("line %q is %d columns wide, max 20", line, w)
+		}
+		if strings.HasPrefix(line, " ") || strings.HasSuffix(line, " ") {
+			t.Errorf("unexpected stray spaces around %q", line)
+		}
+	}
+	if got := strings.Join(strings.Fields(strings.Join(lines, " ")), " "); got != msg {
+		t.Errorf("wrapping lost or reordered words: %q", got)
+	}
+}
+
+func TestWrapMessagePreservesHardNewlines(t *testing.T) {
+	msg := "first paragraph\n\nsecond paragraph"
+
+	if got := wrapMessage(msg, 40, false); got != msg {
+		t.Errorf("expected short lines untouched, got %q", got)
+	}
+}
+
+func TestWrapMessageHangsListContinuations(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    string
+		indent string
+	}{
+		{"bullet", "- a bullet item with far too many words to stay on a line", "  "},
+		{"numbered", "12. a numbered item with far too many words to stay on a line", "    "},
+		{"indented bullet", "  * nested bullet with far too many words to stay on a line", "    "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(wrapMessage(tt.msg, 30, false), "\n")
+			if len(lines) < 2 {
+				t.Fatalf("expected the item to wrap, got %q", lines)
+			}
+			for _, cont := range lines[1:] {
+				if !strings.HasPrefix(cont, tt.indent) || strings.HasPrefix(cont, tt.indent+" ") {
+					t.Errorf("expected continuation hanging under the marker with %q, got %q", tt.indent, cont)
+				}
+			}
+		})
+	}
+}
+
+func TestWrapMessageLeavesCodeBlocksVerbatim(t *testing.T) {
+	code := "```go\nfunc veryLongFunctionName(aVeryLongParameterName string) error { return nil }\n```"
+	msg := "see below\n" + code
+
+	if got := wrapMessage(msg, 20, false); !strings.Contains(got, code) {
+		t.Errorf("expected the fenced block untouched, got %q", got)
+	}
+}
+
+func TestWrapMessageCodeGutter(t *testing.T) {
+	orig := ColorsEnabled()
+	defer SetColors(orig)
+	SetColors(true)
+
+	msg := "```\ncode line\n```"
+
+	got := wrapMessage(msg, 40, true)
+
+	want := ColorDim + "│ " + ColorReset
+	for i, line := range strings.Split(got, "\n") {
+		if !strings.HasPrefix(line, want) {
+			t.Errorf("line %d: expected the dim gutter prefix, got %q", i, line)
+		}
+	}
+}
+
+func TestWrapMessageCountsDisplayColumns(t *testing.T) {
+	// Each CJK rune is two columns, so six runes already fill twelve columns
+	msg := "日本語のテキスト です ね"
+
+	for _, line := range strings.Split(wrapMessage(msg, 16, false), "\n") {
+		if w := text.Width(line); w > 16 {
+			t.Errorf("line %q is %d display columns wide, max 16", line, w)
+		}
+	}
+}
+
+func TestWrapMessageOverlongWordOverflows(t *testing.T) {
+	url := "https://example.invalid/a/very/long/path/that/keeps/going"
+
+	got := wrapMessage("see "+url, 20, false)
+
+	if !strings.Contains(got, url) {
+		t.Errorf("expected the overlong word unbroken, got %q", got)
+	}
+}
+
+func TestListMarker(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"- item", "- "},
+		{"* item", "* "},
+		{"• item", "• "},
+		{"12. item", "12. "},
+		{"3) item", "3) "},
+		{"plain text", ""},
+		{"12.no space", ""},
+		{"-dash word", ""},
+	}
+	for _, tt := range tests {
+		if got := listMarker(tt.line); got != tt.want {
+			t.Errorf("listMarker(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
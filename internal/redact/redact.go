@@ -0,0 +1,157 @@
+// Package redact scans outgoing text for secrets such as API keys and
+// private key material, so credentials pasted into a prompt or leaked
+// through a tool result never reach the API.
+package redact
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Finding is one detected secret occurrence.
+type Finding struct {
+	Type  string // detector name, e.g. "aws-access-key"
+	Match string // the matched text
+}
+
+// detector pairs a name with the pattern that detects one kind of secret.
+type detector struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// defaultPatterns are the built-in detectors. They favor precision over
+// recall: false positives train users to confirm without reading.
+var defaultPatterns = []struct{ name, pattern string }{
+	{"aws-access-key", `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+	{"api-key-assignment", `(?i)\b(?:api[_-]?key|secret|token|password)\b["']?\s*[=:]\s*["']?[A-Za-z0-9_\-/+]{16,}`},
+	{"private-key", `-----BEGIN [A-Z ]*PRIVATE KEY-----`},
+	{"jwt", `\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\b`},
+}
+
+// highEntropyCandidate matches tokens long enough to be machine-generated
+// credentials; entropy filtering decides whether they actually look random.
+var highEntropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_\-]{32,}`)
+
+// entropyThreshold is the Shannon entropy (bits per character) above which
+// a candidate token is reported. English prose sits well below 4; random
+// base64 approaches 6.
+const entropyThreshold = 4.5
+
+// Scanner detects secrets in text using the built-in patterns, optional
+// custom patterns from config, and an opt-in high-entropy heuristic.
+type Scanner struct {
+	detectors   []detector
+	highEntropy bool
+}
+
+// NewScanner builds a Scanner from the default detectors plus custom
+// name-to-regex patterns. With highEntropy set, long random-looking tokens
+// are reported even when no pattern matches them.
+func NewScanner(custom map[string]string, highEntropy bool) (*Scanner, error) {
+	s := &Scanner{highEntropy: highEntropy}
+	for _, p := range defaultPatterns {
+		s.detectors = append(s.detectors, detector{name: p.name, re: regexp.MustCompile(p.pattern)})
+	}
+
+	// Sorted so findings come out in a stable order
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := regexp.Compile(custom[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", name, err)
+		}
+		s.detectors = append(s.detectors, detector{name: name, re: re})
+	}
+	return s, nil
+}
+
+// Scan reports all secrets found in text without modifying it.
+func (s *Scanner) Scan(text string) []Finding {
+	var findings []Finding
+	for _, d := range s.detectors {
+		for _, match := range d.re.FindAllString(text, -1) {
+			findings = append(findings, Finding{Type: d.name, Match: match})
+		}
+	}
+	if s.highEntropy {
+		for _, match := range highEntropyCandidate.FindAllString(text, -1) {
+			if shannonEntropy(match) >= entropyThreshold {
+				findings = append(findings, Finding{Type: "high-entropy", Match: match})
+			}
+		}
+	}
+	return findings
+}
+
+// Redact replaces every secret in text with a [REDACTED:<type>] marker and
+// returns the findings. Pattern detectors run before the entropy heuristic,
+// so a recognized secret keeps its specific type.
+func (s *Scanner) Redact(text string) (string, []Finding) {
+	var findings []Finding
+	for _, d := range s.detectors {
+		text = d.re.ReplaceAllStringFunc(text, func(match string) string {
+			findings = append(findings, Finding{Type: d.name, Match: match})
+			return "[REDACTED:" + d.name + "]"
+		})
+	}
+	if s.highEntropy {
+		text = highEntropyCandidate.ReplaceAllStringFunc(text, func(match string) string {
+			if shannonEntropy(match) < entropyThreshold {
+				return match
+			}
+			findings = append(findings, Finding{Type: "high-entropy", Match: match})
+			return "[REDACTED:high-entropy]"
+		})
+	}
+	return text, findings
+}
+
+// Types returns the distinct finding types in first-seen order, for
+// compact notices like "redacted: aws-access-key, jwt".
+func Types(findings []Finding) []string {
+	var types []string
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		if !seen[f.Type] {
+			seen[f.Type] = true
+			types = append(types, f.Type)
+		}
+	}
+	return types
+}
+
+// Preview returns a shortened form of a match safe to print: enough to
+// recognize the secret without echoing all of it.
+func Preview(match string) string {
+	match = strings.ReplaceAll(match, "\n", " ")
+	if len(match) <= 12 {
+		return match
+	}
+	return match[:8] + "…" + match[len(match)-4:]
+}
+
+// shannonEntropy computes the Shannon entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	total := float64(len([]rune(s)))
+	entropy := 0.0
+	for _, n := range counts {
+		p := float64(n) / total
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
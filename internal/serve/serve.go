@@ -0,0 +1,235 @@
+// Package serve runs gopus itself as an MCP server, so other MCP-capable
+// applications can chat through gopus sessions and inspect stored history.
+// The server speaks MCP over stdio and exposes three tools: chat,
+// list_sessions, and get_session_messages.
+package serve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"gopus/internal/config"
+	"gopus/internal/history"
+	"gopus/internal/mcp/builtin"
+	"gopus/internal/output"
+	"gopus/internal/version"
+	"gopus/pkg/gopus"
+)
+
+// Server hosts the chat and session tools over MCP. Each session is backed
+// by one gopus.Conversation, whose own mutex serializes concurrent chat
+// calls to the same session while calls to different sessions proceed in
+// parallel.
+//
+// Unless allowTools is set, conversations run with no tools at all: a chat
+// driven by one MCP client must not itself reach shell, filesystem, or
+// other tool capabilities by surprise.
+type Server struct {
+	cfg        *config.Config
+	allowTools bool
+	manager    *history.Manager // read-only session listing and loading
+
+	mu    sync.Mutex
+	convs map[string]*gopus.Conversation
+}
+
+// New creates a Server from the given config. With allowTools set, the
+// builtin tools are available to chats driven through the server.
+func New(cfg *config.Config, allowTools bool) (*Server, error) {
+	manager, err := history.NewManager(cfg.History.SessionsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize history: %w", err)
+	}
+	return &Server{
+		cfg:        cfg,
+		allowTools: allowTools,
+		manager:    manager,
+		convs:      make(map[string]*gopus.Conversation),
+	}, nil
+}
+
+// MCPServer builds the MCP server with the chat and session tools
+// registered.
+func (s *Server) MCPServer() *server.MCPServer {
+	srv := server.NewMCPServer(
+		"gopus",
+		version.Version,
+		server.WithToolCapabilities(true),
+	)
+
+	srv.AddTool(mcplib.NewTool("chat",
+		mcplib.WithDescription("Send a message to a gopus session and return the assistant reply"),
+		mcplib.WithString("message",
+			mcplib.Required(),
+			mcplib.Description("The user message to send"),
+		),
+		mcplib.WithString("session",
+			mcplib.Description("ID of the session to continue; omit to start a new session"),
+		),
+	), s.handleChat)
+
+	srv.AddTool(mcplib.NewTool("list_sessions",
+		mcplib.WithDescription("List stored gopus sessions as JSON"),
+	), s.handleListSessions)
+
+	srv.AddTool(mcplib.NewTool("get_session_messages",
+		mcplib.WithDescription("Return the messages of a gopus session as JSON"),
+		mcplib.WithString("session",
+			mcplib.Required(),
+			mcplib.Description("ID of the session to read"),
+		),
+	), s.handleGetSessionMessages)
+
+	return srv
+}
+
+// Run serves MCP over the given streams until the context ends or the
+// client disconnects. The CLI passes stdin and stdout; stderr stays free
+// for diagnostics.
+func (s *Server) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	defer s.Close()
+	return server.NewStdioServer(s.MCPServer()).Listen(ctx, in, out)
+}
+
+// Close flushes and shuts down all open conversations.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var firstErr error
+	for id, conv := range s.convs {
+		if err := conv.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(s.convs, id)
+	}
+	return firstErr
+}
+
+// chatResult is the JSON payload the chat tool returns. The session ID lets
+// the caller continue the same session in later calls.
+type chatResult struct {
+	SessionID string `json:"session_id"`
+	Reply     string `json:"reply"`
+}
+
+// handleChat sends a message through the conversation engine and returns
+// the reply together with the session ID.
+func (s *Server) handleChat(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	message, err := builtin.GetRequiredStringArg(req, "message")
+	if err != nil {
+		return nil, err
+	}
+	sessionID := ""
+	if args, err := builtin.GetArgs(req); err == nil {
+		sessionID = builtin.GetOptionalStringArg(args, "session", "")
+	}
+
+	conv, err := s.conversation(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := conv.SendMessage(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(chatResult{SessionID: conv.Session().ID, Reply: reply})
+	if err != nil {
+		return nil, err
+	}
+	return mcplib.NewToolResultText(string(payload)), nil
+}
+
+// handleListSessions returns the stored sessions in the same JSON shape as
+// `gopus sessions list --json`.
+func (s *Server) handleListSessions(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	sessions, err := s.manager.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	list := make([]output.Session, 0, len(sessions))
+	for _, session := range sessions {
+		list = append(list, output.Session{
+			ID:           session.ID,
+			Name:         session.Name,
+			MessageCount: len(session.Messages),
+			CreatedAt:    session.CreatedAt,
+			UpdatedAt:    session.UpdatedAt,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := output.Encode(&buf, list); err != nil {
+		return nil, err
+	}
+	return mcplib.NewToolResultText(buf.String()), nil
+}
+
+// handleGetSessionMessages returns a session's messages as JSON. A session
+// currently open for chatting is read live; others come from disk.
+func (s *Server) handleGetSessionMessages(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	sessionID, err := builtin.GetRequiredStringArg(req, "session")
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []history.Message
+	s.mu.Lock()
+	conv, open := s.convs[sessionID]
+	s.mu.Unlock()
+	if open {
+		messages = conv.Session().Messages
+	} else {
+		session, err := s.manager.LoadSessionByID(sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load session: %w", err)
+		}
+		messages = session.Messages
+	}
+
+	payload, err := json.MarshalIndent(messages, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return mcplib.NewToolResultText(string(payload)), nil
+}
+
+// conversation returns the open conversation for the given session ID,
+// opening (or, with an empty ID, creating) one on first use.
+func (s *Server) conversation(sessionID string) (*gopus.Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sessionID != "" {
+		if conv, ok := s.convs[sessionID]; ok {
+			return conv, nil
+		}
+	}
+
+	conv, err := gopus.New(gopus.Options{
+		APIKey:             s.cfg.OpenAI.APIKey,
+		Model:              s.cfg.OpenAI.Model,
+		BaseURL:            s.cfg.OpenAI.BaseURL,
+		MaxTokens:          s.cfg.OpenAI.MaxTokens,
+		Temperature:        s.cfg.OpenAI.Temperature,
+		SystemPrompt:       s.cfg.Chat.SystemPrompt,
+		SessionsDir:        s.cfg.History.SessionsDir,
+		SessionID:          sessionID,
+		EnableBuiltinTools: s.allowTools,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session: %w", err)
+	}
+	s.convs[conv.Session().ID] = conv
+	return conv, nil
+}
@@ -0,0 +1,225 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"gopus/internal/config"
+)
+
+// completionBody builds a minimal chat completion response body.
+func completionBody(content string) string {
+	return fmt.Sprintf(`{
+		"id": "chatcmpl-test",
+		"object": "chat.completion",
+		"created": 0,
+		"model": "test-model",
+		"choices": [{
+			"index": 0,
+			"message": {"role": "assistant", "content": %q},
+			"finish_reason": "stop"
+		}]
+	}`, content)
+}
+
+// newTestClient starts a Server over an in-process transport and returns an
+// initialized MCP client driving it.
+func newTestClient(t *testing.T, cfg *config.Config, allowTools bool) *client.Client {
+	t.Helper()
+
+	srv, err := New(cfg, allowTools)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+
+	tr := transport.NewInProcessTransport(srv.MCPServer())
+	if err := tr.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start transport: %v", err)
+	}
+
+	c := client.NewClient(tr)
+	t.Cleanup(func() { c.Close() })
+
+	initRequest := mcplib.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcplib.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcplib.Implementation{Name: "test", Version: "0"}
+	if _, err := c.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("failed to initialize client: %v", err)
+	}
+	return c
+}
+
+func testServerConfig(t *testing.T, baseURL string) *config.Config {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.OpenAI.APIKey = "test-key"
+	cfg.OpenAI.Model = "test-model"
+	cfg.OpenAI.BaseURL = baseURL
+	cfg.History.SessionsDir = t.TempDir()
+	cfg.ApplyDefaults()
+	return cfg
+}
+
+// callTool invokes a tool and returns the text of its first content part.
+func callTool(t *testing.T, c *client.Client, name string, args map[string]any) string {
+	t.Helper()
+	req := mcplib.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+
+	result, err := c.CallTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("tool %s failed: %v", name, err)
+	}
+	if result.IsError {
+		t.Fatalf("tool %s returned an error result: %+v", name, result.Content)
+	}
+	if len(result.Content) == 0 {
+		t.Fatalf("tool %s returned no content", name)
+	}
+	text, ok := result.Content[0].(mcplib.TextContent)
+	if !ok {
+		t.Fatalf("tool %s returned non-text content %T", name, result.Content[0])
+	}
+	return text.Text
+}
+
+func TestServeChatAndSessionTools(t *testing.T) {
+	apiSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, completionBody("hello from gopus"))
+	}))
+	defer apiSrv.Close()
+
+	c := newTestClient(t, testServerConfig(t, apiSrv.URL), false)
+
+	// The server advertises exactly the three session tools
+	toolsResult, err := c.ListTools(context.Background(), mcplib.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("failed to list tools: %v", err)
+	}
+	names := make(map[string]bool)
+	for _, tool := range toolsResult.Tools {
+		names[tool.Name] = true
+	}
+	for _, want := range []string{"chat", "list_sessions", "get_session_messages"} {
+		if !names[want] {
+			t.Errorf("tool %s not advertised; got %v", want, names)
+		}
+	}
+
+	// chat without a session ID starts a new session
+	var first chatResult
+	if err := json.Unmarshal([]byte(callTool(t, c, "chat", map[string]any{"message": "hi"})), &first); err != nil {
+		t.Fatalf("failed to decode chat result: %v", err)
+	}
+	if first.Reply != "hello from gopus" || first.SessionID == "" {
+		t.Errorf("unexpected chat result: %+v", first)
+	}
+
+	// chat with the returned ID continues the same session
+	var second chatResult
+	if err := json.Unmarshal([]byte(callTool(t, c, "chat", map[string]any{
+		"message": "and again",
+		"session": first.SessionID,
+	})), &second); err != nil {
+		t.Fatalf("failed to decode chat result: %v", err)
+	}
+	if second.SessionID != first.SessionID {
+		t.Errorf("expected the same session, got %s and %s", first.SessionID, second.SessionID)
+	}
+
+	// get_session_messages sees all four messages of the open session
+	var messages []map[string]any
+	if err := json.Unmarshal([]byte(callTool(t, c, "get_session_messages", map[string]any{
+		"session": first.SessionID,
+	})), &messages); err != nil {
+		t.Fatalf("failed to decode messages: %v", err)
+	}
+	if len(messages) != 4 {
+		t.Errorf("expected 4 messages, got %d", len(messages))
+	}
+
+	// list_sessions reports the session with its message count
+	var sessions []map[string]any
+	if err := json.Unmarshal([]byte(callTool(t, c, "list_sessions", nil)), &sessions); err != nil {
+		t.Fatalf("failed to decode sessions: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d", len(sessions))
+	}
+	if sessions[0]["id"] != first.SessionID {
+		t.Errorf("listed session %v, want %s", sessions[0]["id"], first.SessionID)
+	}
+}
+
+func TestServeConcurrentChats(t *testing.T) {
+	apiSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, completionBody("ok"))
+	}))
+	defer apiSrv.Close()
+
+	c := newTestClient(t, testServerConfig(t, apiSrv.URL), false)
+
+	// Open two sessions, then hammer both concurrently; per-session locking
+	// must keep each session's history consistent.
+	var ids [2]string
+	for i := range ids {
+		var result chatResult
+		if err := json.Unmarshal([]byte(callTool(t, c, "chat", map[string]any{"message": "open"})), &result); err != nil {
+			t.Fatalf("failed to decode chat result: %v", err)
+		}
+		ids[i] = result.SessionID
+	}
+	if ids[0] == ids[1] {
+		t.Fatalf("expected two distinct sessions, both were %s", ids[0])
+	}
+
+	const perSession = 5
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		for range perSession {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				callTool(t, c, "chat", map[string]any{"message": "ping", "session": id})
+			}(id)
+		}
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		var messages []map[string]any
+		if err := json.Unmarshal([]byte(callTool(t, c, "get_session_messages", map[string]any{"session": id})), &messages); err != nil {
+			t.Fatalf("failed to decode messages: %v", err)
+		}
+		// 1 opening exchange + perSession pings, 2 messages each
+		if want := 2 * (1 + perSession); len(messages) != want {
+			t.Errorf("session %s has %d messages, want %d", id, len(messages), want)
+		}
+	}
+}
+
+func TestServeRefusesUnknownSession(t *testing.T) {
+	c := newTestClient(t, testServerConfig(t, "http://127.0.0.1:0"), false)
+
+	req := mcplib.CallToolRequest{}
+	req.Params.Name = "chat"
+	req.Params.Arguments = map[string]any{"message": "hi", "session": "no-such-session"}
+
+	result, err := c.CallTool(context.Background(), req)
+	if err == nil && (result == nil || !result.IsError) {
+		t.Error("expected an error for an unknown session ID")
+	}
+}
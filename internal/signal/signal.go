@@ -6,26 +6,102 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 )
 
+var (
+	cleanupMu sync.Mutex
+	cleanups  []func()
+
+	interceptMu     sync.Mutex
+	interceptActive int
+)
+
+// OnShutdown registers fn to run before RunWithContext returns, whether the
+// run ended normally or was interrupted by a signal. Functions run in
+// reverse registration order, like defers, and at most once.
+func OnShutdown(fn func()) {
+	cleanupMu.Lock()
+	defer cleanupMu.Unlock()
+	cleanups = append(cleanups, fn)
+}
+
+// InterceptInterrupt claims SIGINT for the caller until the returned release
+// func runs: signals arrive on the returned channel and RunWithContext stops
+// treating them as a shutdown request. This lets Ctrl+C cancel just the
+// current operation (e.g. a running tool call) instead of ending the whole
+// session. SIGTERM still shuts down as usual.
+func InterceptInterrupt() (interrupts <-chan os.Signal, release func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGINT)
+
+	interceptMu.Lock()
+	interceptActive++
+	interceptMu.Unlock()
+
+	return ch, func() {
+		signal.Stop(ch)
+		interceptMu.Lock()
+		interceptActive--
+		interceptMu.Unlock()
+	}
+}
+
+// interceptingInterrupts reports whether an InterceptInterrupt claim is
+// currently active.
+func interceptingInterrupts() bool {
+	interceptMu.Lock()
+	defer interceptMu.Unlock()
+	return interceptActive > 0
+}
+
+func runCleanups() {
+	cleanupMu.Lock()
+	fns := cleanups
+	cleanups = nil
+	cleanupMu.Unlock()
+
+	for i := len(fns) - 1; i >= 0; i-- {
+		fns[i]()
+	}
+}
+
 // RunWithContext sets up signal handling for graceful shutdown.
-// It listens for SIGINT and SIGTERM signals and calls the provided action
-// function with a cancellable context. When a signal is received, the context
-// is cancelled and the program exits gracefully.
+// It runs action with a context that is cancelled on SIGINT or SIGTERM.
+// On a signal the action is usually blocked reading stdin and cannot
+// return, so instead of waiting for it the cleanup functions registered
+// with OnShutdown run and RunWithContext returns, letting the process
+// exit normally rather than through os.Exit. The same cleanups run when
+// the action finishes on its own.
 func RunWithContext(action func(context.Context)) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
 
+	done := make(chan struct{})
 	go func() {
-		<-sigChan
-		fmt.Println("\n\nGoodbye!")
-		cancel()
-		os.Exit(0)
+		defer close(done)
+		action(ctx)
 	}()
 
-	action(ctx)
+	for {
+		select {
+		case <-done:
+		case sig := <-sigChan:
+			// A SIGINT claimed by InterceptInterrupt is handled there;
+			// keep waiting instead of shutting down
+			if sig == syscall.SIGINT && interceptingInterrupts() {
+				continue
+			}
+			fmt.Println("\n\nGoodbye!")
+			cancel()
+		}
+		break
+	}
+
+	runCleanups()
 }
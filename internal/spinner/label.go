@@ -0,0 +1,60 @@
+package spinner
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gopus/internal/printer"
+)
+
+// statusLabel holds the optional status label and start time every
+// animation style renders after its glyphs together with the elapsed time,
+// e.g. " thinking… 3s". Embedded by the animation implementations.
+type statusLabel struct {
+	mu      sync.Mutex // guards label, which SetLabel may swap mid-run
+	label   string
+	started time.Time
+}
+
+// SetLabel replaces the status label while the animation is running, e.g.
+// with progress reported by a long tool call. Safe to call from other
+// goroutines; the next frame picks it up.
+func (l *statusLabel) SetLabel(label string) {
+	l.mu.Lock()
+	l.label = label
+	l.mu.Unlock()
+}
+
+// suffix returns the label and elapsed time rendered after glyphCols
+// columns of animation, sized for the current terminal width. Returns ""
+// when no label is set.
+func (l *statusLabel) suffix(glyphCols int) string {
+	return l.suffixForWidth(printer.TerminalWidth(), glyphCols)
+}
+
+// suffixForWidth renders the suffix for a given terminal width. On narrow
+// terminals the label is dropped entirely so the line cannot wrap; otherwise
+// it is truncated to the available columns.
+func (l *statusLabel) suffixForWidth(width, glyphCols int) string {
+	l.mu.Lock()
+	label := l.label
+	l.mu.Unlock()
+
+	if label == "" || width < printer.NarrowWidth {
+		return ""
+	}
+
+	text := fmt.Sprintf(" %s %ds", label, int(time.Since(l.started).Seconds()))
+
+	// Keep the line from wrapping past the animated glyphs.
+	available := width - glyphCols
+	if available < 0 {
+		available = 0
+	}
+	runes := []rune(text)
+	if len(runes) > available {
+		runes = runes[:available]
+	}
+	return string(runes)
+}
@@ -1,14 +1,19 @@
-// Package chat provides the main chat loop functionality.
-package chat
+// Package spinner provides the braille loading animations shown while the
+// application waits on a long call. Visual rendering lives here; frame
+// timing and lifecycle come from the animator package.
+package spinner
 
 import (
 	"fmt"
 	"math"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"gopus/internal/animator"
 	"gopus/internal/canvas"
+	"gopus/internal/printer"
 )
 
 // ANSI escape codes for terminal control.
@@ -45,30 +50,68 @@ const trailLength = 4
 //	  1   *           *
 //	  2   *           *
 //	  3       *   *
-var circlePixels = [][2]int{
-	{1, 0}, // top center-left
-	{2, 0}, // top center-right
-	{3, 1}, // right upper
-	{3, 2}, // right lower
-	{2, 3}, // bottom center-right
-	{1, 3}, // bottom center-left
-	{0, 2}, // left lower
-	{0, 1}, // left upper
-}
-
-// CircleSpinner implements the animator.Animation interface with a circular
-// braille pattern and smooth rainbow color cycling.
-type CircleSpinner struct {
+var circlePixels = circlePath()
+
+// circlePath builds the ring the trail travels from the canvas drawing
+// primitives. A 4x4 grid has no integer center, so a midpoint DrawCircle
+// cannot render this ring directly; the same pixels come from the bounding
+// rectangle with its corners knocked off. They are then ordered clockwise
+// from the top, which the trail animation needs and a draw call cannot
+// provide.
+func circlePath() [][2]int {
+	ring := canvas.New(4, 4)
+	ring.DrawRect(0, 0, 3, 3)
+	for _, corner := range [][2]int{{0, 0}, {3, 0}, {0, 3}, {3, 3}} {
+		ring.Clear(corner[0], corner[1])
+	}
+
+	var path [][2]int
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if ring.Get(x, y) {
+				path = append(path, [2]int{x, y})
+			}
+		}
+	}
+	sort.Slice(path, func(i, j int) bool {
+		return ringAngle(path[i]) < ringAngle(path[j])
+	})
+	return path
+}
+
+// ringAngle measures a pixel's clockwise angle around the grid center
+// (1.5, 1.5), shifted so the path starts at the top-center pixel.
+func ringAngle(p [2]int) float64 {
+	a := math.Atan2(float64(p[0])-1.5, 1.5-float64(p[1]))
+	if a < -math.Pi/2 {
+		a += 2 * math.Pi
+	}
+	return a
+}
+
+// CircleAnimation implements the animator.Animation interface with a circular
+// braille pattern and smooth rainbow color cycling. An optional label is
+// rendered after the animated glyphs together with the elapsed time, e.g.
+// "⣷ running tool read_file… 3s".
+type CircleAnimation struct {
+	statusLabel
 	canvas       *canvas.Canvas // braille character renderer
 	phase        float64        // current phase angle for RGB cycling (radians)
 	frameIdx     int            // current position in circlePixels
 	useTrueColor bool           // true for 24-bit color, false for 256-color fallback
 }
 
-// NewCircleSpinner creates a new CircleSpinner.
+// NewCircleAnimation creates a new CircleAnimation without a status label.
 // It auto-detects terminal color support via the COLORTERM environment variable.
-func NewCircleSpinner() *CircleSpinner {
-	return &CircleSpinner{
+func NewCircleAnimation() *CircleAnimation {
+	return NewCircleAnimationWithLabel("")
+}
+
+// NewCircleAnimationWithLabel creates a CircleAnimation that renders the given
+// status label and the elapsed time after the animated glyphs.
+func NewCircleAnimationWithLabel(label string) *CircleAnimation {
+	return &CircleAnimation{
+		statusLabel:  statusLabel{label: label},
 		canvas:       canvas.New(4, 4), // 2 braille chars wide, 1 char tall
 		phase:        0,
 		frameIdx:     0,
@@ -87,23 +130,25 @@ func supportsTrueColor() bool {
 
 // Start hides the cursor and renders the initial frame.
 // Implements Animation.Start().
-func (s *CircleSpinner) Start() {
+func (s *CircleAnimation) Start() {
+	s.started = time.Now()
 	fmt.Print(ansiHideCursor)
 	s.Render()
 }
 
 // Stop clears the animation line, resets colors, and restores the cursor.
 // Implements Animation.Stop().
-func (s *CircleSpinner) Stop() {
+func (s *CircleAnimation) Stop() {
 	fmt.Print(carriageReturn + ansiClearLine + ansiResetColor + ansiShowCursor)
 }
 
 // Render prints the current frame with color, then advances state for the next frame.
 // Implements Animation.Render().
-func (s *CircleSpinner) Render() {
+func (s *CircleAnimation) Render() {
 	frame := s.renderFrame()
 	colorCode := s.getColorCode()
-	fmt.Printf("%s%s%s", carriageReturn, colorCode, frame)
+	// The animated glyphs occupy two columns.
+	fmt.Printf("%s%s%s%s%s%s", carriageReturn, colorCode, frame, ansiResetColor, s.suffix(2), ansiClearLine)
 
 	// Advance to next frame position and color
 	s.frameIdx = (s.frameIdx + 1) % len(circlePixels)
@@ -112,13 +157,13 @@ func (s *CircleSpinner) Render() {
 
 // FrameCount returns the number of frames in one complete rotation (8 positions).
 // Implements Animation.FrameCount().
-func (s *CircleSpinner) FrameCount() int {
+func (s *CircleAnimation) FrameCount() int {
 	return len(circlePixels)
 }
 
 // advanceColor increments the color phase for rainbow cycling.
 // The phase completes a full cycle every ~3 seconds at 80ms frame intervals.
-func (s *CircleSpinner) advanceColor() {
+func (s *CircleAnimation) advanceColor() {
 	s.phase += 2.0 * math.Pi / 37.5 // ~37.5 frames per color cycle
 	if s.phase >= 2.0*math.Pi {
 		s.phase -= 2.0 * math.Pi
@@ -127,7 +172,7 @@ func (s *CircleSpinner) advanceColor() {
 
 // getRGB calculates RGB values (0-255) using phase-shifted sine waves.
 // Each color component is offset by 120° to create smooth rainbow transitions.
-func (s *CircleSpinner) getRGB() (r, g, b int) {
+func (s *CircleAnimation) getRGB() (r, g, b int) {
 	// sin(x) ∈ [-1,1] → (sin(x)+1)/2 ∈ [0,1] → scaled to [0,255]
 	r = int((math.Sin(s.phase+redPhase) + 1.0) / 2.0 * 255.0)
 	g = int((math.Sin(s.phase+greenPhase) + 1.0) / 2.0 * 255.0)
@@ -147,7 +192,7 @@ func rgbTo256(r, g, b int) int {
 
 // getColorCode returns the ANSI escape sequence for the current rainbow color.
 // Uses 24-bit true color if supported, otherwise falls back to 256-color mode.
-func (s *CircleSpinner) getColorCode() string {
+func (s *CircleAnimation) getColorCode() string {
 	r, g, b := s.getRGB()
 	if s.useTrueColor {
 		return fmt.Sprintf("%s%d;%d;%dm", ansiTrueColorFg, r, g, b)
@@ -157,7 +202,7 @@ func (s *CircleSpinner) getColorCode() string {
 
 // renderFrame draws the current animation frame to the canvas and returns it as a string.
 // The frame consists of a trail of pixels following the circular path.
-func (s *CircleSpinner) renderFrame() string {
+func (s *CircleAnimation) renderFrame() string {
 	s.canvas.Reset()
 
 	// Draw trail: i=0 is head, i=1..trailLength-1 trail behind
@@ -171,12 +216,26 @@ func (s *CircleSpinner) renderFrame() string {
 	return s.canvas.String()
 }
 
-// WithSpinner executes the given action function while displaying a CircleSpinner.
-// It starts the spinner, runs the action, stops the spinner, and returns the action's result.
-// The type parameter T allows returning any value type from the action.
-func WithSpinner[T any](action func() (T, error)) (T, error) {
-	spinner := NewCircleSpinner()
-	anim := animator.NewAnimator(spinner)
+// Run executes action while displaying the configured animation with the
+// given status label and the elapsed time next to it, e.g. "⣷ thinking… 12s".
+// With the style set to off a static "…" is printed instead; when output is
+// not a terminal the animation is skipped and the label (or a plain "…") is
+// printed once. The animation always stops — clearing its line and restoring
+// the cursor — even when action panics.
+func Run[T any](label string, action func() (T, error)) (T, error) {
+	if !Enabled() {
+		fmt.Println("…")
+		return action()
+	}
+	if !printer.ColorsEnabled() {
+		if label == "" {
+			label = "…"
+		}
+		fmt.Println(label)
+		return action()
+	}
+
+	anim := animator.NewAnimator(NewWithLabel(label))
 	anim.Start()
 	defer anim.Stop()
 
@@ -0,0 +1,135 @@
+package spinner
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gopus/internal/printer"
+)
+
+func TestSpinnerSuffixShowsLabelAndElapsed(t *testing.T) {
+	s := NewCircleAnimationWithLabel("thinking…")
+	s.started = time.Now().Add(-12 * time.Second)
+
+	got := s.suffix(2)
+
+	if !strings.Contains(got, "thinking…") {
+		t.Errorf("expected label in suffix, got %q", got)
+	}
+	if !strings.Contains(got, "12s") {
+		t.Errorf("expected elapsed time in suffix, got %q", got)
+	}
+}
+
+func TestSpinnerSuffixEmptyWithoutLabel(t *testing.T) {
+	s := NewCircleAnimation()
+	s.started = time.Now()
+
+	if got := s.suffix(2); got != "" {
+		t.Errorf("expected empty suffix without label, got %q", got)
+	}
+}
+
+func TestSpinnerSuffixTruncatedToTerminalWidth(t *testing.T) {
+	s := NewCircleAnimationWithLabel(strings.Repeat("x", 500))
+	s.started = time.Now()
+
+	for _, width := range []int{50, 120} {
+		got := s.suffixForWidth(width, 2)
+
+		// The glyphs take two columns; the suffix must fit the remainder.
+		if max := width - 2; len([]rune(got)) > max {
+			t.Errorf("width %d: suffix length %d exceeds available width %d", width, len([]rune(got)), max)
+		}
+		if got == "" {
+			t.Errorf("width %d: expected a truncated label, got none", width)
+		}
+	}
+}
+
+func TestSpinnerSuffixDroppedOnNarrowTerminal(t *testing.T) {
+	s := NewCircleAnimationWithLabel("thinking…")
+	s.started = time.Now()
+
+	if got := s.suffixForWidth(30, 2); got != "" {
+		t.Errorf("expected no suffix on narrow terminal, got %q", got)
+	}
+}
+
+// captureRun runs fn with colors forced on and stdout redirected to a pipe,
+// returning everything written while it ran.
+func captureRun(t *testing.T, fn func()) string {
+	t.Helper()
+
+	wasEnabled := printer.ColorsEnabled()
+	printer.SetColors(true)
+	defer printer.SetColors(wasEnabled)
+
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+
+	os.Stdout = orig
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestRunReturnsActionResult(t *testing.T) {
+	out := captureRun(t, func() {
+		got, err := Run("working…", func() (int, error) {
+			return 42, nil
+		})
+		if err != nil || got != 42 {
+			t.Errorf("expected (42, nil), got (%d, %v)", got, err)
+		}
+	})
+
+	if !strings.HasSuffix(out, ansiShowCursor) {
+		t.Errorf("expected the cursor restored at the end, got %q", out)
+	}
+}
+
+func TestRunRestoresCursorOnPanic(t *testing.T) {
+	out := captureRun(t, func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to propagate")
+			}
+		}()
+		_, _ = Run("exploding…", func() (any, error) {
+			panic("boom")
+		})
+	})
+
+	if !strings.Contains(out, ansiHideCursor) {
+		t.Errorf("expected the animation to start, got %q", out)
+	}
+	if !strings.HasSuffix(out, ansiShowCursor) {
+		t.Errorf("expected the cursor restored after the panic, got %q", out)
+	}
+}
+
+func TestRunPlainWhenColorsDisabled(t *testing.T) {
+	wasEnabled := printer.ColorsEnabled()
+	printer.SetColors(false)
+	defer printer.SetColors(wasEnabled)
+
+	ran := false
+	_, err := Run("label", func() (any, error) {
+		ran = true
+		return nil, nil
+	})
+	if err != nil || !ran {
+		t.Errorf("expected the action to run without animation, err %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package spinner
+
+import (
+	"os"
+	"strings"
+
+	"gopus/internal/animator"
+)
+
+// Spinner is an animation with a relabelable status line, as rendered by
+// every style in this package. Progress notifications swap the label while
+// the animation runs.
+type Spinner interface {
+	animator.Animation
+	SetLabel(label string)
+}
+
+// Style selects which spinner animation to render.
+type Style string
+
+const (
+	// StyleBraille is the colorful braille circle; needs a UTF-8 terminal.
+	StyleBraille Style = "braille"
+	// StyleASCII is the classic |/-\ spinner; safe on any terminal.
+	StyleASCII Style = "ascii"
+	// StyleDots is a growing ellipsis.
+	StyleDots Style = "dots"
+	// StyleOff disables animations entirely; Run prints a static "…".
+	StyleOff Style = "off"
+)
+
+// activeStyle is the style new spinners are built with. It defaults to a
+// locale-based guess and may be overridden from config via SetStyle.
+var activeStyle = detectStyle()
+
+// SetStyle overrides the spinner style, typically from the ui.spinner config
+// value. An empty style restores the locale-based auto-detection; unknown
+// styles are ignored (config validation rejects them before this point).
+func SetStyle(style Style) {
+	switch style {
+	case StyleBraille, StyleASCII, StyleDots, StyleOff:
+		activeStyle = style
+	case "":
+		activeStyle = detectStyle()
+	}
+}
+
+// Enabled reports whether spinner animations are enabled at all. With the
+// style set to off callers should print a static placeholder instead.
+func Enabled() bool {
+	return activeStyle != StyleOff
+}
+
+// NewWithLabel builds a spinner in the active style with the given status
+// label. With the style set to off it still returns a braille spinner;
+// callers are expected to check Enabled first, as Run does.
+func NewWithLabel(label string) Spinner {
+	switch activeStyle {
+	case StyleASCII:
+		return NewASCIIAnimation(label)
+	case StyleDots:
+		return NewDotsAnimation(label)
+	default:
+		return NewCircleAnimationWithLabel(label)
+	}
+}
+
+// detectStyle guesses a style from the locale environment. Braille glyphs
+// render as tofu boxes outside UTF-8, so a non-UTF-8 locale falls back to
+// the ASCII spinner. With no locale set at all UTF-8 is assumed, matching
+// what modern terminal emulators default to.
+func detectStyle() Style {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := os.Getenv(name)
+		if locale == "" {
+			continue
+		}
+		upper := strings.ToUpper(locale)
+		if strings.Contains(upper, "UTF-8") || strings.Contains(upper, "UTF8") {
+			return StyleBraille
+		}
+		return StyleASCII
+	}
+	return StyleBraille
+}
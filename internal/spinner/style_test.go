@@ -0,0 +1,88 @@
+package spinner
+
+import (
+	"strings"
+	"testing"
+)
+
+// setStyle overrides the active style for a test and restores it afterwards.
+func setStyle(t *testing.T, style Style) {
+	t.Helper()
+	prev := activeStyle
+	SetStyle(style)
+	t.Cleanup(func() { activeStyle = prev })
+}
+
+func TestNewWithLabelHonorsStyle(t *testing.T) {
+	tests := []struct {
+		style     Style
+		wantASCII bool
+	}{
+		{StyleBraille, false},
+		{StyleASCII, true},
+		{StyleDots, true},
+	}
+	for _, tt := range tests {
+		setStyle(t, tt.style)
+
+		_, isText := NewWithLabel("label").(*TextAnimation)
+		if isText != tt.wantASCII {
+			t.Errorf("style %q: expected text animation %v, got %v", tt.style, tt.wantASCII, isText)
+		}
+	}
+}
+
+func TestSetStyleIgnoresUnknown(t *testing.T) {
+	setStyle(t, StyleDots)
+
+	SetStyle("marquee")
+
+	if activeStyle != StyleDots {
+		t.Errorf("expected the unknown style to be ignored, got %q", activeStyle)
+	}
+}
+
+func TestDetectStyle(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Style
+	}{
+		{"utf8 lang", map[string]string{"LANG": "en_US.UTF-8"}, StyleBraille},
+		{"non-utf8 lang", map[string]string{"LANG": "en_US.ISO8859-1"}, StyleASCII},
+		{"lc_all wins", map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"}, StyleASCII},
+		{"utf8 without dash", map[string]string{"LC_CTYPE": "de_DE.utf8"}, StyleBraille},
+		{"no locale", map[string]string{}, StyleBraille},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			if got := detectStyle(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRunOffPrintsStaticEllipsis(t *testing.T) {
+	setStyle(t, StyleOff)
+
+	out := captureRun(t, func() {
+		got, err := Run("working…", func() (int, error) {
+			return 7, nil
+		})
+		if err != nil || got != 7 {
+			t.Errorf("expected (7, nil), got (%d, %v)", got, err)
+		}
+	})
+
+	if out != "…\n" {
+		t.Errorf("expected a static ellipsis and nothing else, got %q", out)
+	}
+	if strings.Contains(out, ansiHideCursor) {
+		t.Errorf("expected no animation with the style off, got %q", out)
+	}
+}
@@ -0,0 +1,67 @@
+package spinner
+
+import (
+	"fmt"
+	"time"
+)
+
+// TextAnimation implements the animator.Animation interface by cycling
+// through a fixed sequence of plain-text frames. It backs the ASCII and
+// dots spinner styles for terminals where braille renders poorly.
+type TextAnimation struct {
+	statusLabel
+	frames []string // rendered in order, wrapping around
+	idx    int      // current position in frames
+	cols   int      // columns of the widest frame, for suffix sizing
+}
+
+// NewASCIIAnimation creates the classic |/-\ spinner with the given status
+// label. Every frame is plain ASCII, so it is safe on any terminal.
+func NewASCIIAnimation(label string) *TextAnimation {
+	return newTextAnimation(label, []string{"|", "/", "-", "\\"})
+}
+
+// NewDotsAnimation creates a growing-ellipsis spinner with the given status
+// label. Frames are padded to equal width so the suffix does not jitter.
+func NewDotsAnimation(label string) *TextAnimation {
+	return newTextAnimation(label, []string{"   ", ".  ", ".. ", "..."})
+}
+
+func newTextAnimation(label string, frames []string) *TextAnimation {
+	cols := 0
+	for _, frame := range frames {
+		cols = max(cols, len([]rune(frame)))
+	}
+	return &TextAnimation{
+		statusLabel: statusLabel{label: label},
+		frames:      frames,
+		cols:        cols,
+	}
+}
+
+// Start hides the cursor and renders the initial frame.
+// Implements Animation.Start().
+func (a *TextAnimation) Start() {
+	a.started = time.Now()
+	fmt.Print(ansiHideCursor)
+	a.Render()
+}
+
+// Stop clears the animation line, resets colors, and restores the cursor.
+// Implements Animation.Stop().
+func (a *TextAnimation) Stop() {
+	fmt.Print(carriageReturn + ansiClearLine + ansiResetColor + ansiShowCursor)
+}
+
+// Render prints the current frame, then advances to the next one.
+// Implements Animation.Render().
+func (a *TextAnimation) Render() {
+	fmt.Printf("%s%s%s%s", carriageReturn, a.frames[a.idx], a.suffix(a.cols), ansiClearLine)
+	a.idx = (a.idx + 1) % len(a.frames)
+}
+
+// FrameCount returns the number of frames in one complete cycle.
+// Implements Animation.FrameCount().
+func (a *TextAnimation) FrameCount() int {
+	return len(a.frames)
+}
@@ -0,0 +1,91 @@
+package spinner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestASCIIAnimationFrameSequence(t *testing.T) {
+	a := NewASCIIAnimation("")
+
+	out := captureRun(t, func() {
+		a.Start()
+		for range a.FrameCount() - 1 {
+			a.Render()
+		}
+	})
+
+	want := ansiHideCursor +
+		carriageReturn + "|" + ansiClearLine +
+		carriageReturn + "/" + ansiClearLine +
+		carriageReturn + "-" + ansiClearLine +
+		carriageReturn + `\` + ansiClearLine
+	if out != want {
+		t.Errorf("expected frame sequence %q, got %q", want, out)
+	}
+}
+
+func TestDotsAnimationFrameSequence(t *testing.T) {
+	a := NewDotsAnimation("")
+
+	out := captureRun(t, func() {
+		a.Start()
+		for range a.FrameCount() - 1 {
+			a.Render()
+		}
+	})
+
+	want := ansiHideCursor +
+		carriageReturn + "   " + ansiClearLine +
+		carriageReturn + ".  " + ansiClearLine +
+		carriageReturn + ".. " + ansiClearLine +
+		carriageReturn + "..." + ansiClearLine
+	if out != want {
+		t.Errorf("expected frame sequence %q, got %q", want, out)
+	}
+}
+
+func TestTextAnimationWrapsAround(t *testing.T) {
+	a := NewASCIIAnimation("")
+
+	out := captureRun(t, func() {
+		a.Start()
+		for range a.FrameCount() {
+			a.Render()
+		}
+	})
+
+	// One full cycle later the first frame renders again.
+	if got := strings.Count(out, carriageReturn+"|"+ansiClearLine); got != 2 {
+		t.Errorf("expected the first frame twice after a full cycle, got %d", got)
+	}
+}
+
+func TestTextAnimationStopLeavesLineClean(t *testing.T) {
+	a := NewDotsAnimation("working…")
+
+	out := captureRun(t, func() {
+		a.Start()
+		a.Render()
+		a.Stop()
+	})
+
+	want := carriageReturn + ansiClearLine + ansiResetColor + ansiShowCursor
+	if !strings.HasSuffix(out, want) {
+		t.Errorf("expected Stop to clear the line and restore the cursor, got %q", out)
+	}
+}
+
+func TestTextAnimationRendersLabelSuffix(t *testing.T) {
+	a := NewASCIIAnimation("")
+
+	out := captureRun(t, func() {
+		a.Start()
+		a.SetLabel("downloading…")
+		a.Render()
+	})
+
+	if !strings.Contains(out, "downloading…") {
+		t.Errorf("expected the label after the glyph, got %q", out)
+	}
+}
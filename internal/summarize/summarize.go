@@ -26,12 +26,18 @@ func New(client *openai.ChatClient, cfg config.SummarizationConfig) *Summarizer
 	}
 }
 
+// SetConfig replaces the summarization settings, e.g. after a config reload.
+func (s *Summarizer) SetConfig(cfg config.SummarizationConfig) {
+	s.config = cfg
+}
+
 // TierClassification holds messages classified by tier.
 type TierClassification struct {
 	Recent     []history.Message // Messages to keep in full detail
 	ToCondense []history.Message // Messages to condense
 	ToCompress []history.Message // Messages to highly compress
 	Existing   []history.Message // Existing summaries to preserve or re-compress
+	Notes      []history.Message // User notes, never summarized
 }
 
 // ClassifyTiers classifies messages into tiers based on configuration.
@@ -39,12 +45,15 @@ type TierClassification struct {
 func (s *Summarizer) ClassifyTiers(messages []history.Message) TierClassification {
 	var result TierClassification
 
-	// Separate existing summaries from regular messages
+	// Separate existing summaries and notes from regular messages
 	var regularMessages []history.Message
 	for _, msg := range messages {
-		if msg.IsSummary() {
+		switch {
+		case msg.IsSummary():
 			result.Existing = append(result.Existing, msg)
-		} else {
+		case msg.IsNote():
+			result.Notes = append(result.Notes, msg)
+		default:
 			regularMessages = append(regularMessages, msg)
 		}
 	}
@@ -103,34 +112,37 @@ func (s *Summarizer) ShouldAutoSummarize(messages []history.Message) bool {
 	return count > s.config.AutoThreshold
 }
 
+// PromptContext carries session metadata woven into a summarization prompt:
+// it fills the {{placeholders}} a prompt template may use, and whatever the
+// template does not reference is appended to the conversation dump as a
+// labeled section instead, so the context is never silently lost.
+type PromptContext struct {
+	SessionName     string
+	PinnedFacts     []string // facts the summary must always preserve
+	PreviousSummary string   // existing compressed summary, fed back as long-term memory
+}
+
 // SummarizeMessages generates a summary for a group of messages.
-func (s *Summarizer) SummarizeMessages(ctx context.Context, messages []history.Message, level history.SummaryLevel) (history.Message, error) {
+func (s *Summarizer) SummarizeMessages(ctx context.Context, messages []history.Message, level history.SummaryLevel, pctx PromptContext) (history.Message, error) {
 	if len(messages) == 0 {
 		return history.Message{}, fmt.Errorf("no messages to summarize")
 	}
 
-	// Build the conversation text
-	var conversationBuilder strings.Builder
-	for _, msg := range messages {
-		conversationBuilder.WriteString(fmt.Sprintf("%s: %s\n\n", msg.Role, msg.Content))
-	}
-
-	// Select prompt based on level (using configurable prompts)
-	prompt := s.config.CondensedPrompt
+	// Select prompt template based on level (using configurable prompts)
+	template := s.config.CondensedPrompt
 	if level == history.LevelCompressed {
-		prompt = s.config.CompressedPrompt
+		template = s.config.CompressedPrompt
 	}
 
-	// Create the summarization request
-	userContent := conversationBuilder.String()
+	prompt, userContent := buildSummaryRequest(template, messages, pctx)
 	apiMessages := []openai.ChatCompletionRequestMessage{
 		{
 			Role:    openai.RoleSystem,
-			Content: &prompt,
+			Content: openai.StringContent(prompt),
 		},
 		{
 			Role:    openai.RoleUser,
-			Content: &userContent,
+			Content: openai.StringContent(userContent),
 		},
 	}
 
@@ -151,6 +163,92 @@ func (s *Summarizer) SummarizeMessages(ctx context.Context, messages []history.M
 	}, nil
 }
 
+// buildSummaryRequest renders the system prompt and the user content for a
+// summarization call. The template's {{session_name}}, {{date_range}},
+// {{pinned_facts}}, and {{previous_summary}} placeholders are filled in;
+// values the template does not reference are appended to the conversation
+// dump as labeled sections. Unknown placeholders are left untouched, so a
+// typo shows up in the prompt instead of failing the summarization.
+func buildSummaryRequest(template string, messages []history.Message, pctx PromptContext) (prompt, userContent string) {
+	values := map[string]string{
+		"session_name":     pctx.SessionName,
+		"date_range":       messageDateRange(messages),
+		"pinned_facts":     formatPinnedFacts(pctx.PinnedFacts),
+		"previous_summary": pctx.PreviousSummary,
+	}
+
+	prompt = template
+	for key, value := range values {
+		prompt = strings.ReplaceAll(prompt, "{{"+key+"}}", value)
+	}
+
+	var b strings.Builder
+	if pctx.SessionName != "" && !usesPlaceholder(template, "session_name") {
+		fmt.Fprintf(&b, "Session: %s\n", pctx.SessionName)
+	}
+	if values["date_range"] != "" && !usesPlaceholder(template, "date_range") {
+		fmt.Fprintf(&b, "Covers: %s\n", values["date_range"])
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	if pctx.PreviousSummary != "" && !usesPlaceholder(template, "previous_summary") {
+		fmt.Fprintf(&b, "Previous long-term memory (carry forward what is still relevant):\n%s\n\n", pctx.PreviousSummary)
+	}
+	if len(pctx.PinnedFacts) > 0 && !usesPlaceholder(template, "pinned_facts") {
+		fmt.Fprintf(&b, "Pinned facts (must be preserved in the summary):\n%s\n\n", values["pinned_facts"])
+	}
+
+	b.WriteString("Conversation:\n")
+	for _, msg := range messages {
+		fmt.Fprintf(&b, "%s: %s\n\n", msg.Role, msg.Content)
+	}
+
+	return prompt, b.String()
+}
+
+// usesPlaceholder reports whether a prompt template references the given
+// placeholder.
+func usesPlaceholder(template, key string) bool {
+	return strings.Contains(template, "{{"+key+"}}")
+}
+
+// formatPinnedFacts renders pinned facts as a bulleted list.
+func formatPinnedFacts(facts []string) string {
+	var b strings.Builder
+	for _, fact := range facts {
+		fmt.Fprintf(&b, "- %s\n", fact)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// messageDateRange renders the dates the messages span, e.g.
+// "2026-08-01 to 2026-08-31", or a single date when they all share one.
+// Messages without a timestamp are ignored; all-untimestamped returns "".
+func messageDateRange(messages []history.Message) string {
+	var first, last time.Time
+	for _, msg := range messages {
+		if msg.CreatedAt.IsZero() {
+			continue
+		}
+		if first.IsZero() || msg.CreatedAt.Before(first) {
+			first = msg.CreatedAt
+		}
+		if msg.CreatedAt.After(last) {
+			last = msg.CreatedAt
+		}
+	}
+	if first.IsZero() {
+		return ""
+	}
+
+	from, to := first.Format("2006-01-02"), last.Format("2006-01-02")
+	if from == to {
+		return from
+	}
+	return from + " to " + to
+}
+
 // ProcessSession summarizes a session's messages according to tier configuration.
 // Returns the new message list with summaries replacing original messages.
 func (s *Summarizer) ProcessSession(ctx context.Context, session *history.Session) ([]history.Message, error) {
@@ -160,28 +258,37 @@ func (s *Summarizer) ProcessSession(ctx context.Context, session *history.Sessio
 
 	tiers := s.ClassifyTiers(session.Messages)
 
+	pctx := PromptContext{
+		SessionName: session.Name,
+		PinnedFacts: append(append([]string{}, s.config.PinnedFacts...), session.PinnedFacts...),
+	}
+
 	var result []history.Message
 
 	// Process messages that need to be compressed (oldest tier)
 	if len(tiers.ToCompress) > 0 {
-		// Check if we already have existing compressed summaries
-		var existingCompressed []history.Message
+		// Feed existing compressed summaries back as prior long-term memory
+		// rather than mixing them into the message dump, so re-compression
+		// does not progressively lose the thread
+		var previousParts []string
+		previousCount := 0
 		for _, msg := range tiers.Existing {
 			if msg.SummaryLevel == history.LevelCompressed {
-				existingCompressed = append(existingCompressed, msg)
+				previousParts = append(previousParts, msg.Content)
+				previousCount += msg.MessageCount
 			}
 		}
 
-		// Combine existing compressed summaries with new messages to compress
-		toCompressAll := append(existingCompressed, tiers.ToCompress...)
+		compressCtx := pctx
+		compressCtx.PreviousSummary = strings.Join(previousParts, "\n\n")
 
-		if len(toCompressAll) > 0 {
-			summary, err := s.SummarizeMessages(ctx, toCompressAll, history.LevelCompressed)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create compressed summary: %w", err)
-			}
-			result = append(result, summary)
+		summary, err := s.SummarizeMessages(ctx, tiers.ToCompress, history.LevelCompressed, compressCtx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create compressed summary: %w", err)
 		}
+		// Count the messages the prior summaries already covered
+		summary.MessageCount += previousCount
+		result = append(result, summary)
 	} else {
 		// Keep existing compressed summaries
 		for _, msg := range tiers.Existing {
@@ -193,7 +300,7 @@ func (s *Summarizer) ProcessSession(ctx context.Context, session *history.Sessio
 
 	// Process messages that need to be condensed
 	if len(tiers.ToCondense) > 0 {
-		summary, err := s.SummarizeMessages(ctx, tiers.ToCondense, history.LevelCondensed)
+		summary, err := s.SummarizeMessages(ctx, tiers.ToCondense, history.LevelCondensed, pctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create condensed summary: %w", err)
 		}
@@ -207,6 +314,9 @@ func (s *Summarizer) ProcessSession(ctx context.Context, session *history.Sessio
 		}
 	}
 
+	// Keep notes verbatim (they are never summarized)
+	result = append(result, tiers.Notes...)
+
 	// Keep recent messages in full
 	result = append(result, tiers.Recent...)
 
@@ -220,6 +330,7 @@ type Stats struct {
 	CondensedMessages int
 	CompressedCount   int
 	ExistingSummaries int
+	Notes             int
 }
 
 // GetStats returns statistics about how messages would be classified.
@@ -231,5 +342,6 @@ func (s *Summarizer) GetStats(messages []history.Message) Stats {
 		CondensedMessages: len(tiers.ToCondense),
 		CompressedCount:   len(tiers.ToCompress),
 		ExistingSummaries: len(tiers.Existing),
+		Notes:             len(tiers.Notes),
 	}
 }
@@ -0,0 +1,154 @@
+package summarize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gopus/internal/config"
+	"gopus/internal/history"
+	"gopus/internal/openai"
+)
+
+func day(d int) time.Time {
+	return time.Date(2026, time.August, d, 12, 0, 0, 0, time.UTC)
+}
+
+func TestBuildSummaryRequestAppendsContextSections(t *testing.T) {
+	messages := []history.Message{
+		{Role: history.RoleUser, Content: "hello", CreatedAt: day(1)},
+		{Role: history.RoleAssistant, Content: "hi there", CreatedAt: day(3)},
+	}
+	pctx := PromptContext{
+		SessionName:     "refactor plan",
+		PinnedFacts:     []string{"The user's name is Alex."},
+		PreviousSummary: "Earlier we chose approach B.",
+	}
+
+	prompt, userContent := buildSummaryRequest("Summarize briefly.", messages, pctx)
+
+	if prompt != "Summarize briefly." {
+		t.Errorf("expected template without placeholders to pass through, got %q", prompt)
+	}
+	for _, want := range []string{
+		"Session: refactor plan",
+		"Covers: 2026-08-01 to 2026-08-03",
+		"Previous long-term memory",
+		"Earlier we chose approach B.",
+		"- The user's name is Alex.",
+		"Conversation:\nuser: hello",
+		"assistant: hi there",
+	} {
+		if !strings.Contains(userContent, want) {
+			t.Errorf("expected user content to contain %q, got:\n%s", want, userContent)
+		}
+	}
+}
+
+func TestBuildSummaryRequestFillsPlaceholders(t *testing.T) {
+	messages := []history.Message{
+		{Role: history.RoleUser, Content: "hello", CreatedAt: day(2)},
+	}
+	pctx := PromptContext{
+		SessionName: "refactor plan",
+		PinnedFacts: []string{"fact one", "fact two"},
+	}
+	template := "Summarize {{session_name}} ({{date_range}}). Keep:\n{{pinned_facts}}\nAlso {{unknown_thing}}."
+
+	prompt, userContent := buildSummaryRequest(template, messages, pctx)
+
+	want := "Summarize refactor plan (2026-08-02). Keep:\n- fact one\n- fact two\nAlso {{unknown_thing}}."
+	if prompt != want {
+		t.Errorf("expected prompt:\n%s\ngot:\n%s", want, prompt)
+	}
+
+	// Values the template consumed must not be duplicated as sections
+	for _, unwanted := range []string{"Session:", "Covers:", "Pinned facts"} {
+		if strings.Contains(userContent, unwanted) {
+			t.Errorf("expected no %q section when the template uses the placeholder, got:\n%s", unwanted, userContent)
+		}
+	}
+	if !strings.Contains(userContent, "Conversation:") {
+		t.Errorf("expected the conversation dump, got:\n%s", userContent)
+	}
+}
+
+func TestBuildSummaryRequestOmitsEmptyValues(t *testing.T) {
+	messages := []history.Message{
+		{Role: history.RoleUser, Content: "hello"},
+	}
+
+	_, userContent := buildSummaryRequest("Summarize.", messages, PromptContext{})
+
+	if !strings.HasPrefix(userContent, "Conversation:") {
+		t.Errorf("expected only the conversation dump for an empty context, got:\n%s", userContent)
+	}
+}
+
+func TestMessageDateRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []history.Message
+		want     string
+	}{
+		{"spanning days", []history.Message{{CreatedAt: day(5)}, {CreatedAt: day(1)}}, "2026-08-01 to 2026-08-05"},
+		{"single day", []history.Message{{CreatedAt: day(7)}, {CreatedAt: day(7)}}, "2026-08-07"},
+		{"no timestamps", []history.Message{{}, {}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messageDateRange(tt.messages); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestSummarizeMessagesToolCallReplyErrors is a regression test: a pure
+// tool-call reply (content null) used to panic in extractMessageContent;
+// the summarizer must surface ErrEmptyResponse instead.
+func TestSummarizeMessagesToolCallReplyErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"id": "chatcmpl-test",
+			"object": "chat.completion",
+			"created": 0,
+			"model": "test-model",
+			"choices": [{
+				"index": 0,
+				"message": {
+					"role": "assistant",
+					"content": null,
+					"tool_calls": [{
+						"id": "call_1",
+						"type": "function",
+						"function": {"name": "read_file", "arguments": "{}"}
+					}]
+				},
+				"finish_reason": "tool_calls"
+			}]
+		}`)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{OpenAI: config.OpenAIConfig{APIKey: "test-key", BaseURL: srv.URL}}
+	cfg.ApplyDefaults()
+	client, err := openai.NewChatClient(cfg)
+	if err != nil {
+		t.Fatalf("NewChatClient: %v", err)
+	}
+
+	s := New(client, cfg.Summarization)
+	messages := []history.Message{{Role: history.RoleUser, Content: "hello"}}
+
+	_, err = s.SummarizeMessages(context.Background(), messages, history.LevelCondensed, PromptContext{})
+	if !errors.Is(err, openai.ErrEmptyResponse) {
+		t.Errorf("expected ErrEmptyResponse, got %v", err)
+	}
+}
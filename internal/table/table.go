@@ -5,15 +5,29 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+
+	"gopus/internal/printer"
+	"gopus/internal/text"
 )
 
+// terminalWidth reports the terminal width for auto-fitting; a variable so
+// tests can substitute a fixed width.
+var terminalWidth = printer.TerminalWidth
+
 // Column represents a table column with its configuration.
 type Column struct {
 	Header   string
 	MinWidth int
 	MaxWidth int
 	Align    Alignment
+
+	// WrapText soft-wraps cell content at word boundaries within the
+	// column width instead of truncating it with "...". Embedded newlines
+	// force a break. Rows with wrapped cells render as multi-line blocks.
+	WrapText bool
 }
 
 // Alignment specifies how content should be aligned within a column.
@@ -42,7 +56,7 @@ func New(columns ...Column) *Table {
 
 	// Initialize widths with header lengths and minimum widths
 	for i, col := range columns {
-		t.widths[i] = len(col.Header)
+		t.widths[i] = text.Width(col.Header)
 		if col.MinWidth > t.widths[i] {
 			t.widths[i] = col.MinWidth
 		}
@@ -61,91 +75,283 @@ func (t *Table) AddRow(values ...string) {
 		}
 	}
 
-	// Update column widths based on content
+	// Update column widths based on content, measuring multi-line cells
+	// by their widest line. Wrapped cells may contain ANSI color codes,
+	// which occupy no columns.
 	for i, val := range row {
-		if len(val) > t.widths[i] {
-			t.widths[i] = len(val)
+		for _, line := range strings.Split(val, "\n") {
+			w := text.Width(line)
+			if t.columns[i].WrapText {
+				w = visibleWidth(line)
+			}
+			if w > t.widths[i] {
+				t.widths[i] = w
+			}
 		}
 	}
 
 	t.rows = append(t.rows, row)
 }
 
-// calculateFinalWidths applies max width constraints and returns final widths.
+// calculateFinalWidths applies max width constraints, auto-fit to the
+// terminal, and returns final widths.
 func (t *Table) calculateFinalWidths() []int {
+	return t.fitWidths(terminalWidth())
+}
+
+// fitWidths applies max width constraints and returns final widths that fit
+// in available columns where possible. A wrapping column whose content all
+// wraps short of MaxWidth shrinks to its widest wrapped line, so the table
+// claims no more room than it uses. When the grid would still overflow, the
+// flexible columns (those without a MaxWidth) shrink proportionally toward
+// their minimum; rendering then truncates or wraps cells to the reduced
+// widths.
+func (t *Table) fitWidths(available int) []int {
 	widths := make([]int, len(t.widths))
 	copy(widths, t.widths)
 
 	for i, col := range t.columns {
 		if col.MaxWidth > 0 && widths[i] > col.MaxWidth {
 			widths[i] = col.MaxWidth
+			if col.WrapText {
+				widths[i] = t.widestWrappedLine(i, col.MaxWidth)
+			}
+		}
+	}
+
+	// Each " │ " separator occupies three columns
+	total := 3 * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	overflow := total - available
+	if overflow <= 0 {
+		return widths
+	}
+
+	// Shrink each flexible column proportionally to its slack above the
+	// header/MinWidth floor, then hand out the rounding remainder one
+	// column at a time
+	flexSlack := 0
+	for i, col := range t.columns {
+		if col.MaxWidth == 0 {
+			flexSlack += widths[i] - t.floorWidth(i)
+		}
+	}
+	if flexSlack == 0 {
+		return widths
+	}
+	remaining := min(overflow, flexSlack)
+	for i, col := range t.columns {
+		if col.MaxWidth != 0 {
+			continue
+		}
+		slack := widths[i] - t.floorWidth(i)
+		cut := min(overflow*slack/flexSlack, slack, remaining)
+		widths[i] -= cut
+		remaining -= cut
+	}
+	for remaining > 0 {
+		shrunk := false
+		for i, col := range t.columns {
+			if remaining == 0 {
+				break
+			}
+			if col.MaxWidth == 0 && widths[i] > t.floorWidth(i) {
+				widths[i]--
+				remaining--
+				shrunk = true
+			}
+		}
+		if !shrunk {
+			break
 		}
 	}
 
 	return widths
 }
 
-// truncate truncates a string to the specified width, adding ellipsis if needed.
-func truncate(s string, width int) string {
-	if len(s) <= width {
-		return s
+// floorWidth returns the width column i can never shrink below: its header
+// or its MinWidth, whichever is wider.
+func (t *Table) floorWidth(i int) int {
+	floor := text.Width(t.columns[i].Header)
+	if t.columns[i].MinWidth > floor {
+		floor = t.columns[i].MinWidth
 	}
-	if width <= 3 {
-		return s[:width]
+	return floor
+}
+
+// widestWrappedLine returns the width column i needs once every cell is
+// wrapped to maxWidth: the widest wrapped line across all rows, but never
+// less than the header or MinWidth require.
+func (t *Table) widestWrappedLine(i, maxWidth int) int {
+	widest := t.floorWidth(i)
+	for _, row := range t.rows {
+		for _, line := range wrapCell(row[i], maxWidth) {
+			if w := visibleWidth(line); w > widest {
+				widest = w
+			}
+		}
+	}
+	if widest > maxWidth {
+		widest = maxWidth
 	}
-	return s[:width-3] + "..."
+	return widest
 }
 
 // formatCell formats a cell value according to column width and alignment.
+// Truncation and padding work on display columns, not bytes, so cells with
+// CJK text or emoji still line up.
 func formatCell(value string, width int, align Alignment) string {
-	value = truncate(value, width)
+	value = text.TruncateWidth(value, width)
+	pad := width - text.Width(value)
+	if pad < 0 {
+		pad = 0
+	}
 	switch align {
 	case AlignRight:
-		return fmt.Sprintf("%*s", width, value)
+		return strings.Repeat(" ", pad) + value
 	default:
-		return fmt.Sprintf("%-*s", width, value)
+		return value + strings.Repeat(" ", pad)
 	}
 }
 
 // RenderHeader returns the formatted header row.
 func (t *Table) RenderHeader() string {
-	widths := t.calculateFinalWidths()
-	var parts []string
+	return t.renderHeader(t.calculateFinalWidths())
+}
 
+func (t *Table) renderHeader(widths []int) string {
+	var parts []string
 	for i, col := range t.columns {
 		parts = append(parts, formatCell(col.Header, widths[i], col.Align))
 	}
-
-	return "\033[1m" + strings.Join(parts, " │ ") + "\033[0m"
+	return printer.ColorBold + strings.Join(parts, " │ ") + printer.ColorReset
 }
 
 // RenderSeparator returns the separator line between header and rows.
 func (t *Table) RenderSeparator() string {
-	widths := t.calculateFinalWidths()
-	var parts []string
+	return t.renderSeparator(t.calculateFinalWidths())
+}
 
+func (t *Table) renderSeparator(widths []int) string {
+	var parts []string
 	for _, w := range widths {
 		parts = append(parts, strings.Repeat("─", w))
 	}
-
 	return strings.Join(parts, "─┼─")
 }
 
-// RenderRow returns a formatted row at the specified index.
+// RenderRow returns a formatted row at the specified index. Rows with
+// wrapped or multi-line cells come back as a block of newline-separated
+// lines with separators and alignment kept on continuation lines.
 func (t *Table) RenderRow(index int) string {
 	if index < 0 || index >= len(t.rows) {
 		return ""
 	}
+	return strings.Join(t.renderRowLines(index, t.calculateFinalWidths()), "\n")
+}
 
-	widths := t.calculateFinalWidths()
+// renderRowLines renders row index as one physical line per wrapped cell
+// line. Cells shorter than the tallest cell in the row pad out with blanks.
+func (t *Table) renderRowLines(index int, widths []int) []string {
 	row := t.rows[index]
-	var parts []string
 
+	cells := make([][]string, len(t.columns))
+	height := 1
 	for i, col := range t.columns {
-		parts = append(parts, formatCell(row[i], widths[i], col.Align))
+		if col.WrapText {
+			cells[i] = wrapCell(row[i], widths[i])
+		} else {
+			cells[i] = strings.Split(row[i], "\n")
+		}
+		if len(cells[i]) > height {
+			height = len(cells[i])
+		}
 	}
 
-	return strings.Join(parts, " │ ")
+	lines := make([]string, 0, height)
+	for line := range height {
+		parts := make([]string, len(t.columns))
+		for i, col := range t.columns {
+			val := ""
+			if line < len(cells[i]) {
+				val = cells[i][line]
+			}
+			if col.WrapText {
+				parts[i] = padCell(val, widths[i], col.Align)
+			} else {
+				parts[i] = formatCell(val, widths[i], col.Align)
+			}
+		}
+		lines = append(lines, strings.Join(parts, " │ "))
+	}
+	return lines
+}
+
+// RenderStacked returns the table as a stacked "key: value" listing, one
+// block per row separated by blank lines. Used on narrow terminals where
+// the grid layout would overflow.
+func (t *Table) RenderStacked() string {
+	var b strings.Builder
+
+	for i, row := range t.rows {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for j, col := range t.columns {
+			fmt.Fprintf(&b, "%s: %s\n", col.Header, row[j])
+		}
+	}
+
+	return b.String()
+}
+
+// RenderForWidth returns the table rendered for the given terminal width:
+// the regular grid fitted to it when there is room, or the stacked layout
+// below printer.NarrowWidth columns.
+func (t *Table) RenderForWidth(width int) string {
+	if width < printer.NarrowWidth {
+		return t.RenderStacked()
+	}
+	return t.renderWithWidths(t.fitWidths(width))
+}
+
+// SortBy stably sorts the rows by the given column, so rows with equal keys
+// keep their insertion order. With numeric set, cell values compare as
+// numbers ("3" before "12") and values that do not parse sort after every
+// number. An out-of-range column leaves the order unchanged.
+func (t *Table) SortBy(column int, ascending, numeric bool) {
+	if column < 0 || column >= len(t.columns) {
+		return
+	}
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		a, b := t.rows[i][column], t.rows[j][column]
+		if !ascending {
+			a, b = b, a
+		}
+		if numeric {
+			return lessNumeric(a, b)
+		}
+		return a < b
+	})
+}
+
+// lessNumeric orders two cell values numerically, falling back to a string
+// comparison when neither parses as a number.
+func lessNumeric(a, b string) bool {
+	av, aerr := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	bv, berr := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	switch {
+	case aerr == nil && berr == nil:
+		return av < bv
+	case aerr == nil:
+		return true
+	case berr == nil:
+		return false
+	default:
+		return a < b
+	}
 }
 
 // RowCount returns the number of rows in the table.
@@ -155,13 +361,14 @@ func (t *Table) RowCount() int {
 
 // Render returns the complete table as a string.
 func (t *Table) Render() string {
-	var lines []string
+	return t.renderWithWidths(t.calculateFinalWidths())
+}
 
-	lines = append(lines, t.RenderHeader())
-	lines = append(lines, t.RenderSeparator())
+func (t *Table) renderWithWidths(widths []int) string {
+	lines := []string{t.renderHeader(widths), t.renderSeparator(widths)}
 
 	for i := range t.rows {
-		lines = append(lines, t.RenderRow(i))
+		lines = append(lines, t.renderRowLines(i, widths)...)
 	}
 
 	return strings.Join(lines, "\n")
@@ -195,31 +402,61 @@ func (t *Table) Print(opts PrintOptions) {
 		opts.Writer = os.Stdout
 	}
 
+	// Narrow terminals get the stacked layout instead of the grid
+	if terminalWidth() < printer.NarrowWidth {
+		fmt.Fprintln(opts.Writer)
+		for _, line := range strings.Split(strings.TrimRight(t.RenderStacked(), "\n"), "\n") {
+			fmt.Fprintf(opts.Writer, "%s%s\n", opts.Indent, line)
+		}
+		fmt.Fprintln(opts.Writer)
+		return
+	}
+
+	widths := t.calculateFinalWidths()
 	fmt.Fprintln(opts.Writer)
-	fmt.Fprintf(opts.Writer, "%s%s\n", opts.Indent, t.RenderHeader())
-	fmt.Fprintf(opts.Writer, "%s%s\n", opts.Indent, t.RenderSeparator())
+	fmt.Fprintf(opts.Writer, "%s%s\n", opts.Indent, t.renderHeader(widths))
+	fmt.Fprintf(opts.Writer, "%s%s\n", opts.Indent, t.renderSeparator(widths))
 
+	highlight := opts.HighlightColumn >= 0 && opts.HighlightColumn < len(t.columns) && printer.ColorsEnabled()
 	for i := 0; i < t.RowCount(); i++ {
-		row := t.RenderRow(i)
-		if opts.HighlightColumn >= 0 && opts.HighlightColumn < len(t.columns) {
-			// Split the row to apply highlighting to the specified column
-			parts := strings.SplitN(row, " │ ", opts.HighlightColumn+2)
-			if len(parts) > opts.HighlightColumn {
-				var highlighted strings.Builder
-				for j, part := range parts {
-					if j > 0 {
-						highlighted.WriteString(" │ ")
-					}
-					if j == opts.HighlightColumn {
-						highlighted.WriteString(fmt.Sprintf("\033[%sm%s\033[0m", opts.HighlightColor, part))
-					} else {
-						highlighted.WriteString(part)
-					}
-				}
-				row = highlighted.String()
+		// Highlight each physical line, so wrapped rows keep the
+		// highlighted column colored on continuation lines too
+		for _, line := range t.renderRowLines(i, widths) {
+			if highlight {
+				line = highlightColumn(line, opts.HighlightColumn, opts.HighlightColor)
 			}
+			fmt.Fprintf(opts.Writer, "%s%s\n", opts.Indent, line)
 		}
-		fmt.Fprintf(opts.Writer, "%s%s\n", opts.Indent, row)
 	}
 	fmt.Fprintln(opts.Writer)
 }
+
+// RenderTo prints the table to w with the given options, overriding the
+// options' Writer field. Saves call sites from mutating a PrintOptions just
+// to direct the output.
+func (t *Table) RenderTo(w io.Writer, opts PrintOptions) {
+	opts.Writer = w
+	t.Print(opts)
+}
+
+// highlightColumn wraps the given column of a rendered line in the ANSI
+// color, splitting on the cell separator.
+func highlightColumn(line string, column int, color string) string {
+	parts := strings.SplitN(line, " │ ", column+2)
+	if len(parts) <= column {
+		return line
+	}
+
+	var highlighted strings.Builder
+	for j, part := range parts {
+		if j > 0 {
+			highlighted.WriteString(" │ ")
+		}
+		if j == column {
+			highlighted.WriteString(fmt.Sprintf("\033[%sm%s\033[0m", color, part))
+		} else {
+			highlighted.WriteString(part)
+		}
+	}
+	return highlighted.String()
+}
@@ -0,0 +1,331 @@
+package table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gopus/internal/printer"
+	"gopus/internal/text"
+)
+
+func sampleTable() *Table {
+	t := New(
+		Column{Header: "Name", MinWidth: 4, MaxWidth: 20},
+		Column{Header: "Messages", MinWidth: 8, MaxWidth: 8, Align: AlignRight},
+	)
+	t.AddRow("first session", "12")
+	t.AddRow("second session", "3")
+	return t
+}
+
+func TestRenderForWidthNarrowStacks(t *testing.T) {
+	got := sampleTable().RenderForWidth(30)
+
+	if strings.Contains(got, "│") {
+		t.Errorf("expected stacked layout without column separators, got:\n%s", got)
+	}
+	for _, want := range []string{"Name: first session", "Messages: 12", "Name: second session"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected stacked output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderForWidthGrid(t *testing.T) {
+	for _, width := range []int{50, 120} {
+		got := sampleTable().RenderForWidth(width)
+
+		if !strings.Contains(got, "│") {
+			t.Errorf("width %d: expected grid layout, got:\n%s", width, got)
+		}
+		if !strings.Contains(got, "Name") || !strings.Contains(got, "Messages") {
+			t.Errorf("width %d: expected header row, got:\n%s", width, got)
+		}
+	}
+}
+
+func TestRenderAlignsWideRunes(t *testing.T) {
+	tbl := New(
+		Column{Header: "Name", MinWidth: 4, MaxWidth: 12},
+		Column{Header: "Msgs", MinWidth: 4, Align: AlignRight},
+	)
+	tbl.AddRow("日本語のセッション", "3")
+	tbl.AddRow("ascii name", "12")
+
+	rows := strings.Split(tbl.Render(), "\n")[2:]
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rendered rows, got %d", len(rows))
+	}
+
+	// Both rows must place the separator at the same column, and the
+	// truncated CJK cell must not contain a replacement character.
+	first := strings.Split(rows[0], "│")[0]
+	second := strings.Split(rows[1], "│")[0]
+	if w1, w2 := text.Width(first), text.Width(second); w1 != w2 {
+		t.Errorf("misaligned columns: %d vs %d columns (%q vs %q)", w1, w2, first, second)
+	}
+	if strings.ContainsRune(rows[0], '�') {
+		t.Errorf("truncation split a rune: %q", rows[0])
+	}
+}
+
+func TestRenderRowWrapsAtWordBoundaries(t *testing.T) {
+	tbl := New(
+		Column{Header: "Tool", MinWidth: 4},
+		Column{Header: "Description", MaxWidth: 16, WrapText: true},
+	)
+	tbl.AddRow("read_file", "Reads a file from the local workspace and returns it")
+
+	lines := strings.Split(tbl.RenderRow(0), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected the description to wrap over several lines, got:\n%s", tbl.RenderRow(0))
+	}
+
+	for _, line := range lines {
+		cells := strings.Split(line, " │ ")
+		if len(cells) != 2 {
+			t.Fatalf("expected the separator on every wrapped line, got %q", line)
+		}
+		if w := visibleWidth(cells[1]); w > 16 {
+			t.Errorf("wrapped line %q is %d columns wide, max 16", cells[1], w)
+		}
+		if strings.Contains(cells[1], "...") {
+			t.Errorf("expected wrapping instead of truncation, got %q", cells[1])
+		}
+	}
+
+	// Continuation lines leave the non-wrapping cell blank
+	if got := strings.Split(lines[1], " │ ")[0]; strings.TrimSpace(got) != "" {
+		t.Errorf("expected a blank first cell on the continuation line, got %q", got)
+	}
+}
+
+func TestRenderRowEmbeddedNewlinesForceBreaks(t *testing.T) {
+	tbl := New(
+		Column{Header: "Server", MinWidth: 4},
+		Column{Header: "Error", MaxWidth: 40, WrapText: true},
+	)
+	tbl.AddRow("files", "connection refused\nretrying in 5s")
+
+	lines := strings.Split(tbl.RenderRow(0), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines from the forced break, got:\n%s", tbl.RenderRow(0))
+	}
+	if !strings.Contains(lines[0], "connection refused") || !strings.Contains(lines[1], "retrying in 5s") {
+		t.Errorf("expected the newline to split the cell, got:\n%s", tbl.RenderRow(0))
+	}
+}
+
+func TestWrapAnsiCodesDoNotCountTowardWidth(t *testing.T) {
+	colored := "\033[31mfailed\033[0m after three attempts"
+	tbl := New(Column{Header: "Status", MaxWidth: 13, WrapText: true})
+	tbl.AddRow(colored)
+
+	lines := strings.Split(tbl.RenderRow(0), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 wrapped lines, got:\n%s", tbl.RenderRow(0))
+	}
+	if !strings.Contains(lines[0], "\033[31mfailed\033[0m after") {
+		t.Errorf("expected the color codes kept and not counted toward the width, got %q", lines[0])
+	}
+	for _, line := range lines {
+		if w := visibleWidth(line); w > 13 {
+			t.Errorf("wrapped line %q is %d visible columns wide, max 13", line, w)
+		}
+	}
+}
+
+func TestWrapHardBreaksOversizedWords(t *testing.T) {
+	tbl := New(Column{Header: "URL", MaxWidth: 10, WrapText: true})
+	tbl.AddRow("https://example.invalid/very/long/path")
+
+	for _, line := range strings.Split(tbl.RenderRow(0), "\n") {
+		if w := visibleWidth(line); w > 10 {
+			t.Errorf("hard-broken line %q is %d columns wide, max 10", line, w)
+		}
+	}
+}
+
+func TestWrapColumnShrinksToWidestLine(t *testing.T) {
+	tbl := New(Column{Header: "Note", MaxWidth: 30, WrapText: true})
+	tbl.AddRow("short words only wrap here early")
+
+	// Every wrapped line is narrower than MaxWidth, so the column should
+	// claim only as much room as its widest line.
+	width := tbl.calculateFinalWidths()[0]
+	if width >= 30 {
+		t.Errorf("expected the column narrower than MaxWidth, got %d", width)
+	}
+	widest := 0
+	for _, line := range wrapCell("short words only wrap here early", 30) {
+		if w := visibleWidth(line); w > widest {
+			widest = w
+		}
+	}
+	if width != widest {
+		t.Errorf("expected column width %d to match the widest wrapped line %d", width, widest)
+	}
+}
+
+func TestSortByNumeric(t *testing.T) {
+	tbl := New(
+		Column{Header: "Name"},
+		Column{Header: "Msgs", Align: AlignRight},
+	)
+	tbl.AddRow("twelve", "12")
+	tbl.AddRow("three", "3")
+	tbl.AddRow("broken", "n/a")
+	tbl.AddRow("hundred", "100")
+
+	tbl.SortBy(1, true, true)
+
+	var names []string
+	for i := range tbl.RowCount() {
+		names = append(names, strings.Fields(tbl.RenderRow(i))[0])
+	}
+	// Numeric order, with the unparsable value after every number
+	want := []string{"three", "twelve", "hundred", "broken"}
+	for i, name := range want {
+		if names[i] != name {
+			t.Fatalf("expected row order %v, got %v", want, names)
+		}
+	}
+}
+
+func TestSortByStableForEqualKeys(t *testing.T) {
+	tbl := New(
+		Column{Header: "Server"},
+		Column{Header: "Tool"},
+	)
+	tbl.AddRow("files", "read")
+	tbl.AddRow("web", "fetch")
+	tbl.AddRow("files", "write")
+
+	tbl.SortBy(0, true, false)
+
+	rows := []string{tbl.RenderRow(0), tbl.RenderRow(1), tbl.RenderRow(2)}
+	// The two files rows keep their insertion order
+	if !strings.Contains(rows[0], "read") || !strings.Contains(rows[1], "write") || !strings.Contains(rows[2], "fetch") {
+		t.Errorf("expected a stable sort, got:\n%s", strings.Join(rows, "\n"))
+	}
+}
+
+func TestSortByDescending(t *testing.T) {
+	tbl := New(Column{Header: "Msgs"})
+	tbl.AddRow("3")
+	tbl.AddRow("12")
+
+	tbl.SortBy(0, false, true)
+
+	if first := strings.TrimSpace(tbl.RenderRow(0)); first != "12" {
+		t.Errorf("expected the largest value first, got %q", first)
+	}
+}
+
+func TestFitWidthsShrinksFlexibleColumns(t *testing.T) {
+	tbl := New(
+		Column{Header: "Name", MinWidth: 4},
+		Column{Header: "Note", MinWidth: 4},
+	)
+	tbl.AddRow(strings.Repeat("n", 40), strings.Repeat("x", 40))
+
+	widths := tbl.fitWidths(50)
+
+	total := widths[0] + widths[1] + 3
+	if total > 50 {
+		t.Errorf("expected the fitted grid within 50 columns, got %d (%v)", total, widths)
+	}
+	for i, w := range widths {
+		if w < 4 {
+			t.Errorf("column %d shrunk below its minimum: %d", i, w)
+		}
+	}
+
+	// Rendering at the fitted widths truncates rather than overflows
+	for _, line := range strings.Split(tbl.RenderForWidth(50), "\n") {
+		if w := visibleWidth(line); w > 50 {
+			t.Errorf("rendered line %q is %d columns wide", line, w)
+		}
+	}
+}
+
+func TestFitWidthsRespectsFixedColumns(t *testing.T) {
+	tbl := New(
+		Column{Header: "Name", MaxWidth: 20},
+		Column{Header: "Note"},
+	)
+	tbl.AddRow(strings.Repeat("n", 20), strings.Repeat("x", 60))
+
+	widths := tbl.fitWidths(40)
+
+	// Only the flexible column gives up room
+	if widths[0] != 20 {
+		t.Errorf("expected the capped column untouched at 20, got %d", widths[0])
+	}
+	if widths[1] >= 60 {
+		t.Errorf("expected the flexible column to shrink, got %d", widths[1])
+	}
+}
+
+func TestFitWidthsLeavesFittingTablesAlone(t *testing.T) {
+	tbl := sampleTable()
+
+	if got, want := tbl.fitWidths(120), tbl.fitWidths(80); !slicesEqual(got, want) {
+		t.Errorf("expected identical widths when the table fits, got %v vs %v", got, want)
+	}
+}
+
+func slicesEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRenderToWritesToWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	sampleTable().RenderTo(&buf, DefaultPrintOptions())
+
+	if !strings.Contains(buf.String(), "first session") {
+		t.Errorf("expected the table in the writer, got:\n%s", buf.String())
+	}
+}
+
+func TestPrintHighlightsWrappedLines(t *testing.T) {
+	wasEnabled := printer.ColorsEnabled()
+	printer.SetColors(true)
+	defer printer.SetColors(wasEnabled)
+
+	tbl := New(
+		Column{Header: "Name", MinWidth: 4},
+		Column{Header: "Description", MaxWidth: 12, WrapText: true},
+	)
+	tbl.AddRow("files", "serves files over the local socket")
+
+	var buf bytes.Buffer
+	opts := DefaultPrintOptions()
+	opts.Writer = &buf
+	opts.HighlightColumn = 0
+	tbl.Print(opts)
+
+	var rowLines int
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if !strings.Contains(line, "│") || strings.Contains(line, "Description") {
+			continue
+		}
+		rowLines++
+		if !strings.Contains(line, "\033[33m") {
+			t.Errorf("expected the highlight on wrapped line %q", line)
+		}
+	}
+	if rowLines < 2 {
+		t.Fatalf("expected the row to wrap over several printed lines, got:\n%s", buf.String())
+	}
+}
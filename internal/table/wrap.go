@@ -0,0 +1,123 @@
+package table
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"gopus/internal/text"
+)
+
+// wrapCell wraps a cell value to the given display width: embedded newlines
+// force a break, and each resulting segment is soft-wrapped at word
+// boundaries. Always returns at least one line.
+func wrapCell(value string, width int) []string {
+	var lines []string
+	for _, segment := range strings.Split(value, "\n") {
+		lines = append(lines, wrapLine(segment, width)...)
+	}
+	return lines
+}
+
+// wrapLine greedily wraps a single line at word boundaries. Runs of
+// whitespace collapse to a single space; a word wider than the column is
+// hard-broken at the width limit so the row cannot overflow.
+func wrapLine(line string, width int) []string {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	current, currentWidth := "", 0
+	for _, word := range words {
+		wordWidth := visibleWidth(word)
+		if current != "" && currentWidth+1+wordWidth > width {
+			lines = append(lines, current)
+			current, currentWidth = "", 0
+		}
+		if current != "" {
+			current += " "
+			currentWidth++
+		}
+		current += word
+		currentWidth += wordWidth
+
+		for currentWidth > width {
+			head, tail := splitAtWidth(current, width)
+			if tail == "" {
+				break
+			}
+			lines = append(lines, head)
+			current, currentWidth = tail, visibleWidth(tail)
+		}
+	}
+	return append(lines, current)
+}
+
+// padCell pads value to width display columns without truncating it; wrapped
+// lines are already within the column width. ANSI escape sequences inside
+// the value occupy no columns.
+func padCell(value string, width int, align Alignment) string {
+	pad := width - visibleWidth(value)
+	if pad < 0 {
+		pad = 0
+	}
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", pad) + value
+	default:
+		return value + strings.Repeat(" ", pad)
+	}
+}
+
+// visibleWidth returns the display width of s with ANSI escape sequences
+// counted as zero columns, so colored cell content still lines up.
+func visibleWidth(s string) int {
+	w := 0
+	for i := 0; i < len(s); {
+		if end := ansiEnd(s, i); end > i {
+			i = end
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w += text.RuneWidth(r)
+		i += size
+	}
+	return w
+}
+
+// splitAtWidth splits s at the last rune boundary within width display
+// columns. The head always contains at least one visible rune so callers
+// make progress even at tiny widths; ANSI escape sequences never split.
+func splitAtWidth(s string, width int) (head, tail string) {
+	w := 0
+	for i := 0; i < len(s); {
+		if end := ansiEnd(s, i); end > i {
+			i = end
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rw := text.RuneWidth(r)
+		if w > 0 && w+rw > width {
+			return s[:i], s[i:]
+		}
+		w += rw
+		i += size
+	}
+	return s, ""
+}
+
+// ansiEnd returns the index just past the ANSI CSI sequence starting at i,
+// or i when s[i:] does not start one. An unterminated sequence runs to the
+// end of the string.
+func ansiEnd(s string, i int) int {
+	if i+1 >= len(s) || s[i] != '\033' || s[i+1] != '[' {
+		return i
+	}
+	for j := i + 2; j < len(s); j++ {
+		if c := s[j]; c >= '@' && c <= '~' {
+			return j + 1
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package term
+
+import "time"
+
+// Without a resize signal the cached size is simply expired on a short
+// interval, so a resize shows up within a second.
+func init() {
+	go func() {
+		for range time.Tick(time.Second) {
+			Invalidate()
+		}
+	}()
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package term
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// The kernel reports terminal resizes with SIGWINCH, so the cached size
+// only needs re-querying when one arrives.
+func init() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGWINCH)
+	go func() {
+		for range ch {
+			Invalidate()
+		}
+	}()
+}
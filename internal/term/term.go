@@ -0,0 +1,83 @@
+// Package term tracks the terminal size so rendering code can consult the
+// current width on every render. On Unix the cached size is invalidated by
+// SIGWINCH; elsewhere it expires on a short poll interval. Either way a
+// resize is picked up without an ioctl per render.
+package term
+
+import (
+	"os"
+	"sync"
+
+	xterm "golang.org/x/term"
+)
+
+// FallbackWidth is assumed when stdout is not a terminal or does not know
+// its size.
+const FallbackWidth = 80
+
+var (
+	mu     sync.Mutex
+	query  func() (int, bool) = systemWidth
+	cached int
+	valid  bool
+)
+
+// Width returns the current terminal width in columns, or FallbackWidth
+// when it cannot be determined. Cheap enough to call on every render.
+func Width() int {
+	if w, ok := WidthOK(); ok {
+		return w
+	}
+	return FallbackWidth
+}
+
+// WidthOK is Width plus a flag reporting whether the size is actually
+// known, for callers that skip width-dependent formatting entirely when
+// stdout is not a terminal.
+func WidthOK() (int, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !valid {
+		if w, ok := query(); ok {
+			cached = w
+		} else {
+			cached = 0
+		}
+		valid = true
+	}
+	return cached, cached != 0
+}
+
+// Invalidate drops the cached size so the next Width call re-queries it.
+// Called from the resize watcher; safe from any goroutine.
+func Invalidate() {
+	mu.Lock()
+	valid = false
+	mu.Unlock()
+}
+
+// SetQuery substitutes the size query, for tests that need to fake the
+// terminal size. It returns a function restoring the previous query.
+func SetQuery(fn func() (int, bool)) (restore func()) {
+	mu.Lock()
+	prev := query
+	query = fn
+	valid = false
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		query = prev
+		valid = false
+		mu.Unlock()
+	}
+}
+
+// systemWidth asks the terminal for its width, reporting failure when
+// stdout is not a terminal or does not know its size.
+func systemWidth() (int, bool) {
+	w, _, err := xterm.GetSize(int(os.Stdout.Fd()))
+	if err != nil || w <= 0 {
+		return 0, false
+	}
+	return w, true
+}
@@ -0,0 +1,40 @@
+package term
+
+import "testing"
+
+func TestWidthUsesInjectedQuery(t *testing.T) {
+	calls := 0
+	restore := SetQuery(func() (int, bool) {
+		calls++
+		return 60, true
+	})
+	defer restore()
+
+	if got := Width(); got != 60 {
+		t.Errorf("expected injected width 60, got %d", got)
+	}
+
+	// The size is cached until invalidated
+	Width()
+	if calls != 1 {
+		t.Errorf("expected a single query while cached, got %d", calls)
+	}
+
+	Invalidate()
+	Width()
+	if calls != 2 {
+		t.Errorf("expected a re-query after Invalidate, got %d calls", calls)
+	}
+}
+
+func TestWidthFallsBack(t *testing.T) {
+	restore := SetQuery(func() (int, bool) { return 0, false })
+	defer restore()
+
+	if got := Width(); got != FallbackWidth {
+		t.Errorf("expected fallback width %d, got %d", FallbackWidth, got)
+	}
+	if _, ok := WidthOK(); ok {
+		t.Error("expected WidthOK to report an unknown size")
+	}
+}
@@ -0,0 +1,115 @@
+// Package testutil provides shared test fixtures: a scripted fake of the
+// OpenAI chat-completions endpoint and builders for its response bodies,
+// so packages exercising ChatClient or the chat loop do not each grow
+// their own copy.
+package testutil
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Response is one scripted reply. A zero Status means 200; Delay pauses
+// before replying, to exercise timeouts and slow-server behavior.
+type Response struct {
+	Status int
+	Body   string
+	Delay  time.Duration
+}
+
+// Server is a fake chat-completions endpoint that replies to successive
+// requests from a script, in order, and records every request body it
+// receives. Requests beyond the script fail the test.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	call     int
+	requests []string
+}
+
+// NewServer starts a scripted server. It is closed via t.Cleanup, so
+// callers may but need not close it themselves.
+func NewServer(t testing.TB, script []Response) *Server {
+	t.Helper()
+
+	s := &Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.requests = append(s.requests, string(body))
+		index := s.call
+		s.call++
+		s.mu.Unlock()
+
+		if index >= len(script) {
+			t.Errorf("unexpected request #%d to scripted server", index+1)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		resp := script[index]
+		if resp.Delay > 0 {
+			time.Sleep(resp.Delay)
+		}
+		status := resp.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, resp.Body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Requests returns a copy of the request bodies received so far, in order.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+// CompletionBody builds a minimal chat completion response body.
+// content must be a JSON literal: "null" or a quoted string.
+func CompletionBody(content, finishReason string) string {
+	return fmt.Sprintf(`{
+		"id": "chatcmpl-test",
+		"object": "chat.completion",
+		"created": 0,
+		"model": "test-model",
+		"choices": [{
+			"index": 0,
+			"message": {"role": "assistant", "content": %s},
+			"finish_reason": %q
+		}]
+	}`, content, finishReason)
+}
+
+// ToolCallBody builds a response body where the model calls one tool.
+func ToolCallBody(callID, name, arguments string) string {
+	return fmt.Sprintf(`{
+		"id": "chatcmpl-test",
+		"object": "chat.completion",
+		"created": 0,
+		"model": "test-model",
+		"choices": [{
+			"index": 0,
+			"message": {"role": "assistant", "content": null, "tool_calls": [
+				{"id": %q, "type": "function", "function": {"name": %q, "arguments": %q}}
+			]},
+			"finish_reason": "tool_calls"
+		}]
+	}`, callID, name, arguments)
+}
+
+// ErrorBody builds a minimal API error envelope.
+func ErrorBody(message string) string {
+	return fmt.Sprintf(`{"error": {"message": %q, "type": "server_error"}}`, message)
+}
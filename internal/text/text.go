@@ -0,0 +1,64 @@
+// Package text provides display-width-aware string helpers for terminal
+// output. Byte- or rune-counting truncation splits multi-byte characters and
+// misaligns columns as soon as CJK text or emoji show up; these helpers
+// count terminal columns instead.
+package text
+
+import (
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// RuneWidth returns the number of terminal columns r occupies: zero for
+// combining marks and the zero-width joiner, two for East Asian wide and
+// fullwidth runes, one for everything else.
+func RuneWidth(r rune) int {
+	if r == '\u200d' || unicode.In(r, unicode.Mn, unicode.Me) {
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// Width returns the number of terminal columns s occupies.
+func Width(s string) int {
+	w := 0
+	for _, r := range s {
+		w += RuneWidth(r)
+	}
+	return w
+}
+
+// TruncateWidth shortens s to at most maxWidth display columns, replacing
+// the cut-off tail with "..." when s does not fit. Runes are never split,
+// so the result can come up one column short when a wide rune would
+// straddle the boundary. Widths too small for the ellipsis cut without one.
+func TruncateWidth(s string, maxWidth int) string {
+	if Width(s) <= maxWidth {
+		return s
+	}
+
+	const ellipsis = "..."
+	target := maxWidth - len(ellipsis)
+	if target < 0 {
+		target = maxWidth
+	}
+
+	w := 0
+	for i, r := range s {
+		rw := RuneWidth(r)
+		if w+rw > target {
+			if target == maxWidth {
+				// No room for an ellipsis; just cut cleanly
+				return s[:i]
+			}
+			return s[:i] + ellipsis
+		}
+		w += rw
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package text
+
+import "testing"
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"empty", "", 0},
+		{"ascii", "hello", 5},
+		{"cjk", "こんにちは", 10},
+		{"mixed", "go言語", 6},
+		{"combining accent", "é", 1},
+		{"fullwidth digits", "１２３", 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Width(tt.input); got != tt.want {
+				t.Errorf("Width(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxWidth int
+		want     string
+	}{
+		{"fits untouched", "hello", 10, "hello"},
+		{"ascii truncated", "hello world", 8, "hello..."},
+		{"cjk truncated", "こんにちは世界", 9, "こんに..."},
+		{"cjk never split mid-rune", "ああああ", 7, "ああ..."},
+		{"emoji truncated whole", "🎉🎉🎉🎉🎉", 7, "🎉🎉..."},
+		{"combining chars stay attached", "ééééé", 4, "é..."},
+		{"width too small for ellipsis", "hello", 2, "he"},
+		{"exact fit", "abc", 3, "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateWidth(tt.input, tt.maxWidth)
+			if got != tt.want {
+				t.Errorf("TruncateWidth(%q, %d) = %q, want %q", tt.input, tt.maxWidth, got, tt.want)
+			}
+			if w := Width(got); w > tt.maxWidth {
+				t.Errorf("TruncateWidth(%q, %d) is %d columns wide", tt.input, tt.maxWidth, w)
+			}
+		})
+	}
+}
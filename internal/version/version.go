@@ -0,0 +1,58 @@
+// Package version exposes build metadata for the running binary. The
+// variables are injected at build time via -ldflags; without them (e.g. a
+// plain `go install`) the module build info fills in what it can.
+package version
+
+import "runtime/debug"
+
+// Populated via:
+//
+//	-ldflags "-X gopus/internal/version.Version=v1.2.3 \
+//	          -X gopus/internal/version.Commit=abc1234 \
+//	          -X gopus/internal/version.BuildDate=2024-01-02"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+func init() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	if Version == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		Version = info.Main.Version
+	}
+	if Commit == "" {
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if len(setting.Value) >= 7 {
+					Commit = setting.Value[:7]
+				} else {
+					Commit = setting.Value
+				}
+			case "vcs.time":
+				if BuildDate == "" {
+					BuildDate = setting.Value
+				}
+			}
+		}
+	}
+}
+
+// String returns the version with commit and build date when known, e.g.
+// "v1.2.3 (abc1234, 2024-01-02)".
+func String() string {
+	s := Version
+	switch {
+	case Commit != "" && BuildDate != "":
+		s += " (" + Commit + ", " + BuildDate + ")"
+	case Commit != "":
+		s += " (" + Commit + ")"
+	case BuildDate != "":
+		s += " (" + BuildDate + ")"
+	}
+	return s
+}
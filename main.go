@@ -6,26 +6,109 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"gopus/internal/chat"
 	"gopus/internal/config"
 	"gopus/internal/history"
+	"gopus/internal/i18n"
 	"gopus/internal/mcp"
 	"gopus/internal/openai"
+	"gopus/internal/printer"
 	"gopus/internal/signal"
+	"gopus/internal/spinner"
+
+	"golang.org/x/term"
 
 	// Import builtin package for side effects (registers builtin servers)
 	_ "gopus/internal/mcp/builtin"
 )
 
+// safeMode is set by the --safe flag and overrides chat.safe_mode in config.
+var safeMode bool
+
+// quietMode is set by the -q/--quiet flag and suppresses the startup
+// summary panel, like ui.quiet in config.
+var quietMode bool
+
+// workspaceName is set by the --workspace flag; when empty, GOPUS_WORKSPACE
+// is consulted instead.
+var workspaceName string
+
+// sessionsDirOverride is set by the --sessions-dir flag and wins over both
+// history.sessions_dir and the active workspace's directory.
+var sessionsDirOverride string
+
 func main() {
+	args := os.Args[1:]
+	// Global flags may precede a subcommand
+	for len(args) > 0 {
+		handled := true
+		switch args[0] {
+		case "--safe":
+			safeMode = true
+		case "-q", "--quiet":
+			quietMode = true
+		case "--no-color":
+			printer.SetColors(false)
+		case "--force-color":
+			printer.SetColors(true)
+		case "--workspace":
+			workspaceName = requireFlagValue(args, "--workspace")
+			args = args[1:]
+		case "--sessions-dir":
+			sessionsDirOverride = requireFlagValue(args, "--sessions-dir")
+			args = args[1:]
+		default:
+			handled = false
+		}
+		if !handled {
+			break
+		}
+		args = args[1:]
+	}
+
+	// Non-interactive subcommands (e.g. `gopus sessions list --json`)
+	if len(args) > 0 {
+		os.Exit(runCommand(args[0], args[1:]))
+	}
+
 	// Set up signal handling for graceful shutdown
 	signal.RunWithContext(main0)
 }
 
-func main0(ctx context.Context) {
-	fmt.Printf("Press Ctrl+D to end the session.\n")
+// requireFlagValue returns the value following a flag, exiting with a usage
+// error when it is missing.
+func requireFlagValue(args []string, flag string) string {
+	if len(args) < 2 {
+		fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", flag)
+		os.Exit(2)
+	}
+	return args[1]
+}
 
+// applyStartupOverrides applies the workspace selection (--workspace, or
+// GOPUS_WORKSPACE when the flag is absent) and the --sessions-dir override
+// to a loaded config. An explicit --sessions-dir wins over the workspace's
+// directory.
+func applyStartupOverrides(cfg *config.Config) error {
+	name := workspaceName
+	if name == "" {
+		name = os.Getenv("GOPUS_WORKSPACE")
+	}
+	if name != "" {
+		if err := cfg.ApplyWorkspace(name); err != nil {
+			return err
+		}
+	}
+	if sessionsDirOverride != "" {
+		cfg.History.SessionsDir = sessionsDirOverride
+	}
+	return nil
+}
+
+func main0(ctx context.Context) {
 	// Create scanner for reading user input
 	scanner := bufio.NewScanner(os.Stdin)
 
@@ -37,6 +120,31 @@ func main0(ctx context.Context) {
 		os.Exit(1)
 	}
 
+	if err := applyStartupOverrides(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	quiet := quietMode || cfg.UI.Quiet
+
+	// An empty value keeps the locale-based auto-detection
+	spinner.SetStyle(spinner.Style(cfg.UI.Spinner))
+	printer.SetShowTimestamps(cfg.UI.ShowTimestamps)
+	i18n.SetLocale(cfg.UI.Language)
+
+	if cfg.History.Encryption {
+		if err := enableHistoryEncryption(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if safeMode {
+		cfg.Chat.SafeMode = true
+	}
+	if cfg.Chat.SafeMode {
+		fmt.Println("Safe mode enabled: destructive commands are blocked and all tool calls require confirmation.")
+	}
+
 	// Create OpenAI client
 	client, err := openai.NewChatClient(cfg)
 	if err != nil {
@@ -50,6 +158,22 @@ func main0(ctx context.Context) {
 		fmt.Fprintf(os.Stderr, "Error initializing history: %v\n", err)
 		os.Exit(1)
 	}
+	// Signals bypass the chat loop's own cleanup, so release the session
+	// lock here too; releasing twice is harmless
+	signal.OnShutdown(historyManager.ReleaseSessionLock)
+
+	// Apply the retention policy before a session is opened, so even the
+	// session picked below was a pruning candidate moments ago
+	if policy := retentionPolicy(cfg); policy.Enabled() {
+		archived, err := historyManager.Prune(policy, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to prune old sessions: %v\n", err)
+		}
+		if len(archived) > 0 {
+			fmt.Printf("Moved %d old session(s) to the %s subdirectory (see `gopus sessions prune --dry-run`).\n",
+				len(archived), history.TrashDirName)
+		}
+	}
 
 	// Session selection at startup
 	if err := history.SelectSession(historyManager, scanner); err != nil {
@@ -57,15 +181,36 @@ func main0(ctx context.Context) {
 		os.Exit(1)
 	}
 
+	// Another instance may have the selected session open; offer to take
+	// over its lock instead of staying read-only
+	if historyManager.ReadOnly() {
+		fmt.Print("Take over the session lock and write anyway? [y/N]: ")
+		if scanner.Scan() && strings.EqualFold(strings.TrimSpace(scanner.Text()), "y") {
+			if err := historyManager.ForceSessionLock(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to take over the session lock: %v\n", err)
+			}
+		}
+	}
+
 	// Initialize MCP manager
-	mcpManager, err := initMCPManager(ctx, cfg.MCP, client)
+	mcpManager, err := initMCPManager(ctx, cfg.MCP, mcp.Deps{OpenAI: client, History: historyManager})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to initialize MCP manager: %v\n", err)
 		// Continue without MCP support
 	} else {
-		defer mcpManager.Close()
+		// Registered rather than deferred so server subprocesses are also
+		// shut down when a signal interrupts the run
+		signal.OnShutdown(func() { mcpManager.Close() })
 	}
 
+	// One-look summary of what this run is wired to; failures appear as
+	// warnings beneath it (and on their own when the panel is suppressed)
+	if !quiet {
+		printStartupSummary(cfg, historyManager, mcpManager)
+		fmt.Println("Press Ctrl+D to end the session.")
+	}
+	reportServerFailures(mcpManager)
+
 	// Create and run the chat loop
 	chatLoop := chat.NewChatLoop(client, historyManager, mcpManager, cfg)
 
@@ -78,9 +223,27 @@ func main0(ctx context.Context) {
 	}
 }
 
+// enableHistoryEncryption turns on at-rest encryption for session files,
+// taking the passphrase from GOPUS_PASSPHRASE or prompting for it without
+// echo. Must run before any stdin scanning so the prompt reads cleanly.
+func enableHistoryEncryption() error {
+	passphrase := os.Getenv("GOPUS_PASSPHRASE")
+	if passphrase == "" {
+		fmt.Print("Session passphrase: ")
+		raw, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err != nil {
+			return fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		passphrase = string(raw)
+	}
+	return history.EnableEncryption(passphrase)
+}
+
 // initMCPManager creates and initializes the MCP manager with configured servers.
-// The openaiClient is passed to builtin servers that may need OpenAI API access.
-func initMCPManager(ctx context.Context, mcpCfg config.MCPConfig, openaiClient *openai.ChatClient) (*mcp.Manager, error) {
+// The deps container is passed to builtin servers that may need OpenAI API
+// or session history access.
+func initMCPManager(ctx context.Context, mcpCfg config.MCPConfig, deps mcp.Deps) (*mcp.Manager, error) {
 	// Create the MCP manager with optional debug logging
 	manager := mcp.NewManagerWithDebug(mcpCfg.Debug)
 
@@ -88,8 +251,24 @@ func initMCPManager(ctx context.Context, mcpCfg config.MCPConfig, openaiClient *
 		fmt.Fprintln(os.Stderr, "MCP debug logging enabled - JSON-RPC messages will be displayed")
 	}
 
-	// Initialize builtin servers first
-	builtinCount := initBuiltinServers(ctx, manager, mcpCfg.Builtin, openaiClient)
+	if mcpCfg.ShutdownGrace > 0 {
+		manager.SetShutdownGrace(time.Duration(mcpCfg.ShutdownGrace) * time.Second)
+	}
+
+	if mcpCfg.DisableValidation {
+		manager.SetValidationDisabled(true)
+	}
+
+	// Enable result caching for tools with a configured TTL
+	for name, toolCfg := range mcpCfg.Tools {
+		if toolCfg.CacheTTL > 0 {
+			manager.SetToolCacheTTL(name, time.Duration(toolCfg.CacheTTL)*time.Second)
+		}
+	}
+
+	// Initialize builtin servers first, honoring the enable/disable lists
+	builtinSummary := mcp.StartBuiltinServers(ctx, manager, mcpCfg.Builtin, deps)
+	builtinCount := len(builtinSummary.Started)
 
 	// Connect to each enabled external server
 	connectedServers := 0
@@ -98,19 +277,11 @@ func initMCPManager(ctx context.Context, mcpCfg config.MCPConfig, openaiClient *
 			continue
 		}
 
-		// Convert env map to slice format
-		var envSlice []string
-		for k, v := range serverCfg.Env {
-			envSlice = append(envSlice, fmt.Sprintf("%s=%s", k, v))
-		}
-
-		// Add the server (uses stdio transport internally)
-		if err := manager.AddServer(ctx, serverCfg.Name, serverCfg.Command, envSlice, serverCfg.Args...); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to connect to MCP server %q: %v\n", serverCfg.Name, err)
+		// Add the server (uses stdio transport internally); failures are
+		// recorded by the manager and reported below
+		if err := manager.AddServerWithConfig(ctx, serverCfg); err != nil {
 			continue
 		}
-
-		fmt.Printf("Connected to MCP server: %s\n", serverCfg.Name)
 		connectedServers++
 	}
 
@@ -119,34 +290,71 @@ func initMCPManager(ctx context.Context, mcpCfg config.MCPConfig, openaiClient *
 		return nil, fmt.Errorf("no MCP servers connected successfully")
 	}
 
-	if totalServers > 0 {
-		fmt.Printf("MCP: %d server(s) connected (%d builtin, %d external), %d tool(s) available\n",
-			totalServers, builtinCount, connectedServers, manager.ToolCount())
-	}
-
 	return manager, nil
 }
 
-// initBuiltinServers initializes the single builtin MCP server with all registered tools.
-// The openaiClient is passed to the builtin server for tools that need OpenAI API access.
-func initBuiltinServers(ctx context.Context, manager *mcp.Manager, builtinCfg config.BuiltinConfig, openaiClient *openai.ChatClient) int {
-	// Check if builtin server is enabled
-	if !builtinCfg.IsServerEnabled("builtin") {
-		return 0
+// printStartupSummary renders a one-look panel of what this run is wired
+// to: config source, model, session, sessions directory, MCP servers with
+// their status, and whether summarization is on. Suppressed by -q/ui.quiet.
+// Colors go through the printer package, so redirected output stays clean.
+func printStartupSummary(cfg *config.Config, historyManager *history.Manager, mcpManager *mcp.Manager) {
+	fmt.Println()
+	fmt.Printf("  Config:        %s\n", config.DefaultConfigPath)
+
+	provider := cfg.OpenAI.APIType
+	if provider == "" {
+		provider = config.APITypeOpenAI
 	}
+	fmt.Printf("  Model:         %s (%s)\n", cfg.OpenAI.DisplayModel(), provider)
 
-	// Check if there are any tools registered
-	if mcp.DefaultToolRegistry.Count() == 0 {
-		return 0
+	if cfg.ActiveWorkspace != "" {
+		fmt.Printf("  Workspace:     %s\n", cfg.ActiveWorkspace)
+	}
+	if session := historyManager.Current(); session != nil {
+		name := session.Name
+		if name == "" {
+			name = session.ID
+		}
+		fmt.Printf("  Session:       %s (%d messages)\n", name, len(session.Messages))
 	}
+	fmt.Printf("  Sessions dir:  %s\n", historyManager.SessionsDir())
 
-	// Create and add the single builtin server
-	builtin := &mcp.BuiltinServer{}
-	if err := manager.AddBuiltinServer(ctx, builtin, openaiClient); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Failed to initialize builtin server: %v\n", err)
-		return 0
+	summarization := "off"
+	if cfg.Summarization.Enabled {
+		summarization = "on"
+		if cfg.Summarization.AutoSummarize {
+			summarization = fmt.Sprintf("on (auto above %d messages)", cfg.Summarization.AutoThreshold)
+		}
 	}
+	fmt.Printf("  Summarization: %s\n", summarization)
 
-	fmt.Printf("Initialized builtin MCP server: %s (%d tools)\n", builtin.Name(), mcp.DefaultToolRegistry.Count())
-	return 1
+	if mcpManager == nil {
+		fmt.Println("  MCP:           not configured")
+		fmt.Println()
+		return
+	}
+	fmt.Println("  MCP servers:")
+	for _, status := range mcpManager.ServerStatuses() {
+		if status.Connected {
+			fmt.Printf("    %s✓%s %s (%d tools)\n", printer.ColorGreen, printer.ColorReset, status.ID, status.Tools)
+		} else {
+			fmt.Printf("    %s✗%s %s: %s\n", printer.ColorRed, printer.ColorReset, status.ID, status.LastError)
+		}
+	}
+	fmt.Printf("  Tools:         %d\n", mcpManager.ToolCount())
+	fmt.Println()
+}
+
+// reportServerFailures warns about MCP servers that failed to start. Kept
+// separate from the panel so failures are visible even with -q; they stay
+// listed in /servers and can be reconnected with /mcp retry.
+func reportServerFailures(mcpManager *mcp.Manager) {
+	if mcpManager == nil {
+		return
+	}
+	for _, status := range mcpManager.ServerStatuses() {
+		if !status.Connected {
+			printer.PrintWarning("MCP server %s failed to start: %s", status.ID, status.LastError)
+		}
+	}
 }
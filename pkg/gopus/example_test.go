@@ -0,0 +1,43 @@
+package gopus_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"gopus/pkg/gopus"
+)
+
+// Example shows a minimal embedding: one conversation, one tool, replies
+// printed as they come back.
+func Example() {
+	conv, err := gopus.New(gopus.Options{
+		APIKey:      os.Getenv("OPENAI_API_KEY"),
+		SessionsDir: "/tmp/example-sessions",
+		Tools: []gopus.Tool{{
+			Name:        "lookup_order",
+			Description: "Looks up an order by its number",
+			Schema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"number": map[string]any{"type": "string"},
+				},
+				"required": []string{"number"},
+			},
+			Handler: func(ctx context.Context, args map[string]any) (string, error) {
+				return fmt.Sprintf("order %v: shipped", args["number"]), nil
+			},
+		}},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conv.Close()
+
+	reply, err := conv.SendMessage(context.Background(), "Where is order 1234?")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(reply)
+}
@@ -0,0 +1,368 @@
+// Package gopus exposes the gopus conversation engine for embedding in
+// other programs: persistent session history, automatic summarization of
+// long conversations, and a tool-calling loop backed by in-process tools.
+//
+// The interactive CLI is a thin terminal front end over the same engine;
+// this package is the supported surface for everything that does not need
+// a terminal. A minimal embedding looks like:
+//
+//	conv, err := gopus.New(gopus.Options{
+//		APIKey:      os.Getenv("OPENAI_API_KEY"),
+//		SessionsDir: "/tmp/myapp-sessions",
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer conv.Close()
+//
+//	reply, err := conv.SendMessage(ctx, "Hello!")
+//
+// Tools registered through Options.Tools run in-process; the model decides
+// when to call them, and a ToolObserver can watch the calls as they happen.
+package gopus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopus/internal/config"
+	"gopus/internal/history"
+	"gopus/internal/mcp"
+	"gopus/internal/openai"
+	"gopus/internal/summarize"
+)
+
+// Options configures a Conversation. Only APIKey is required; every other
+// field falls back to the same default the CLI uses.
+type Options struct {
+	// APIKey authenticates against the OpenAI API (required).
+	APIKey string
+
+	// Model selects the chat model (default: gpt-3.5-turbo).
+	Model string
+
+	// BaseURL overrides the API endpoint, for proxies or alternative
+	// providers.
+	BaseURL string
+
+	// MaxTokens caps the response length (default: 1000).
+	MaxTokens int
+
+	// Temperature sets response randomness, 0.0-2.0 (default: 0.7).
+	Temperature float64
+
+	// SystemPrompt is prepended to every request; empty sends no system
+	// message.
+	SystemPrompt string
+
+	// SessionsDir is where session files are stored (default:
+	// ./.gopus/sessions/). Embedding programs usually want their own
+	// directory.
+	SessionsDir string
+
+	// SessionID resumes an existing session from SessionsDir; empty starts
+	// a fresh one.
+	SessionID string
+
+	// DisableSummarization turns off the automatic condensing of long
+	// histories. Sessions then grow without bound.
+	DisableSummarization bool
+
+	// Tools are made available to the model for calling. They run
+	// in-process; no confirmation is asked, so only register tools the
+	// embedding program is prepared to have called.
+	Tools []Tool
+
+	// EnableBuiltinTools additionally exposes gopus's own builtin tools
+	// (echo, calculator, history search, ...) to the model. Off by
+	// default: embedders usually want full control over the tool surface.
+	EnableBuiltinTools bool
+
+	// Observer, when set, is notified of every tool call the model makes.
+	Observer ToolObserver
+}
+
+// Conversation is an embeddable chat conversation: messages sent through it
+// are persisted to the session, answered via the OpenAI API (running any
+// tool calls the model requests), and summarized automatically once the
+// history grows past the configured threshold.
+//
+// A Conversation is safe for concurrent use, but messages are processed one
+// at a time.
+type Conversation struct {
+	mu          sync.Mutex
+	cfg         *config.Config
+	client      *openai.ChatClient
+	history     *history.Manager
+	summarizer  *summarize.Summarizer
+	mcpManager  *mcp.Manager
+	observer    ToolObserver
+	chatHistory []openai.ChatCompletionRequestMessage
+}
+
+// New creates a Conversation from the given options.
+func New(opts Options) (*Conversation, error) {
+	if opts.APIKey == "" {
+		return nil, fmt.Errorf("APIKey is required")
+	}
+
+	cfg := &config.Config{}
+	cfg.OpenAI.APIKey = opts.APIKey
+	cfg.OpenAI.Model = opts.Model
+	cfg.OpenAI.BaseURL = opts.BaseURL
+	cfg.OpenAI.MaxTokens = opts.MaxTokens
+	cfg.OpenAI.Temperature = opts.Temperature
+	cfg.Chat.SystemPrompt = opts.SystemPrompt
+	cfg.History.SessionsDir = opts.SessionsDir
+	cfg.ApplyDefaults()
+	if opts.DisableSummarization {
+		cfg.Summarization.Enabled = false
+		cfg.Summarization.AutoSummarize = false
+	}
+
+	client, err := openai.NewChatClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	manager, err := history.NewManager(cfg.History.SessionsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize history: %w", err)
+	}
+	if opts.SessionID != "" {
+		session, err := manager.LoadSessionByID(opts.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resume session: %w", err)
+		}
+		manager.SetCurrent(session)
+	} else {
+		manager.NewSession()
+	}
+
+	c := &Conversation{
+		cfg:        cfg,
+		client:     client,
+		history:    manager,
+		summarizer: summarize.New(client, cfg.Summarization),
+		observer:   opts.Observer,
+	}
+
+	if len(opts.Tools) > 0 || opts.EnableBuiltinTools {
+		mcpManager, err := startToolServer(opts.Tools, opts.EnableBuiltinTools, mcp.Deps{OpenAI: client, History: manager})
+		if err != nil {
+			return nil, err
+		}
+		c.mcpManager = mcpManager
+	}
+
+	c.chatHistory = c.buildChatHistory()
+	return c, nil
+}
+
+// SendMessage sends a user message and returns the assistant's reply. Tool
+// calls the model requests are executed before the reply comes back; the
+// Observer, if any, sees each of them. On error, the failed user message is
+// removed from the session again so a retry does not duplicate it.
+func (c *Conversation) SendMessage(ctx context.Context, text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("message is empty")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.history.AddMessage(history.RoleUser, text); err != nil {
+		return "", fmt.Errorf("failed to save message: %w", err)
+	}
+	c.chatHistory = append(c.chatHistory, openai.ChatCompletionRequestMessage{
+		Role:    openai.RoleUser,
+		Content: openai.StringContent(text),
+	})
+
+	reply, err := c.completeTurn(ctx)
+	if err != nil {
+		// Remove the failed message from both histories
+		c.chatHistory = c.chatHistory[:len(c.chatHistory)-1]
+		if session := c.history.Current(); len(session.Messages) > 0 {
+			session.Messages = session.Messages[:len(session.Messages)-1]
+			c.history.SaveCurrent()
+		}
+		return "", err
+	}
+
+	c.maybeSummarize(ctx)
+	return reply, nil
+}
+
+// completeTurn runs the request loop for one user message, executing tool
+// calls until the model produces a final text reply. Callers hold c.mu.
+func (c *Conversation) completeTurn(ctx context.Context) (reply string, err error) {
+	// A tool-call turn appends several messages before the final
+	// completion; if it fails partway, roll them all back so the next
+	// request stays API-valid.
+	preTurnLen := len(c.chatHistory)
+	defer func() {
+		if err != nil {
+			c.chatHistory = c.chatHistory[:preTurnLen]
+		}
+	}()
+
+	tools := c.openAITools(ctx)
+	meta := history.Meta{}
+	emptyRetries := 0
+
+	for {
+		resp, err := c.client.ChatCompletionWithTools(ctx, c.chatHistory, tools)
+		if err != nil {
+			return "", err
+		}
+
+		meta.Model = resp.Model
+		if resp.Usage != nil {
+			meta.PromptTokens += resp.Usage.PromptTokens
+			meta.CompletionTokens += resp.Usage.CompletionTokens
+		}
+
+		choice, err := openai.ExtractFirstChoice(resp)
+		if err != nil {
+			return "", err
+		}
+		if choice.FinishReason != nil {
+			meta.FinishReason = string(*choice.FinishReason)
+		}
+
+		message := choice.Message
+		if message.ToolCalls != nil && len(*message.ToolCalls) > 0 {
+			c.chatHistory = append(c.chatHistory, openai.AssistantMessageWithToolCalls(message))
+			meta.ToolsExecuted += len(*message.ToolCalls)
+			for _, toolCall := range *message.ToolCalls {
+				content := c.runToolCall(ctx, toolCall)
+				c.chatHistory = append(c.chatHistory, openai.ToolResultMessage(toolCall.Id, content))
+			}
+			continue
+		}
+
+		// Some providers occasionally return a choice with null content and
+		// finish_reason stop; retry once before treating it as a failure.
+		if message.Content == nil {
+			if emptyRetries == 0 {
+				emptyRetries++
+				continue
+			}
+			return "", openai.ErrEmptyResponse
+		}
+
+		reply := *message.Content
+		if err := c.history.AddMessageWithMeta(history.RoleAssistant, reply, &meta); err != nil {
+			return "", fmt.Errorf("failed to save message: %w", err)
+		}
+		c.chatHistory = append(c.chatHistory, openai.ChatCompletionRequestMessage{
+			Role:    openai.RoleAssistant,
+			Content: openai.StringContent(reply),
+		})
+		return reply, nil
+	}
+}
+
+// runToolCall executes one tool call and returns the content for its tool
+// result message. Failures are reported to the model as text rather than
+// aborting the turn, matching how the CLI treats tool errors.
+func (c *Conversation) runToolCall(ctx context.Context, toolCall openai.ChatCompletionMessageToolCall) string {
+	name := toolCall.Function.Name
+	args := toolCall.Function.Arguments
+	c.observeStarted(name, args)
+
+	var content string
+	var err error
+	if c.mcpManager == nil {
+		err = fmt.Errorf("no tools are configured")
+	} else {
+		content, err = c.mcpManager.ExecuteToolCall(ctx, name, args)
+	}
+	c.observeFinished(name, content, err)
+	if err != nil {
+		return fmt.Sprintf("Tool error: %v", err)
+	}
+	return content
+}
+
+// openAITools returns the registered tools in OpenAI format, or nil when
+// none are configured.
+func (c *Conversation) openAITools(ctx context.Context) []openai.ChatCompletionTool {
+	if c.mcpManager == nil {
+		return nil
+	}
+	return c.mcpManager.OpenAITools(ctx)
+}
+
+// maybeSummarize condenses the session history once it crosses the
+// auto-summarization threshold. Failures are swallowed: summarization is an
+// optimization, and the next turn will simply try again.
+func (c *Conversation) maybeSummarize(ctx context.Context) {
+	session := c.history.Current()
+	if !c.cfg.Summarization.Enabled || !c.summarizer.ShouldAutoSummarize(session.Messages) {
+		return
+	}
+
+	newMessages, err := c.summarizer.ProcessSession(ctx, session)
+	if err != nil {
+		return
+	}
+	session.Messages = newMessages
+	c.history.SaveCurrent()
+	c.chatHistory = c.buildChatHistory()
+}
+
+// buildChatHistory converts the current session's messages to API format,
+// prepending the system prompt. The prompt lives only in the request
+// history, never in the session, so summarization cannot condense it away.
+func (c *Conversation) buildChatHistory() []openai.ChatCompletionRequestMessage {
+	messages := history.MessagesToOpenAI(c.history.Current().Messages)
+	prompt := c.cfg.Chat.SystemPrompt
+	if prompt == "" {
+		return messages
+	}
+	return append([]openai.ChatCompletionRequestMessage{{
+		Role:    openai.RoleSystem,
+		Content: openai.StringContent(prompt),
+	}}, messages...)
+}
+
+// Session returns the session this conversation appends to.
+func (c *Conversation) Session() *Session {
+	return c.history.Current()
+}
+
+// History returns the underlying history manager, for listing, forking, or
+// switching sessions.
+func (c *Conversation) History() *HistoryManager {
+	return c.history
+}
+
+// Close flushes pending session writes, releases the session lock, and
+// shuts down the tool server.
+func (c *Conversation) Close() error {
+	err := c.history.Flush()
+	c.history.ReleaseSessionLock()
+	if c.mcpManager != nil {
+		if closeErr := c.mcpManager.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (c *Conversation) observeStarted(name, arguments string) {
+	if c.observer != nil {
+		c.observer.ToolCallStarted(name, arguments)
+	}
+}
+
+func (c *Conversation) observeFinished(name, result string, err error) {
+	if c.observer != nil {
+		c.observer.ToolCallFinished(name, result, err)
+	}
+}
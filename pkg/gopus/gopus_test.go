@@ -0,0 +1,176 @@
+package gopus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gopus/internal/testutil"
+)
+
+// completionBody builds a scripted reply with the given assistant text.
+func completionBody(content string) testutil.Response {
+	return testutil.Response{Body: testutil.CompletionBody(fmt.Sprintf("%q", content), "stop")}
+}
+
+func newTestConversation(t *testing.T, baseURL string, extra Options) *Conversation {
+	t.Helper()
+	opts := extra
+	opts.APIKey = "test-key"
+	opts.Model = "test-model"
+	opts.BaseURL = baseURL
+	opts.SessionsDir = t.TempDir()
+
+	conv, err := New(opts)
+	if err != nil {
+		t.Fatalf("failed to create conversation: %v", err)
+	}
+	t.Cleanup(func() { conv.Close() })
+	return conv
+}
+
+func TestSendMessage(t *testing.T) {
+	srv := testutil.NewServer(t, []testutil.Response{completionBody("hello there")})
+
+	conv := newTestConversation(t, srv.URL, Options{})
+	reply, err := conv.SendMessage(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if reply != "hello there" {
+		t.Errorf("reply = %q, want %q", reply, "hello there")
+	}
+
+	messages := conv.Session().Messages
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 persisted messages, got %d", len(messages))
+	}
+	if messages[0].Role != RoleUser || messages[1].Role != RoleAssistant {
+		t.Errorf("unexpected roles: %s, %s", messages[0].Role, messages[1].Role)
+	}
+}
+
+// toolRecorder records observer callbacks.
+type toolRecorder struct {
+	started  []string
+	finished []string
+}
+
+func (r *toolRecorder) ToolCallStarted(name, arguments string) {
+	r.started = append(r.started, name+" "+arguments)
+}
+
+func (r *toolRecorder) ToolCallFinished(name, result string, err error) {
+	r.finished = append(r.finished, name+" "+result)
+}
+
+func TestSendMessageRunsTools(t *testing.T) {
+	srv := testutil.NewServer(t, []testutil.Response{
+		{Body: testutil.ToolCallBody("call_1", "lookup", `{"key": "answer"}`)},
+		completionBody("the answer is 42"),
+	})
+
+	recorder := &toolRecorder{}
+	conv := newTestConversation(t, srv.URL, Options{
+		Observer: recorder,
+		Tools: []Tool{{
+			Name:        "lookup",
+			Description: "Looks up a value by key",
+			Schema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"key": map[string]any{"type": "string"}},
+			},
+			Handler: func(ctx context.Context, args map[string]any) (string, error) {
+				if args["key"] != "answer" {
+					return "", fmt.Errorf("unexpected key %v", args["key"])
+				}
+				return "42", nil
+			},
+		}},
+	})
+
+	reply, err := conv.SendMessage(context.Background(), "what is the answer?")
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if reply != "the answer is 42" {
+		t.Errorf("reply = %q, want %q", reply, "the answer is 42")
+	}
+
+	if len(recorder.started) != 1 || recorder.started[0] != `lookup {"key": "answer"}` {
+		t.Errorf("unexpected started calls: %v", recorder.started)
+	}
+	if len(recorder.finished) != 1 || recorder.finished[0] != "lookup 42" {
+		t.Errorf("unexpected finished calls: %v", recorder.finished)
+	}
+}
+
+func TestSendMessageErrorRollsBack(t *testing.T) {
+	srv := testutil.NewServer(t, []testutil.Response{
+		{Status: http.StatusInternalServerError, Body: testutil.ErrorBody("boom")},
+	})
+
+	conv := newTestConversation(t, srv.URL, Options{})
+	if _, err := conv.SendMessage(context.Background(), "hi"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := len(conv.Session().Messages); n != 0 {
+		t.Errorf("expected the failed message to be rolled back, found %d messages", n)
+	}
+
+	// The next message goes through cleanly after the rollback
+	srv2 := testutil.NewServer(t, []testutil.Response{completionBody("recovered")})
+	conv2 := newTestConversation(t, srv2.URL, Options{})
+	if reply, err := conv2.SendMessage(context.Background(), "hi again"); err != nil || reply != "recovered" {
+		t.Errorf("expected a clean reply, got %q, %v", reply, err)
+	}
+}
+
+func TestSendMessageEmptyInput(t *testing.T) {
+	srv := testutil.NewServer(t, nil)
+
+	conv := newTestConversation(t, srv.URL, Options{})
+	if _, err := conv.SendMessage(context.Background(), "   "); err == nil {
+		t.Error("expected an error for a blank message")
+	}
+}
+
+func TestNewRequiresAPIKey(t *testing.T) {
+	if _, err := New(Options{SessionsDir: t.TempDir()}); err == nil {
+		t.Error("expected an error without an API key")
+	}
+}
+
+func TestResumeSession(t *testing.T) {
+	srv := testutil.NewServer(t, []testutil.Response{completionBody("first"), completionBody("second")})
+
+	dir := t.TempDir()
+	opts := Options{APIKey: "test-key", Model: "test-model", BaseURL: srv.URL, SessionsDir: dir}
+
+	conv, err := New(opts)
+	if err != nil {
+		t.Fatalf("failed to create conversation: %v", err)
+	}
+	if _, err := conv.SendMessage(context.Background(), "hi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	sessionID := conv.Session().ID
+	if err := conv.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	opts.SessionID = sessionID
+	resumed, err := New(opts)
+	if err != nil {
+		t.Fatalf("failed to resume session: %v", err)
+	}
+	defer resumed.Close()
+
+	if _, err := resumed.SendMessage(context.Background(), "more"); err != nil {
+		t.Fatalf("SendMessage after resume failed: %v", err)
+	}
+	if n := len(resumed.Session().Messages); n != 4 {
+		t.Errorf("expected 4 messages across both runs, got %d", n)
+	}
+}
@@ -0,0 +1,40 @@
+package gopus
+
+import "gopus/internal/history"
+
+// The session history types are aliases for the engine's own, so values
+// returned here interoperate with every Conversation method.
+type (
+	// Session is one persisted conversation: its messages, metadata, and
+	// per-session overrides.
+	Session = history.Session
+
+	// Message is a single entry in a session: a chat message, a summary,
+	// or a note.
+	Message = history.Message
+
+	// Meta records per-turn metadata (model, token usage, timing) on
+	// assistant messages.
+	Meta = history.Meta
+
+	// Role identifies who produced a message.
+	Role = history.Role
+
+	// HistoryManager persists sessions to disk and tracks the current one.
+	HistoryManager = history.Manager
+)
+
+// Message roles.
+const (
+	RoleUser      = history.RoleUser
+	RoleAssistant = history.RoleAssistant
+	RoleSystem    = history.RoleSystem
+)
+
+// NewHistoryManager creates a history manager storing sessions in the given
+// directory (or the default ./.gopus/sessions/ when empty). Most embedders
+// get one implicitly via New; use this to inspect sessions without opening
+// a conversation.
+func NewHistoryManager(sessionsDir string) (*HistoryManager, error) {
+	return history.NewManager(sessionsDir)
+}
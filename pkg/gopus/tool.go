@@ -0,0 +1,105 @@
+package gopus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"gopus/internal/mcp"
+
+	// Registers the builtin tools served when EnableBuiltinTools is set
+	_ "gopus/internal/mcp/builtin"
+)
+
+// Tool is a function the model may call during a conversation. Tools run
+// in-process within the embedding program.
+type Tool struct {
+	// Name identifies the tool to the model (required, unique).
+	Name string
+
+	// Description tells the model what the tool does and when to use it.
+	Description string
+
+	// Schema is a JSON Schema describing the arguments, as a generic map
+	// (e.g. {"type": "object", "properties": {...}}). Nil means the tool
+	// takes no arguments.
+	Schema map[string]any
+
+	// Handler executes the call. The returned string is fed back to the
+	// model; a returned error is reported to the model as a tool failure
+	// and does not abort the conversation.
+	Handler ToolFunc
+}
+
+// ToolFunc executes a tool call with the arguments the model supplied.
+type ToolFunc func(ctx context.Context, args map[string]any) (string, error)
+
+// ToolObserver is notified of tool calls as a conversation runs, e.g. to
+// surface activity in a UI or log. Calls arrive in execution order from the
+// goroutine running SendMessage.
+type ToolObserver interface {
+	// ToolCallStarted reports a tool about to run, with the raw JSON
+	// arguments the model supplied.
+	ToolCallStarted(name, arguments string)
+
+	// ToolCallFinished reports a completed tool call with its result, or
+	// the error that prevented it from running.
+	ToolCallFinished(name, result string, err error)
+}
+
+// startToolServer hosts the given tools on an in-process MCP server behind
+// a fresh manager, leaving the global registry untouched. With builtin set,
+// a second server hosting gopus's builtin tools is added alongside.
+func startToolServer(tools []Tool, builtin bool, deps mcp.Deps) (*mcp.Manager, error) {
+	registry := mcp.NewToolRegistry()
+	for _, tool := range tools {
+		if tool.Name == "" {
+			return nil, fmt.Errorf("tool has no name")
+		}
+		if tool.Handler == nil {
+			return nil, fmt.Errorf("tool %s has no handler", tool.Name)
+		}
+
+		schema := tool.Schema
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+		schemaBytes, err := json.Marshal(schema)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s has an invalid schema: %w", tool.Name, err)
+		}
+
+		handler := tool.Handler
+		registry.Register(
+			mcplib.NewToolWithRawSchema(tool.Name, tool.Description, schemaBytes),
+			func(mcp.Deps) mcp.ToolHandler {
+				return func(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+					args, _ := req.Params.Arguments.(map[string]any)
+					result, err := handler(ctx, args)
+					if err != nil {
+						return nil, err
+					}
+					return mcplib.NewToolResultText(result), nil
+				}
+			},
+		)
+	}
+
+	manager := mcp.NewManager()
+	if registry.Count() > 0 {
+		server := mcp.NewBuiltinServer("tools", registry)
+		if err := manager.AddBuiltinServer(context.Background(), server, deps); err != nil {
+			manager.Close()
+			return nil, fmt.Errorf("failed to start tool server: %w", err)
+		}
+	}
+	if builtin && mcp.DefaultToolRegistry.Count() > 0 {
+		if err := manager.AddBuiltinServer(context.Background(), &mcp.BuiltinServer{}, deps); err != nil {
+			manager.Close()
+			return nil, fmt.Errorf("failed to start builtin tool server: %w", err)
+		}
+	}
+	return manager, nil
+}